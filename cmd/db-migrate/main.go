@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/procedures/migrate"
+)
+
+var args struct {
+	config.ConfigArgs
+
+	Source string `help:"The type of the database to copy from." arg:"" enum:"disk,sqlite,postgres"`
+	Target string `help:"The type of the database to copy into." arg:"" enum:"disk,sqlite,postgres"`
+
+	ClearTarget bool `help:"Clear the target database before copying (otherwise the target must be empty)." default:"false"`
+	VerifyOnly  bool `help:"Do not copy anything, only compare the two databases." default:"false"`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Copy all data from one type of database to another (e.g., disk to postgres), and verify the copy."+
+			" Both databases are configured with the normal config options (e.g., db.sqlite.path and db.pg.uri)."+
+			" The server should not be running during a migration."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	if args.Source == args.Target {
+		log.Fatal("Source and target database types must be different.", log.NewAttr("type", args.Source))
+	}
+
+	source, err := db.OpenBackend(args.Source)
+	if err != nil {
+		log.Fatal("Failed to open source database.", err, log.NewAttr("type", args.Source))
+	}
+	defer source.Close()
+
+	target, err := db.OpenBackend(args.Target)
+	if err != nil {
+		log.Fatal("Failed to open target database.", err, log.NewAttr("type", args.Target))
+	}
+	defer target.Close()
+
+	var report *migrate.VerifyReport
+	if args.VerifyOnly {
+		report, err = migrate.Verify(source, target)
+	} else {
+		report, err = migrate.Migrate(source, target, migrate.MigrateOptions{ClearTarget: args.ClearTarget})
+	}
+
+	if report != nil {
+		printReport(report)
+	}
+
+	if err != nil {
+		log.Fatal("Failed to migrate database.", err)
+	}
+
+	if !report.Matches() {
+		log.Fatal("Databases do not match.")
+	}
+
+	fmt.Println("Databases match.")
+}
+
+func printReport(report *migrate.VerifyReport) {
+	fmt.Printf("%-14s %12s %12s  %s\n", "Category", "Source Count", "Target Count", "Checksums")
+	for _, category := range report.Categories {
+		checksums := "match"
+		if category.SourceChecksum != category.TargetChecksum {
+			checksums = "DIFFER"
+		}
+
+		fmt.Printf("%-14s %12d %12d  %s\n", category.Category, category.SourceCount, category.TargetCount, checksums)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/scoring"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var args struct {
+	config.ConfigArgs
+	Course string `help:"ID of the course." arg:""`
+	Format string `help:"The gradebook format to export." default:"generic" enum:"generic,canvas,gradescope"`
+	Out    string `help:"If provided, the gradebook will be written to the specified file instead of stdout."`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Perform a full course scoring (including late policy) and export the scores as a gradebook CSV (without uploading to an LMS)."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	course := db.MustGetCourse(args.Course)
+
+	book, err := scoring.ComputeCourseGradebook(course)
+	if err != nil {
+		log.Fatal("Failed to compute gradebook.", err, course)
+	}
+
+	text, err := book.ToCSV(args.Format)
+	if err != nil {
+		log.Fatal("Failed to write gradebook.", err, course, log.NewAttr("format", args.Format))
+	}
+
+	if args.Out == "" {
+		fmt.Print(text)
+		return
+	}
+
+	err = util.WriteFile(text, args.Out)
+	if err != nil {
+		log.Fatal("Failed to write gradebook file.", err, course, log.NewAttr("path", args.Out))
+	}
+}
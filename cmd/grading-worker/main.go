@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/worker"
+)
+
+var args struct {
+	config.ConfigArgs
+
+	Server      string `help:"The base URL of the autograder server (overrides worker.server)."`
+	Name        string `help:"A name to identify this worker (overrides worker.name)."`
+	Concurrency int    `help:"The number of grading tasks to run at the same time (overrides worker.concurrency)." default:"0"`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Run a remote grading worker that gets grading tasks from an autograder server."+
+			" The server must have remote grading enabled (grading.remote.enable),"+
+			" and the worker must authenticate as a server admin (worker.email and worker.pass)."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	if !docker.CanAccessDocker() {
+		log.Fatal("Could not access docker, which is required to run a remote grading worker.")
+	}
+
+	options := worker.GetDefaultOptions()
+
+	if args.Server != "" {
+		options.ServerURL = args.Server
+	}
+
+	if args.Name != "" {
+		options.Name = args.Name
+	}
+
+	if args.Concurrency > 0 {
+		options.Concurrency = args.Concurrency
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err = worker.Run(ctx, options)
+	if err != nil {
+		log.Fatal("Failed to run the remote grading worker.", err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/procedures/regrade"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var args struct {
+	config.ConfigArgs
+	regrade.RegradeOptions
+
+	Course     string `help:"ID of the course." arg:""`
+	Assignment string `help:"ID of the assignment." arg:""`
+	Table      bool   `help:"Output only the submissions that changed or failed as a TSV (instead of the full JSON report)." default:"false"`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Regrade the submissions for an assignment (the most recent submission for each user by default)."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	assignment := db.MustGetAssignment(args.Course, args.Assignment)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	status, err := regrade.Regrade(ctx, assignment, args.RegradeOptions, printProgress)
+	if err != nil {
+		log.Fatal("Failed to regrade assignment.", err, assignment)
+	}
+
+	if !args.Table {
+		fmt.Println(util.MustToJSONIndent(status))
+		return
+	}
+
+	fmt.Println("user\told-submission-id\tnew-submission-id\told-score\tnew-score\tdiff\tmessage")
+	for _, result := range status.Results {
+		if result.Success && !result.Changed {
+			continue
+		}
+
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n", result.User, result.OldSubmissionID, result.NewSubmissionID,
+			util.FloatToStr(result.OldScore), util.FloatToStr(result.NewScore), util.FloatToStr(result.ScoreDiff()), result.Message)
+	}
+}
+
+func printProgress(status *regrade.RegradeStatus) {
+	fmt.Fprintf(os.Stderr, "Regraded %d / %d submissions (failed: %d, changed: %d).\n", status.Completed, status.Total, status.Failed, status.Changed)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/similarity"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var args struct {
+	config.ConfigArgs
+	similarity.SimilarityOptions
+
+	Course     string `help:"ID of the course." arg:""`
+	Assignment string `help:"ID of the assignment." arg:""`
+	HTML       bool   `help:"Output report as html." default:"false"`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Compare the most recent submissions for an assignment and report pairs of similar submissions."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	assignment := db.MustGetAssignment(args.Course, args.Assignment)
+
+	report, err := similarity.GetSimilarityReport(assignment, args.SimilarityOptions)
+	if err != nil {
+		log.Fatal("Failed to get similarity report.", assignment, err)
+	}
+
+	if args.HTML {
+		html, err := report.ToHTML()
+		if err != nil {
+			log.Fatal("Failed to generate HTML similarity report.", assignment, err)
+		}
+
+		fmt.Println(html)
+	} else {
+		fmt.Println(util.MustToJSONIndent(report))
+	}
+}
@@ -8,7 +8,9 @@ import (
 // Return a user only in the case that the authentication is successful.
 // If any error is retuturned, then the request should end and the response sent based on the error.
 // This assumes basic validation has already been done on the request.
-func (this *APIRequestUserContext) Auth() (*model.ServerUser, *APIError) {
+// The course ID is the course the request is for (empty if the request is not for a course),
+// tokens that are limited to a course can only be used for requests to that course.
+func (this *APIRequestUserContext) Auth(courseID string) (*model.ServerUser, *APIError) {
 	if this.UserEmail == model.RootUserEmail {
 		return nil, NewAuthBadRequestError("-051", this, "Root is not allowed to authenticate.")
 	}
@@ -22,14 +24,18 @@ func (this *APIRequestUserContext) Auth() (*model.ServerUser, *APIError) {
 		return nil, NewAuthBadRequestError("-013", this, "Unknown User")
 	}
 
-	auth, err := user.Auth(this.UserPass)
+	token, err := user.AuthToken(this.UserPass)
 	if err != nil {
 		return nil, NewBareInternalError("-037", this.Endpoint, "User auth failed.").Err(err)
 	}
 
-	if !auth {
+	if token == nil {
 		return nil, NewAuthBadRequestError("-014", this, "Bad Password")
 	}
 
+	if (token.Course != "") && (token.Course != courseID) {
+		return nil, NewAuthBadRequestError("-058", this, "Token is limited to a different course.").Add("token-course", token.Course)
+	}
+
 	return user, nil
 }
@@ -3,6 +3,8 @@ package core
 import (
 	"testing"
 
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -56,3 +58,55 @@ func TestAuth(test *testing.T) {
 		}
 	}
 }
+
+// Tokens that are limited to a course can only authenticate requests for that course.
+func TestAuthCourseToken(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	type userRequest struct {
+		APIRequestUserContext
+		MinServerRoleUser
+	}
+
+	type courseRequest struct {
+		APIRequestCourseUserContext
+		MinCourseRoleStudent
+	}
+
+	email := "course-student@test.edulinq.org"
+
+	user := db.MustGetServerUser(email)
+	token, cleartext, err := user.CreateRandomToken("limited", model.TokenSourceLTI)
+	if err != nil {
+		test.Fatalf("Failed to create token: '%v'.", err)
+	}
+
+	token.Course = "course101"
+	db.MustUpsertUser(user)
+
+	pass := util.Sha256HexFromString(cleartext)
+
+	testCases := []struct {
+		request any
+		locator string
+	}{
+		{&courseRequest{APIRequestCourseUserContext: APIRequestCourseUserContext{APIRequestUserContext: APIRequestUserContext{UserEmail: email, UserPass: pass}, CourseID: "course101"}}, ""},
+		{&courseRequest{APIRequestCourseUserContext: APIRequestCourseUserContext{APIRequestUserContext: APIRequestUserContext{UserEmail: email, UserPass: pass}, CourseID: " COURSE101 "}}, ""},
+		{&courseRequest{APIRequestCourseUserContext: APIRequestCourseUserContext{APIRequestUserContext: APIRequestUserContext{UserEmail: email, UserPass: pass}, CourseID: "course-languages"}}, "-058"},
+		{&userRequest{APIRequestUserContext: APIRequestUserContext{UserEmail: email, UserPass: pass}}, "-058"},
+	}
+
+	for i, testCase := range testCases {
+		apiErr := ValidateAPIRequest(nil, testCase.request, "")
+
+		locator := ""
+		if apiErr != nil {
+			locator = apiErr.Locator
+		}
+
+		if testCase.locator != locator {
+			test.Errorf("Case %d: Unexpected result. Expected: '%s', Actual: '%s' -- '%v'.", i, testCase.locator, locator, apiErr)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Get the feedback policy to apply to a target user's submissions for the requesting user.
+// Returns nil if the requesting user can see full feedback,
+// which is always the case for course staff (graders and above).
+func (this *APIRequestAssignmentContext) GetFeedbackRestriction(targetEmail string) (*model.FeedbackPolicy, error) {
+	if this.User.Role >= model.CourseRoleGrader {
+		return nil, nil
+	}
+
+	if (this.Assignment.FeedbackPolicy == nil) || (this.Assignment.FeedbackPolicy.Type == model.FeedbackFull) {
+		return nil, nil
+	}
+
+	extension, err := db.GetExtension(this.Assignment, targetEmail)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get extension: '%w'.", err)
+	}
+
+	if !this.Assignment.IsFeedbackRestricted(extension, timestamp.Now()) {
+		return nil, nil
+	}
+
+	return this.Assignment.FeedbackPolicy, nil
+}
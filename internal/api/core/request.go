@@ -83,6 +83,12 @@ func (this *APIRequest) Validate(httpRequest *http.Request, request any, endpoin
 // This means that this request will be authenticated here.
 // The full request (object that this is embedded in) is also sent.
 func (this *APIRequestUserContext) Validate(httpRequest *http.Request, request any, endpoint string) *APIError {
+	return this.validate(httpRequest, request, endpoint, "")
+}
+
+// See APIRequestUserContext.Validate().
+// The course ID is the (validated) course the request is for, or empty if the request is not for a course.
+func (this *APIRequestUserContext) validate(httpRequest *http.Request, request any, endpoint string, courseID string) *APIError {
 	apiErr := this.APIRequest.Validate(httpRequest, request, endpoint)
 	if apiErr != nil {
 		return apiErr
@@ -115,7 +121,7 @@ func (this *APIRequestUserContext) Validate(httpRequest *http.Request, request a
 			return NewBadRequestError("-017", &this.APIRequest, "No user password specified.")
 		}
 
-		this.ServerUser, apiErr = this.Auth()
+		this.ServerUser, apiErr = this.Auth(courseID)
 		if apiErr != nil {
 			return apiErr
 		}
@@ -136,7 +142,10 @@ func (this *APIRequestUserContext) Validate(httpRequest *http.Request, request a
 // See APIRequestUserContext.Validate().
 // The server user will be converted into a course user to be stored within this request.
 func (this *APIRequestCourseUserContext) Validate(httpRequest *http.Request, request any, endpoint string) *APIError {
-	apiErr := this.APIRequestUserContext.Validate(httpRequest, request, endpoint)
+	// An invalid course ID is reported after auth, so it cannot match a token's course here.
+	courseID, _ := common.ValidateID(this.CourseID)
+
+	apiErr := this.APIRequestUserContext.validate(httpRequest, request, endpoint, courseID)
 	if apiErr != nil {
 		return apiErr
 	}
@@ -29,6 +29,8 @@ type APIRoute struct {
 	RequestType  reflect.Type
 	ResponseType reflect.Type
 	Description  string
+	// The endpoint streams events before its response (see APIStream).
+	Stream bool
 }
 
 func (this *BaseRoute) GetMethod() string {
@@ -70,6 +72,16 @@ func NewRedirect(method string, basePath string, target string) *BaseRoute {
 }
 
 func MustNewAPIRoute(basePath string, apiHandler any) *APIRoute {
+	return mustNewAPIRoute(basePath, apiHandler, false)
+}
+
+// Create a route for an endpoint that streams events back to the client (see APIStream).
+// The handler should look like APIStreamHandler.
+func MustNewAPIStreamRoute(basePath string, apiHandler any) *APIRoute {
+	return mustNewAPIRoute(basePath, apiHandler, true)
+}
+
+func mustNewAPIRoute(basePath string, apiHandler any, stream bool) *APIRoute {
 	handler := func(response http.ResponseWriter, request *http.Request) (err error) {
 		// Recover from any panic.
 		defer func() {
@@ -86,14 +98,18 @@ func MustNewAPIRoute(basePath string, apiHandler any) *APIRoute {
 			err = sendAPIResponse(nil, response, nil, apiErr, false)
 		}()
 
-		err = handleAPIEndpoint(response, request, apiHandler)
+		if stream {
+			err = handleAPIStreamEndpoint(response, request, apiHandler)
+		} else {
+			err = handleAPIEndpoint(response, request, apiHandler)
+		}
 
 		return err
 	}
 
 	fullPath := MakeFullAPIPath(basePath)
 
-	_, requestType, responseType, err := validateAPIHandler(fullPath, apiHandler)
+	_, requestType, responseType, err := validateAPIHandler(fullPath, apiHandler, stream)
 	if err != nil {
 		log.FatalWithCode(exit.EXIT_SOFTWARE, "Error while validating API handler.", err, log.NewAttr("endpoint", fullPath))
 	}
@@ -107,5 +123,6 @@ func MustNewAPIRoute(basePath string, apiHandler any) *APIRoute {
 		},
 		RequestType:  requestType,
 		ResponseType: responseType,
+		Stream:       stream,
 	}
 }
@@ -94,7 +94,7 @@ func handleRedirect(target string, response http.ResponseWriter, request *http.R
 
 func handleAPIEndpoint(response http.ResponseWriter, request *http.Request, apiHandler any) error {
 	// Ensure the handler looks good.
-	validAPIHandler, _, _, apiErr := validateAPIHandler(request.URL.Path, apiHandler)
+	validAPIHandler, _, _, apiErr := validateAPIHandler(request.URL.Path, apiHandler, false)
 	if apiErr != nil {
 		return sendAPIResponse(nil, response, nil, apiErr, false)
 	}
@@ -211,9 +211,13 @@ func createAPIRequest(request *http.Request, apiHandler ValidAPIHandler) (ValidA
 	return ValidAPIRequest(apiRequest), nil
 }
 
-// Reflexively call the API handler with the request.
-func callHandler(apiHandler ValidAPIHandler, apiRequest ValidAPIRequest) (any, *APIError) {
+// Reflexively call the API handler with the request (and any extra arguments the handler takes, e.g., an APIStream).
+func callHandler(apiHandler ValidAPIHandler, apiRequest ValidAPIRequest, extraArgs ...any) (any, *APIError) {
 	input := []reflect.Value{reflect.ValueOf(apiRequest)}
+	for _, extraArg := range extraArgs {
+		input = append(input, reflect.ValueOf(extraArg))
+	}
+
 	output := reflect.ValueOf(apiHandler).Call(input)
 
 	response := output[0].Interface()
@@ -232,9 +236,10 @@ func allocateAPIRequest(endpoint string, apiHandler ValidAPIHandler) (any, *APIE
 }
 
 // Reflexively ensure that the api handler is of the correct type/format (e.g. looks like APIHandler).
+// Stream handlers take an additional *APIStream argument (see APIStreamHandler).
 // Returns the handler, the type of the handler's input, the type of the handler's output, and an error.
 // Once you have a ValidAPIHandler, there is no need to check before doing reflection operations.
-func validateAPIHandler(endpoint string, apiHandler any) (ValidAPIHandler, reflect.Type, reflect.Type, *APIError) {
+func validateAPIHandler(endpoint string, apiHandler any, stream bool) (ValidAPIHandler, reflect.Type, reflect.Type, *APIError) {
 	reflectValue := reflect.ValueOf(apiHandler)
 	reflectType := reflect.TypeOf(apiHandler)
 
@@ -245,7 +250,19 @@ func validateAPIHandler(endpoint string, apiHandler any) (ValidAPIHandler, refle
 
 	funcInfo := getFuncInfo(apiHandler)
 
-	if reflectType.NumIn() != 1 {
+	if stream {
+		if reflectType.NumIn() != 2 {
+			return nil, nil, nil, NewBareInternalError("-053", endpoint, "API stream handler does not have exactly 2 arguments.").
+				Add("num-in", reflectType.NumIn()).
+				Add("function-info", funcInfo)
+		}
+
+		if reflectType.In(1) != reflect.TypeOf((*APIStream)(nil)) {
+			return nil, nil, nil, NewBareInternalError("-054", endpoint, "API stream handler's second argument is not a *APIStream.").
+				Add("type", reflectType.In(1).String()).
+				Add("function-info", funcInfo)
+		}
+	} else if reflectType.NumIn() != 1 {
 		return nil, nil, nil, NewBareInternalError("-007", endpoint, "API handler does not have exactly 1 argument.").
 			Add("num-in", reflectType.NumIn()).
 			Add("function-info", funcInfo)
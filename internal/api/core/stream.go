@@ -0,0 +1,211 @@
+package core
+
+// Support for endpoints that stream events back to the client before sending their final response.
+// A stream response is newline-delimited JSON (one APIStreamMessage per line),
+// where every message but the last holds an event and the last message holds the normal API response.
+// If a handler does not send any events (e.g., the request failed validation),
+// then the normal (non-stream) response is sent instead.
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const API_STREAM_CONTENT_TYPE = "application/x-ndjson"
+
+// Like APIHandler, but for endpoints that stream events (see MustNewAPIStreamRoute()).
+// Thus alias is not actually used (any and reflection are used), but shows what the structure is.
+type APIStreamHandler func(*any, *APIStream) (*any, *APIError)
+
+// A single line of a stream response.
+// Exactly one of the fields will be set.
+type APIStreamMessage struct {
+	Event    any          `json:"event,omitempty"`
+	Response *APIResponse `json:"response,omitempty"`
+}
+
+// A way for stream handlers to send events to the client.
+// Safe to use from multiple goroutines.
+type APIStream struct {
+	response http.ResponseWriter
+	flusher  http.Flusher
+
+	lock    sync.Mutex
+	started bool
+	// Set once a write fails (e.g., the client disconnected), all later sends will fail with this error.
+	err error
+}
+
+func newAPIStream(response http.ResponseWriter) *APIStream {
+	flusher, _ := response.(http.Flusher)
+
+	return &APIStream{
+		response: response,
+		flusher:  flusher,
+	}
+}
+
+// Send an event to the client (immediately).
+func (this *APIStream) Send(event any) error {
+	return this.write(&APIStreamMessage{Event: event})
+}
+
+func (this *APIStream) hasStarted() bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.started
+}
+
+func (this *APIStream) hasFailed() bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return (this.err != nil)
+}
+
+func (this *APIStream) write(message *APIStreamMessage) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.err != nil {
+		return this.err
+	}
+
+	payload, err := util.ToJSON(message)
+	if err != nil {
+		return fmt.Errorf("Could not serialize API stream message: '%w'.", err)
+	}
+
+	if !this.started {
+		this.started = true
+
+		// When in testing mode, allow cross-origin requests.
+		if config.UNIT_TESTING_MODE.Get() {
+			this.response.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+
+		this.response.Header().Set("Content-Type", API_STREAM_CONTENT_TYPE)
+		this.response.WriteHeader(HTTP_STATUS_GOOD)
+	}
+
+	_, err = fmt.Fprintln(this.response, payload)
+	if err != nil {
+		this.err = fmt.Errorf("Could not write API stream message: '%w'.", err)
+		return this.err
+	}
+
+	if this.flusher != nil {
+		this.flusher.Flush()
+	}
+
+	return nil
+}
+
+func handleAPIStreamEndpoint(response http.ResponseWriter, request *http.Request, apiHandler any) error {
+	// Ensure the handler looks good.
+	validAPIHandler, _, _, apiErr := validateAPIHandler(request.URL.Path, apiHandler, true)
+	if apiErr != nil {
+		return sendAPIResponse(nil, response, nil, apiErr, false)
+	}
+
+	// Get the actual request.
+	apiRequest, apiErr := createAPIRequest(request, validAPIHandler)
+	if apiErr != nil {
+		return sendAPIResponse(nil, response, nil, apiErr, false)
+	}
+	defer CleanupAPIrequest(apiRequest)
+
+	_, ok := apiRequest.(log.Loggable)
+	if ok {
+		log.Debug("Incoming API Stream Request", apiRequest)
+	}
+
+	// Execute the handler.
+	stream := newAPIStream(response)
+	apiResponse, apiErr := callHandler(apiHandler, apiRequest, stream)
+
+	return sendAPIStreamResponse(apiRequest, response, stream, apiResponse, apiErr)
+}
+
+// Send out the final result from a stream API call.
+// If no events were sent, then this is the same as sendAPIResponse().
+func sendAPIStreamResponse(apiRequest ValidAPIRequest, response http.ResponseWriter, stream *APIStream,
+	content any, apiErr *APIError) error {
+	if !stream.hasStarted() {
+		return sendAPIResponse(apiRequest, response, content, apiErr, false)
+	}
+
+	var apiResponse *APIResponse = nil
+
+	if apiErr != nil {
+		apiResponse = apiErr.ToResponse()
+
+		// This is the last interaction we will have with this error, log it.
+		apiErr.Log()
+	} else {
+		apiResponse = NewAPIResponse(apiRequest, content)
+	}
+
+	err := stream.write(&APIStreamMessage{Response: apiResponse})
+	if err == nil {
+		return nil
+	}
+
+	// The client is gone, there is no one to report the error to.
+	if stream.hasFailed() {
+		log.Warn("Failed to write final API stream response.", err, log.NewAttr("request", apiRequest))
+		return nil
+	}
+
+	apiErr = NewBareInternalError("-055", "", "Could not serialize API stream response.").Err(err)
+	apiErr.Log()
+
+	return stream.write(&APIStreamMessage{Response: apiErr.ToResponse()})
+}
+
+// Parse the text of an API response that may be a stream response.
+// Returns the events (in the order they were sent) and the final response.
+// A normal (non-stream) response is returned as a response with no events.
+func ParseAPIStream(text string) ([]any, *APIResponse, error) {
+	events := make([]any, 0)
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var message APIStreamMessage
+		err := util.JSONFromString(line, &message)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to parse API stream message: '%w'.", err)
+		}
+
+		if message.Response != nil {
+			return events, message.Response, nil
+		}
+
+		if message.Event != nil {
+			events = append(events, message.Event)
+			continue
+		}
+
+		// Not a stream message, this should be a normal response.
+		var response APIResponse
+		err = util.JSONFromString(line, &response)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to parse API response: '%w'.", err)
+		}
+
+		return events, &response, nil
+	}
+
+	return nil, nil, fmt.Errorf("API stream did not contain a response.")
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type testStreamResponse struct {
+	Count int `json:"count"`
+}
+
+func TestAPIStreamBase(test *testing.T) {
+	endpoint := `/test/api/stream/base`
+
+	handler := func(request *BaseTestRequest, stream *APIStream) (*testStreamResponse, *APIError) {
+		for i := 0; i < 3; i++ {
+			err := stream.Send(map[string]any{"index": i})
+			if err != nil {
+				return nil, NewBareInternalError("-056", endpoint, "Failed to send event.").Err(err)
+			}
+		}
+
+		return &testStreamResponse{Count: 3}, nil
+	}
+
+	routes = append(routes, MustNewAPIStreamRoute(endpoint, handler))
+
+	events, response := SendTestAPIStreamRequestFull(test, endpoint, nil, nil, "course-student")
+	if !response.Success {
+		test.Fatalf("Response is not a success: '%s'.", util.MustToJSONIndent(response))
+	}
+
+	expectedEvents := []any{
+		map[string]any{"index": float64(0)},
+		map[string]any{"index": float64(1)},
+		map[string]any{"index": float64(2)},
+	}
+
+	if !reflect.DeepEqual(expectedEvents, events) {
+		test.Fatalf("Unexpected events. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expectedEvents), util.MustToJSONIndent(events))
+	}
+
+	expectedContent := map[string]any{"count": float64(3)}
+	if !reflect.DeepEqual(expectedContent, response.Content) {
+		test.Fatalf("Unexpected content. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expectedContent), util.MustToJSONIndent(response.Content))
+	}
+}
+
+// A stream that fails after sending events still gets its error as the final response.
+func TestAPIStreamError(test *testing.T) {
+	endpoint := `/test/api/stream/error`
+
+	handler := func(request *BaseTestRequest, stream *APIStream) (*testStreamResponse, *APIError) {
+		stream.Send("foo")
+		return nil, NewBadRequestError("-057", &request.APIRequest, "Forced error.")
+	}
+
+	routes = append(routes, MustNewAPIStreamRoute(endpoint, handler))
+
+	events, response := SendTestAPIStreamRequestFull(test, endpoint, nil, nil, "course-student")
+	if response.Success || (response.Locator != "-057") {
+		test.Fatalf("Unexpected response: '%s'.", util.MustToJSONIndent(response))
+	}
+
+	if !reflect.DeepEqual([]any{"foo"}, events) {
+		test.Fatalf("Unexpected events: '%s'.", util.MustToJSONIndent(events))
+	}
+}
+
+// Streams without any events (and requests that fail validation) get a normal response.
+func TestAPIStreamNoEvents(test *testing.T) {
+	endpoint := `/test/api/stream/none`
+
+	handler := func(request *BaseTestRequest, stream *APIStream) (*testStreamResponse, *APIError) {
+		return &testStreamResponse{Count: 0}, nil
+	}
+
+	routes = append(routes, MustNewAPIStreamRoute(endpoint, handler))
+
+	testCases := []struct {
+		email   string
+		success bool
+		locator string
+	}{
+		{"course-student", true, ""},
+		{"server-user", false, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		// Normal responses can be parsed as both a stream and a normal response.
+		response := SendTestAPIRequestFull(test, endpoint, nil, nil, testCase.email)
+		events, streamResponse := SendTestAPIStreamRequestFull(test, endpoint, nil, nil, testCase.email)
+
+		if len(events) != 0 {
+			test.Errorf("Case %d: Unexpected events: '%s'.", i, util.MustToJSONIndent(events))
+			continue
+		}
+
+		for _, response := range []*APIResponse{response, streamResponse} {
+			if (response.Success != testCase.success) || (response.Locator != testCase.locator) {
+				test.Errorf("Case %d: Unexpected response: '%s'.", i, util.MustToJSONIndent(response))
+			}
+		}
+	}
+}
+
+func TestAPIStreamMalformedHandlers(test *testing.T) {
+	testCases := []struct {
+		handler any
+		locator string
+	}{
+		{func(request *BaseTestRequest) (*any, *APIError) { return nil, nil }, "-053"},
+		{func(request *BaseTestRequest, stream *APIStream, extra int) (*any, *APIError) { return nil, nil }, "-053"},
+		{func(request *BaseTestRequest, stream int) (*any, *APIError) { return nil, nil }, "-054"},
+		{func(request BaseTestRequest, stream *APIStream) (*any, *APIError) { return nil, nil }, "-008"},
+		{func(request *BaseTestRequest, stream *APIStream) (*any, *APIError) { return nil, nil }, ""},
+	}
+
+	for i, testCase := range testCases {
+		_, _, _, apiErr := validateAPIHandler("/test/api/stream/malformed", testCase.handler, true)
+
+		locator := ""
+		if apiErr != nil {
+			locator = apiErr.Locator
+		}
+
+		if testCase.locator != locator {
+			test.Errorf("Case %d: Unexpected locator. Expected: '%s', Actual: '%s'.", i, testCase.locator, locator)
+		}
+	}
+}
+
+func TestParseAPIStreamMissingResponse(test *testing.T) {
+	_, _, err := ParseAPIStream(`{"event": "foo"}` + "\n")
+	if err == nil {
+		test.Fatalf("Did not get an error on a stream without a response.")
+	}
+}
@@ -58,6 +58,31 @@ func SendTestAPIRequest(test *testing.T, endpoint string, fields map[string]any)
 // The base API path will be expanded to the full API path.
 // If an email is provided without an "@", we will suffix the email with the common test domain.
 func SendTestAPIRequestFull(test *testing.T, basePath string, fields map[string]any, paths []string, email string) *APIResponse {
+	responseText := sendTestAPIRequestText(test, basePath, fields, paths, email)
+
+	var response APIResponse
+	err := util.JSONFromString(responseText, &response)
+	if err != nil {
+		test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", responseText, err)
+	}
+
+	return &response
+}
+
+// Send a request to a stream endpoint (see MustNewAPIStreamRoute()).
+// Returns the events that were streamed and the final response.
+func SendTestAPIStreamRequestFull(test *testing.T, basePath string, fields map[string]any, paths []string, email string) ([]any, *APIResponse) {
+	responseText := sendTestAPIRequestText(test, basePath, fields, paths, email)
+
+	events, response, err := ParseAPIStream(responseText)
+	if err != nil {
+		test.Fatalf("Could not parse stream response '%s': '%v'.", responseText, err)
+	}
+
+	return events, response
+}
+
+func sendTestAPIRequestText(test *testing.T, basePath string, fields map[string]any, paths []string, email string) string {
 	url := serverURL + MakeFullAPIPath(basePath)
 
 	if !strings.Contains(email, "@") {
@@ -99,11 +124,5 @@ func SendTestAPIRequestFull(test *testing.T, basePath string, fields map[string]
 		test.Fatalf("API POST returned an error: '%v'.", err)
 	}
 
-	var response APIResponse
-	err = util.JSONFromString(responseText, &response)
-	if err != nil {
-		test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", responseText, err)
-	}
-
-	return &response
+	return responseText
 }
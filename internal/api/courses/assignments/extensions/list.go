@@ -0,0 +1,27 @@
+package extensions
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+}
+
+type ListResponse struct {
+	Extensions map[string]*model.Extension `json:"extensions"`
+}
+
+// List all the extensions for an assignment (keyed by user email, or 'team::<team id>' for team extensions).
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	extensions, err := db.GetExtensions(request.Assignment)
+	if err != nil {
+		return nil, core.NewInternalError("-638", &request.APIRequestCourseUserContext, "Failed to get extensions.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	return &ListResponse{extensions}, nil
+}
@@ -0,0 +1,51 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestList(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email   string
+		locator string
+	}{
+		{"course-admin", ""},
+		{"server-admin", ""},
+
+		{"course-grader", "-020"},
+		{"course-student", "-020"},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTestExtension("course-student@test.edulinq.org")
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/extensions/list`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		extension := responseContent.Extensions["course-student@test.edulinq.org"]
+		if (len(responseContent.Extensions) != 1) || (extension == nil) || (extension.DueDate == nil) || (*extension.DueDate != 1000) {
+			test.Errorf("Case %d: Unexpected response: '%s'.", i, util.MustToJSONIndent(responseContent))
+			continue
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Use the common main for all tests in this package.
+func TestMain(suite *testing.M) {
+	core.APITestingMain(suite, GetRoutes())
+}
+
+// Reset the database and give a user an extension on the test assignment.
+func resetWithTestExtension(email string) {
+	db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	dueDate := timestamp.FromMSecs(1000)
+
+	err := db.SaveExtension(assignment, &model.Extension{
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		User:         email,
+		DueDate:      &dueDate,
+		Author:       "course-admin@test.edulinq.org",
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,39 @@
+package extensions
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+)
+
+type RemoveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	TargetUser core.TargetCourseUser `json:"target-email"`
+}
+
+type RemoveResponse struct {
+	FoundUser      bool `json:"found-user"`
+	FoundExtension bool `json:"found-extension"`
+}
+
+// Remove a user's extension for an assignment.
+func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
+	response := RemoveResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	removed, err := db.RemoveExtension(request.Assignment, request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-637", &request.APIRequestCourseUserContext, "Failed to remove extension.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email)
+	}
+
+	response.FoundExtension = removed
+
+	return &response, nil
+}
@@ -0,0 +1,67 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRemove(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email    string
+		target   string
+		locator  string
+		expected RemoveResponse
+	}{
+		{"course-admin", "course-student@test.edulinq.org", "", RemoveResponse{true, true}},
+		{"course-admin", "course-other@test.edulinq.org", "", RemoveResponse{true, false}},
+		{"course-admin", "ZZZ@test.edulinq.org", "", RemoveResponse{false, false}},
+
+		{"course-grader", "course-student@test.edulinq.org", "-020", RemoveResponse{}},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTestExtension("course-student@test.edulinq.org")
+
+		fields := map[string]any{
+			"target-email": testCase.target,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/extensions/remove`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent RemoveResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expected != responseContent {
+			test.Errorf("Case %d: Unexpected response. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, responseContent)
+			continue
+		}
+
+		extension, err := db.GetExtension(db.MustGetTestAssignment(), testCase.target)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get extension: '%v'.", i, err)
+			continue
+		}
+
+		if extension != nil {
+			test.Errorf("Case %d: Extension was not removed.", i)
+			continue
+		}
+	}
+}
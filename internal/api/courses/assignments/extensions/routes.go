@@ -0,0 +1,23 @@
+package extensions
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/extensions/team"
+)
+
+var baseRoutes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/extensions/list`, HandleList),
+	core.MustNewAPIRoute(`courses/assignments/extensions/remove`, HandleRemove),
+	core.MustNewAPIRoute(`courses/assignments/extensions/set`, HandleSet),
+}
+
+func GetRoutes() *[]core.Route {
+	routes := make([]core.Route, 0)
+
+	routes = append(routes, baseRoutes...)
+	routes = append(routes, *(team.GetRoutes())...)
+
+	return &routes
+}
@@ -0,0 +1,65 @@
+package extensions
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type SetRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	TargetUser core.TargetCourseUser `json:"target-email"`
+
+	DueDate         *timestamp.Timestamp       `json:"due-date"`
+	SubmissionLimit *model.SubmissionLimitInfo `json:"submission-limit"`
+	Reason          string                     `json:"reason"`
+}
+
+type SetResponse struct {
+	FoundUser bool             `json:"found-user"`
+	Extension *model.Extension `json:"extension"`
+}
+
+// Give a user their own due date and/or submission limit for an assignment. Replaces any existing extension for the user.
+func HandleSet(request *SetRequest) (*SetResponse, *core.APIError) {
+	response := SetResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	extension := &model.Extension{
+		CourseID:        request.Course.GetID(),
+		AssignmentID:    request.Assignment.GetID(),
+		User:            request.TargetUser.Email,
+		DueDate:         request.DueDate,
+		SubmissionLimit: request.SubmissionLimit,
+		Author:          request.User.Email,
+		Reason:          request.Reason,
+		Time:            timestamp.Now(),
+	}
+
+	err := extension.Validate()
+	if err != nil {
+		return nil, core.NewBadRequestError("-635", &request.APIRequest, fmt.Sprintf("Invalid extension: '%v'.", err)).
+			Err(err).Course(request.Course.GetID()).Assignment(request.Assignment.GetID()).
+			Add("target-user", request.TargetUser.Email)
+	}
+
+	err = db.SaveExtension(request.Assignment, extension)
+	if err != nil {
+		return nil, core.NewInternalError("-636", &request.APIRequestCourseUserContext, "Failed to save extension.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email)
+	}
+
+	response.Extension = extension
+
+	return &response, nil
+}
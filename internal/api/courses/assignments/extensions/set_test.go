@@ -0,0 +1,91 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSet(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email       string
+		targetEmail string
+		fields      map[string]any
+		locator     string
+		foundUser   bool
+	}{
+		// Valid extensions.
+		{"course-admin", "course-student@test.edulinq.org", map[string]any{"due-date": 1000, "reason": "Illness."}, "", true},
+		{"course-admin", "course-student@test.edulinq.org", map[string]any{"submission-limit": map[string]any{"max": 10}}, "", true},
+		{"server-admin", "course-other@test.edulinq.org", map[string]any{"due-date": 1000, "submission-limit": map[string]any{}}, "", true},
+
+		// Missing user.
+		{"course-admin", "ZZZ@test.edulinq.org", map[string]any{"due-date": 1000}, "", false},
+
+		// Empty extension.
+		{"course-admin", "course-student@test.edulinq.org", map[string]any{"reason": "Nothing."}, "-635", false},
+
+		// Invalid permissions.
+		{"course-grader", "course-student@test.edulinq.org", map[string]any{"due-date": 1000}, "-020", false},
+		{"course-student", "course-student@test.edulinq.org", map[string]any{"due-date": 1000}, "-020", false},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+		}
+
+		for key, value := range testCase.fields {
+			fields[key] = value
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/extensions/set`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent SetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: %v, Actual: %v.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if !testCase.foundUser {
+			continue
+		}
+
+		extension, err := db.GetExtension(db.MustGetTestAssignment(), testCase.targetEmail)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get saved extension: '%v'.", i, err)
+			continue
+		}
+
+		if (extension == nil) || (extension.Author != (testCase.email + "@test.edulinq.org")) {
+			test.Errorf("Case %d: Unexpected saved extension: '%s'.", i, util.MustToJSONIndent(extension))
+			continue
+		}
+
+		if util.MustToJSON(extension) != util.MustToJSON(responseContent.Extension) {
+			test.Errorf("Case %d: Saved extension does not match response. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(responseContent.Extension), util.MustToJSONIndent(extension))
+			continue
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package team
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Use the common main for all tests in this package.
+func TestMain(suite *testing.M) {
+	core.APITestingMain(suite, GetRoutes())
+}
+
+// Reset the database and put some users on a team for the test assignment.
+// The members are made students in the test course.
+func resetWithTestTeam(teamID string, members ...string) {
+	db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	db.MustSetTestStudents(assignment.GetCourse(), members...)
+
+	assignment.Teams = &model.TeamInfo{MaxSize: 2, Teams: []*model.Team{&model.Team{ID: teamID, Members: members}}}
+
+	err := assignment.Teams.Validate()
+	if err != nil {
+		panic(err)
+	}
+
+	db.MustSaveAssignment(assignment)
+}
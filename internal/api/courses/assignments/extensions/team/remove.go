@@ -0,0 +1,28 @@
+package team
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+)
+
+type RemoveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	TeamID string `json:"team-id"`
+}
+
+type RemoveResponse struct {
+	FoundExtension bool `json:"found-extension"`
+}
+
+// Remove a team's extension for an assignment. Extensions for individual team members are not affected.
+func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
+	removed, err := db.RemoveTeamExtension(request.Assignment, request.TeamID)
+	if err != nil {
+		return nil, core.NewInternalError("-662", &request.APIRequestCourseUserContext, "Failed to remove extension.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("team-id", request.TeamID)
+	}
+
+	return &RemoveResponse{removed}, nil
+}
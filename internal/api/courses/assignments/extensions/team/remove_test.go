@@ -0,0 +1,82 @@
+package team
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRemove(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email    string
+		teamID   string
+		locator  string
+		expected RemoveResponse
+	}{
+		{"course-admin", "team-a", "", RemoveResponse{true}},
+		{"course-admin", "ZZZ", "", RemoveResponse{false}},
+
+		{"course-grader", "team-a", "-020", RemoveResponse{}},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTestTeam("team-a", "course-student@test.edulinq.org")
+
+		assignment := db.MustGetTestAssignment()
+		dueDate := timestamp.FromMSecs(1000)
+
+		err := db.SaveExtension(assignment, &model.Extension{
+			CourseID:     assignment.GetCourse().GetID(),
+			AssignmentID: assignment.GetID(),
+			Team:         "team-a",
+			DueDate:      &dueDate,
+			Author:       "course-admin@test.edulinq.org",
+		})
+		if err != nil {
+			test.Fatalf("Case %d: Failed to save team extension: '%v'.", i, err)
+		}
+
+		fields := map[string]any{
+			"team-id": testCase.teamID,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/extensions/team/remove`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent RemoveResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expected != responseContent {
+			test.Errorf("Case %d: Unexpected response. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, responseContent)
+			continue
+		}
+
+		extension, err := db.GetExtension(db.MustGetTestAssignment(), "course-student@test.edulinq.org")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get extension: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expected.FoundExtension != (extension == nil) {
+			test.Errorf("Case %d: Unexpected extension state: '%s'.", i, util.MustToJSONIndent(extension))
+			continue
+		}
+	}
+}
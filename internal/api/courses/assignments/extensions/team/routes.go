@@ -0,0 +1,16 @@
+package team
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/extensions/team/remove`, HandleRemove),
+	core.MustNewAPIRoute(`courses/assignments/extensions/team/set`, HandleSet),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
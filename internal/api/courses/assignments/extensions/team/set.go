@@ -0,0 +1,71 @@
+package team
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type SetRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	TeamID string `json:"team-id"`
+
+	DueDate         *timestamp.Timestamp       `json:"due-date"`
+	SubmissionLimit *model.SubmissionLimitInfo `json:"submission-limit"`
+	Reason          string                     `json:"reason"`
+}
+
+type SetResponse struct {
+	FoundTeam bool             `json:"found-team"`
+	Extension *model.Extension `json:"extension"`
+}
+
+// Give every member of a team their own due date and/or submission limit for an assignment. Replaces any existing extension for the team. A member's own extension takes precedence over their team's.
+func HandleSet(request *SetRequest) (*SetResponse, *core.APIError) {
+	response := SetResponse{}
+
+	teams, err := db.GetTeams(request.Assignment)
+	if err != nil {
+		return nil, core.NewInternalError("-659", &request.APIRequestCourseUserContext, "Failed to get teams.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("team-id", request.TeamID)
+	}
+
+	if teams[request.TeamID] == nil {
+		return &response, nil
+	}
+
+	response.FoundTeam = true
+
+	extension := &model.Extension{
+		CourseID:        request.Course.GetID(),
+		AssignmentID:    request.Assignment.GetID(),
+		Team:            request.TeamID,
+		DueDate:         request.DueDate,
+		SubmissionLimit: request.SubmissionLimit,
+		Author:          request.User.Email,
+		Reason:          request.Reason,
+		Time:            timestamp.Now(),
+	}
+
+	err = extension.Validate()
+	if err != nil {
+		return nil, core.NewBadRequestError("-660", &request.APIRequest, fmt.Sprintf("Invalid extension: '%v'.", err)).
+			Err(err).Course(request.Course.GetID()).Assignment(request.Assignment.GetID()).
+			Add("team-id", request.TeamID)
+	}
+
+	err = db.SaveExtension(request.Assignment, extension)
+	if err != nil {
+		return nil, core.NewInternalError("-661", &request.APIRequestCourseUserContext, "Failed to save extension.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("team-id", request.TeamID)
+	}
+
+	response.Extension = extension
+
+	return &response, nil
+}
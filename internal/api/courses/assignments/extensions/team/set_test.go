@@ -0,0 +1,90 @@
+package team
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSet(test *testing.T) {
+	defer db.ResetForTesting()
+
+	members := []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"}
+
+	testCases := []struct {
+		email     string
+		teamID    string
+		fields    map[string]any
+		locator   string
+		foundTeam bool
+	}{
+		// Valid extensions.
+		{"course-admin", "team-a", map[string]any{"due-date": 1000, "reason": "Illness."}, "", true},
+		{"server-admin", "team-a", map[string]any{"submission-limit": map[string]any{"max": 10}}, "", true},
+
+		// Missing team.
+		{"course-admin", "ZZZ", map[string]any{"due-date": 1000}, "", false},
+
+		// Empty extension.
+		{"course-admin", "team-a", map[string]any{"reason": "Nothing."}, "-660", false},
+
+		// Invalid permissions.
+		{"course-grader", "team-a", map[string]any{"due-date": 1000}, "-020", false},
+		{"course-student", "team-a", map[string]any{"due-date": 1000}, "-020", false},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTestTeam("team-a", members...)
+
+		fields := map[string]any{
+			"team-id": testCase.teamID,
+		}
+
+		for key, value := range testCase.fields {
+			fields[key] = value
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/extensions/team/set`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent SetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundTeam != responseContent.FoundTeam {
+			test.Errorf("Case %d: Unexpected found team. Expected: %v, Actual: %v.", i, testCase.foundTeam, responseContent.FoundTeam)
+			continue
+		}
+
+		if !testCase.foundTeam {
+			continue
+		}
+
+		// Every member of the team gets the extension.
+		for _, member := range members {
+			extension, err := db.GetExtension(db.MustGetTestAssignment(), member)
+			if err != nil {
+				test.Errorf("Case %d: Failed to get saved extension for '%s': '%v'.", i, member, err)
+				continue
+			}
+
+			if util.MustToJSON(extension) != util.MustToJSON(responseContent.Extension) {
+				test.Errorf("Case %d: Saved extension for '%s' does not match response. Expected: '%s', Actual: '%s'.",
+					i, member, util.MustToJSONIndent(responseContent.Extension), util.MustToJSONIndent(extension))
+				continue
+			}
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package assignments
 
 import (
+	"fmt"
+
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 type GetRequest struct {
@@ -13,8 +17,13 @@ type GetResponse struct {
 	Assignment *core.AssignmentInfo `json:"assignment"`
 }
 
-// Get the information for a course assignment.
+// Get the information for a course assignment. Assignments that are not visible look missing to students.
 func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	if (request.User.Role < model.CourseRoleGrader) && !request.Assignment.IsVisible(timestamp.Now()) {
+		return nil, core.NewBadRequestError("-639", &request.APIRequest, fmt.Sprintf("Could not find assignment: '%s'.", request.AssignmentID)).
+			Course(request.CourseID).Assignment(request.AssignmentID)
+	}
+
 	response := GetResponse{
 		Assignment: core.NewAssignmentInfo(request.Assignment),
 	}
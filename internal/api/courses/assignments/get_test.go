@@ -66,3 +66,39 @@ func TestGet(test *testing.T) {
 		}
 	}
 }
+
+// Hidden assignments look missing to students.
+func TestGetHidden(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	assignment.Hidden = true
+	db.MustSaveAssignment(assignment)
+
+	testCases := []struct {
+		email   string
+		locator string
+	}{
+		{"course-other", "-639"},
+		{"course-student", "-639"},
+		{"course-grader", ""},
+		{"course-admin", ""},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/get`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package assignments
 
 import (
+	"slices"
+
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 type ListRequest struct {
@@ -13,10 +17,19 @@ type ListResponse struct {
 	Assignments []*core.AssignmentInfo `json:"assignments"`
 }
 
-// List the assignments in the course.
+// List the assignments in the course. Students only see assignments that are visible (not hidden and past their open date).
 func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	assignments := request.Course.GetSortedAssignments()
+
+	if request.User.Role < model.CourseRoleGrader {
+		now := timestamp.Now()
+		assignments = slices.DeleteFunc(assignments, func(assignment *model.Assignment) bool {
+			return !assignment.IsVisible(now)
+		})
+	}
+
 	response := ListResponse{
-		Assignments: core.NewAssignmentInfos(request.Course.GetSortedAssignments()),
+		Assignments: core.NewAssignmentInfos(assignments),
 	}
 
 	return &response, nil
@@ -7,6 +7,7 @@ import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -87,3 +88,55 @@ func TestList(test *testing.T) {
 		}
 	}
 }
+
+// Students do not see hidden or unopened assignments.
+func TestListReleaseWindow(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	future := timestamp.Now() + timestamp.FromMSecs(1000*60*60)
+
+	course := db.MustGetCourse("course-languages")
+	assignments := course.GetSortedAssignments()
+	if len(assignments) < 3 {
+		test.Fatalf("Test course has too few assignments: %d.", len(assignments))
+	}
+
+	assignments[0].Hidden = true
+	db.MustSaveAssignment(assignments[0])
+
+	assignments[1].OpenDate = &future
+	db.MustSaveAssignment(assignments[1])
+
+	testCases := []struct {
+		email    string
+		expected int
+	}{
+		{"course-other", len(assignments) - 2},
+		{"course-student", len(assignments) - 2},
+		{"course-grader", len(assignments)},
+		{"course-admin", len(assignments)},
+		{"server-admin", len(assignments)},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"course-id": "course-languages",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/list`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expected != len(responseContent.Assignments) {
+			test.Errorf("Case %d: Unexpected number of assignments. Expected: %d, Actual: %d.",
+				i, testCase.expected, len(responseContent.Assignments))
+			continue
+		}
+	}
+}
@@ -4,15 +4,22 @@ package assignments
 
 import (
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/extensions"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/rubric"
 	"github.com/edulinq/autograder/internal/api/courses/assignments/submissions"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/teams"
 )
 
 var routes []core.Route = []core.Route{
 	core.MustNewAPIRoute(`courses/assignments/get`, HandleGet),
 	core.MustNewAPIRoute(`courses/assignments/list`, HandleList),
+	core.MustNewAPIRoute(`courses/assignments/similarity`, HandleSimilarity),
 }
 
 func GetRoutes() *[]core.Route {
-	fullRoutes := append(routes, *(submissions.GetRoutes())...)
+	fullRoutes := append(routes, *(extensions.GetRoutes())...)
+	fullRoutes = append(fullRoutes, *(rubric.GetRoutes())...)
+	fullRoutes = append(fullRoutes, *(submissions.GetRoutes())...)
+	fullRoutes = append(fullRoutes, *(teams.GetRoutes())...)
 	return &fullRoutes
 }
@@ -0,0 +1,55 @@
+package rubric
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type GetRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TargetUser core.TargetCourseUserSelfOrGrader `json:"target-email"`
+}
+
+type GetResponse struct {
+	FoundUser       bool                `json:"found-user"`
+	Rubric          []*model.RubricItem `json:"rubric"`
+	ManualMaxPoints float64             `json:"manual-max-points"`
+	ManualScore     float64             `json:"manual-score"`
+	Grade           *model.ManualGrade  `json:"grade"`
+}
+
+// Get an assignment's manual rubric and a user's manual grade for it.
+func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	response := GetResponse{
+		Rubric:          getRubric(request.Assignment),
+		ManualMaxPoints: request.Assignment.GetManualMaxPoints(),
+	}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	grade, err := db.GetManualGrade(request.Assignment, request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-629", &request.APIRequestCourseUserContext, "Failed to get manual grade.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email)
+	}
+
+	response.Grade = grade
+	response.ManualScore = request.Assignment.ComputeManualScore(grade)
+
+	return &response, nil
+}
+
+func getRubric(assignment *model.Assignment) []*model.RubricItem {
+	if assignment.ManualRubric == nil {
+		return make([]*model.RubricItem, 0)
+	}
+
+	return assignment.ManualRubric
+}
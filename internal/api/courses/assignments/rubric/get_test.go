@@ -0,0 +1,88 @@
+package rubric
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGet(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email         string
+		targetEmail   string
+		locator       string
+		foundUser     bool
+		hasGrade      bool
+		expectedScore float64
+	}{
+		// Self.
+		{"course-student", "", "", true, true, 1.5},
+		{"course-student", "course-student@test.edulinq.org", "", true, true, 1.5},
+
+		// Staff.
+		{"course-grader", "course-student@test.edulinq.org", "", true, true, 1.5},
+		{"course-admin", "course-other@test.edulinq.org", "", true, false, 0.0},
+		{"course-grader", "ZZZ@test.edulinq.org", "", false, false, 0.0},
+
+		// Invalid permissions.
+		{"course-student", "course-grader@test.edulinq.org", "-033", false, false, 0.0},
+		{"course-other", "", "-020", false, false, 0.0},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTestRubric()
+
+		_, err := db.UpdateManualGrade(db.MustGetTestAssignment(), "course-student@test.edulinq.org", map[string]*model.ManualGradeItem{
+			"style": &model.ManualGradeItem{Score: 1.5, Grader: "course-grader@test.edulinq.org"},
+		})
+		if err != nil {
+			test.Fatalf("Case %d: Failed to set manual grade: '%v'.", i, err)
+		}
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/rubric/get`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent GetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (len(responseContent.Rubric) != 2) || (responseContent.ManualMaxPoints != 5.0) {
+			test.Errorf("Case %d: Unexpected rubric: '%s'.", i, util.MustToJSONIndent(responseContent))
+			continue
+		}
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: %v, Actual: %v.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.hasGrade != (responseContent.Grade != nil) {
+			test.Errorf("Case %d: Unexpected grade presence. Expected: %v, Actual: '%s'.", i, testCase.hasGrade, util.MustToJSONIndent(responseContent.Grade))
+			continue
+		}
+
+		if responseContent.ManualScore != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected manual score. Expected: %f, Actual: %f.", i, testCase.expectedScore, responseContent.ManualScore)
+			continue
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package rubric
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type GradeRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+
+	TargetUser core.TargetCourseUser `json:"target-email"`
+
+	// Keyed by rubric item ID.
+	Items map[string]*GradeItem `json:"items"`
+}
+
+type GradeItem struct {
+	Score   float64 `json:"score"`
+	Comment string  `json:"comment"`
+}
+
+type GradeResponse struct {
+	FoundUser   bool               `json:"found-user"`
+	ManualScore float64            `json:"manual-score"`
+	Grade       *model.ManualGrade `json:"grade"`
+}
+
+// Set scores (and comments) for manual rubric items for a user. Items that are not included are left unchanged.
+func HandleGrade(request *GradeRequest) (*GradeResponse, *core.APIError) {
+	if !request.Assignment.HasManualRubric() {
+		return nil, core.NewBadRequestError("-630", &request.APIRequest, "Assignment does not have a manual rubric.").
+			Course(request.Course.GetID()).Assignment(request.Assignment.GetID())
+	}
+
+	if len(request.Items) == 0 {
+		return nil, core.NewBadRequestError("-631", &request.APIRequest, "No rubric items were graded.").
+			Course(request.Course.GetID()).Assignment(request.Assignment.GetID())
+	}
+
+	response := GradeResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	now := timestamp.Now()
+	items := make(map[string]*model.ManualGradeItem, len(request.Items))
+
+	for id, item := range request.Items {
+		if item == nil {
+			item = &GradeItem{}
+		}
+
+		items[id] = &model.ManualGradeItem{
+			Score:   item.Score,
+			Comment: item.Comment,
+			Grader:  request.User.Email,
+			Time:    now,
+		}
+
+		err := request.Assignment.CheckManualGradeItem(id, items[id])
+		if err != nil {
+			return nil, core.NewBadRequestError("-632", &request.APIRequest, fmt.Sprintf("Invalid manual grade: '%v'.", err)).
+				Err(err).Course(request.Course.GetID()).Assignment(request.Assignment.GetID()).
+				Add("target-user", request.TargetUser.Email)
+		}
+	}
+
+	grade, err := db.UpdateManualGrade(request.Assignment, request.TargetUser.Email, items)
+	if err != nil {
+		return nil, core.NewInternalError("-633", &request.APIRequestCourseUserContext, "Failed to save manual grade.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email)
+	}
+
+	response.Grade = grade
+	response.ManualScore = request.Assignment.ComputeManualScore(grade)
+
+	return &response, nil
+}
@@ -0,0 +1,107 @@
+package rubric
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGrade(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email         string
+		targetEmail   string
+		items         map[string]any
+		noRubric      bool
+		locator       string
+		foundUser     bool
+		expectedScore float64
+	}{
+		// Valid grades.
+		{"course-grader", "course-student@test.edulinq.org", map[string]any{"style": map[string]any{"score": 1.5, "comment": "Long lines."}}, false, "", true, 1.5},
+		{"course-admin", "course-student@test.edulinq.org", map[string]any{"style": map[string]any{"score": 2}, "report": map[string]any{"score": 3}}, false, "", true, 5.0},
+		{"course-grader", "course-other@test.edulinq.org", map[string]any{"report": map[string]any{"score": 0}}, false, "", true, 0.0},
+
+		// Missing user.
+		{"course-grader", "ZZZ@test.edulinq.org", map[string]any{"style": map[string]any{"score": 1}}, false, "", false, 0.0},
+
+		// No rubric.
+		{"course-grader", "course-student@test.edulinq.org", map[string]any{"style": map[string]any{"score": 1}}, true, "-630", false, 0.0},
+
+		// No items.
+		{"course-grader", "course-student@test.edulinq.org", map[string]any{}, false, "-631", false, 0.0},
+
+		// Bad items.
+		{"course-grader", "course-student@test.edulinq.org", map[string]any{"ZZZ": map[string]any{"score": 1}}, false, "-632", false, 0.0},
+		{"course-grader", "course-student@test.edulinq.org", map[string]any{"style": map[string]any{"score": 3}}, false, "-632", false, 0.0},
+		{"course-grader", "course-student@test.edulinq.org", map[string]any{"style": map[string]any{"score": -1}}, false, "-632", false, 0.0},
+
+		// Invalid permissions.
+		{"course-student", "course-student@test.edulinq.org", map[string]any{"style": map[string]any{"score": 1}}, false, "-020", false, 0.0},
+		{"course-other", "course-student@test.edulinq.org", map[string]any{"style": map[string]any{"score": 1}}, false, "-020", false, 0.0},
+	}
+
+	for i, testCase := range testCases {
+		if testCase.noRubric {
+			db.ResetForTesting()
+		} else {
+			resetWithTestRubric()
+		}
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+			"items":        testCase.items,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/rubric/grade`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent GradeResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: %v, Actual: %v.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if !testCase.foundUser {
+			continue
+		}
+
+		if responseContent.ManualScore != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected manual score. Expected: %f, Actual: %f.", i, testCase.expectedScore, responseContent.ManualScore)
+			continue
+		}
+
+		grade, err := db.GetManualGrade(db.MustGetTestAssignment(), testCase.targetEmail)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get saved grade: '%v'.", i, err)
+			continue
+		}
+
+		if (grade == nil) || (len(grade.Items) != len(testCase.items)) {
+			test.Errorf("Case %d: Unexpected saved grade: '%s'.", i, util.MustToJSONIndent(grade))
+			continue
+		}
+
+		for _, item := range grade.Items {
+			if item.Grader != (testCase.email + "@test.edulinq.org") {
+				test.Errorf("Case %d: Unexpected grader. Expected: '%s', Actual: '%s'.", i, testCase.email, item.Grader)
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package rubric
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+}
+
+type ListResponse struct {
+	Rubric          []*model.RubricItem           `json:"rubric"`
+	ManualMaxPoints float64                       `json:"manual-max-points"`
+	Grades          map[string]*model.ManualGrade `json:"grades"`
+}
+
+// Get an assignment's manual rubric and all of its manual grades (keyed by user email).
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	grades, err := db.GetManualGrades(request.Assignment)
+	if err != nil {
+		return nil, core.NewInternalError("-634", &request.APIRequestCourseUserContext, "Failed to get manual grades.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	response := ListResponse{
+		Rubric:          getRubric(request.Assignment),
+		ManualMaxPoints: request.Assignment.GetManualMaxPoints(),
+		Grades:          grades,
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,66 @@
+package rubric
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestList(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email   string
+		locator string
+	}{
+		{"course-grader", ""},
+		{"course-admin", ""},
+		{"server-admin", ""},
+
+		{"course-student", "-020"},
+		{"course-other", "-020"},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTestRubric()
+
+		for _, email := range []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"} {
+			_, err := db.UpdateManualGrade(db.MustGetTestAssignment(), email, map[string]*model.ManualGradeItem{
+				"report": &model.ManualGradeItem{Score: 2.0, Grader: "course-grader@test.edulinq.org"},
+			})
+			if err != nil {
+				test.Fatalf("Case %d: Failed to set manual grade: '%v'.", i, err)
+			}
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/rubric/list`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (len(responseContent.Rubric) != 2) || (len(responseContent.Grades) != 2) {
+			test.Errorf("Case %d: Unexpected response: '%s'.", i, util.MustToJSONIndent(responseContent))
+			continue
+		}
+
+		if responseContent.Grades["course-other@test.edulinq.org"].Items["report"].Score != 2.0 {
+			test.Errorf("Case %d: Unexpected grade: '%s'.", i, util.MustToJSONIndent(responseContent.Grades))
+			continue
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package rubric
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Use the common main for all tests in this package.
+func TestMain(suite *testing.M) {
+	core.APITestingMain(suite, GetRoutes())
+}
+
+// Reset the database and give the test assignment a manual rubric.
+func resetWithTestRubric() {
+	db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	assignment.ManualRubric = []*model.RubricItem{
+		&model.RubricItem{ID: "style", Description: "Code style.", MaxPoints: 2.0},
+		&model.RubricItem{ID: "report", Description: "Written report.", MaxPoints: 3.0},
+	}
+
+	db.MustSaveAssignment(assignment)
+}
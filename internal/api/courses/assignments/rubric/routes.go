@@ -0,0 +1,17 @@
+package rubric
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/rubric/get`, HandleGet),
+	core.MustNewAPIRoute(`courses/assignments/rubric/grade`, HandleGrade),
+	core.MustNewAPIRoute(`courses/assignments/rubric/list`, HandleList),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -0,0 +1,44 @@
+package assignments
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/similarity"
+)
+
+type SimilarityRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+
+	similarity.SimilarityOptions
+
+	// Also include an HTML version of the report.
+	HTML bool `json:"html"`
+}
+
+type SimilarityResponse struct {
+	Report *similarity.SimilarityReport `json:"report"`
+	HTML   string                       `json:"html,omitempty"`
+}
+
+// Compare the most recent submissions of all users for an assignment and report pairs of similar submissions.
+func HandleSimilarity(request *SimilarityRequest) (*SimilarityResponse, *core.APIError) {
+	report, err := similarity.GetSimilarityReport(request.Assignment, request.SimilarityOptions)
+	if err != nil {
+		return nil, core.NewInternalError("-623", &request.APIRequestCourseUserContext, "Failed to get similarity report.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	response := SimilarityResponse{
+		Report: report,
+	}
+
+	if request.HTML {
+		response.HTML, err = report.ToHTML()
+		if err != nil {
+			return nil, core.NewInternalError("-624", &request.APIRequestCourseUserContext, "Failed to generate HTML similarity report.").
+				Err(err).Assignment(request.Assignment.GetID())
+		}
+	}
+
+	return &response, nil
+}
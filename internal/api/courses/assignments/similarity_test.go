@@ -0,0 +1,75 @@
+package assignments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSimilarity(test *testing.T) {
+	testCases := []struct {
+		email     string
+		html      bool
+		permError bool
+	}{
+		// Valid permissions.
+		{"course-grader", false, false},
+		{"course-admin", true, false},
+		{"server-admin", false, false},
+
+		// Invalid permissions.
+		{"course-student", false, true},
+		{"course-other", false, true},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"html": testCase.html,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/similarity`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.permError {
+				expectedLocator := "-020"
+				if response.Locator != expectedLocator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.",
+						i, expectedLocator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.permError {
+			test.Errorf("Case %d: Did not get an expected permissions error.", i)
+			continue
+		}
+
+		var responseContent SimilarityResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if responseContent.Report == nil {
+			test.Errorf("Case %d: Did not get a report.", i)
+			continue
+		}
+
+		if responseContent.Report.AssignmentID != "hw0" {
+			test.Errorf("Case %d: Unexpected assignment. Expected: 'hw0', Actual: '%s'.", i, responseContent.Report.AssignmentID)
+			continue
+		}
+
+		if responseContent.Report.NumberOfSubmissions == 0 {
+			test.Errorf("Case %d: No submissions were compared.", i)
+			continue
+		}
+
+		if testCase.html != strings.Contains(responseContent.HTML, "<html>") {
+			test.Errorf("Case %d: Unexpected HTML. Expected HTML: %v, Actual: '%s'.", i, testCase.html, responseContent.HTML)
+			continue
+		}
+	}
+}
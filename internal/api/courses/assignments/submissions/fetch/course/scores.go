@@ -18,9 +18,9 @@ type FetchCourseScoresResponse struct {
 	SubmissionInfos map[string]*model.SubmissionHistoryItem `json:"submission-infos"`
 }
 
-// Get a summary of the most recent scores for this assignment.
+// Get a summary of the scores that count for this assignment (see the assignment's submission selection policy).
 func HandleFetchCourseScores(request *FetchCourseScoresRequest) (*FetchCourseScoresResponse, *core.APIError) {
-	submissionInfos, err := db.GetRecentSubmissionSurvey(request.Assignment, request.FilterRole)
+	submissionInfos, err := db.GetSelectedSubmissionSurvey(request.Assignment, request.FilterRole)
 	if err != nil {
 		return nil, core.NewInternalError("-602", &request.APIRequestCourseUserContext, "Failed to get submission summaries.").
 			Err(err).Assignment(request.Assignment.GetID())
@@ -41,6 +41,16 @@ func HandleFetchUserAttempt(request *FetchUserAttemptRequest) (*FetchUserAttempt
 		return &response, nil
 	}
 
+	policy, err := request.GetFeedbackRestriction(request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-651", &request.APIRequestCourseUserContext, "Failed to get feedback restriction.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email)
+	}
+
+	if policy != nil {
+		gradingResult = policy.RestrictGradingResult(gradingResult)
+	}
+
 	response.FoundSubmission = true
 	response.GradingResult = gradingResult
 
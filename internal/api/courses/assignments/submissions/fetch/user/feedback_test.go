@@ -0,0 +1,160 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestFetchUserFeedbackPolicy(test *testing.T) {
+	defer db.ResetForTesting()
+
+	past := timestamp.Now() - timestamp.FromMSecs(1000*60*60)
+	future := timestamp.Now() + timestamp.FromMSecs(1000*60*60)
+
+	testCases := []struct {
+		email         string
+		policyType    model.FeedbackPolicyType
+		releaseDate   *timestamp.Timestamp
+		restricted    bool
+		score         float64
+		numQuestions  int
+		firstQuestion string
+	}{
+		// Full feedback.
+		{"course-student", model.FeedbackFull, &future, false, 2.0, 3, "Q1"},
+
+		// Restricted feedback.
+		{"course-student", model.FeedbackHideHiddenQuestions, &future, true, 1.0, 2, "Q2"},
+		{"course-student", model.FeedbackHideMessages, &future, true, 2.0, 3, "Q1"},
+		{"course-student", model.FeedbackTotalOnly, &future, true, 2.0, 0, ""},
+		{"course-student", model.FeedbackNone, &future, true, 0.0, 0, ""},
+
+		// Released feedback.
+		{"course-student", model.FeedbackNone, &past, false, 2.0, 3, "Q1"},
+
+		// Staff always see full feedback.
+		{"course-grader", model.FeedbackNone, &future, false, 2.0, 3, "Q1"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		assignment := db.MustGetTestAssignment()
+		assignment.FeedbackPolicy = &model.FeedbackPolicy{Type: testCase.policyType, ReleaseDate: testCase.releaseDate}
+		db.MustSaveAssignment(assignment)
+
+		gradingResult, err := db.GetSubmissionContents(assignment, "course-student@test.edulinq.org", "1697406272")
+		if err != nil {
+			test.Fatalf("Case %d: Failed to get submission: '%v'.", i, err)
+		}
+
+		// Mark the first question as hidden.
+		gradingResult.Info.Questions[0].Hidden = true
+
+		err = db.SaveSubmission(assignment, gradingResult)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to save submission: '%v'.", i, err)
+		}
+
+		fields := map[string]any{
+			"target-email":      "course-student@test.edulinq.org",
+			"target-submission": "1697406272",
+		}
+
+		// Peek.
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/user/peek`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Peek response is not a success: '%v'.", i, response)
+			continue
+		}
+
+		var peekContent FetchUserPeekResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &peekContent)
+
+		checkFeedback(test, i, "peek", peekContent.GradingInfo, testCase.restricted, testCase.score, testCase.numQuestions, testCase.firstQuestion)
+
+		if (testCase.policyType == model.FeedbackHideMessages) && testCase.restricted && (peekContent.GradingInfo.Questions[2].Message != "") {
+			test.Errorf("Case %d: Question message was not hidden.", i)
+		}
+
+		// Attempt.
+
+		response = core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/user/attempt`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Attempt response is not a success: '%v'.", i, response)
+			continue
+		}
+
+		var attemptContent FetchUserAttemptResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &attemptContent)
+
+		checkFeedback(test, i, "attempt", attemptContent.GradingResult.Info, testCase.restricted, testCase.score, testCase.numQuestions, testCase.firstQuestion)
+
+		hasOutput := (attemptContent.GradingResult.Stdout != "") || (len(attemptContent.GradingResult.OutputFilesGZip) > 0)
+		if testCase.restricted == hasOutput {
+			test.Errorf("Case %d: Unexpected grader output. Restricted: %v, Has Output: %v.", i, testCase.restricted, hasOutput)
+		}
+
+		// History.
+
+		response = core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/user/history`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: History response is not a success: '%v'.", i, response)
+			continue
+		}
+
+		var historyContent FetchUserHistoryResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &historyContent)
+
+		var item *model.SubmissionHistoryItem
+		for _, historyItem := range historyContent.History {
+			if historyItem.ShortID == "1697406272" {
+				item = historyItem
+			}
+		}
+
+		if item == nil {
+			test.Errorf("Case %d: Could not find submission in history.", i)
+			continue
+		}
+
+		if (item.FeedbackRestricted != testCase.restricted) || !util.IsClose(item.Score, testCase.score) {
+			test.Errorf("Case %d: Unexpected history item: '%s'.", i, util.MustToJSONIndent(item))
+			continue
+		}
+	}
+}
+
+func checkFeedback(test *testing.T, i int, endpoint string, info *model.GradingInfo,
+	restricted bool, score float64, numQuestions int, firstQuestion string) {
+	if info == nil {
+		test.Errorf("Case %d (%s): Missing grading info.", i, endpoint)
+		return
+	}
+
+	if info.FeedbackRestricted != restricted {
+		test.Errorf("Case %d (%s): Unexpected restriction. Expected: %v, Actual: %v.", i, endpoint, restricted, info.FeedbackRestricted)
+		return
+	}
+
+	if !util.IsClose(info.Score, score) {
+		test.Errorf("Case %d (%s): Unexpected score. Expected: %f, Actual: %f.", i, endpoint, score, info.Score)
+		return
+	}
+
+	if len(info.Questions) != numQuestions {
+		test.Errorf("Case %d (%s): Unexpected number of questions. Expected: %d, Actual: %d.", i, endpoint, numQuestions, len(info.Questions))
+		return
+	}
+
+	if (numQuestions > 0) && (info.Questions[0].Name != firstQuestion) {
+		test.Errorf("Case %d (%s): Unexpected first question. Expected: '%s', Actual: '%s'.", i, endpoint, firstQuestion, info.Questions[0].Name)
+		return
+	}
+}
@@ -38,6 +38,31 @@ func HandleFetchUserHistory(request *FetchUserHistoryRequest) (*FetchUserHistory
 			Add("target-user", request.TargetUser.Email)
 	}
 
+	policy, err := request.GetFeedbackRestriction(request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-649", &request.APIRequestCourseUserContext, "Failed to get feedback restriction.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email)
+	}
+
+	if policy != nil {
+		// History items do not have enough information to restrict (e.g., hidden questions),
+		// so restrict the full result and rebuild the item.
+		for i, item := range history {
+			gradingInfo, err := db.GetSubmissionResult(request.Assignment, request.TargetUser.Email, item.ShortID)
+			if err != nil {
+				return nil, core.NewInternalError("-650", &request.APIRequestCourseUserContext, "Failed to get submission result.").
+					Err(err).Assignment(request.Assignment.GetID()).
+					Add("target-user", request.TargetUser.Email).Add("submission", item.ShortID)
+			}
+
+			if gradingInfo == nil {
+				continue
+			}
+
+			history[i] = policy.RestrictGradingInfo(gradingInfo).ToHistoryItem()
+		}
+	}
+
 	response.History = history
 
 	return &response, nil
@@ -41,6 +41,16 @@ func HandleFetchUserPeek(request *FetchUserPeekRequest) (*FetchUserPeekResponse,
 		return &response, nil
 	}
 
+	policy, err := request.GetFeedbackRestriction(request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-648", &request.APIRequestCourseUserContext, "Failed to get feedback restriction.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email)
+	}
+
+	if policy != nil {
+		submissionResult = policy.RestrictGradingInfo(submissionResult)
+	}
+
 	response.FoundSubmission = true
 	response.GradingInfo = submissionResult
 
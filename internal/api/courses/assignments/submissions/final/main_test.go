@@ -0,0 +1,41 @@
+package final
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Use the common main for all tests in this package.
+func TestMain(suite *testing.M) {
+	core.APITestingMain(suite, GetRoutes())
+}
+
+// Reset the database, let students choose their final submission on the test assignment,
+// and set its due date (and the due date of an extension for course-student, if not nil).
+func resetWithDueDate(dueDate *timestamp.Timestamp, extensionDueDate *timestamp.Timestamp) {
+	db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	assignment.SubmissionSelection = model.SelectStudentFinal
+	assignment.DueDate = dueDate
+	db.MustSaveAssignment(assignment)
+
+	if extensionDueDate == nil {
+		return
+	}
+
+	err := db.SaveExtension(assignment, &model.Extension{
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		User:         "course-student@test.edulinq.org",
+		DueDate:      extensionDueDate,
+		Author:       "course-admin@test.edulinq.org",
+	})
+	if err != nil {
+		panic(err)
+	}
+}
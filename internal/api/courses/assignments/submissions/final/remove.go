@@ -0,0 +1,51 @@
+package final
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type RemoveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TargetUser core.TargetCourseUserSelfOrGrader `json:"target-email"`
+}
+
+type RemoveResponse struct {
+	FoundUser            bool `json:"found-user"`
+	FoundFinalSubmission bool `json:"found-final-submission"`
+}
+
+// Unmark a final submission for an assignment, so the most recent submission counts again.
+// Students cannot change their final submission after their due date (including any extension).
+func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
+	if request.Assignment.SubmissionSelection != model.SelectStudentFinal {
+		return nil, core.NewBadRequestError("-666", &request.APIRequest, "Assignment does not let students choose their final submission.").
+			Course(request.Course.GetID()).Assignment(request.Assignment.GetID())
+	}
+
+	response := RemoveResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	apiErr := checkDueDate(&request.APIRequestAssignmentContext, request.TargetUser.Email)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	removed, err := db.RemoveFinalSubmission(request.Assignment, request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-667", &request.APIRequestCourseUserContext, "Failed to remove final submission.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", request.TargetUser.Email)
+	}
+
+	response.FoundFinalSubmission = removed
+
+	return &response, nil
+}
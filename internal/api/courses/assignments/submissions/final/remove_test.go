@@ -0,0 +1,105 @@
+package final
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRemove(test *testing.T) {
+	defer db.ResetForTesting()
+
+	past := timestamp.Now() - timestamp.FromMSecs(60*60*1000)
+	future := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+
+	testCases := []struct {
+		email            string
+		targetEmail      string
+		dueDate          *timestamp.Timestamp
+		extensionDueDate *timestamp.Timestamp
+		locator          string
+		expected         RemoveResponse
+	}{
+		// Self.
+		{"course-student", "", nil, nil, "", RemoveResponse{true, true}},
+		{"course-student", "", &future, nil, "", RemoveResponse{true, true}},
+		{"course-student", "", &past, &future, "", RemoveResponse{true, true}},
+
+		// Other.
+		{"course-grader", "course-student@test.edulinq.org", &past, nil, "", RemoveResponse{true, true}},
+		{"course-grader", "course-other@test.edulinq.org", nil, nil, "", RemoveResponse{true, false}},
+		{"course-grader", "ZZZ@test.edulinq.org", nil, nil, "", RemoveResponse{false, false}},
+		{"course-student", "course-grader@test.edulinq.org", nil, nil, "-033", RemoveResponse{}},
+
+		// Past the due date.
+		{"course-student", "", &past, nil, "-665", RemoveResponse{}},
+		{"course-student", "", &future, &past, "-665", RemoveResponse{}},
+	}
+
+	for i, testCase := range testCases {
+		resetWithDueDate(testCase.dueDate, testCase.extensionDueDate)
+
+		err := db.SaveFinalSubmission(db.MustGetTestAssignment(), &model.FinalSubmission{
+			User:         "course-student@test.edulinq.org",
+			SubmissionID: "1697406265",
+			Time:         timestamp.Now(),
+		})
+		if err != nil {
+			test.Fatalf("Case %d: Failed to save final submission: '%v'.", i, err)
+		}
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/final/remove`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent RemoveResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expected != responseContent {
+			test.Errorf("Case %d: Unexpected response. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, responseContent)
+			continue
+		}
+
+		final, err := db.GetFinalSubmission(db.MustGetTestAssignment(), "course-student@test.edulinq.org")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get final submission: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expected.FoundFinalSubmission != (final == nil) {
+			test.Errorf("Case %d: Unexpected final submission: '%s'.", i, util.MustToJSONIndent(final))
+			continue
+		}
+	}
+}
+
+func TestRemoveWrongSelection(test *testing.T) {
+	defer db.ResetForTesting()
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/final/remove`, nil, nil, "course-student")
+	if response.Success {
+		test.Fatalf("Response is a success when it should not be: '%v'.", response)
+	}
+
+	if response.Locator != "-666" {
+		test.Fatalf("Incorrect error returned. Expected '-666', found '%s'.", response.Locator)
+	}
+}
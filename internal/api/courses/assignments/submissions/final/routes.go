@@ -0,0 +1,16 @@
+package final
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/submissions/final/remove`, HandleRemove),
+	core.MustNewAPIRoute(`courses/assignments/submissions/final/set`, HandleSet),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
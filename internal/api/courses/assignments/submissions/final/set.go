@@ -0,0 +1,95 @@
+package final
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type SetRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TargetUser       core.TargetCourseUserSelfOrGrader `json:"target-email"`
+	TargetSubmission string                            `json:"target-submission"`
+}
+
+type SetResponse struct {
+	FoundUser       bool                   `json:"found-user"`
+	FoundSubmission bool                   `json:"found-submission"`
+	FinalSubmission *model.FinalSubmission `json:"final-submission"`
+}
+
+// Mark a submission as the one that counts for an assignment. Defaults to the most recent submission.
+// Students cannot change their final submission after their due date (including any extension).
+func HandleSet(request *SetRequest) (*SetResponse, *core.APIError) {
+	if request.Assignment.SubmissionSelection != model.SelectStudentFinal {
+		return nil, core.NewBadRequestError("-652", &request.APIRequest, "Assignment does not let students choose their final submission.").
+			Course(request.Course.GetID()).Assignment(request.Assignment.GetID())
+	}
+
+	response := SetResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	apiErr := checkDueDate(&request.APIRequestAssignmentContext, request.TargetUser.Email)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	gradingInfo, err := db.GetSubmissionResult(request.Assignment, request.TargetUser.Email, request.TargetSubmission)
+	if err != nil {
+		return nil, core.NewInternalError("-653", &request.APIRequestCourseUserContext, "Failed to get submission result.").
+			Err(err).Assignment(request.Assignment.GetID()).
+			Add("target-user", request.TargetUser.Email).Add("submission", request.TargetSubmission)
+	}
+
+	if gradingInfo == nil {
+		return &response, nil
+	}
+
+	response.FoundSubmission = true
+
+	final := &model.FinalSubmission{
+		User:         request.TargetUser.Email,
+		SubmissionID: gradingInfo.ShortID,
+		Time:         timestamp.Now(),
+	}
+
+	err = db.SaveFinalSubmission(request.Assignment, final)
+	if err != nil {
+		return nil, core.NewInternalError("-654", &request.APIRequestCourseUserContext, "Failed to save final submission.").
+			Err(err).Assignment(request.Assignment.GetID()).
+			Add("target-user", request.TargetUser.Email).Add("submission", gradingInfo.ShortID)
+	}
+
+	response.FinalSubmission = final
+
+	return &response, nil
+}
+
+// Students (but not graders and above) can only change their final submission before their due date (including any extension).
+func checkDueDate(request *core.APIRequestAssignmentContext, email string) *core.APIError {
+	if request.User.Role >= model.CourseRoleGrader {
+		return nil
+	}
+
+	extension, err := db.GetExtension(request.Assignment, email)
+	if err != nil {
+		return core.NewInternalError("-664", &request.APIRequestCourseUserContext, "Failed to get extension.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("target-user", email)
+	}
+
+	dueDate := request.Assignment.GetEffectiveDueDate(extension)
+	if (dueDate == nil) || (timestamp.Now() <= *dueDate) {
+		return nil
+	}
+
+	return core.NewBadRequestError("-665", &request.APIRequest, "The due date has passed, your final submission can no longer be changed.").
+		Course(request.Course.GetID()).Assignment(request.Assignment.GetID()).Add("target-user", email)
+}
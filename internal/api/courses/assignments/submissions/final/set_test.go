@@ -0,0 +1,160 @@
+package final
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSet(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email            string
+		targetEmail      string
+		targetSubmission string
+		selection        model.SubmissionSelectionPolicy
+		foundUser        bool
+		foundSubmission  bool
+		expectedID       string
+		locator          string
+	}{
+		// Self.
+		{"course-student", "", "1697406265", model.SelectStudentFinal, true, true, "1697406265", ""},
+		{"course-student", "", "", model.SelectStudentFinal, true, true, "1697406272", ""},
+		{"course-student", "", "ZZZ", model.SelectStudentFinal, true, false, "", ""},
+
+		// Other.
+		{"course-grader", "course-student@test.edulinq.org", "1697406256", model.SelectStudentFinal, true, true, "1697406256", ""},
+		{"course-grader", "ZZZ@test.edulinq.org", "", model.SelectStudentFinal, false, false, "", ""},
+		{"course-student", "course-grader@test.edulinq.org", "", model.SelectStudentFinal, false, false, "", "-033"},
+
+		// Wrong selection policy.
+		{"course-student", "", "1697406265", model.SelectLatest, false, false, "", "-652"},
+		{"course-student", "", "1697406265", model.SelectHighestScore, false, false, "", "-652"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		assignment := db.MustGetTestAssignment()
+		assignment.SubmissionSelection = testCase.selection
+		db.MustSaveAssignment(assignment)
+
+		fields := map[string]any{
+			"target-email":      testCase.targetEmail,
+			"target-submission": testCase.targetSubmission,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/final/set`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent SetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (testCase.foundUser != responseContent.FoundUser) || (testCase.foundSubmission != responseContent.FoundSubmission) {
+			test.Errorf("Case %d: Unexpected response: '%s'.", i, util.MustToJSONIndent(responseContent))
+			continue
+		}
+
+		if !testCase.foundSubmission {
+			continue
+		}
+
+		final, err := db.GetFinalSubmission(db.MustGetTestAssignment(), "course-student@test.edulinq.org")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get final submission: '%v'.", i, err)
+			continue
+		}
+
+		if (final == nil) || (final.SubmissionID != testCase.expectedID) {
+			test.Errorf("Case %d: Unexpected final submission. Expected: '%s', Actual: '%s'.", i, testCase.expectedID, util.MustToJSONIndent(final))
+			continue
+		}
+
+		gradingInfos, err := db.GetSelectedSubmissions(db.MustGetTestAssignment(), model.CourseRoleStudent)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get selected submissions: '%v'.", i, err)
+			continue
+		}
+
+		if gradingInfos["course-student@test.edulinq.org"].ShortID != testCase.expectedID {
+			test.Errorf("Case %d: Final submission was not selected.", i)
+			continue
+		}
+	}
+}
+
+func TestSetDueDate(test *testing.T) {
+	defer db.ResetForTesting()
+
+	past := timestamp.Now() - timestamp.FromMSecs(60*60*1000)
+	future := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+
+	testCases := []struct {
+		email            string
+		dueDate          *timestamp.Timestamp
+		extensionDueDate *timestamp.Timestamp
+		locator          string
+	}{
+		{"course-student", nil, nil, ""},
+		{"course-student", &future, nil, ""},
+		{"course-student", &past, nil, "-665"},
+
+		// Extensions move the student's due date.
+		{"course-student", &past, &future, ""},
+		{"course-student", &future, &past, "-665"},
+
+		// Graders are not limited by the due date.
+		{"course-grader", &past, nil, ""},
+	}
+
+	for i, testCase := range testCases {
+		resetWithDueDate(testCase.dueDate, testCase.extensionDueDate)
+
+		fields := map[string]any{
+			"target-email":      "course-student@test.edulinq.org",
+			"target-submission": "1697406265",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/final/set`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		final, err := db.GetFinalSubmission(db.MustGetTestAssignment(), "course-student@test.edulinq.org")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get final submission: '%v'.", i, err)
+			continue
+		}
+
+		if (final == nil) || (final.SubmissionID != "1697406265") {
+			test.Errorf("Case %d: Unexpected final submission: '%s'.", i, util.MustToJSONIndent(final))
+			continue
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package jobs
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/submissions/jobs/status`, HandleStatus),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
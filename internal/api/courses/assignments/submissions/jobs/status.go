@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type StatusRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	JobID core.NonEmptyString `json:"job-id"`
+}
+
+type StatusResponse struct {
+	FoundJob bool `json:"found-job"`
+
+	Status     model.GradingJobStatus `json:"status"`
+	CreateTime timestamp.Timestamp    `json:"create-time"`
+	StartTime  timestamp.Timestamp    `json:"start-time"`
+	EndTime    timestamp.Timestamp    `json:"end-time"`
+
+	// The same fields as a synchronous submission (only set once the job is complete).
+	Rejected       bool               `json:"rejected"`
+	Message        string             `json:"message"`
+	GradingSuccess bool               `json:"grading-success"`
+	GradingInfo    *model.GradingInfo `json:"result"`
+}
+
+// Get the status of an asynchronous grading job (and the grading result once it is complete).
+func HandleStatus(request *StatusRequest) (*StatusResponse, *core.APIError) {
+	response := StatusResponse{}
+
+	job, err := db.GetGradingJob(string(request.JobID))
+	if err != nil {
+		return nil, core.NewInternalError("-620", &request.APIRequestCourseUserContext, "Failed to get grading job.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("job-id", request.JobID)
+	}
+
+	if job == nil {
+		return &response, nil
+	}
+
+	if (job.CourseID != request.Course.GetID()) || (job.AssignmentID != request.Assignment.GetID()) {
+		return &response, nil
+	}
+
+	// Students may only see their own jobs.
+	if (job.User != request.User.Email) && (request.User.Role < model.CourseRoleGrader) {
+		return &response, nil
+	}
+
+	response.FoundJob = true
+	response.Status = job.Status
+	response.CreateTime = job.CreateTime
+	response.StartTime = job.StartTime
+	response.EndTime = job.EndTime
+
+	if !job.IsDone() {
+		return &response, nil
+	}
+
+	response.Rejected = job.Rejected
+	response.Message = job.ResultMessage
+	response.GradingSuccess = job.GradingSuccess
+
+	if job.SubmissionID != "" {
+		response.GradingInfo, err = db.GetSubmissionResult(request.Assignment, job.User, job.SubmissionID)
+		if err != nil {
+			return nil, core.NewInternalError("-621", &request.APIRequestCourseUserContext, "Failed to get submission result for grading job.").
+				Err(err).Assignment(request.Assignment.GetID()).Add("job-id", request.JobID).Add("submission", job.SubmissionID)
+		}
+
+		policy, err := request.GetFeedbackRestriction(job.User)
+		if err != nil {
+			return nil, core.NewInternalError("-647", &request.APIRequestCourseUserContext, "Failed to get feedback restriction.").
+				Err(err).Assignment(request.Assignment.GetID()).Add("job-id", request.JobID)
+		}
+
+		if policy != nil {
+			response.GradingInfo = policy.RestrictGradingInfo(response.GradingInfo)
+		}
+	}
+
+	return &response, nil
+}
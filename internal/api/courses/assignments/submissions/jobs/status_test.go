@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestStatus(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	oldDockerVal := config.DOCKER_DISABLE.Get()
+	config.DOCKER_DISABLE.Set(true)
+	defer config.DOCKER_DISABLE.Set(oldDockerVal)
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	submissionPath := filepath.Join(assignment.GetSourceDir(), "test-submissions", "solution")
+
+	job := enqueueForTesting(test, assignment, submissionPath, "course-student@test.edulinq.org")
+	graderJob := enqueueForTesting(test, assignment, submissionPath, "course-grader@test.edulinq.org")
+
+	testCases := []struct {
+		email        string
+		owner        string
+		assignmentID string
+		jobID        string
+		foundJob     bool
+		permError    bool
+		locator      string
+	}{
+		// Own job.
+		{"course-student", "course-student", "bash", job.ID, true, false, ""},
+		{"course-grader", "course-grader", "bash", graderJob.ID, true, false, ""},
+
+		// Graders can see other users' jobs.
+		{"course-grader", "course-student", "bash", job.ID, true, false, ""},
+		{"course-admin", "course-student", "bash", job.ID, true, false, ""},
+
+		// Students cannot see other users' jobs.
+		{"course-student", "course-grader", "bash", graderJob.ID, false, false, ""},
+
+		// Wrong assignment.
+		{"course-student", "course-student", "cpp-simple", job.ID, false, false, ""},
+
+		// Missing job.
+		{"course-student", "", "bash", "ZZZ", false, false, ""},
+
+		// Empty job.
+		{"course-student", "", "bash", "", false, true, "-038"},
+	}
+
+	for _, status := range []model.GradingJobStatus{model.GradingJobStatusPending, model.GradingJobStatusComplete} {
+		if status == model.GradingJobStatusComplete {
+			for i := 0; i < 2; i++ {
+				_, err := grader.RunNextGradingJob(context.Background())
+				if err != nil {
+					test.Fatalf("Failed to run job: '%v'.", err)
+				}
+			}
+		}
+
+		for i, testCase := range testCases {
+			fields := map[string]any{
+				"course-id":     "course-languages",
+				"assignment-id": testCase.assignmentID,
+				"job-id":        testCase.jobID,
+			}
+
+			response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/jobs/status`, fields, nil, testCase.email)
+			if !response.Success {
+				if testCase.permError {
+					if response.Locator != testCase.locator {
+						test.Errorf("Case (%s) %d: Incorrect locator. Expected: '%s', Actual: '%s'.", status, i, testCase.locator, response.Locator)
+					}
+				} else {
+					test.Errorf("Case (%s) %d: Response is not a success when it should be: '%v'.", status, i, response)
+				}
+
+				continue
+			}
+
+			if testCase.permError {
+				test.Errorf("Case (%s) %d: Response is a success when it should not be: '%v'.", status, i, response)
+				continue
+			}
+
+			var responseContent StatusResponse
+			util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+			if testCase.foundJob != responseContent.FoundJob {
+				test.Errorf("Case (%s) %d: Found job does not match. Expected: '%v', Actual: '%v'.", status, i, testCase.foundJob, responseContent.FoundJob)
+				continue
+			}
+
+			if !testCase.foundJob {
+				continue
+			}
+
+			if status != responseContent.Status {
+				test.Errorf("Case (%s) %d: Unexpected status: '%s'.", status, i, responseContent.Status)
+				continue
+			}
+
+			if status == model.GradingJobStatusPending {
+				if responseContent.GradingSuccess || (responseContent.GradingInfo != nil) {
+					test.Errorf("Case (%s) %d: Pending job has a result: '%v'.", status, i, responseContent)
+				}
+
+				continue
+			}
+
+			if !responseContent.GradingSuccess || responseContent.Rejected || (responseContent.Message != "") {
+				test.Errorf("Case (%s) %d: Job was not successful: '%v'.", status, i, responseContent)
+				continue
+			}
+
+			if (responseContent.GradingInfo == nil) || (responseContent.GradingInfo.User != (testCase.owner + "@test.edulinq.org")) {
+				test.Errorf("Case (%s) %d: Unexpected grading info: '%v'.", status, i, responseContent.GradingInfo)
+				continue
+			}
+		}
+	}
+}
+
+func enqueueForTesting(test *testing.T, assignment *model.Assignment, submissionPath string, email string) *model.GradingJob {
+	job, reject, err := grader.EnqueueGrading(assignment, submissionPath, email, "", true)
+	if err != nil {
+		test.Fatalf("Failed to enqueue: '%v'.", err)
+	}
+
+	if reject != nil {
+		test.Fatalf("Submission was rejected: '%s'.", reject.String())
+	}
+
+	return job
+}
@@ -0,0 +1,54 @@
+package overrides
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type HistoryRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TargetUser       core.TargetCourseUserSelfOrGrader `json:"target-email"`
+	TargetSubmission string                            `json:"target-submission"`
+}
+
+type HistoryResponse struct {
+	FoundUser       bool                   `json:"found-user"`
+	FoundSubmission bool                   `json:"found-submission"`
+	SubmissionID    string                 `json:"submission-id"`
+	History         []*model.ScoreOverride `json:"history"`
+}
+
+// Get every change that has been made to the question scores of a submission (oldest first). Defaults to the most recent submission.
+func HandleHistory(request *HistoryRequest) (*HistoryResponse, *core.APIError) {
+	response := HistoryResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	info, err := db.GetSubmissionResult(request.Assignment, request.TargetUser.Email, request.TargetSubmission)
+	if err != nil {
+		return nil, core.NewInternalError("-628", &request.APIRequestCourseUserContext, "Failed to get submission result.").
+			Err(err).Assignment(request.Assignment.GetID()).
+			Add("target-user", request.TargetUser.Email).Add("submission", request.TargetSubmission)
+	}
+
+	if info == nil {
+		return &response, nil
+	}
+
+	response.FoundSubmission = true
+	response.SubmissionID = info.ID
+
+	response.History = info.ScoreOverrides
+	if response.History == nil {
+		response.History = make([]*model.ScoreOverride, 0)
+	}
+
+	return &response, nil
+}
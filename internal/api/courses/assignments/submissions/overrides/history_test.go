@@ -0,0 +1,97 @@
+package overrides
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestHistory(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	email := "course-student@test.edulinq.org"
+
+	overrides := []*model.ScoreOverride{
+		&model.ScoreOverride{Question: "Q1", Score: 0.5, Author: "course-grader@test.edulinq.org", Reason: "First."},
+		&model.ScoreOverride{Question: "Q1", Score: 0.25, Author: "course-admin@test.edulinq.org", Reason: "Second."},
+	}
+
+	for _, override := range overrides {
+		_, err := db.ApplyScoreOverride(assignment, email, "", override)
+		if err != nil {
+			test.Fatalf("Failed to apply override: '%v'.", err)
+		}
+	}
+
+	testCases := []struct {
+		email            string
+		targetSubmission string
+		locator          string
+		foundSubmission  bool
+		historyLength    int
+	}{
+		// Own submission.
+		{"course-student", "", "", true, 2},
+
+		// Staff.
+		{"course-grader", "", "", true, 2},
+		{"course-admin", "", "", true, 2},
+
+		// Submission without overrides.
+		{"course-grader", "1697406256", "", true, 0},
+
+		// Missing submission.
+		{"course-grader", "ZZZ", "", false, 0},
+
+		// Invalid permissions.
+		{"course-other", "", "-020", false, 0},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-email":      email,
+			"target-submission": testCase.targetSubmission,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/overrides/history`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent HistoryResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundSubmission != responseContent.FoundSubmission {
+			test.Errorf("Case %d: Unexpected found submission. Expected: %v, Actual: %v.", i, testCase.foundSubmission, responseContent.FoundSubmission)
+			continue
+		}
+
+		if testCase.historyLength != len(responseContent.History) {
+			test.Errorf("Case %d: Unexpected history length. Expected: %d, Actual: %d.", i, testCase.historyLength, len(responseContent.History))
+			continue
+		}
+
+		if testCase.historyLength == 0 {
+			continue
+		}
+
+		if (responseContent.History[0].Reason != "First.") || (responseContent.History[1].PreviousScore != 0.5) {
+			test.Errorf("Case %d: Unexpected history: '%s'.", i, util.MustToJSONIndent(responseContent.History))
+			continue
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package overrides
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type RemoveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+
+	TargetUser       core.TargetCourseUser `json:"target-email"`
+	TargetSubmission string                `json:"target-submission"`
+
+	Question core.NonEmptyString `json:"question"`
+	Reason   core.NonEmptyString `json:"reason"`
+}
+
+// Remove the score override of a single question in a submission (restoring the grader's score). Defaults to the most recent submission.
+func HandleRemove(request *RemoveRequest) (*OverrideResponse, *core.APIError) {
+	override := &model.ScoreOverride{
+		Question: string(request.Question),
+		Remove:   true,
+		Author:   request.User.Email,
+		Reason:   string(request.Reason),
+		Time:     timestamp.Now(),
+	}
+
+	return applyOverride(&request.APIRequestAssignmentContext, request.TargetUser, request.TargetSubmission, override)
+}
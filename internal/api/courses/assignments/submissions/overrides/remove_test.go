@@ -0,0 +1,92 @@
+package overrides
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRemove(test *testing.T) {
+	testCases := []struct {
+		email    string
+		question string
+		locator  string
+	}{
+		// Valid removal.
+		{"course-grader", "Q1", ""},
+		{"course-admin", "Q1", ""},
+
+		// No override to remove.
+		{"course-grader", "Q2", "-626"},
+
+		// Unknown question.
+		{"course-grader", "ZZZ", "-626"},
+
+		// Invalid permissions.
+		{"course-student", "Q1", "-020"},
+		{"course-other", "Q1", "-020"},
+	}
+
+	email := "course-student@test.edulinq.org"
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		assignment := db.MustGetTestAssignment()
+
+		override := &model.ScoreOverride{
+			Question: "Q1",
+			Score:    0.0,
+			Author:   "course-grader@test.edulinq.org",
+			Reason:   "Manual review.",
+		}
+
+		_, err := db.ApplyScoreOverride(assignment, email, "", override)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to apply initial override: '%v'.", i, err)
+		}
+
+		fields := map[string]any{
+			"target-email": email,
+			"question":     testCase.question,
+			"reason":       "Mistake.",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/overrides/remove`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent OverrideResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !responseContent.FoundSubmission {
+			test.Errorf("Case %d: Did not find submission.", i)
+			continue
+		}
+
+		if (responseContent.Result.Score != 2.0) || responseContent.Result.HasScoreOverrides() {
+			test.Errorf("Case %d: Override was not removed: '%s'.", i, util.MustToJSONIndent(responseContent.Result))
+			continue
+		}
+
+		if len(responseContent.Result.ScoreOverrides) != 2 {
+			test.Errorf("Case %d: Unexpected history length. Expected: 2, Actual: %d.", i, len(responseContent.Result.ScoreOverrides))
+			continue
+		}
+	}
+
+	db.ResetForTesting()
+}
@@ -0,0 +1,17 @@
+package overrides
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/submissions/overrides/history`, HandleHistory),
+	core.MustNewAPIRoute(`courses/assignments/submissions/overrides/remove`, HandleRemove),
+	core.MustNewAPIRoute(`courses/assignments/submissions/overrides/set`, HandleSet),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
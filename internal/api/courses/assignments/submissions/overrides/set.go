@@ -0,0 +1,87 @@
+package overrides
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type SetRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+
+	TargetUser       core.TargetCourseUser `json:"target-email"`
+	TargetSubmission string                `json:"target-submission"`
+
+	Question core.NonEmptyString `json:"question"`
+	Score    float64             `json:"score"`
+	Reason   core.NonEmptyString `json:"reason"`
+}
+
+type OverrideResponse struct {
+	FoundUser       bool                 `json:"found-user"`
+	FoundSubmission bool                 `json:"found-submission"`
+	Override        *model.ScoreOverride `json:"override"`
+	Result          *model.GradingInfo   `json:"result"`
+}
+
+// Override the score of a single question in a submission. Defaults to the most recent submission.
+func HandleSet(request *SetRequest) (*OverrideResponse, *core.APIError) {
+	override := &model.ScoreOverride{
+		Question: string(request.Question),
+		Score:    request.Score,
+		Author:   request.User.Email,
+		Reason:   string(request.Reason),
+		Time:     timestamp.Now(),
+	}
+
+	return applyOverride(&request.APIRequestAssignmentContext, request.TargetUser, request.TargetSubmission, override)
+}
+
+func applyOverride(request *core.APIRequestAssignmentContext, targetUser core.TargetCourseUser, targetSubmission string, override *model.ScoreOverride) (*OverrideResponse, *core.APIError) {
+	response := OverrideResponse{}
+
+	if !targetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	info, err := db.GetSubmissionResult(request.Assignment, targetUser.Email, targetSubmission)
+	if err != nil {
+		return nil, core.NewInternalError("-625", &request.APIRequestCourseUserContext, "Failed to get submission result.").
+			Err(err).Assignment(request.Assignment.GetID()).
+			Add("target-user", targetUser.Email).Add("submission", targetSubmission)
+	}
+
+	if info == nil {
+		return &response, nil
+	}
+
+	err = info.CheckScoreOverride(override)
+	if err != nil {
+		return nil, core.NewBadRequestError("-626", &request.APIRequest, fmt.Sprintf("Invalid score override: '%v'.", err)).
+			Err(err).Course(request.Course.GetID()).Assignment(request.Assignment.GetID()).
+			Add("target-user", targetUser.Email).Add("submission", info.ID)
+	}
+
+	info, err = db.ApplyScoreOverride(request.Assignment, targetUser.Email, info.ID, override)
+	if err != nil {
+		return nil, core.NewInternalError("-627", &request.APIRequestCourseUserContext, "Failed to apply score override.").
+			Err(err).Assignment(request.Assignment.GetID()).
+			Add("target-user", targetUser.Email).Add("submission", targetSubmission)
+	}
+
+	if info == nil {
+		return &response, nil
+	}
+
+	response.FoundSubmission = true
+	response.Override = override
+	response.Result = info
+
+	return &response, nil
+}
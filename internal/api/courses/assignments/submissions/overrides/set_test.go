@@ -0,0 +1,104 @@
+package overrides
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSet(test *testing.T) {
+	testCases := []struct {
+		email            string
+		targetEmail      string
+		targetSubmission string
+		question         string
+		score            float64
+		locator          string
+		foundUser        bool
+		foundSubmission  bool
+		expectedScore    float64
+	}{
+		// Valid overrides.
+		{"course-grader", "course-student@test.edulinq.org", "", "Q1", 0.5, "", true, true, 1.5},
+		{"course-admin", "course-student@test.edulinq.org", "1697406256", "Q2", 1.0, "", true, true, 1.0},
+		{"server-admin", "course-student@test.edulinq.org", "", "Q2", 5.0, "", true, true, 6.0},
+
+		// Missing user/submission.
+		{"course-grader", "ZZZ@test.edulinq.org", "", "Q1", 0.5, "", false, false, 0.0},
+		{"course-grader", "course-student@test.edulinq.org", "ZZZ", "Q1", 0.5, "", true, false, 0.0},
+
+		// Unknown question.
+		{"course-grader", "course-student@test.edulinq.org", "", "ZZZ", 0.5, "-626", false, false, 0.0},
+
+		// Invalid permissions.
+		{"course-student", "course-student@test.edulinq.org", "", "Q1", 0.5, "-020", false, false, 0.0},
+		{"course-other", "course-student@test.edulinq.org", "", "Q1", 0.5, "-020", false, false, 0.0},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"target-email":      testCase.targetEmail,
+			"target-submission": testCase.targetSubmission,
+			"question":          testCase.question,
+			"score":             testCase.score,
+			"reason":            "Manual review.",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/overrides/set`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent OverrideResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (testCase.foundUser != responseContent.FoundUser) || (testCase.foundSubmission != responseContent.FoundSubmission) {
+			test.Errorf("Case %d: Unexpected found values. Expected: (%v, %v), Actual: (%v, %v).",
+				i, testCase.foundUser, testCase.foundSubmission, responseContent.FoundUser, responseContent.FoundSubmission)
+			continue
+		}
+
+		if !testCase.foundSubmission {
+			continue
+		}
+
+		if responseContent.Result.Score != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected score. Expected: %f, Actual: %f.", i, testCase.expectedScore, responseContent.Result.Score)
+			continue
+		}
+
+		expectedAuthor := testCase.email + "@test.edulinq.org"
+		if responseContent.Override.Author != expectedAuthor {
+			test.Errorf("Case %d: Unexpected author. Expected: '%s', Actual: '%s'.", i, expectedAuthor, responseContent.Override.Author)
+			continue
+		}
+
+		// The override should be saved.
+		assignment := db.MustGetTestAssignment()
+		info, err := db.GetSubmissionResult(assignment, testCase.targetEmail, responseContent.Result.ID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission result: '%v'.", i, err)
+			continue
+		}
+
+		if (info.Score != testCase.expectedScore) || (len(info.ScoreOverrides) != 1) {
+			test.Errorf("Case %d: Override was not saved: '%s'.", i, util.MustToJSONIndent(info))
+			continue
+		}
+	}
+
+	db.ResetForTesting()
+}
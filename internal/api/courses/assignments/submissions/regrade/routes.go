@@ -0,0 +1,16 @@
+package regrade
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/submissions/regrade/start`, HandleStart),
+	core.MustNewAPIRoute(`courses/assignments/submissions/regrade/status`, HandleStatus),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
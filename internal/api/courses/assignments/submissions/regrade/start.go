@@ -0,0 +1,41 @@
+package regrade
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/procedures/regrade"
+)
+
+type StartRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	regrade.RegradeOptions
+
+	// Wait for the regrade to complete before responding.
+	// Otherwise, the regrade runs in the background and its progress can be checked with the status endpoint.
+	WaitForCompletion bool `json:"wait-for-completion"`
+}
+
+type StartResponse struct {
+	Status *regrade.RegradeStatus `json:"status"`
+}
+
+// Regrade the submissions for an assignment (the most recent submission for each user by default).
+func HandleStart(request *StartRequest) (*StartResponse, *core.APIError) {
+	response := StartResponse{}
+
+	if !request.WaitForCompletion {
+		response.Status = regrade.Start(request.Assignment, request.RegradeOptions)
+		return &response, nil
+	}
+
+	status, err := regrade.Regrade(request.Context, request.Assignment, request.RegradeOptions, nil)
+	if err != nil {
+		return nil, core.NewInternalError("-622", &request.APIRequestCourseUserContext, "Failed to regrade assignment.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	response.Status = status
+
+	return &response, nil
+}
@@ -0,0 +1,115 @@
+package regrade
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/procedures/regrade"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestStart(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	oldDockerVal := config.DOCKER_DISABLE.Get()
+	config.DOCKER_DISABLE.Set(true)
+	defer config.DOCKER_DISABLE.Set(oldDockerVal)
+
+	submitForTesting(test, "course-student@test.edulinq.org")
+
+	testCases := []struct {
+		email     string
+		wait      bool
+		dryRun    bool
+		permError bool
+	}{
+		{"course-admin", true, true, false},
+		{"course-owner", true, false, false},
+		{"course-admin", false, true, false},
+
+		{"course-grader", true, true, true},
+		{"course-student", true, true, true},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"course-id":           "course-languages",
+			"assignment-id":       "bash",
+			"users":               []string{"course-student@test.edulinq.org"},
+			"dry-run":             testCase.dryRun,
+			"wait-for-completion": testCase.wait,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/regrade/start`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.permError {
+				expectedLocator := "-020"
+				if response.Locator != expectedLocator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, expectedLocator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.permError {
+			test.Errorf("Case %d: Did not get an expected permissions error.", i)
+			continue
+		}
+
+		var responseContent StartResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		status := responseContent.Status
+		if !testCase.wait {
+			status = waitForStatus(test, status.ID)
+		}
+
+		if !status.Done || (status.Total != 1) || (status.Failed != 0) || (len(status.Results) != 1) {
+			test.Errorf("Case %d: Unexpected status: '%s'.", i, util.MustToJSONIndent(status))
+			continue
+		}
+
+		if testCase.dryRun != (status.Results[0].NewSubmissionID == "") {
+			test.Errorf("Case %d: Unexpected new submission (dry run: %v): '%s'.", i, testCase.dryRun, status.Results[0].NewSubmissionID)
+			continue
+		}
+	}
+}
+
+func submitForTesting(test *testing.T, user string) {
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	submissionPath := filepath.Join(assignment.GetSourceDir(), "test-submissions", "solution")
+
+	_, _, softError, err := grader.Grade(context.Background(), assignment, submissionPath, user, "", false, grader.GetDefaultGradeOptions())
+	if err != nil {
+		test.Fatalf("Failed to grade: '%v'.", err)
+	}
+
+	if softError != "" {
+		test.Fatalf("Got a soft error: '%s'.", softError)
+	}
+}
+
+func waitForStatus(test *testing.T, id string) *regrade.RegradeStatus {
+	for i := 0; i < 100; i++ {
+		status := regrade.GetStatus(id)
+		if (status != nil) && status.Done {
+			return status
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	test.Fatalf("Regrade '%s' did not finish.", id)
+	return nil
+}
@@ -0,0 +1,37 @@
+package regrade
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/procedures/regrade"
+)
+
+type StatusRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	RegradeID core.NonEmptyString `json:"regrade-id"`
+}
+
+type StatusResponse struct {
+	Found  bool                   `json:"found"`
+	Status *regrade.RegradeStatus `json:"status"`
+}
+
+// Get the progress of a regrade (and the full report once it is done).
+func HandleStatus(request *StatusRequest) (*StatusResponse, *core.APIError) {
+	response := StatusResponse{}
+
+	status := regrade.GetStatus(string(request.RegradeID))
+	if status == nil {
+		return &response, nil
+	}
+
+	if (status.CourseID != request.Course.GetID()) || (status.AssignmentID != request.Assignment.GetID()) {
+		return &response, nil
+	}
+
+	response.Found = true
+	response.Status = status
+
+	return &response, nil
+}
@@ -0,0 +1,89 @@
+package regrade
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/procedures/regrade"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestStatus(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	oldDockerVal := config.DOCKER_DISABLE.Get()
+	config.DOCKER_DISABLE.Set(true)
+	defer config.DOCKER_DISABLE.Set(oldDockerVal)
+
+	submitForTesting(test, "course-student@test.edulinq.org")
+
+	options := regrade.RegradeOptions{
+		DryRun: true,
+		Users:  []string{"course-student@test.edulinq.org"},
+	}
+
+	id := regrade.Start(db.MustGetAssignment("course-languages", "bash"), options).ID
+	waitForStatus(test, id)
+
+	testCases := []struct {
+		email        string
+		assignmentID string
+		regradeID    string
+		found        bool
+		locator      string
+	}{
+		{"course-admin", "bash", id, true, ""},
+
+		// Wrong assignment.
+		{"course-admin", "cpp-simple", id, false, ""},
+
+		// Missing regrade.
+		{"course-admin", "bash", "ZZZ", false, ""},
+
+		// Empty regrade.
+		{"course-admin", "bash", "", false, "-038"},
+
+		// Permissions.
+		{"course-grader", "bash", id, false, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"course-id":     "course-languages",
+			"assignment-id": testCase.assignmentID,
+			"regrade-id":    testCase.regradeID,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/regrade/status`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator == "" {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			} else if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent StatusResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.found != responseContent.Found {
+			test.Errorf("Case %d: Unexpected found value. Expected: '%v', Actual: '%v'.", i, testCase.found, responseContent.Found)
+			continue
+		}
+
+		if testCase.found && ((responseContent.Status == nil) || (responseContent.Status.ID != id)) {
+			test.Errorf("Case %d: Unexpected status: '%s'.", i, util.MustToJSONIndent(responseContent.Status))
+			continue
+		}
+	}
+}
@@ -5,11 +5,16 @@ package submissions
 import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/api/courses/assignments/submissions/fetch"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/submissions/final"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/submissions/jobs"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/submissions/overrides"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/submissions/regrade"
 )
 
 var baseRoutes []core.Route = []core.Route{
 	core.MustNewAPIRoute(`courses/assignments/submissions/remove`, HandleRemove),
 	core.MustNewAPIRoute(`courses/assignments/submissions/submit`, HandleSubmit),
+	core.MustNewAPIStreamRoute(`courses/assignments/submissions/stream`, HandleStream),
 }
 
 func GetRoutes() *[]core.Route {
@@ -17,6 +22,10 @@ func GetRoutes() *[]core.Route {
 
 	routes = append(routes, baseRoutes...)
 	routes = append(routes, *(fetch.GetRoutes())...)
+	routes = append(routes, *(final.GetRoutes())...)
+	routes = append(routes, *(jobs.GetRoutes())...)
+	routes = append(routes, *(overrides.GetRoutes())...)
+	routes = append(routes, *(regrade.GetRoutes())...)
 
 	return &routes
 }
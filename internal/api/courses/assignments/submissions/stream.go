@@ -0,0 +1,83 @@
+package submissions
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// The number of events that can be waiting to be sent to a client.
+// When a client falls this far behind, grader output events are dropped (other events will wait).
+const STREAM_EVENT_BUFFER_SIZE = 1024
+
+type StreamRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+	Files core.POSTFiles
+
+	Message   string `json:"message"`
+	AllowLate bool   `json:"allow-late"`
+}
+
+// Submit an assignment submission to the autograder and stream grading events as they happen.
+// Events (see model.GradingEvent) are streamed as newline-delimited JSON, followed by the same response as a normal submission.
+// Grader output (stdout/stderr) events are only sent to graders and above.
+// Question events follow the assignment's feedback policy (restricted questions are not sent).
+func HandleStream(request *StreamRequest, stream *core.APIStream) (*SubmitResponse, *core.APIError) {
+	includeOutput := (request.User.Role >= model.CourseRoleGrader)
+
+	policy, err := request.GetFeedbackRestriction(request.User.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-663", &request.APIRequestCourseUserContext, "Failed to get feedback restriction.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	events := make(chan *model.GradingEvent, STREAM_EVENT_BUFFER_SIZE)
+	done := make(chan any)
+
+	// Send events in the background so a slow client does not slow down grading.
+	go func() {
+		defer close(done)
+
+		for event := range events {
+			// Once the client is gone, keep draining events so grading is not blocked.
+			stream.Send(event)
+		}
+	}()
+
+	listener := func(event *model.GradingEvent) {
+		if (event.Type == model.GradingEventTypeQuestion) && (policy != nil) {
+			question := policy.RestrictGradedQuestion(event.Question)
+			if question == nil {
+				return
+			}
+
+			restricted := *event
+			restricted.Question = question
+			event = &restricted
+		}
+
+		if event.Type != model.GradingEventTypeOutput {
+			events <- event
+			return
+		}
+
+		if !includeOutput {
+			return
+		}
+
+		select {
+		case events <- event:
+		default:
+			// The client is too far behind, drop the output.
+		}
+	}
+
+	ctx := grader.WithEventListener(request.Context, listener)
+	response := gradeSubmission(ctx, &request.APIRequestAssignmentContext, request.Files.TempDir, request.Message, request.AllowLate, request)
+
+	close(events)
+	<-done
+
+	return response, nil
+}
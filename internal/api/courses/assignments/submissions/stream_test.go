@@ -0,0 +1,225 @@
+package submissions
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// A bash submission that passes all the tests and writes to stdout when it is loaded.
+const STREAM_TEST_SUBMISSION = `
+echo "Loading submission."
+
+function add() {
+    echo $(($1 + $2))
+}
+`
+
+func TestStream(test *testing.T) {
+	defer db.ResetForTesting()
+
+	tempDir := util.MustMkDirTemp("test-submission-stream-")
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "assignment.sh")
+	err := util.WriteFile(STREAM_TEST_SUBMISSION, path)
+	if err != nil {
+		test.Fatalf("Failed to write submission: '%v'.", err)
+	}
+
+	fields := map[string]any{
+		"course-id":     "course-languages",
+		"assignment-id": "bash",
+		"allow-late":    true,
+	}
+
+	testCases := []struct {
+		email         string
+		includeOutput bool
+	}{
+		{"course-student", false},
+		{"course-grader", true},
+		{"course-admin", true},
+	}
+
+	for i, testCase := range testCases {
+		rawEvents, response := core.SendTestAPIStreamRequestFull(test, `courses/assignments/submissions/stream`, fields, []string{path}, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent SubmitResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !responseContent.GradingSuccess || (responseContent.GradingInfo == nil) {
+			test.Errorf("Case %d: Response is not a grading success when it should be: '%s'.", i, util.MustToJSONIndent(responseContent))
+			continue
+		}
+
+		var events []*model.GradingEvent
+		util.MustJSONFromString(util.MustToJSON(rawEvents), &events)
+
+		types := make([]model.GradingEventType, 0, len(events))
+		stdout := ""
+		for _, event := range events {
+			if event.Type == model.GradingEventTypeOutput {
+				stdout += event.Text
+				continue
+			}
+
+			types = append(types, event.Type)
+		}
+
+		expectedTypes := []model.GradingEventType{
+			model.GradingEventTypeQueued,
+			model.GradingEventTypeStarted,
+			model.GradingEventTypeQuestion,
+			model.GradingEventTypeFinished,
+		}
+
+		// An image build may happen first (if docker is enabled).
+		types = slices.DeleteFunc(types, func(eventType model.GradingEventType) bool {
+			return eventType == model.GradingEventTypeBuildingImage
+		})
+
+		if !slices.Equal(expectedTypes, types) {
+			test.Errorf("Case %d: Unexpected event types. Expected: '%v', Actual: '%v'.", i, expectedTypes, types)
+			continue
+		}
+
+		expectedStdout := ""
+		if testCase.includeOutput {
+			expectedStdout = "Loading submission.\n"
+		}
+
+		if expectedStdout != stdout {
+			test.Errorf("Case %d: Unexpected output. Expected: '%s', Actual: '%s'.", i, expectedStdout, stdout)
+			continue
+		}
+
+		question := events[slices.IndexFunc(events, func(event *model.GradingEvent) bool {
+			return event.Type == model.GradingEventTypeQuestion
+		})].Question
+
+		if (question == nil) || (question.Score != responseContent.GradingInfo.Questions[0].Score) {
+			test.Errorf("Case %d: Question event does not match the result. Event: '%s', Result: '%s'.",
+				i, util.MustToJSONIndent(question), util.MustToJSONIndent(responseContent.GradingInfo.Questions[0]))
+			continue
+		}
+	}
+}
+
+// Question events follow the assignment's feedback policy.
+func TestStreamFeedbackPolicy(test *testing.T) {
+	defer db.ResetForTesting()
+
+	// A submission that misses every test case (so the question has a message).
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	path := filepath.Join(assignment.GetSourceDir(), "test-submissions", "not-implemented", "assignment.sh")
+
+	fields := map[string]any{
+		"course-id":     "course-languages",
+		"assignment-id": "bash",
+		"allow-late":    true,
+	}
+
+	testCases := []struct {
+		email           string
+		policyType      model.FeedbackPolicyType
+		expectQuestion  bool
+		expectedMessage bool
+	}{
+		{"course-student", model.FeedbackFull, true, true},
+		{"course-student", model.FeedbackHideMessages, true, false},
+		{"course-student", model.FeedbackTotalOnly, false, false},
+		{"course-student", model.FeedbackNone, false, false},
+
+		// Staff always get full feedback.
+		{"course-grader", model.FeedbackNone, true, true},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		// Feedback is never released during the test.
+		releaseDate := timestamp.Now() + timestamp.FromMSecs(24*60*60*1000)
+
+		assignment = db.MustGetAssignment("course-languages", "bash")
+		assignment.FeedbackPolicy = &model.FeedbackPolicy{Type: testCase.policyType, ReleaseDate: &releaseDate}
+		db.MustSaveAssignment(assignment)
+
+		rawEvents, response := core.SendTestAPIStreamRequestFull(test, `courses/assignments/submissions/stream`, fields, []string{path}, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var events []*model.GradingEvent
+		util.MustJSONFromString(util.MustToJSON(rawEvents), &events)
+
+		var question *model.GradedQuestion = nil
+		for _, event := range events {
+			if event.Type == model.GradingEventTypeQuestion {
+				question = event.Question
+			}
+		}
+
+		if testCase.expectQuestion != (question != nil) {
+			test.Errorf("Case %d: Unexpected question event. Expected: %v, Actual: '%s'.", i, testCase.expectQuestion, util.MustToJSONIndent(question))
+			continue
+		}
+
+		if question == nil {
+			continue
+		}
+
+		if testCase.expectedMessage != (question.Message != "") {
+			test.Errorf("Case %d: Unexpected question message. Expected: %v, Actual: '%s'.", i, testCase.expectedMessage, question.Message)
+			continue
+		}
+	}
+}
+
+// Rejected submissions do not stream any events and get a normal response.
+func TestStreamRejected(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	// Disable testing mode to check for rejection.
+	config.UNIT_TESTING_MODE.Set(false)
+	defer config.UNIT_TESTING_MODE.Set(true)
+
+	course := db.MustGetCourse("course101")
+	course.SubmissionLimit = &model.SubmissionLimitInfo{
+		Max: util.IntPointer(0),
+	}
+	db.MustSaveCourse(course)
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	paths := []string{filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)}
+
+	events, response := core.SendTestAPIStreamRequestFull(test, `courses/assignments/submissions/stream`, nil, paths, "course-student")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	if len(events) != 0 {
+		test.Fatalf("Got events for a rejected submission: '%s'.", util.MustToJSONIndent(events))
+	}
+
+	var responseContent SubmitResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if !responseContent.Rejected {
+		test.Fatalf("Response is not rejected when it should be: '%s'.", util.MustToJSONIndent(responseContent))
+	}
+}
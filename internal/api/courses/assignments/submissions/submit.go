@@ -1,6 +1,8 @@
 package submissions
 
 import (
+	"context"
+
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/grader"
 	"github.com/edulinq/autograder/internal/log"
@@ -14,6 +16,10 @@ type SubmitRequest struct {
 
 	Message   string `json:"message"`
 	AllowLate bool   `json:"allow-late"`
+
+	// Queue the submission for grading instead of waiting for the result.
+	// The returned job ID can be used to check on the status/result of grading.
+	Async bool `json:"async"`
 }
 
 type SubmitResponse struct {
@@ -22,16 +28,53 @@ type SubmitResponse struct {
 
 	GradingSuccess bool               `json:"grading-success"`
 	GradingInfo    *model.GradingInfo `json:"result"`
+
+	JobID string `json:"job-id,omitempty"`
 }
 
 // Submit an assignment submission to the autograder.
 func HandleSubmit(request *SubmitRequest) (*SubmitResponse, *core.APIError) {
+	if request.Async {
+		return handleAsyncSubmit(request)
+	}
+
+	response := gradeSubmission(request.Context, &request.APIRequestAssignmentContext, request.Files.TempDir, request.Message, request.AllowLate, request)
+
+	return response, nil
+}
+
+func handleAsyncSubmit(request *SubmitRequest) (*SubmitResponse, *core.APIError) {
+	response := SubmitResponse{}
+
+	job, reject, err := grader.EnqueueGrading(request.Assignment, request.Files.TempDir, request.User.Email, request.Message, request.AllowLate)
+	if err != nil {
+		return nil, core.NewInternalError("-619", &request.APIRequestCourseUserContext, "Failed to enqueue submission for grading.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	if reject != nil {
+		log.LogToSplitLevels(log.LevelTrace, log.LevelDebug, "Submission rejected.", request.Assignment, log.NewAttr("reason", reject.String()), log.NewAttr("request", request), request.User)
+
+		response.Rejected = true
+		response.Message = reject.String()
+		return &response, nil
+	}
+
+	response.JobID = job.ID
+
+	return &response, nil
+}
+
+// Grade a submission and wait for the result.
+// Internal grading errors are logged (and result in an empty response).
+// |logRequest| is the full request (used for logging).
+func gradeSubmission(ctx context.Context, request *core.APIRequestAssignmentContext, submissionDir string, message string, allowLate bool, logRequest any) *SubmitResponse {
 	response := SubmitResponse{}
 
 	gradeOptions := grader.GetDefaultGradeOptions()
-	gradeOptions.AllowLate = request.AllowLate
+	gradeOptions.AllowLate = allowLate
 
-	result, reject, failureMessage, err := grader.Grade(request.Context, request.Assignment, request.Files.TempDir, request.User.Email, request.Message, true, gradeOptions)
+	result, reject, failureMessage, err := grader.Grade(ctx, request.Assignment, submissionDir, request.User.Email, message, true, gradeOptions)
 	if err != nil {
 		stdout := ""
 		stderr := ""
@@ -43,26 +86,36 @@ func HandleSubmit(request *SubmitRequest) (*SubmitResponse, *core.APIError) {
 
 		log.LogToSplitLevels(log.LevelDebug, log.LevelInfo, "Submission failed internally.", err, request.Assignment, log.NewAttr("stdout", stdout), log.NewAttr("stderr", stderr), request.User)
 
-		return &response, nil
+		return &response
 	}
 
 	if reject != nil {
-		log.LogToSplitLevels(log.LevelTrace, log.LevelDebug, "Submission rejected.", request.Assignment, log.NewAttr("reason", reject.String()), log.NewAttr("request", request), request.User)
+		log.LogToSplitLevels(log.LevelTrace, log.LevelDebug, "Submission rejected.", request.Assignment, log.NewAttr("reason", reject.String()), log.NewAttr("request", logRequest), request.User)
 
 		response.Rejected = true
 		response.Message = reject.String()
-		return &response, nil
+		return &response
 	}
 
 	if failureMessage != "" {
-		log.LogToSplitLevels(log.LevelTrace, log.LevelDebug, "Submission got a soft error.", request.Assignment, log.NewAttr("message", failureMessage), log.NewAttr("request", request), request.User)
+		log.LogToSplitLevels(log.LevelTrace, log.LevelDebug, "Submission got a soft error.", request.Assignment, log.NewAttr("message", failureMessage), log.NewAttr("exit-status", result.GetExitStatus()), log.NewAttr("request", logRequest), request.User)
 
 		response.Message = failureMessage
-		return &response, nil
+		return &response
+	}
+
+	policy, err := request.GetFeedbackRestriction(request.User.Email)
+	if err != nil {
+		log.Error("Failed to get feedback restriction.", err, request.Assignment, request.User)
+		return &response
 	}
 
 	response.GradingSuccess = true
 	response.GradingInfo = result.Info
 
-	return &response, nil
+	if policy != nil {
+		response.GradingInfo = policy.RestrictGradingInfo(result.Info)
+	}
+
+	return &response
 }
@@ -218,3 +218,149 @@ func TestRejectLateSubmission(test *testing.T) {
 		}
 	}
 }
+
+func TestSubmitAsync(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	paths := []string{filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)}
+
+	fields := map[string]any{
+		"course-id":     "course-languages",
+		"assignment-id": "bash",
+		"allow-late":    true,
+		"async":         true,
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/submit`, fields, paths, "course-student")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent SubmitResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if responseContent.Rejected || responseContent.GradingSuccess || (responseContent.GradingInfo != nil) {
+		test.Fatalf("Async response has grading information: '%v'.", responseContent)
+	}
+
+	if responseContent.JobID == "" {
+		test.Fatalf("Async response does not have a job ID: '%v'.", responseContent)
+	}
+
+	job, err := db.GetGradingJob(responseContent.JobID)
+	if err != nil {
+		test.Fatalf("Failed to get job: '%v'.", err)
+	}
+
+	if (job == nil) || (job.Status != model.GradingJobStatusPending) {
+		test.Fatalf("Job is not pending: '%v'.", job)
+	}
+
+	if (job.User != "course-student@test.edulinq.org") || (job.CourseID != "course-languages") || (job.AssignmentID != "bash") || !job.AllowLate {
+		test.Fatalf("Job does not match the request: '%v'.", job)
+	}
+}
+
+func TestSubmitAsyncReject(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	dueDate := timestamp.Zero()
+	assignment.DueDate = &dueDate
+	db.MustSaveAssignment(assignment)
+
+	paths := []string{filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)}
+
+	fields := map[string]any{
+		"course-id":     "course-languages",
+		"assignment-id": "bash",
+		"async":         true,
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/submit`, fields, paths, "course-student")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent SubmitResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if !responseContent.Rejected || (responseContent.Message == "") {
+		test.Fatalf("Response is not rejected when it should be: '%v'.", responseContent)
+	}
+
+	if responseContent.JobID != "" {
+		test.Fatalf("Rejected submission has a job ID: '%v'.", responseContent)
+	}
+}
+
+func TestSubmitFeedbackPolicy(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	testSubmissions, err := grader.GetTestSubmissions(config.GetTestdataDir(), !config.DOCKER_DISABLE.Get())
+	if err != nil {
+		test.Fatalf("Failed to get test submissions in '%s': '%v'.", config.GetTestdataDir(), err)
+	}
+
+	testSubmission := testSubmissions[0]
+
+	releaseDate := timestamp.Now() + timestamp.FromMSecs(1000*60*60)
+	testSubmission.Assignment.FeedbackPolicy = &model.FeedbackPolicy{Type: model.FeedbackNone, ReleaseDate: &releaseDate}
+	db.MustSaveAssignment(testSubmission.Assignment)
+
+	testCases := []struct {
+		email      string
+		restricted bool
+	}{
+		{"course-student", true},
+		{"course-grader", false},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"course-id":     testSubmission.Assignment.GetCourse().GetID(),
+			"assignment-id": testSubmission.Assignment.GetID(),
+			"allow-late":    true,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/submit`, fields, testSubmission.Files, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent SubmitResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !responseContent.GradingSuccess {
+			test.Errorf("Case %d: Response is not a grading success when it should be: '%v'.", i, responseContent)
+			continue
+		}
+
+		if responseContent.GradingInfo.FeedbackRestricted != testCase.restricted {
+			test.Errorf("Case %d: Unexpected feedback restriction. Expected: %v, Actual: %v.", i, testCase.restricted, responseContent.GradingInfo.FeedbackRestricted)
+			continue
+		}
+
+		if testCase.restricted && ((len(responseContent.GradingInfo.Questions) != 0) || (responseContent.GradingInfo.Score != 0.0)) {
+			test.Errorf("Case %d: Restricted feedback was not removed: '%s'.", i, util.MustToJSONIndent(responseContent.GradingInfo))
+			continue
+		}
+
+		// The full result is always stored.
+		submission, err := db.GetSubmissionResult(testSubmission.Assignment, testCase.email+"@test.edulinq.org", "")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission: '%v'.", i, err)
+			continue
+		}
+
+		if submission.FeedbackRestricted || !submission.Equals(*testSubmission.TestSubmission.GradingInfo, !testSubmission.TestSubmission.IgnoreMessages) {
+			test.Errorf("Case %d: Stored submission does not match expected output: '%s'.", i, util.MustToJSONIndent(submission))
+			continue
+		}
+	}
+}
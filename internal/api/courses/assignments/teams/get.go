@@ -0,0 +1,27 @@
+package teams
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type GetRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+}
+
+type GetResponse struct {
+	Team *model.Team `json:"team"`
+}
+
+// Get the team that you are on for an assignment (if any).
+func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	team, err := db.GetUserTeam(request.Assignment, request.User.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-640", &request.APIRequestCourseUserContext, "Failed to get team.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	return &GetResponse{team}, nil
+}
@@ -0,0 +1,60 @@
+package teams
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGet(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email        string
+		expectedTeam string
+		locator      string
+	}{
+		{"course-student", "team-a", ""},
+		{"course-grader", "", ""},
+		{"course-admin", "", ""},
+
+		{"course-other", "", "-020"},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTeams(&model.Team{ID: "team-a", Members: []string{"course-student@test.edulinq.org"}})
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/teams/get`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent GetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expectedTeam == "" {
+			if responseContent.Team != nil {
+				test.Errorf("Case %d: Got an unexpected team: '%s'.", i, util.MustToJSONIndent(responseContent.Team))
+			}
+
+			continue
+		}
+
+		if (responseContent.Team == nil) || (responseContent.Team.ID != testCase.expectedTeam) {
+			test.Errorf("Case %d: Unexpected team. Expected: '%s', Actual: '%s'.", i, testCase.expectedTeam, util.MustToJSONIndent(responseContent.Team))
+			continue
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package teams
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+}
+
+type ListResponse struct {
+	Teams map[string]*model.Team `json:"teams"`
+}
+
+// List all the teams for an assignment (keyed by team ID).
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	teams, err := db.GetTeams(request.Assignment)
+	if err != nil {
+		return nil, core.NewInternalError("-641", &request.APIRequestCourseUserContext, "Failed to get teams.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	return &ListResponse{teams}, nil
+}
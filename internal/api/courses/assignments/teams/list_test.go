@@ -0,0 +1,60 @@
+package teams
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestList(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email   string
+		locator string
+	}{
+		{"course-grader", ""},
+		{"course-admin", ""},
+		{"server-admin", ""},
+
+		{"course-student", "-020"},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTeams(&model.Team{ID: "team-a", Members: []string{"course-student@test.edulinq.org"}})
+		db.MustSetTestStudents(db.MustGetTestCourse(), "course-other@test.edulinq.org")
+
+		err := db.SaveTeam(db.MustGetTestAssignment(), &model.Team{ID: "team-b", Members: []string{"course-other@test.edulinq.org"}})
+		if err != nil {
+			test.Fatalf("Case %d: Failed to save team: '%v'.", i, err)
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/teams/list`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		teamA := responseContent.Teams["team-a"]
+		teamB := responseContent.Teams["team-b"]
+		if (len(responseContent.Teams) != 2) || (teamA == nil) || (teamB == nil) ||
+			(teamA.Source != model.TEAM_SOURCE_CONFIG) || (teamB.Source != model.TEAM_SOURCE_API) {
+			test.Errorf("Case %d: Unexpected response: '%s'.", i, util.MustToJSONIndent(responseContent))
+			continue
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package teams
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Use the common main for all tests in this package.
+func TestMain(suite *testing.M) {
+	core.APITestingMain(suite, GetRoutes())
+}
+
+// Reset the database and turn on teams for the test assignment.
+func resetWithTeams(teams ...*model.Team) {
+	db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	assignment.Teams = &model.TeamInfo{MaxSize: 2, LMSGroupSetID: "group-set", Teams: teams}
+
+	err := assignment.Teams.Validate()
+	if err != nil {
+		panic(err)
+	}
+
+	db.MustSaveAssignment(assignment)
+}
@@ -0,0 +1,28 @@
+package teams
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+)
+
+type RemoveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	TeamID string `json:"team-id"`
+}
+
+type RemoveResponse struct {
+	FoundTeam bool `json:"found-team"`
+}
+
+// Remove a team from an assignment. Teams defined in the assignment config cannot be removed.
+func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
+	removed, err := db.RemoveTeam(request.Assignment, request.TeamID)
+	if err != nil {
+		return nil, core.NewInternalError("-644", &request.APIRequestCourseUserContext, "Failed to remove team.").
+			Err(err).Assignment(request.Assignment.GetID()).Add("team-id", request.TeamID)
+	}
+
+	return &RemoveResponse{removed}, nil
+}
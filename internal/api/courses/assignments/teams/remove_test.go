@@ -0,0 +1,84 @@
+package teams
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRemove(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email     string
+		teamID    string
+		foundTeam bool
+		locator   string
+	}{
+		{"course-admin", "team-b", true, ""},
+		{"server-admin", "team-b", true, ""},
+
+		// Config teams and missing teams are not removed.
+		{"course-admin", "team-a", false, ""},
+		{"course-admin", "zzz", false, ""},
+
+		// Invalid permissions.
+		{"course-grader", "team-b", false, "-020"},
+		{"course-student", "team-b", false, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTeams(&model.Team{ID: "team-a", Members: []string{"course-student@test.edulinq.org"}})
+		db.MustSetTestStudents(db.MustGetTestCourse(), "course-other@test.edulinq.org")
+
+		err := db.SaveTeam(db.MustGetTestAssignment(), &model.Team{ID: "team-b", Members: []string{"course-other@test.edulinq.org"}})
+		if err != nil {
+			test.Fatalf("Case %d: Failed to save team: '%v'.", i, err)
+		}
+
+		fields := map[string]any{
+			"team-id": testCase.teamID,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/teams/remove`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent RemoveResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundTeam != responseContent.FoundTeam {
+			test.Errorf("Case %d: Unexpected found team. Expected: %v, Actual: %v.", i, testCase.foundTeam, responseContent.FoundTeam)
+			continue
+		}
+
+		teams, err := db.GetTeams(db.MustGetTestAssignment())
+		if err != nil {
+			test.Errorf("Case %d: Failed to get teams: '%v'.", i, err)
+			continue
+		}
+
+		if teams["team-a"] == nil {
+			test.Errorf("Case %d: Config team was removed.", i)
+			continue
+		}
+
+		if testCase.foundTeam && (teams[testCase.teamID] != nil) {
+			test.Errorf("Case %d: Team was not removed.", i)
+			continue
+		}
+	}
+}
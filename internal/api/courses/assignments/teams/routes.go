@@ -0,0 +1,19 @@
+package teams
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/teams/get`, HandleGet),
+	core.MustNewAPIRoute(`courses/assignments/teams/list`, HandleList),
+	core.MustNewAPIRoute(`courses/assignments/teams/remove`, HandleRemove),
+	core.MustNewAPIRoute(`courses/assignments/teams/set`, HandleSet),
+	core.MustNewAPIRoute(`courses/assignments/teams/sync`, HandleSync),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
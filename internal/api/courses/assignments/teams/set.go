@@ -0,0 +1,45 @@
+package teams
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type SetRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	TeamID  string   `json:"team-id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+type SetResponse struct {
+	Team *model.Team `json:"team"`
+}
+
+// Create or replace a team for an assignment. Members must be students in the course and not already on another team.
+func HandleSet(request *SetRequest) (*SetResponse, *core.APIError) {
+	if request.Assignment.Teams == nil {
+		return nil, core.NewBadRequestError("-642", &request.APIRequest, "Assignment does not use teams.").
+			Course(request.Course.GetID()).Assignment(request.Assignment.GetID())
+	}
+
+	team := &model.Team{
+		ID:      request.TeamID,
+		Name:    request.Name,
+		Members: request.Members,
+		Source:  model.TEAM_SOURCE_API,
+	}
+
+	err := db.SaveTeam(request.Assignment, team)
+	if err != nil {
+		return nil, core.NewBadRequestError("-643", &request.APIRequest, fmt.Sprintf("Failed to save team: '%v'.", err)).
+			Err(err).Course(request.Course.GetID()).Assignment(request.Assignment.GetID()).Add("team-id", request.TeamID)
+	}
+
+	return &SetResponse{team}, nil
+}
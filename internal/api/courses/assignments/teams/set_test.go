@@ -0,0 +1,98 @@
+package teams
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSet(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email           string
+		teamID          string
+		members         []string
+		expectedMembers []string
+		locator         string
+	}{
+		// Valid teams.
+		{"course-admin", "team-b", []string{"course-other@test.edulinq.org", "course-grader@test.edulinq.org"}, []string{"course-grader@test.edulinq.org", "course-other@test.edulinq.org"}, ""},
+		{"server-admin", "team-b", []string{" COURSE-OTHER@test.edulinq.org "}, []string{"course-other@test.edulinq.org"}, ""},
+
+		// Replace a config team.
+		{"course-admin", "team-a", []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"}, []string{"course-other@test.edulinq.org", "course-student@test.edulinq.org"}, ""},
+
+		// Invalid teams.
+		{"course-admin", "team-b", []string{"course-student@test.edulinq.org"}, nil, "-643"},
+		{"course-admin", "team-b", []string{"ZZZ@test.edulinq.org"}, nil, "-643"},
+		{"course-admin", "team-b", []string{"course-admin@test.edulinq.org"}, nil, "-643"},
+		{"course-admin", "team-b", []string{"course-other@test.edulinq.org", "course-grader@test.edulinq.org", "course-admin@test.edulinq.org"}, nil, "-643"},
+		{"course-admin", "team-b", []string{}, nil, "-643"},
+		{"course-admin", "", []string{"course-other@test.edulinq.org"}, nil, "-643"},
+
+		// Invalid permissions.
+		{"course-grader", "team-b", []string{"course-other@test.edulinq.org"}, nil, "-020"},
+		{"course-student", "team-b", []string{"course-other@test.edulinq.org"}, nil, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTeams(&model.Team{ID: "team-a", Members: []string{"course-student@test.edulinq.org"}})
+		db.MustSetTestStudents(db.MustGetTestCourse(), "course-other@test.edulinq.org", "course-grader@test.edulinq.org")
+
+		fields := map[string]any{
+			"team-id": testCase.teamID,
+			"name":    "Test Team",
+			"members": testCase.members,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/teams/set`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		teams, err := db.GetTeams(db.MustGetTestAssignment())
+		if err != nil {
+			test.Errorf("Case %d: Failed to get teams: '%v'.", i, err)
+			continue
+		}
+
+		team := teams[testCase.teamID]
+		if (team == nil) || (team.Source != model.TEAM_SOURCE_API) || !reflect.DeepEqual(testCase.expectedMembers, team.Members) {
+			test.Errorf("Case %d: Unexpected saved team. Expected members: '%v', Actual: '%s'.", i, testCase.expectedMembers, util.MustToJSONIndent(team))
+			continue
+		}
+	}
+}
+
+func TestSetNoTeams(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	fields := map[string]any{
+		"team-id": "team-a",
+		"members": []string{"course-student@test.edulinq.org"},
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/teams/set`, fields, nil, "course-admin")
+	if response.Success {
+		test.Fatalf("Request did not fail on an assignment without teams.")
+	}
+
+	if response.Locator != "-642" {
+		test.Fatalf("Incorrect error returned. Expected '-642', found '%s'.", response.Locator)
+	}
+}
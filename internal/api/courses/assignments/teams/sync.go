@@ -0,0 +1,37 @@
+package teams
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/lms/lmssync"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type SyncRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	DryRun bool `json:"dry-run"`
+}
+
+type SyncResponse struct {
+	DryRun bool                  `json:"dry-run"`
+	Result *model.TeamSyncResult `json:"result"`
+}
+
+// Replace an assignment's LMS teams with the groups in the assignment's LMS group set.
+func HandleSync(request *SyncRequest) (*SyncResponse, *core.APIError) {
+	if (request.Assignment.Teams == nil) || (request.Assignment.Teams.LMSGroupSetID == "") {
+		return nil, core.NewBadRequestError("-645", &request.APIRequest, "Assignment does not have an LMS group set.").
+			Course(request.Course.GetID()).Assignment(request.Assignment.GetID())
+	}
+
+	result, err := lmssync.SyncTeams(request.Assignment, request.DryRun)
+	if err != nil {
+		return nil, core.NewInternalError("-646", &request.APIRequestCourseUserContext, fmt.Sprintf("Failed to sync teams: '%v'.", err)).
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
+	return &SyncResponse{request.DryRun, result}, nil
+}
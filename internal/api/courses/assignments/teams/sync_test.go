@@ -0,0 +1,96 @@
+package teams
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSync(test *testing.T) {
+	defer db.ResetForTesting()
+	defer lmstest.ClearGroups()
+
+	lmstest.SetGroups("group-set", []*lmstypes.Group{
+		&lmstypes.Group{ID: "1", Name: "Team One", Members: []*lmstypes.User{
+			&lmstypes.User{Email: "course-student@test.edulinq.org"},
+			&lmstypes.User{Email: "course-other@test.edulinq.org"},
+		}},
+	})
+
+	testCases := []struct {
+		email   string
+		dryRun  bool
+		locator string
+	}{
+		{"course-admin", false, ""},
+		{"course-admin", true, ""},
+		{"server-admin", false, ""},
+
+		{"course-grader", false, "-020"},
+		{"course-student", false, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		resetWithTeams()
+
+		fields := map[string]any{
+			"dry-run": testCase.dryRun,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/teams/sync`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.locator)
+			continue
+		}
+
+		var responseContent SyncResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (responseContent.DryRun != testCase.dryRun) || (responseContent.Result == nil) || (len(responseContent.Result.SyncedTeams) != 1) {
+			test.Errorf("Case %d: Unexpected response: '%s'.", i, util.MustToJSONIndent(responseContent))
+			continue
+		}
+
+		teams, err := db.GetTeams(db.MustGetTestAssignment())
+		if err != nil {
+			test.Errorf("Case %d: Failed to get teams: '%v'.", i, err)
+			continue
+		}
+
+		expectedCount := 1
+		if testCase.dryRun {
+			expectedCount = 0
+		}
+
+		if len(teams) != expectedCount {
+			test.Errorf("Case %d: Unexpected number of teams. Expected: %d, Actual: %d.", i, expectedCount, len(teams))
+			continue
+		}
+	}
+}
+
+func TestSyncNoGroupSet(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/teams/sync`, nil, nil, "course-admin")
+	if response.Success {
+		test.Fatalf("Request did not fail on an assignment without an LMS group set.")
+	}
+
+	if response.Locator != "-645" {
+		test.Fatalf("Incorrect error returned. Expected '-645', found '%s'.", response.Locator)
+	}
+}
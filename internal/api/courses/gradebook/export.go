@@ -0,0 +1,56 @@
+package gradebook
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/scoring"
+)
+
+type ExportRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	// Defaults to model.GRADEBOOK_FORMAT_GENERIC.
+	Format string `json:"format"`
+}
+
+type ExportResponse struct {
+	Format string `json:"format"`
+	CSV    string `json:"csv"`
+}
+
+// Perform a full scoring (without uploading) and export the course's scores as a gradebook CSV.
+func HandleExport(request *ExportRequest) (*ExportResponse, *core.APIError) {
+	format := strings.ToLower(strings.TrimSpace(request.Format))
+	if format == "" {
+		format = model.GRADEBOOK_FORMAT_GENERIC
+	}
+
+	err := model.ValidateGradebookFormat(format)
+	if err != nil {
+		return nil, core.NewBadRequestError("-655", &request.APIRequest, fmt.Sprintf("Invalid gradebook format: '%v'.", err)).
+			Err(err).Course(request.Course.GetID()).Add("format", format)
+	}
+
+	book, err := scoring.ComputeCourseGradebook(request.Course)
+	if err != nil {
+		return nil, core.NewInternalError("-656", &request.APIRequestCourseUserContext,
+			"Failed to compute the course gradebook.").Err(err)
+	}
+
+	text, err := book.ToCSV(format)
+	if err != nil {
+		return nil, core.NewInternalError("-657", &request.APIRequestCourseUserContext,
+			"Failed to write the course gradebook.").Err(err).Add("format", format)
+	}
+
+	response := &ExportResponse{
+		Format: format,
+		CSV:    text,
+	}
+
+	return response, nil
+}
@@ -0,0 +1,78 @@
+package gradebook
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestExport(test *testing.T) {
+	testCases := []struct {
+		email    string
+		format   string
+		expected *ExportResponse
+		locator  string
+	}{
+		{
+			"course-admin", "",
+			&ExportResponse{
+				Format: "generic",
+				CSV: "email,name,lms-id,hw0\n" +
+					"course-student@test.edulinq.org,course-student,lms-course-student@test.edulinq.org,2\n",
+			},
+			"",
+		},
+		{
+			"course-owner", "Canvas",
+			&ExportResponse{
+				Format: "canvas",
+				CSV: "Student,ID,SIS User ID,SIS Login ID,Section,Homework 0\n" +
+					"\"    Points Possible\",,,,,0\n" +
+					"course-student,lms-course-student@test.edulinq.org,,course-student@test.edulinq.org,,2\n",
+			},
+			"",
+		},
+
+		// Bad format.
+		{"course-admin", "zzz", nil, "-655"},
+
+		// Perm errors.
+		{"course-grader", "", nil, "-020"},
+		{"course-student", "", nil, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"format": testCase.format,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/gradebook/export`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != "" {
+				if response.Locator != testCase.locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ExportResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !reflect.DeepEqual(testCase.expected, &responseContent) {
+			test.Errorf("Case %d: Unexpected result. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(responseContent))
+			continue
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package scores
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/report"
+)
+
+type ReconcileRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+}
+
+type ReconcileResponse struct {
+	// True if any student has a conflict (e.g., an LMS score that was edited outside the autograder).
+	HasConflicts bool                               `json:"has-conflicts"`
+	Report       *report.CourseReconciliationReport `json:"report"`
+}
+
+// Compare the course's computed scores against the scores in the course's LMS (without uploading).
+func HandleReconcile(request *ReconcileRequest) (*ReconcileResponse, *core.APIError) {
+	reconciliationReport, err := report.GetCourseReconciliationReport(request.Course)
+	if err != nil {
+		return nil, core.NewInternalError("-658", &request.APIRequestCourseUserContext,
+			"Failed to reconcile course scores with the LMS.").Err(err)
+	}
+
+	response := &ReconcileResponse{
+		HasConflicts: reconciliationReport.HasConflicts(),
+		Report:       reconciliationReport,
+	}
+
+	return response, nil
+}
@@ -0,0 +1,79 @@
+package scores
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/scoring"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestLMSScoresReconcile(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer lmstest.ClearAssignmentScores()
+
+	// Set the assignment's LMS ID.
+	course := db.MustGetTestCourse()
+	course.Assignments["hw0"].LMSID = "001"
+	db.MustSaveCourse(course)
+
+	lmstest.SetAssignmentScores("001", []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID: "lms-course-student@test.edulinq.org",
+			Score:  1,
+		},
+	})
+
+	testCases := []struct {
+		email   string
+		locator string
+	}{
+		{"course-admin", ""},
+		{"course-owner", ""},
+		{"course-grader", "-020"},
+		{"course-student", "-020"},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, `courses/lms/scores/reconcile`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != "" {
+				if response.Locator != testCase.locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ReconcileResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !responseContent.HasConflicts {
+			test.Errorf("Case %d: Did not find an expected conflict.", i)
+			continue
+		}
+
+		assignments := responseContent.Report.Assignments
+		if (len(assignments) != 1) || (len(assignments[0].Results) != 1) {
+			test.Errorf("Case %d: Unexpected report: '%s'.", i, util.MustToJSONIndent(responseContent.Report))
+			continue
+		}
+
+		if assignments[0].Results[0].Status != scoring.ReconcileStatusLMSEdited {
+			test.Errorf("Case %d: Unexpected status. Expected: '%s', Actual: '%s'.", i, scoring.ReconcileStatusLMSEdited, assignments[0].Results[0].Status)
+			continue
+		}
+	}
+}
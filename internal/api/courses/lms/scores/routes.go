@@ -7,6 +7,7 @@ import (
 )
 
 var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/lms/scores/reconcile`, HandleReconcile),
 	core.MustNewAPIRoute(`courses/lms/scores/upload`, HandleUpload),
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/api/courses/admin"
 	"github.com/edulinq/autograder/internal/api/courses/assignments"
+	"github.com/edulinq/autograder/internal/api/courses/gradebook"
 	"github.com/edulinq/autograder/internal/api/courses/lms"
 	"github.com/edulinq/autograder/internal/api/courses/stats"
 	"github.com/edulinq/autograder/internal/api/courses/upsert"
@@ -17,6 +18,7 @@ func GetRoutes() *[]core.Route {
 
 	routes = append(routes, *(admin.GetRoutes())...)
 	routes = append(routes, *(assignments.GetRoutes())...)
+	routes = append(routes, *(gradebook.GetRoutes())...)
 	routes = append(routes, *(lms.GetRoutes())...)
 	routes = append(routes, *(stats.GetRoutes())...)
 	routes = append(routes, *(upsert.GetRoutes())...)
@@ -0,0 +1,54 @@
+package lti
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/lms/ltilaunch"
+	"github.com/edulinq/autograder/internal/log"
+)
+
+const LAUNCH_TARGET = "/static/index.html"
+
+// Handle a launch (id_token) posted by a platform.
+// The launch's state must match the state cookie set when the login started.
+// On success, the user is redirected to the web UI with credentials in the URL fragment
+// (so they are never sent to the server or included in referrers).
+func HandleLaunch(response http.ResponseWriter, request *http.Request) error {
+	browserState := ""
+	cookie, err := request.Cookie(STATE_COOKIE_NAME)
+	if err == nil {
+		browserState = cookie.Value
+	}
+
+	// The state can only be used once.
+	http.SetCookie(response, &http.Cookie{
+		Name:     STATE_COOKIE_NAME,
+		Path:     core.MakeFullAPIPath(`lti/launch`),
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	})
+
+	result, err := ltilaunch.Launch(request.PostFormValue("id_token"), request.PostFormValue("state"), browserState)
+	if err != nil {
+		log.Warn("Bad LTI launch.", err)
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	fragment := url.Values{}
+	fragment.Set("user-email", result.Email)
+	fragment.Set("user-token", result.TokenCleartext)
+	fragment.Set("course-id", result.Course.GetID())
+
+	if result.Assignment != nil {
+		fragment.Set("assignment-id", result.Assignment.GetID())
+	}
+
+	// Redirect with a GET (the launch was a POST).
+	http.Redirect(response, request, LAUNCH_TARGET+"#"+fragment.Encode(), http.StatusSeeOther)
+	return nil
+}
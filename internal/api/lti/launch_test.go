@@ -0,0 +1,19 @@
+package lti
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// Successful launches are tested in the ltilaunch package.
+func TestLaunchBadRequest(test *testing.T) {
+	form := url.Values{}
+	form.Set("id_token", "a.b.c")
+	form.Set("state", "unknown-state")
+
+	response := serveForm("POST", "lti/launch", form)
+	if response.Code != http.StatusBadRequest {
+		test.Fatalf("Unexpected status. Expected: %d, Actual: %d.", http.StatusBadRequest, response.Code)
+	}
+}
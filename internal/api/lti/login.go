@@ -0,0 +1,49 @@
+package lti
+
+import (
+	"net/http"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/lms/ltilaunch"
+	"github.com/edulinq/autograder/internal/log"
+)
+
+// The cookie that binds a login's state to the user's browser.
+const STATE_COOKIE_NAME = "autograder-lti-state"
+
+// Handle an OIDC login initiation from a platform (sent as either a GET or POST).
+// The user is redirected back to the platform to complete the login.
+// The target link URI must be this server's launch endpoint.
+func HandleLogin(response http.ResponseWriter, request *http.Request) error {
+	loginRequest := &ltilaunch.LoginRequest{
+		Issuer:        request.FormValue("iss"),
+		LoginHint:     request.FormValue("login_hint"),
+		TargetLinkURI: request.FormValue("target_link_uri"),
+		MessageHint:   request.FormValue("lti_message_hint"),
+		ClientID:      request.FormValue("client_id"),
+		DeploymentID:  request.FormValue("lti_deployment_id"),
+		LaunchHost:    request.Host,
+		LaunchPath:    core.MakeFullAPIPath(`lti/launch`),
+	}
+
+	target, state, err := ltilaunch.Login(loginRequest)
+	if err != nil {
+		log.Warn("Bad LTI login.", err, log.NewAttr("iss", loginRequest.Issuer))
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	// The launch is a cross-site POST from the platform, so the cookie must be SameSite=None.
+	http.SetCookie(response, &http.Cookie{
+		Name:     STATE_COOKIE_NAME,
+		Value:    state,
+		Path:     loginRequest.LaunchPath,
+		MaxAge:   int(ltilaunch.LOGIN_LIFETIME_MSECS / 1000),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	})
+
+	http.Redirect(response, request, target, http.StatusFound)
+	return nil
+}
@@ -0,0 +1,115 @@
+package lti
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+const (
+	TEST_ISSUER   = "https://lti.test.com"
+	TEST_AUTH_URL = TEST_ISSUER + "/api/lti/authorize_redirect"
+	TEST_HOST     = "autograder.test.com"
+)
+
+func TestLogin(test *testing.T) {
+	defer db.ResetForTesting()
+
+	course := db.MustGetCourse("course101")
+	course.LMS = &model.LMSAdapter{
+		Type: model.LMS_TYPE_LTI,
+		LTI: &model.LTIPlatformInfo{
+			ClientID:     "10000000000001",
+			TokenURL:     TEST_ISSUER + "/login/oauth2/token",
+			PrivateKey:   "unused",
+			LineItemsURL: TEST_ISSUER + "/api/lti/courses/12345/line_items",
+			Issuer:       TEST_ISSUER,
+			AuthURL:      TEST_AUTH_URL,
+			JWKSURL:      TEST_ISSUER + "/api/lti/security/jwks",
+		},
+	}
+	db.MustSaveCourse(course)
+
+	form := url.Values{}
+	form.Set("iss", TEST_ISSUER)
+	form.Set("login_hint", "hint")
+	form.Set("target_link_uri", "https://"+TEST_HOST+core.MakeFullAPIPath("lti/launch"))
+
+	otherTargetForm := url.Values{}
+	otherTargetForm.Set("iss", TEST_ISSUER)
+	otherTargetForm.Set("login_hint", "hint")
+	otherTargetForm.Set("target_link_uri", "https://evil.test.com"+core.MakeFullAPIPath("lti/launch"))
+
+	testCases := []struct {
+		method         string
+		form           url.Values
+		expectedStatus int
+	}{
+		{"GET", form, http.StatusFound},
+		{"POST", form, http.StatusFound},
+		{"GET", url.Values{"iss": []string{"https://other.test.com"}}, http.StatusBadRequest},
+		{"GET", url.Values{}, http.StatusBadRequest},
+		{"GET", otherTargetForm, http.StatusBadRequest},
+	}
+
+	for i, testCase := range testCases {
+		response := serveForm(testCase.method, "lti/login", testCase.form)
+
+		if response.Code != testCase.expectedStatus {
+			test.Errorf("Case %d: Unexpected status. Expected: %d, Actual: %d.", i, testCase.expectedStatus, response.Code)
+			continue
+		}
+
+		if testCase.expectedStatus != http.StatusFound {
+			continue
+		}
+
+		location := response.Header().Get("Location")
+		if !strings.HasPrefix(location, TEST_AUTH_URL+"?") {
+			test.Errorf("Case %d: Unexpected redirect: '%s'.", i, location)
+			continue
+		}
+
+		parsed, err := url.Parse(location)
+		if err != nil {
+			test.Errorf("Case %d: Failed to parse redirect '%s': '%v'.", i, location, err)
+			continue
+		}
+
+		cookies := response.Result().Cookies()
+		if len(cookies) != 1 {
+			test.Errorf("Case %d: Unexpected number of cookies. Expected: 1, Actual: %d.", i, len(cookies))
+			continue
+		}
+
+		cookie := cookies[0]
+		if (cookie.Name != STATE_COOKIE_NAME) || (cookie.Value != parsed.Query().Get("state")) ||
+			!cookie.Secure || !cookie.HttpOnly || (cookie.SameSite != http.SameSiteNoneMode) {
+			test.Errorf("Case %d: Unexpected state cookie: '%s'.", i, cookie.String())
+			continue
+		}
+	}
+}
+
+func serveForm(method string, basePath string, form url.Values) *httptest.ResponseRecorder {
+	var request *http.Request
+	if method == "GET" {
+		request = httptest.NewRequest(method, core.MakeFullAPIPath(basePath)+"?"+form.Encode(), nil)
+	} else {
+		request = httptest.NewRequest(method, core.MakeFullAPIPath(basePath), strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	request.Host = TEST_HOST
+
+	response := httptest.NewRecorder()
+	core.ServeRoutes(GetRoutes(), response, request)
+
+	return response
+}
@@ -0,0 +1,18 @@
+package lti
+
+// All the routes handled by this package.
+// These are not API endpoints, since they are called by an LTI platform (via the user's browser).
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.NewBaseRoute("GET", `lti/login`, HandleLogin),
+	core.NewBaseRoute("POST", `lti/login`, HandleLogin),
+	core.NewBaseRoute("POST", `lti/launch`, HandleLaunch),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
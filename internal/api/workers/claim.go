@@ -0,0 +1,39 @@
+package workers
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ClaimRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+
+	WorkerID core.NonEmptyString `json:"worker-id"`
+
+	// The hashes of the images that the worker has already built.
+	KnownImages []string `json:"known-images"`
+}
+
+type ClaimResponse struct {
+	// If false, the server does not know this worker (e.g., it was considered dead) and it should register again.
+	Known bool `json:"known"`
+
+	FoundTask bool                     `json:"found-task"`
+	Task      *model.RemoteGradingTask `json:"task"`
+}
+
+// Claim the next grading task for a remote grading worker.
+// If no task is available, the request will wait a short time for one before returning.
+func HandleClaim(request *ClaimRequest) (*ClaimResponse, *core.APIError) {
+	task, known := grader.ClaimRemoteTask(request.Context, string(request.WorkerID), request.KnownImages)
+
+	response := ClaimResponse{
+		Known:     known,
+		FoundTask: (task != nil),
+		Task:      task,
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,152 @@
+package workers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Run a full grading through the API (acting as the worker).
+func TestClaimAndComplete(test *testing.T) {
+	grader.ResetRemoteWorkersForTesting()
+	defer grader.ResetRemoteWorkersForTesting()
+
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	workerID := grader.RegisterRemoteWorker("test-worker").ID
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	submissionDir := filepath.Join(config.GetTestdataDir(), "course-languages", "bash", "test-submissions", "solution")
+
+	options := grader.GetDefaultGradeOptions()
+	options.Remote = true
+
+	type gradeOutput struct {
+		result    *model.GradingResult
+		softError string
+		err       error
+	}
+
+	done := make(chan gradeOutput, 1)
+	go func() {
+		result, _, softError, err := grader.Grade(context.Background(), assignment, submissionDir, "course-student@test.edulinq.org", "", false, options)
+		done <- gradeOutput{result, softError, err}
+	}()
+
+	fields := map[string]any{
+		"worker-id": workerID,
+	}
+
+	response := core.SendTestAPIRequestFull(test, `workers/claim`, fields, nil, "server-admin")
+	if !response.Success {
+		test.Fatalf("Claim response is not a success when it should be: '%v'.", response)
+	}
+
+	var claimContent ClaimResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &claimContent)
+
+	if !claimContent.Known || !claimContent.FoundTask || (claimContent.Task == nil) {
+		test.Fatalf("Did not claim a task: '%s'.", util.MustToJSONIndent(claimContent))
+	}
+
+	task := claimContent.Task
+
+	err := task.Validate()
+	if err != nil {
+		test.Fatalf("Claimed task is not valid: '%v'.", err)
+	}
+
+	if task.ImageContextGZip == nil {
+		test.Fatalf("Task does not have an image context.")
+	}
+
+	_, ok := task.InputFilesGZip["assignment.sh"]
+	if !ok {
+		test.Fatalf("Task does not have the submitted files: '%v'.", util.MustToJSON(task.InputFilesGZip))
+	}
+
+	// Fake the grader's output.
+	outputDir := util.MustMkDirTemp("test-workers-claim-")
+	defer util.RemoveDirent(outputDir)
+
+	gradingResult := `{"name": "bash", "questions": [{"name": "Q1", "max_points": 10, "score": 7}]}`
+	err = util.WriteFile(gradingResult, filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME))
+	if err != nil {
+		test.Fatalf("Failed to write result: '%v'.", err)
+	}
+
+	outputFiles, err := util.GzipDirectoryToBytes(outputDir)
+	if err != nil {
+		test.Fatalf("Failed to gzip output: '%v'.", err)
+	}
+
+	result := model.RemoteGradingResult{
+		TaskID:          task.ID,
+		OutputFilesGZip: outputFiles,
+		Stdout:          "test stdout",
+	}
+
+	fields = map[string]any{
+		"worker-id": workerID,
+		"result":    result,
+	}
+
+	response = core.SendTestAPIRequestFull(test, `workers/complete`, fields, nil, "server-admin")
+	if !response.Success {
+		test.Fatalf("Complete response is not a success when it should be: '%v'.", response)
+	}
+
+	var completeContent CompleteResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &completeContent)
+
+	if !completeContent.Accepted {
+		test.Fatalf("Result was not accepted.")
+	}
+
+	output := <-done
+	if output.err != nil {
+		test.Fatalf("Failed to grade: '%v'.", output.err)
+	}
+
+	if output.softError != "" {
+		test.Fatalf("Got a soft error: '%s'.", output.softError)
+	}
+
+	if (output.result.Info.Score != 7) || (output.result.Info.MaxPoints != 10) {
+		test.Fatalf("Unexpected score: %f / %f.", output.result.Info.Score, output.result.Info.MaxPoints)
+	}
+
+	if output.result.Stdout != "test stdout" {
+		test.Fatalf("Unexpected stdout: '%s'.", output.result.Stdout)
+	}
+}
+
+func TestClaimUnknownWorker(test *testing.T) {
+	grader.ResetRemoteWorkersForTesting()
+	defer grader.ResetRemoteWorkersForTesting()
+
+	fields := map[string]any{
+		"worker-id": "not-a-worker",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `workers/claim`, fields, nil, "server-admin")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent ClaimResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if responseContent.Known || responseContent.FoundTask {
+		test.Fatalf("Unexpected response for an unknown worker: '%s'.", util.MustToJSONIndent(responseContent))
+	}
+}
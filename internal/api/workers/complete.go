@@ -0,0 +1,35 @@
+package workers
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type CompleteRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+
+	WorkerID core.NonEmptyString `json:"worker-id"`
+
+	Result *model.RemoteGradingResult `json:"result"`
+}
+
+type CompleteResponse struct {
+	// If false, the result was ignored (e.g., the task was already given to another worker).
+	Accepted bool `json:"accepted"`
+}
+
+// Send the result of a grading task from a remote grading worker.
+func HandleComplete(request *CompleteRequest) (*CompleteResponse, *core.APIError) {
+	accepted, err := grader.CompleteRemoteTask(string(request.WorkerID), request.Result)
+	if err != nil {
+		return nil, core.NewBadRequestError("-701", &request.APIRequest, "Invalid remote grading result.").Err(err)
+	}
+
+	response := CompleteResponse{
+		Accepted: accepted,
+	}
+
+	return &response, nil
+}
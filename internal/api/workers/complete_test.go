@@ -0,0 +1,61 @@
+package workers
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestCompleteBase(test *testing.T) {
+	grader.ResetRemoteWorkersForTesting()
+	defer grader.ResetRemoteWorkersForTesting()
+
+	workerID := grader.RegisterRemoteWorker("test-worker").ID
+
+	testCases := []struct {
+		result           *model.RemoteGradingResult
+		expectedLocator  string
+		expectedAccepted bool
+	}{
+		// Unknown tasks are ignored.
+		{&model.RemoteGradingResult{TaskID: "not-a-task"}, "", false},
+
+		{nil, "-701", false},
+		{&model.RemoteGradingResult{}, "-701", false},
+		{&model.RemoteGradingResult{TaskID: "not-a-task", OutputFilesGZip: map[string][]byte{"../result.json": []byte("")}}, "-701", false},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"worker-id": workerID,
+			"result":    testCase.result,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `workers/complete`, fields, nil, "server-admin")
+		if !response.Success {
+			if testCase.expectedLocator == "" {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			} else if testCase.expectedLocator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.expectedLocator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.expectedLocator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.expectedLocator)
+			continue
+		}
+
+		var responseContent CompleteResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expectedAccepted != responseContent.Accepted {
+			test.Errorf("Case %d: Unexpected accepted value. Expected: '%v', Actual: '%v'.", i, testCase.expectedAccepted, responseContent.Accepted)
+			continue
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package workers
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+)
+
+type HeartbeatRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+
+	WorkerID core.NonEmptyString `json:"worker-id"`
+}
+
+type HeartbeatResponse struct {
+	// If false, the server does not know this worker (e.g., it was considered dead) and it should register again.
+	Known bool `json:"known"`
+}
+
+// Let the server know that a remote grading worker is still alive.
+func HandleHeartbeat(request *HeartbeatRequest) (*HeartbeatResponse, *core.APIError) {
+	response := HeartbeatResponse{
+		Known: grader.RemoteWorkerHeartbeat(string(request.WorkerID)),
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,44 @@
+package workers
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestHeartbeat(test *testing.T) {
+	grader.ResetRemoteWorkersForTesting()
+	defer grader.ResetRemoteWorkersForTesting()
+
+	workerID := grader.RegisterRemoteWorker("test-worker").ID
+
+	testCases := []struct {
+		workerID      string
+		expectedKnown bool
+	}{
+		{workerID, true},
+		{"not-a-worker", false},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"worker-id": testCase.workerID,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `workers/heartbeat`, fields, nil, "server-admin")
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent HeartbeatResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expectedKnown != responseContent.Known {
+			test.Errorf("Case %d: Unexpected known value. Expected: '%v', Actual: '%v'.", i, testCase.expectedKnown, responseContent.Known)
+			continue
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package workers
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+}
+
+type ListResponse struct {
+	Workers []*model.RemoteWorkerInfo `json:"workers"`
+}
+
+// List the live remote grading workers.
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	response := ListResponse{
+		Workers: grader.GetRemoteWorkers(),
+	}
+
+	return &response, nil
+}
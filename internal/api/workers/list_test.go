@@ -0,0 +1,51 @@
+package workers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestList(test *testing.T) {
+	grader.ResetRemoteWorkersForTesting()
+	defer grader.ResetRemoteWorkersForTesting()
+
+	response := core.SendTestAPIRequestFull(test, `workers/list`, nil, nil, "server-admin")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent ListResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if len(responseContent.Workers) != 0 {
+		test.Fatalf("Unexpected workers: '%s'.", util.MustToJSONIndent(responseContent.Workers))
+	}
+
+	grader.RegisterRemoteWorker("worker-1")
+	grader.RegisterRemoteWorker("worker-2")
+
+	expected := grader.GetRemoteWorkers()
+
+	response = core.SendTestAPIRequestFull(test, `workers/list`, nil, nil, "server-admin")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	responseContent = ListResponse{}
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	// Heartbeats are not sent here, so the workers should not change.
+	if !reflect.DeepEqual(expected, responseContent.Workers) {
+		test.Fatalf("Unexpected workers. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(responseContent.Workers))
+	}
+
+	response = core.SendTestAPIRequestFull(test, `workers/list`, nil, nil, "server-user")
+	if response.Success {
+		test.Fatalf("Non-admin was able to list workers.")
+	}
+}
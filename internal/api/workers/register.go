@@ -0,0 +1,38 @@
+package workers
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/grader"
+)
+
+type RegisterRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+
+	// A human-readable name for the worker (e.g., its hostname).
+	Name string `json:"name"`
+}
+
+type RegisterResponse struct {
+	WorkerID string `json:"worker-id"`
+
+	// How often the worker should send heartbeats.
+	HeartbeatSecs int `json:"heartbeat-secs"`
+}
+
+// Register a remote grading worker.
+func HandleRegister(request *RegisterRequest) (*RegisterResponse, *core.APIError) {
+	if !config.GRADING_REMOTE_ENABLE.Get() {
+		return nil, core.NewBadRequestError("-700", &request.APIRequest, "Remote grading is not enabled on this server.")
+	}
+
+	worker := grader.RegisterRemoteWorker(request.Name)
+
+	response := RegisterResponse{
+		WorkerID:      worker.ID,
+		HeartbeatSecs: max(1, config.GRADING_REMOTE_TIMEOUT_SECS.Get()/3),
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,71 @@
+package workers
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRegister(test *testing.T) {
+	grader.ResetRemoteWorkersForTesting()
+	defer grader.ResetRemoteWorkersForTesting()
+
+	oldValue := config.GRADING_REMOTE_ENABLE.Get()
+	defer config.GRADING_REMOTE_ENABLE.Set(oldValue)
+
+	testCases := []struct {
+		email           string
+		enabled         bool
+		expectedLocator string
+	}{
+		{"server-admin", true, ""},
+		{"server-admin", false, "-700"},
+		{"server-user", true, "-041"},
+		{"course-admin", true, "-041"},
+	}
+
+	for i, testCase := range testCases {
+		config.GRADING_REMOTE_ENABLE.Set(testCase.enabled)
+
+		fields := map[string]any{
+			"name": "test-worker",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `workers/register`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.expectedLocator == "" {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			} else if testCase.expectedLocator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.expectedLocator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.expectedLocator != "" {
+			test.Errorf("Case %d: Did not get an expected error ('%s').", i, testCase.expectedLocator)
+			continue
+		}
+
+		var responseContent RegisterResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if responseContent.WorkerID == "" {
+			test.Errorf("Case %d: Got an empty worker ID.", i)
+			continue
+		}
+
+		if responseContent.HeartbeatSecs < 1 {
+			test.Errorf("Case %d: Got a bad heartbeat interval: %d.", i, responseContent.HeartbeatSecs)
+			continue
+		}
+
+		if !grader.RemoteWorkerHeartbeat(responseContent.WorkerID) {
+			test.Errorf("Case %d: Worker was not registered.", i)
+			continue
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package workers
+
+// All the API endpoints handled by this package.
+// These endpoints make up the protocol used by remote grading workers (see cmd/grading-worker).
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`workers/claim`, HandleClaim),
+	core.MustNewAPIRoute(`workers/complete`, HandleComplete),
+	core.MustNewAPIRoute(`workers/heartbeat`, HandleHeartbeat),
+	core.MustNewAPIRoute(`workers/list`, HandleList),
+	core.MustNewAPIRoute(`workers/register`, HandleRegister),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -94,10 +94,13 @@ func SendCMDRequest(endpoint string, request any) (core.APIResponse, error) {
 		return core.APIResponse{}, fmt.Errorf("Failed to read the response from the unix socket: '%w'.", err)
 	}
 
-	var response core.APIResponse
-	util.MustJSONFromBytes(responseBuffer, &response)
+	// Stream endpoints send events before their response, only the final response is kept.
+	_, response, err := core.ParseAPIStream(string(responseBuffer))
+	if err != nil {
+		return core.APIResponse{}, fmt.Errorf("Failed to parse the response from the unix socket: '%w'.", err)
+	}
 
-	return response, nil
+	return *response, nil
 }
 
 func PrintCMDResponse(request any, response core.APIResponse, responseType any) {
@@ -24,11 +24,28 @@ type SavedHTTPRequest struct {
 	Method         string
 	RequestHeaders map[string][]string
 
+	// The (form) body of the request, if it had one.
+	RequestBody string
+
 	ResponseCode    int
 	ResponseHeaders map[string][]string
 	ResponseBody    string
 }
 
+// The error returned when a request gets a non-OK (non-2XX) response.
+type HTTPStatusError struct {
+	URL  string
+	Verb string
+
+	Code    int
+	Headers map[string][]string
+	Body    string
+}
+
+func (this *HTTPStatusError) Error() string {
+	return fmt.Sprintf("Got a non-OK status code '%d' from %s on URL '%s'.", this.Code, this.Verb, this.URL)
+}
+
 // Get a binary response.
 func RawGet(uri string) ([]byte, error) {
 	response, err := http.Get(uri)
@@ -101,6 +118,25 @@ func PutWithHeaders(uri string, form map[string]string, headers map[string][]str
 	return postPutWithHeaders("PUT", uri, form, headers, true)
 }
 
+// Send a raw body (e.g., JSON) instead of a form.
+// Returns: (body, headers (response), error)
+func PostBodyWithHeaders(uri string, body string, contentType string, headers map[string][]string) (string, map[string][]string, error) {
+	request, err := http.NewRequest("POST", uri, strings.NewReader(body))
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to create POST request on URL '%s': '%w'.", uri, err)
+	}
+
+	request.Header.Add("Content-Type", contentType)
+
+	for key, values := range headers {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	return doRequest(uri, request, "POST", true)
+}
+
 func postPutWithHeaders(verb string, uri string, form map[string]string, headers map[string][]string, checkResult bool) (string, map[string][]string, error) {
 	formValues := url.Values{}
 	for key, value := range form {
@@ -170,6 +206,21 @@ func PostFiles(uri string, form map[string]string, paths []string, checkResult b
 func doRequest(uri string, request *http.Request, verb string, checkResult bool) (string, map[string][]string, error) {
 	client := http.Client{}
 
+	requestBody := ""
+	if (config.STORE_HTTP.Get() != "") && (request.GetBody != nil) {
+		bodyReader, err := request.GetBody()
+		if err != nil {
+			return "", nil, fmt.Errorf("Failed to get body of %s request on URL '%s': '%w'.", verb, uri, err)
+		}
+
+		rawRequestBody, err := io.ReadAll(bodyReader)
+		if err != nil {
+			return "", nil, fmt.Errorf("Failed to read body of %s request on URL '%s': '%w'.", verb, uri, err)
+		}
+
+		requestBody = string(rawRequestBody)
+	}
+
 	response, err := client.Do(request)
 	if err != nil {
 		return "", nil, fmt.Errorf("Failed to perform %s request on URL '%s': '%w'.", verb, uri, err)
@@ -187,6 +238,7 @@ func doRequest(uri string, request *http.Request, verb string, checkResult bool)
 			URL:             uri,
 			Method:          request.Method,
 			RequestHeaders:  request.Header,
+			RequestBody:     requestBody,
 			ResponseCode:    response.StatusCode,
 			ResponseHeaders: response.Header,
 			ResponseBody:    body,
@@ -198,11 +250,18 @@ func doRequest(uri string, request *http.Request, verb string, checkResult bool)
 		}
 	}
 
-	if checkResult && (response.StatusCode != http.StatusOK) {
+	// Some APIs respond to successful requests with other 2XX codes (e.g., 204 No Content).
+	if checkResult && ((response.StatusCode < 200) || (response.StatusCode >= 300)) {
 		log.Error("Got a non-OK status.",
 			log.NewAttr("code", response.StatusCode), log.NewAttr("body", body),
 			log.NewAttr("headers", response.Header), log.NewAttr("url", uri))
-		return "", nil, fmt.Errorf("Got a non-OK status code '%d' from %s on URL '%s'.", response.StatusCode, verb, uri)
+		return "", nil, &HTTPStatusError{
+			URL:     uri,
+			Verb:    verb,
+			Code:    response.StatusCode,
+			Headers: response.Header,
+			Body:    body,
+		}
 	}
 
 	return body, response.Header, nil
@@ -0,0 +1,113 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Start a server that replays saved HTTP requests (see SavedHTTPRequest),
+// e.g., requests recorded against a real LMS.
+// Every JSON file in the passed in file system is loaded as a saved request.
+// Requests are matched on their method, path, query, and body (the host is ignored).
+// Saved requests without a body match any body.
+// The caller is responsible for closing the server.
+func StartSavedHTTPServer(requestsFS fs.FS) (*httptest.Server, error) {
+	requests, err := loadSavedHTTPRequests(requestsFS)
+	if err != nil {
+		return nil, err
+	}
+
+	return httptest.NewServer(&savedHTTPHandler{requests}), nil
+}
+
+type savedHTTPHandler struct {
+	requests map[string]*SavedHTTPRequest
+}
+
+func (this *savedHTTPHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Error("Failed to read HTTP request body.", err)
+		http.Error(response, "Failed to read request body.", http.StatusBadRequest)
+		return
+	}
+
+	key := getSavedHTTPRequestKey(request.Method, request.URL, string(body))
+	savedRequest := this.requests[key]
+	if savedRequest == nil {
+		key = getSavedHTTPRequestKey(request.Method, request.URL, "")
+		savedRequest = this.requests[key]
+	}
+
+	if savedRequest == nil {
+		log.Error("Could not find saved HTTP request.", log.NewAttr("key", key))
+		http.NotFound(response, request)
+		return
+	}
+
+	for key, value := range savedRequest.ResponseHeaders {
+		response.Header()[key] = value
+	}
+
+	response.WriteHeader(savedRequest.ResponseCode)
+	_, err = response.Write([]byte(savedRequest.ResponseBody))
+	if err != nil {
+		log.Error("Failed to write saved HTTP response.", err, log.NewAttr("key", key))
+	}
+}
+
+func loadSavedHTTPRequests(requestsFS fs.FS) (map[string]*SavedHTTPRequest, error) {
+	requests := make(map[string]*SavedHTTPRequest)
+
+	err := fs.WalkDir(requestsFS, ".", func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		if !strings.HasSuffix(info.Name(), ".json") {
+			return nil
+		}
+
+		data, err := fs.ReadFile(requestsFS, path)
+		if err != nil {
+			return fmt.Errorf("Failed to read saved HTTP request '%s': '%w'.", path, err)
+		}
+
+		var request SavedHTTPRequest
+		err = util.JSONFromString(string(data), &request)
+		if err != nil {
+			return fmt.Errorf("Failed to JSON parse saved HTTP request '%s': '%w'.", path, err)
+		}
+
+		uri, err := url.Parse(request.URL)
+		if err != nil {
+			return fmt.Errorf("Failed to parse saved HTTP request URL '%s': '%w'.", request.URL, err)
+		}
+
+		requests[getSavedHTTPRequestKey(request.Method, uri, request.RequestBody)] = &request
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to walk saved HTTP requests: '%w'.", err)
+	}
+
+	return requests, nil
+}
+
+func getSavedHTTPRequestKey(method string, uri *url.URL, body string) string {
+	return fmt.Sprintf("%s::%s?%s::%s", method, uri.Path, uri.RawQuery, body)
+}
@@ -38,7 +38,19 @@ var (
 	DOCKER_MAX_OUTPUT_SIZE_KB = MustNewIntOption("docker.output.maxsize", 4*1024, "The maximum allowed size (in KB) for stdout and stderr combined. The default is 4096 KB (4 MB).")
 
 	// Grading
-	GRADING_RUNTIME_MAX_SECS = MustNewIntOption("grading.runtime.max", 60*5, "The maximum number of seconds a Docker container can be running for.")
+	GRADING_RUNTIME_MAX_SECS    = MustNewIntOption("grading.runtime.max", 60*5, "The maximum number of seconds a Docker container can be running for.")
+	GRADING_MEMORY_MAX_MB       = MustNewIntOption("grading.memory.max", 2*1024, "The maximum amount of memory (in MB) a grader can use. A value <= 0 means there is no server limit.")
+	GRADING_CPUS_MAX            = MustNewFloatOption("grading.cpus.max", 0.0, "The maximum number of CPUs a Docker grader can use. A value <= 0 means there is no server limit.")
+	GRADING_PIDS_MAX            = MustNewIntOption("grading.pids.max", 1024, "The maximum number of processes/threads a grader can have. A value <= 0 means there is no server limit.")
+	GRADING_TMPFS_MAX_MB        = MustNewIntOption("grading.tmpfs.max", 0, "The maximum size (in MB) of the in-memory /tmp directory for a Docker grader. A value <= 0 means there is no server limit.")
+	GRADING_OUTPUT_MAX_MB       = MustNewIntOption("grading.output.max", 256, "The maximum total size (in MB) of the files a grader can output (checked after the grader finishes). A value <= 0 means there is no server limit.")
+	GRADING_QUEUE_WORKERS       = MustNewIntOption("grading.queue.workers", 2, "The number of asynchronous grading jobs that can be graded at the same time.")
+	GRADING_MAX_CONCURRENT      = MustNewIntOption("grading.concurrency.max", 0, "The maximum number of graders (across all courses) that can run at the same time. A value <= 0 means the number of CPUs.")
+	GRADING_MAX_COURSE          = MustNewIntOption("grading.concurrency.course", 0, "The maximum number of graders for a single course that can run at the same time. A value <= 0 means there is no per-course limit.")
+	GRADING_REMOTE_ENABLE       = MustNewBoolOption("grading.remote.enable", false, "Run graders on remote grading workers (see cmd/grading-worker) instead of on this server.")
+	GRADING_REMOTE_TIMEOUT_SECS = MustNewIntOption("grading.remote.timeout", 30, "The number of seconds without a heartbeat before a remote grading worker is considered dead (and its tasks are reassigned).")
+	GRADING_REMOTE_MAX_ATTEMPTS = MustNewIntOption("grading.remote.attempts", 3, "The maximum number of remote grading workers that a single grading task will be tried on.")
+	GRADING_REGRADE_WORKERS     = MustNewIntOption("grading.regrade.workers", 1, "The number of users whose submissions can be regraded at the same time during a regrade.")
 
 	// Tasks
 	NO_TASKS             = MustNewBoolOption("tasks.disable", false, "Disable all scheduled tasks.")
@@ -51,9 +63,17 @@ var (
 	WEB_STATIC_ROOT      = MustNewStringOption("web.static.root", "", "The root directory to serve as part of the static portion of the API. Defaults to empty string, which indicates the embedded static directory.")
 	WEB_STATIC_FALLBACK  = MustNewBoolOption("web.static.fallback", false, "For any unmatched route (potential 404) that does not have an API prefix, try to match it in the static root before giving the final 404.")
 
+	// Remote Grading Worker
+	WORKER_SERVER      = MustNewStringOption("worker.server", "", "The base URL of the autograder server that a remote grading worker gets tasks from.")
+	WORKER_EMAIL       = MustNewStringOption("worker.email", "", "The email of the (server admin) user that a remote grading worker authenticates as.")
+	WORKER_PASS        = MustNewStringOption("worker.pass", "", "The password (or token) of the user that a remote grading worker authenticates as.")
+	WORKER_NAME        = MustNewStringOption("worker.name", "", "A name to identify a remote grading worker. Defaults to empty string, which indicates the hostname.")
+	WORKER_CONCURRENCY = MustNewIntOption("worker.concurrency", 1, "The number of grading tasks that a remote grading worker can run at the same time.")
+
 	// Database
-	DB_TYPE   = MustNewStringOption("db.type", "disk", "The type of database to use.")
-	DB_PG_URI = MustNewStringOption("db.pg.uri", "", "Connection string to connect to a Postgres Database. Empty if not using Postgres.")
+	DB_TYPE        = MustNewStringOption("db.type", "disk", "The type of database to use (\"disk\", \"sqlite\", or \"postgres\").")
+	DB_PG_URI      = MustNewStringOption("db.pg.uri", "", "Connection string to connect to a Postgres Database. Empty if not using Postgres.")
+	DB_SQLITE_PATH = MustNewStringOption("db.sqlite.path", "", "Path to the SQLite database file. Defaults to a file inside the database dir.")
 
 	STALELOCK_DURATION_SECS = MustNewIntOption("lockmanager.staleduration", 2*60*60, "Number of seconds a lock can be unused before getting removed.")
 )
@@ -12,9 +12,12 @@ import (
 
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db/disk"
+	"github.com/edulinq/autograder/internal/db/pg"
+	"github.com/edulinq/autograder/internal/db/sqlite"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 var backend Backend
@@ -120,6 +123,11 @@ type Backend interface {
 	// Get all attempts for a specific user.
 	GetSubmissionAttempts(assignment *model.Assignment, email string) ([]*model.GradingResult, error)
 
+	// Get the emails of all users that have at least one submission for this assignment
+	// (including users that are no longer in the course).
+	// The emails will be sorted.
+	GetSubmissionUsers(assignment *model.Assignment) ([]string, error)
+
 	// Get the scoring infos for an assignment for all users that match the given role.
 	// A role of model.CourseRoleUnknown means all users.
 	// Users without a submission (but with a matching role) will be represented with a nil map value.
@@ -165,6 +173,67 @@ type Backend interface {
 	// and a nil value indicates that the given task should be removed.
 	UpsertActiveTasks(tasks map[string]*model.FullScheduledTask) error
 
+	// Grading job operations.
+
+	// Save (insert or update) a grading job.
+	SaveGradingJob(job *model.GradingJob) error
+
+	// Get a specific grading job.
+	// Returns (nil, nil) if the job does not exist.
+	GetGradingJob(jobID string) (*model.GradingJob, error)
+
+	// Get all the grading jobs with the given status, oldest first.
+	GetGradingJobs(status model.GradingJobStatus) ([]*model.GradingJob, error)
+
+	// Atomically mark the oldest pending grading job as running (starting at the given time) and return it.
+	// Returns (nil, nil) if there are no pending jobs.
+	ClaimNextGradingJob(startTime timestamp.Timestamp) (*model.GradingJob, error)
+
+	// Manual grading operations.
+
+	// Save (insert or replace) a user's manual grade for an assignment.
+	SaveManualGrade(assignment *model.Assignment, grade *model.ManualGrade) error
+
+	// Get all the manual grades for an assignment, keyed by user email.
+	GetManualGrades(assignment *model.Assignment) (map[string]*model.ManualGrade, error)
+
+	// Extension operations.
+
+	// Save (insert or replace) a user's or team's extension for an assignment.
+	SaveExtension(assignment *model.Assignment, extension *model.Extension) error
+
+	// Remove an extension for an assignment by its key (see model.Extension.GetKey()).
+	// Returns true if an extension was removed.
+	RemoveExtension(assignment *model.Assignment, key string) (bool, error)
+
+	// Get all the extensions for an assignment, keyed by model.Extension.GetKey().
+	GetExtensions(assignment *model.Assignment) (map[string]*model.Extension, error)
+
+	// Team operations.
+
+	// Save (insert or replace) a team for an assignment.
+	SaveTeam(assignment *model.Assignment, team *model.Team) error
+
+	// Remove a team from an assignment.
+	// Returns true if a team was removed.
+	RemoveTeam(assignment *model.Assignment, teamID string) (bool, error)
+
+	// Get all the stored teams for an assignment, keyed by team ID.
+	// This does not include teams defined in the assignment config.
+	GetTeams(assignment *model.Assignment) (map[string]*model.Team, error)
+
+	// Final submission operations.
+
+	// Save (insert or replace) the submission a user marked as final for an assignment.
+	SaveFinalSubmission(assignment *model.Assignment, final *model.FinalSubmission) error
+
+	// Remove a user's final submission mark for an assignment.
+	// Returns true if a mark was removed.
+	RemoveFinalSubmission(assignment *model.Assignment, email string) (bool, error)
+
+	// Get all the final submission marks for an assignment, keyed by user email.
+	GetFinalSubmissions(assignment *model.Assignment) (map[string]*model.FinalSubmission, error)
+
 	// Logging operations.
 
 	// DB backends will also be used as logging storage backends.
@@ -195,16 +264,9 @@ func Open() error {
 	}
 
 	var err error
-	dbType := config.DB_TYPE.Get()
-
-	switch dbType {
-	case DB_TYPE_DISK:
-		backend, err = disk.Open()
-	default:
-		err = fmt.Errorf("Unknown database type: '%s'.", dbType)
-	}
-
+	backend, err = openBackend(config.DB_TYPE.Get())
 	if err != nil {
+		backend = nil
 		return fmt.Errorf("Failed to open database: '%w'.", err)
 	}
 
@@ -240,6 +302,38 @@ func Open() error {
 	return nil
 }
 
+// Open a standalone backend of the given type (and ensure its tables exist).
+// The returned backend is NOT the active database (it will not be used by any other part of the db package),
+// and the caller is responsible for closing it.
+// Most callers should use Open(), this is for tools that need to work with multiple backends at once (e.g., migrations).
+func OpenBackend(dbType string) (Backend, error) {
+	newBackend, err := openBackend(dbType)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database: '%w'.", err)
+	}
+
+	err = newBackend.EnsureTables()
+	if err != nil {
+		newBackend.Close()
+		return nil, err
+	}
+
+	return newBackend, nil
+}
+
+func openBackend(dbType string) (Backend, error) {
+	switch dbType {
+	case DB_TYPE_DISK:
+		return disk.Open()
+	case DB_TYPE_SQLITE:
+		return sqlite.Open()
+	case DB_TYPE_POSTGRES:
+		return pg.Open()
+	default:
+		return nil, fmt.Errorf("Unknown database type: '%s'.", dbType)
+	}
+}
+
 func Close() error {
 	dbLock.Lock()
 	defer dbLock.Unlock()
@@ -28,6 +28,8 @@ type backend struct {
 	userLock  sync.RWMutex
 	statsLock sync.RWMutex
 	tasksLock sync.RWMutex
+
+	gradingJobsLock sync.Mutex
 }
 
 func Open() (*backend, error) {
@@ -90,6 +92,9 @@ func (this *backend) Clear() error {
 	this.tasksLock.Lock()
 	defer this.tasksLock.Unlock()
 
+	this.gradingJobsLock.Lock()
+	defer this.gradingJobsLock.Unlock()
+
 	err := util.RemoveDirent(this.baseDir)
 	if err != nil {
 		return err
@@ -0,0 +1,88 @@
+package disk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_EXTENSIONS_DIR = "extensions"
+
+func (this *backend) SaveExtension(assignment *model.Assignment, extension *model.Extension) error {
+	dir := this.getExtensionsDir(assignment)
+
+	this.contextLock(dir)
+	defer this.contextUnlock(dir)
+
+	err := util.MkDir(dir)
+	if err != nil {
+		return fmt.Errorf("Failed to make extensions dir '%s': '%w'.", dir, err)
+	}
+
+	err = util.ToJSONFileIndent(extension, filepath.Join(dir, extension.GetKey()+".json"))
+	if err != nil {
+		return fmt.Errorf("Failed to save extension for '%s' on '%s': '%w'.", extension.GetKey(), assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveExtension(assignment *model.Assignment, key string) (bool, error) {
+	dir := this.getExtensionsDir(assignment)
+
+	this.contextLock(dir)
+	defer this.contextUnlock(dir)
+
+	path := filepath.Join(dir, key+".json")
+	if !util.PathExists(path) {
+		return false, nil
+	}
+
+	err := util.RemoveDirent(path)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove extension for '%s' on '%s': '%w'.", key, assignment.FullID(), err)
+	}
+
+	return true, nil
+}
+
+func (this *backend) GetExtensions(assignment *model.Assignment) (map[string]*model.Extension, error) {
+	dir := this.getExtensionsDir(assignment)
+
+	this.contextReadLock(dir)
+	defer this.contextReadUnlock(dir)
+
+	extensions := make(map[string]*model.Extension)
+	if !util.PathExists(dir) {
+		return extensions, nil
+	}
+
+	dirents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read extensions dir '%s': '%w'.", dir, err)
+	}
+
+	for _, dirent := range dirents {
+		if !strings.HasSuffix(dirent.Name(), ".json") {
+			continue
+		}
+
+		var extension model.Extension
+		err = util.JSONFromFile(filepath.Join(dir, dirent.Name()), &extension)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load extension '%s': '%w'.", dirent.Name(), err)
+		}
+
+		extensions[extension.GetKey()] = &extension
+	}
+
+	return extensions, nil
+}
+
+func (this *backend) getExtensionsDir(assignment *model.Assignment) string {
+	return filepath.Join(this.getCourseDir(assignment.GetCourse()), DISK_DB_EXTENSIONS_DIR, assignment.GetID())
+}
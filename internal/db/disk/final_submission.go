@@ -0,0 +1,88 @@
+package disk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_FINAL_SUBMISSIONS_DIR = "final-submissions"
+
+func (this *backend) SaveFinalSubmission(assignment *model.Assignment, final *model.FinalSubmission) error {
+	dir := this.getFinalSubmissionsDir(assignment)
+
+	this.contextLock(dir)
+	defer this.contextUnlock(dir)
+
+	err := util.MkDir(dir)
+	if err != nil {
+		return fmt.Errorf("Failed to make final submissions dir '%s': '%w'.", dir, err)
+	}
+
+	err = util.ToJSONFileIndent(final, filepath.Join(dir, final.User+".json"))
+	if err != nil {
+		return fmt.Errorf("Failed to save final submission for '%s' on '%s': '%w'.", final.User, assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveFinalSubmission(assignment *model.Assignment, email string) (bool, error) {
+	dir := this.getFinalSubmissionsDir(assignment)
+
+	this.contextLock(dir)
+	defer this.contextUnlock(dir)
+
+	path := filepath.Join(dir, email+".json")
+	if !util.PathExists(path) {
+		return false, nil
+	}
+
+	err := util.RemoveDirent(path)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove final submission for '%s' on '%s': '%w'.", email, assignment.FullID(), err)
+	}
+
+	return true, nil
+}
+
+func (this *backend) GetFinalSubmissions(assignment *model.Assignment) (map[string]*model.FinalSubmission, error) {
+	dir := this.getFinalSubmissionsDir(assignment)
+
+	this.contextReadLock(dir)
+	defer this.contextReadUnlock(dir)
+
+	finals := make(map[string]*model.FinalSubmission)
+	if !util.PathExists(dir) {
+		return finals, nil
+	}
+
+	dirents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read final submissions dir '%s': '%w'.", dir, err)
+	}
+
+	for _, dirent := range dirents {
+		if !strings.HasSuffix(dirent.Name(), ".json") {
+			continue
+		}
+
+		var final model.FinalSubmission
+		err = util.JSONFromFile(filepath.Join(dir, dirent.Name()), &final)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load final submission '%s': '%w'.", dirent.Name(), err)
+		}
+
+		finals[final.User] = &final
+	}
+
+	return finals, nil
+}
+
+func (this *backend) getFinalSubmissionsDir(assignment *model.Assignment) string {
+	return filepath.Join(this.getCourseDir(assignment.GetCourse()), DISK_DB_FINAL_SUBMISSIONS_DIR, assignment.GetID())
+}
@@ -0,0 +1,174 @@
+package disk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	GRADING_JOBS_DIRNAME  = "grading-jobs"
+	GRADING_QUEUE_DIRNAME = "grading-queue"
+)
+
+// Every job is stored in the jobs dir (keyed by ID).
+// Pending jobs also have an empty marker file in the queue dir,
+// named so that the oldest job will sort first.
+// This allows claiming the next job without reading every job.
+
+func (this *backend) SaveGradingJob(job *model.GradingJob) error {
+	this.gradingJobsLock.Lock()
+	defer this.gradingJobsLock.Unlock()
+
+	return this.saveGradingJob(job)
+}
+
+func (this *backend) GetGradingJob(jobID string) (*model.GradingJob, error) {
+	this.gradingJobsLock.Lock()
+	defer this.gradingJobsLock.Unlock()
+
+	return this.getGradingJob(jobID)
+}
+
+func (this *backend) GetGradingJobs(status model.GradingJobStatus) ([]*model.GradingJob, error) {
+	this.gradingJobsLock.Lock()
+	defer this.gradingJobsLock.Unlock()
+
+	jobs := make([]*model.GradingJob, 0)
+
+	jobsDir := filepath.Join(this.baseDir, GRADING_JOBS_DIRNAME)
+	if !util.PathExists(jobsDir) {
+		return jobs, nil
+	}
+
+	dirents, err := os.ReadDir(jobsDir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read grading jobs dir '%s': '%w'.", jobsDir, err)
+	}
+
+	for _, dirent := range dirents {
+		job, err := this.getGradingJob(strings.TrimSuffix(dirent.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+
+		if (job != nil) && (job.Status == status) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	slices.SortStableFunc(jobs, func(a *model.GradingJob, b *model.GradingJob) int {
+		return int(a.CreateTime - b.CreateTime)
+	})
+
+	return jobs, nil
+}
+
+func (this *backend) ClaimNextGradingJob(startTime timestamp.Timestamp) (*model.GradingJob, error) {
+	this.gradingJobsLock.Lock()
+	defer this.gradingJobsLock.Unlock()
+
+	queueDir := filepath.Join(this.baseDir, GRADING_QUEUE_DIRNAME)
+	if !util.PathExists(queueDir) {
+		return nil, nil
+	}
+
+	dirents, err := os.ReadDir(queueDir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read grading queue dir '%s': '%w'.", queueDir, err)
+	}
+
+	for _, dirent := range dirents {
+		parts := strings.SplitN(dirent.Name(), "-", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Malformed grading queue entry: '%s'.", dirent.Name())
+		}
+
+		job, err := this.getGradingJob(parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		// Stale marker.
+		if (job == nil) || (job.Status != model.GradingJobStatusPending) {
+			err = util.RemoveDirent(filepath.Join(queueDir, dirent.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("Failed to remove stale grading queue entry: '%w'.", err)
+			}
+
+			continue
+		}
+
+		job.Status = model.GradingJobStatusRunning
+		job.StartTime = startTime
+
+		err = this.saveGradingJob(job)
+		if err != nil {
+			return nil, err
+		}
+
+		return job, nil
+	}
+
+	return nil, nil
+}
+
+func (this *backend) saveGradingJob(job *model.GradingJob) error {
+	for _, dirname := range []string{GRADING_JOBS_DIRNAME, GRADING_QUEUE_DIRNAME} {
+		err := util.MkDir(filepath.Join(this.baseDir, dirname))
+		if err != nil {
+			return fmt.Errorf("Failed to make grading job dir: '%w'.", err)
+		}
+	}
+
+	err := util.ToJSONFile(job, this.getGradingJobPath(job.ID))
+	if err != nil {
+		return fmt.Errorf("Failed to save grading job '%s': '%w'.", job.ID, err)
+	}
+
+	markerPath := this.getGradingQueueMarkerPath(job)
+
+	if job.Status == model.GradingJobStatusPending {
+		if !util.PathExists(markerPath) {
+			err = util.WriteFile("", markerPath)
+		}
+	} else if util.PathExists(markerPath) {
+		err = util.RemoveDirent(markerPath)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to update grading queue for job '%s': '%w'.", job.ID, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getGradingJob(jobID string) (*model.GradingJob, error) {
+	path := this.getGradingJobPath(jobID)
+	if !util.PathExists(path) {
+		return nil, nil
+	}
+
+	var job model.GradingJob
+	err := util.JSONFromFile(path, &job)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to deserialize grading job '%s': '%w'.", path, err)
+	}
+
+	return &job, nil
+}
+
+func (this *backend) getGradingJobPath(jobID string) string {
+	return filepath.Join(this.baseDir, GRADING_JOBS_DIRNAME, jobID+".json")
+}
+
+func (this *backend) getGradingQueueMarkerPath(job *model.GradingJob) string {
+	filename := fmt.Sprintf("%020d-%s", int64(job.CreateTime), job.ID)
+	return filepath.Join(this.baseDir, GRADING_QUEUE_DIRNAME, filename)
+}
@@ -0,0 +1,69 @@
+package disk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_MANUAL_GRADES_DIR = "manual-grades"
+
+func (this *backend) SaveManualGrade(assignment *model.Assignment, grade *model.ManualGrade) error {
+	dir := this.getManualGradesDir(assignment)
+
+	this.contextLock(dir)
+	defer this.contextUnlock(dir)
+
+	err := util.MkDir(dir)
+	if err != nil {
+		return fmt.Errorf("Failed to make manual grades dir '%s': '%w'.", dir, err)
+	}
+
+	err = util.ToJSONFileIndent(grade, filepath.Join(dir, grade.User+".json"))
+	if err != nil {
+		return fmt.Errorf("Failed to save manual grade for '%s' on '%s': '%w'.", grade.User, assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetManualGrades(assignment *model.Assignment) (map[string]*model.ManualGrade, error) {
+	dir := this.getManualGradesDir(assignment)
+
+	this.contextReadLock(dir)
+	defer this.contextReadUnlock(dir)
+
+	grades := make(map[string]*model.ManualGrade)
+	if !util.PathExists(dir) {
+		return grades, nil
+	}
+
+	dirents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read manual grades dir '%s': '%w'.", dir, err)
+	}
+
+	for _, dirent := range dirents {
+		if !strings.HasSuffix(dirent.Name(), ".json") {
+			continue
+		}
+
+		var grade model.ManualGrade
+		err = util.JSONFromFile(filepath.Join(dir, dirent.Name()), &grade)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load manual grade '%s': '%w'.", dirent.Name(), err)
+		}
+
+		grades[grade.User] = &grade
+	}
+
+	return grades, nil
+}
+
+func (this *backend) getManualGradesDir(assignment *model.Assignment) string {
+	return filepath.Join(this.getCourseDir(assignment.GetCourse()), DISK_DB_MANUAL_GRADES_DIR, assignment.GetID())
+}
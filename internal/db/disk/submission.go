@@ -358,3 +358,25 @@ func (this *backend) GetSubmissionAttempts(assignment *model.Assignment, email s
 
 	return submissions, nil
 }
+
+func (this *backend) GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+	emails := make([]string, 0)
+
+	assignmentDir := filepath.Join(this.getCourseDirFromID(assignment.GetCourse().GetID()), model.SUBMISSIONS_DIRNAME, assignment.GetID())
+	if !util.PathExists(assignmentDir) {
+		return emails, nil
+	}
+
+	dirents, err := os.ReadDir(assignmentDir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read assignment submissions dir '%s': '%w'.", assignmentDir, err)
+	}
+
+	for _, dirent := range dirents {
+		if dirent.IsDir() {
+			emails = append(emails, dirent.Name())
+		}
+	}
+
+	return emails, nil
+}
@@ -0,0 +1,88 @@
+package disk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_TEAMS_DIR = "teams"
+
+func (this *backend) SaveTeam(assignment *model.Assignment, team *model.Team) error {
+	dir := this.getTeamsDir(assignment)
+
+	this.contextLock(dir)
+	defer this.contextUnlock(dir)
+
+	err := util.MkDir(dir)
+	if err != nil {
+		return fmt.Errorf("Failed to make teams dir '%s': '%w'.", dir, err)
+	}
+
+	err = util.ToJSONFileIndent(team, filepath.Join(dir, team.ID+".json"))
+	if err != nil {
+		return fmt.Errorf("Failed to save team '%s' on '%s': '%w'.", team.ID, assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveTeam(assignment *model.Assignment, teamID string) (bool, error) {
+	dir := this.getTeamsDir(assignment)
+
+	this.contextLock(dir)
+	defer this.contextUnlock(dir)
+
+	path := filepath.Join(dir, teamID+".json")
+	if !util.PathExists(path) {
+		return false, nil
+	}
+
+	err := util.RemoveDirent(path)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove team '%s' on '%s': '%w'.", teamID, assignment.FullID(), err)
+	}
+
+	return true, nil
+}
+
+func (this *backend) GetTeams(assignment *model.Assignment) (map[string]*model.Team, error) {
+	dir := this.getTeamsDir(assignment)
+
+	this.contextReadLock(dir)
+	defer this.contextReadUnlock(dir)
+
+	teams := make(map[string]*model.Team)
+	if !util.PathExists(dir) {
+		return teams, nil
+	}
+
+	dirents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read teams dir '%s': '%w'.", dir, err)
+	}
+
+	for _, dirent := range dirents {
+		if !strings.HasSuffix(dirent.Name(), ".json") {
+			continue
+		}
+
+		var team model.Team
+		err = util.JSONFromFile(filepath.Join(dir, dirent.Name()), &team)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load team '%s': '%w'.", dirent.Name(), err)
+		}
+
+		teams[team.ID] = &team
+	}
+
+	return teams, nil
+}
+
+func (this *backend) getTeamsDir(assignment *model.Assignment) string {
+	return filepath.Join(this.getCourseDir(assignment.GetCourse()), DISK_DB_TEAMS_DIR, assignment.GetID())
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Save (insert or replace) an extension for a user or team.
+// Team extensions can only be saved for teams that exist on the assignment.
+func SaveExtension(assignment *model.Assignment, extension *model.Extension) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	err := extension.Validate()
+	if err != nil {
+		return fmt.Errorf("Refusing to save invalid extension: '%w'.", err)
+	}
+
+	if (extension.CourseID != assignment.GetCourse().GetID()) || (extension.AssignmentID != assignment.GetID()) {
+		return fmt.Errorf("Extension is for '%s::%s', but is being saved to '%s'.", extension.CourseID, extension.AssignmentID, assignment.FullID())
+	}
+
+	if extension.Team != "" {
+		teams, err := GetTeams(assignment)
+		if err != nil {
+			return err
+		}
+
+		if teams[extension.Team] == nil {
+			return fmt.Errorf("Extension is for team '%s', which does not exist on '%s'.", extension.Team, assignment.FullID())
+		}
+	}
+
+	return backend.SaveExtension(assignment, extension)
+}
+
+// Remove a user's extension for an assignment.
+// Returns true if an extension was removed.
+func RemoveExtension(assignment *model.Assignment, email string) (bool, error) {
+	if backend == nil {
+		return false, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.RemoveExtension(assignment, email)
+}
+
+// Remove a team's extension for an assignment.
+// Returns true if an extension was removed.
+func RemoveTeamExtension(assignment *model.Assignment, teamID string) (bool, error) {
+	if backend == nil {
+		return false, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.RemoveExtension(assignment, model.GetTeamExtensionKey(teamID))
+}
+
+// Get all the (user and team) extensions for an assignment, keyed by model.Extension.GetKey().
+// To get the extension that applies to a user, use GetExtension() or GetUserExtensions().
+func GetExtensions(assignment *model.Assignment) (map[string]*model.Extension, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetExtensions(assignment)
+}
+
+// Get the extension that applies to each user with one (from the user's own extension or their team's extension), keyed by email.
+func GetUserExtensions(assignment *model.Assignment) (map[string]*model.Extension, error) {
+	extensions, err := GetExtensions(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := GetTeams(assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get teams: '%w'.", err)
+	}
+
+	userExtensions := make(map[string]*model.Extension, len(extensions))
+	for _, extension := range extensions {
+		if extension.User != "" {
+			userExtensions[extension.User] = extension
+		}
+	}
+
+	for _, team := range teams {
+		for _, member := range team.Members {
+			extension := model.ResolveExtension(extensions, member, team)
+			if extension != nil {
+				userExtensions[member] = extension
+			}
+		}
+	}
+
+	return userExtensions, nil
+}
+
+// Get the extension that applies to a user for an assignment (their own extension or their team's extension).
+// Returns (nil, nil) if the user does not have an extension.
+func GetExtension(assignment *model.Assignment, email string) (*model.Extension, error) {
+	extensions, err := GetExtensions(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	extension := extensions[email]
+	if extension != nil {
+		return extension, nil
+	}
+
+	team, err := GetUserTeam(assignment, email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get team: '%w'.", err)
+	}
+
+	return model.ResolveExtension(extensions, email, team), nil
+}
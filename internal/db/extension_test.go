@@ -0,0 +1,216 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestExtensions(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+	dueDate := timestamp.FromMSecs(1000)
+
+	extension := &model.Extension{
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		User:         "course-student@test.edulinq.org",
+		DueDate:      &dueDate,
+		Author:       "course-admin@test.edulinq.org",
+		Reason:       " Accommodation. ",
+	}
+
+	err := SaveExtension(assignment, extension)
+	if err != nil {
+		test.Fatalf("Failed to save extension: '%v'.", err)
+	}
+
+	// An extension without a due date or submission limit is invalid.
+	err = SaveExtension(assignment, &model.Extension{
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		User:         "course-other@test.edulinq.org",
+		Author:       "course-admin@test.edulinq.org",
+	})
+	if err == nil {
+		test.Fatalf("Did not get an error when saving an empty extension.")
+	}
+
+	actual, err := GetExtension(assignment, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get extension: '%v'.", err)
+	}
+
+	if (actual == nil) || (actual.Reason != "Accommodation.") || (*actual.DueDate != dueDate) || actual.Time.IsZero() {
+		test.Fatalf("Unexpected extension: '%s'.", util.MustToJSONIndent(actual))
+	}
+
+	if *assignment.GetEffectiveDueDate(actual) != dueDate {
+		test.Fatalf("Extension due date was not used.")
+	}
+
+	actual, err = GetExtension(assignment, "course-other@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get missing extension: '%v'.", err)
+	}
+
+	if actual != nil {
+		test.Fatalf("Found an unexpected extension: '%s'.", util.MustToJSONIndent(actual))
+	}
+
+	removed, err := RemoveExtension(assignment, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to remove extension: '%v'.", err)
+	}
+
+	if !removed {
+		test.Fatalf("Extension was not removed.")
+	}
+
+	removed, err = RemoveExtension(assignment, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to remove missing extension: '%v'.", err)
+	}
+
+	if removed {
+		test.Fatalf("Missing extension was reported as removed.")
+	}
+
+	extensions, err := GetExtensions(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get extensions: '%v'.", err)
+	}
+
+	if len(extensions) != 0 {
+		test.Fatalf("Unexpected number of extensions. Expected: 0, Actual: %d.", len(extensions))
+	}
+}
+
+func (this *DBTests) DBTestTeamExtensions(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+	teamDueDate := timestamp.FromMSecs(1000)
+	userDueDate := timestamp.FromMSecs(2000)
+
+	teamExtension := &model.Extension{
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		Team:         "a",
+		DueDate:      &teamDueDate,
+		Author:       "course-admin@test.edulinq.org",
+	}
+
+	// Team extensions cannot be saved for assignments without teams.
+	err := SaveExtension(assignment, teamExtension)
+	if err == nil {
+		test.Fatalf("Did not get an error when saving a team extension to an assignment without teams.")
+	}
+
+	MustSetTestStudents(assignment.GetCourse(), "course-other@test.edulinq.org")
+
+	assignment.Teams = &model.TeamInfo{
+		MaxSize: 2,
+		Teams: []*model.Team{
+			&model.Team{ID: "a", Members: []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"}},
+		},
+	}
+
+	err = assignment.Teams.Validate()
+	if err != nil {
+		test.Fatalf("Failed to validate teams: '%v'.", err)
+	}
+
+	MustSaveAssignment(assignment)
+
+	// Team extensions cannot be saved for missing teams.
+	err = SaveExtension(assignment, &model.Extension{
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		Team:         "zzz",
+		DueDate:      &teamDueDate,
+		Author:       "course-admin@test.edulinq.org",
+	})
+	if err == nil {
+		test.Fatalf("Did not get an error when saving an extension for a missing team.")
+	}
+
+	err = SaveExtension(assignment, teamExtension)
+	if err != nil {
+		test.Fatalf("Failed to save team extension: '%v'.", err)
+	}
+
+	// A user's own extension takes precedence over their team's.
+	err = SaveExtension(assignment, &model.Extension{
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		User:         "course-other@test.edulinq.org",
+		DueDate:      &userDueDate,
+		Author:       "course-admin@test.edulinq.org",
+	})
+	if err != nil {
+		test.Fatalf("Failed to save user extension: '%v'.", err)
+	}
+
+	expected := map[string]timestamp.Timestamp{
+		"course-student@test.edulinq.org": teamDueDate,
+		"course-other@test.edulinq.org":   userDueDate,
+	}
+
+	userExtensions, err := GetUserExtensions(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get user extensions: '%v'.", err)
+	}
+
+	if len(expected) != len(userExtensions) {
+		test.Fatalf("Unexpected number of user extensions. Expected: %d, Actual: %d.", len(expected), len(userExtensions))
+	}
+
+	for email, dueDate := range expected {
+		extension, err := GetExtension(assignment, email)
+		if err != nil {
+			test.Fatalf("Failed to get extension for '%s': '%v'.", email, err)
+		}
+
+		if (extension == nil) || (*extension.DueDate != dueDate) {
+			test.Fatalf("Unexpected extension for '%s': '%s'.", email, util.MustToJSONIndent(extension))
+		}
+
+		if util.MustToJSON(extension) != util.MustToJSON(userExtensions[email]) {
+			test.Fatalf("User extension for '%s' does not match. Expected: '%s', Actual: '%s'.",
+				email, util.MustToJSONIndent(extension), util.MustToJSONIndent(userExtensions[email]))
+		}
+	}
+
+	extension, err := GetExtension(assignment, "course-grader@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get missing extension: '%v'.", err)
+	}
+
+	if extension != nil {
+		test.Fatalf("Found an unexpected extension: '%s'.", util.MustToJSONIndent(extension))
+	}
+
+	removed, err := RemoveTeamExtension(assignment, "a")
+	if err != nil {
+		test.Fatalf("Failed to remove team extension: '%v'.", err)
+	}
+
+	if !removed {
+		test.Fatalf("Team extension was not removed.")
+	}
+
+	extension, err = GetExtension(assignment, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get removed extension: '%v'.", err)
+	}
+
+	if extension != nil {
+		test.Fatalf("Found a removed team extension: '%s'.", util.MustToJSONIndent(extension))
+	}
+}
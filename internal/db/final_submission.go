@@ -0,0 +1,67 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Mark a user's submission as their final submission for an assignment (replacing any earlier mark).
+// The submission must exist.
+// If the submission is a regrade, then the original submission is marked instead.
+func SaveFinalSubmission(assignment *model.Assignment, final *model.FinalSubmission) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	err := final.Validate()
+	if err != nil {
+		return fmt.Errorf("Refusing to save invalid final submission: '%w'.", err)
+	}
+
+	gradingInfo, err := backend.GetSubmissionResult(assignment, final.User, final.SubmissionID)
+	if err != nil {
+		return fmt.Errorf("Failed to get submission '%s' for '%s': '%w'.", final.SubmissionID, final.User, err)
+	}
+
+	if gradingInfo == nil {
+		return fmt.Errorf("Could not find submission '%s' for '%s'.", final.SubmissionID, final.User)
+	}
+
+	if gradingInfo.IsRegrade() {
+		final.SubmissionID = common.GetShortSubmissionID(gradingInfo.RegradeOf)
+	}
+
+	return backend.SaveFinalSubmission(assignment, final)
+}
+
+// Remove a user's final submission mark for an assignment.
+// Returns true if a mark was removed.
+func RemoveFinalSubmission(assignment *model.Assignment, email string) (bool, error) {
+	if backend == nil {
+		return false, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.RemoveFinalSubmission(assignment, email)
+}
+
+// Get the submission a user marked as final for an assignment.
+// Returns (nil, nil) if the user has not marked a submission.
+func GetFinalSubmission(assignment *model.Assignment, email string) (*model.FinalSubmission, error) {
+	finals, err := GetFinalSubmissions(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return finals[email], nil
+}
+
+// Get all the final submission marks for an assignment, keyed by user email.
+func GetFinalSubmissions(assignment *model.Assignment) (map[string]*model.FinalSubmission, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetFinalSubmissions(assignment)
+}
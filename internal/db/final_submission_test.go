@@ -0,0 +1,126 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestFinalSubmissions(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	final := &model.FinalSubmission{
+		User:         " course-student@test.edulinq.org ",
+		SubmissionID: "course101::hw0::course-student@test.edulinq.org::1697406265",
+	}
+
+	err := SaveFinalSubmission(assignment, final)
+	if err != nil {
+		test.Fatalf("Failed to save final submission: '%v'.", err)
+	}
+
+	// Missing submissions cannot be marked.
+	err = SaveFinalSubmission(assignment, &model.FinalSubmission{User: "course-student@test.edulinq.org", SubmissionID: "ZZZ"})
+	if err == nil {
+		test.Fatalf("Did not get an error when marking a missing submission.")
+	}
+
+	actual, err := GetFinalSubmission(assignment, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get final submission: '%v'.", err)
+	}
+
+	if (actual == nil) || (actual.SubmissionID != "1697406265") || actual.Time.IsZero() {
+		test.Fatalf("Unexpected final submission: '%s'.", util.MustToJSONIndent(actual))
+	}
+
+	removed, err := RemoveFinalSubmission(assignment, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to remove final submission: '%v'.", err)
+	}
+
+	if !removed {
+		test.Fatalf("Final submission was not removed.")
+	}
+
+	actual, err = GetFinalSubmission(assignment, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get removed final submission: '%v'.", err)
+	}
+
+	if actual != nil {
+		test.Fatalf("Found a removed final submission: '%s'.", util.MustToJSONIndent(actual))
+	}
+}
+
+func (this *DBTests) DBTestSelectedSubmissions(test *testing.T) {
+	defer ResetForTesting()
+
+	dueDate := timestamp.FromMSecs(1697406270000)
+
+	testCases := []struct {
+		selection model.SubmissionSelectionPolicy
+		final     string
+		expected  string
+	}{
+		{"", "", "1697406272"},
+		{model.SelectLatest, "1697406265", "1697406272"},
+		{model.SelectHighestScore, "", "1697406272"},
+		{model.SelectHighestBeforeDue, "", "1697406265"},
+		{model.SelectStudentFinal, "", "1697406272"},
+		{model.SelectStudentFinal, "1697406256", "1697406256"},
+	}
+
+	for i, testCase := range testCases {
+		ResetForTesting()
+
+		assignment := MustGetTestAssignment()
+		assignment.DueDate = &dueDate
+		assignment.SubmissionSelection = testCase.selection
+
+		if testCase.final != "" {
+			err := SaveFinalSubmission(assignment, &model.FinalSubmission{User: "course-student@test.edulinq.org", SubmissionID: testCase.final})
+			if err != nil {
+				test.Fatalf("Case %d: Failed to save final submission: '%v'.", i, err)
+			}
+		}
+
+		gradingInfos, err := GetSelectedSubmissions(assignment, model.CourseRoleStudent)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to get selected submissions: '%v'.", i, err)
+		}
+
+		gradingInfo := gradingInfos["course-student@test.edulinq.org"]
+		if (gradingInfo == nil) || (gradingInfo.ShortID != testCase.expected) {
+			test.Errorf("Case %d: Unexpected selected submission. Expected: '%s', Actual: '%s'.", i, testCase.expected, util.MustToJSONIndent(gradingInfo))
+			continue
+		}
+
+		scoringInfos, err := GetScoringInfos(assignment, model.CourseRoleStudent)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to get scoring infos: '%v'.", i, err)
+		}
+
+		scoringInfo := scoringInfos["course-student@test.edulinq.org"]
+		if (scoringInfo == nil) || (scoringInfo.ID != gradingInfo.ID) {
+			test.Errorf("Case %d: Scoring info does not match selected submission: '%s'.", i, util.MustToJSONIndent(scoringInfo))
+			continue
+		}
+
+		survey, err := GetSelectedSubmissionSurvey(assignment, model.CourseRoleStudent)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to get submission survey: '%v'.", i, err)
+		}
+
+		item := survey["course-student@test.edulinq.org"]
+		if (item == nil) || (item.ShortID != testCase.expected) {
+			test.Errorf("Case %d: Survey does not match selected submission: '%s'.", i, util.MustToJSONIndent(item))
+			continue
+		}
+	}
+}
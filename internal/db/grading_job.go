@@ -0,0 +1,45 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func SaveGradingJob(job *model.GradingJob) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	err := job.Validate()
+	if err != nil {
+		return fmt.Errorf("Refusing to save invalid grading job: '%w'.", err)
+	}
+
+	return backend.SaveGradingJob(job)
+}
+
+func GetGradingJob(jobID string) (*model.GradingJob, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetGradingJob(jobID)
+}
+
+func GetGradingJobs(status model.GradingJobStatus) ([]*model.GradingJob, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetGradingJobs(status)
+}
+
+func ClaimNextGradingJob() (*model.GradingJob, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.ClaimNextGradingJob(timestamp.Now())
+}
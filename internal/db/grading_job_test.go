@@ -0,0 +1,164 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func (this *DBTests) DBTestGradingJobs(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	ids := []string{"job-a", "job-b", "job-c"}
+	for i, id := range ids {
+		err := SaveGradingJob(makeTestGradingJob(id, timestamp.FromMSecs(int64(1000+i))))
+		if err != nil {
+			test.Fatalf("Failed to save job '%s': '%v'.", id, err)
+		}
+	}
+
+	job, err := GetGradingJob("job-b")
+	if err != nil {
+		test.Fatalf("Failed to get job: '%v'.", err)
+	}
+
+	expected := makeTestGradingJob("job-b", timestamp.FromMSecs(1001))
+	if !reflect.DeepEqual(expected, job) {
+		test.Fatalf("Job does not match. Expected: '%v', Actual: '%v'.", expected, job)
+	}
+
+	job, err = GetGradingJob("ZZZ")
+	if err != nil {
+		test.Fatalf("Failed to get missing job: '%v'.", err)
+	}
+
+	if job != nil {
+		test.Fatalf("Found a missing job: '%v'.", job)
+	}
+
+	// Jobs are claimed oldest first.
+	for i, id := range ids {
+		job, err = ClaimNextGradingJob()
+		if err != nil {
+			test.Fatalf("Case %d: Failed to claim job: '%v'.", i, err)
+		}
+
+		if job == nil {
+			test.Fatalf("Case %d: Did not claim a job.", i)
+		}
+
+		if job.ID != id {
+			test.Fatalf("Case %d: Claimed the wrong job. Expected: '%s', Actual: '%s'.", i, id, job.ID)
+		}
+
+		if job.Status != model.GradingJobStatusRunning {
+			test.Fatalf("Case %d: Claimed job is not running: '%s'.", i, job.Status)
+		}
+
+		if job.StartTime.IsZero() {
+			test.Fatalf("Case %d: Claimed job does not have a start time.", i)
+		}
+	}
+
+	job, err = ClaimNextGradingJob()
+	if err != nil {
+		test.Fatalf("Failed to claim from empty queue: '%v'.", err)
+	}
+
+	if job != nil {
+		test.Fatalf("Claimed a job from an empty queue: '%v'.", job)
+	}
+
+	// Complete one job.
+	job, err = GetGradingJob("job-a")
+	if err != nil {
+		test.Fatalf("Failed to get job to complete: '%v'.", err)
+	}
+
+	job.Status = model.GradingJobStatusComplete
+	job.InputFilesGZip = nil
+
+	err = SaveGradingJob(job)
+	if err != nil {
+		test.Fatalf("Failed to save completed job: '%v'.", err)
+	}
+
+	testCases := []struct {
+		status   model.GradingJobStatus
+		expected []string
+	}{
+		{model.GradingJobStatusPending, []string{}},
+		{model.GradingJobStatusRunning, []string{"job-b", "job-c"}},
+		{model.GradingJobStatusComplete, []string{"job-a"}},
+	}
+
+	for i, testCase := range testCases {
+		jobs, err := GetGradingJobs(testCase.status)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get jobs: '%v'.", i, err)
+			continue
+		}
+
+		actual := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			actual = append(actual, job.ID)
+		}
+
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected jobs. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+			continue
+		}
+	}
+
+	// A job put back into the queue can be claimed again.
+	job, err = GetGradingJob("job-c")
+	if err != nil {
+		test.Fatalf("Failed to get job to requeue: '%v'.", err)
+	}
+
+	job.Status = model.GradingJobStatusPending
+
+	err = SaveGradingJob(job)
+	if err != nil {
+		test.Fatalf("Failed to requeue job: '%v'.", err)
+	}
+
+	job, err = ClaimNextGradingJob()
+	if err != nil {
+		test.Fatalf("Failed to claim requeued job: '%v'.", err)
+	}
+
+	if (job == nil) || (job.ID != "job-c") {
+		test.Fatalf("Did not claim requeued job: '%v'.", job)
+	}
+}
+
+func (this *DBTests) DBTestSaveInvalidGradingJob(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	job := makeTestGradingJob("", timestamp.Now())
+
+	err := SaveGradingJob(job)
+	if err == nil {
+		test.Fatalf("Did not get an error when saving an invalid job.")
+	}
+}
+
+func makeTestGradingJob(id string, createTime timestamp.Timestamp) *model.GradingJob {
+	return &model.GradingJob{
+		ID:           id,
+		CourseID:     "course101",
+		AssignmentID: "hw0",
+		User:         "course-student@test.edulinq.org",
+		Message:      "test",
+		Status:       model.GradingJobStatusPending,
+		CreateTime:   createTime,
+		InputFilesGZip: map[string][]byte{
+			"submission.py": []byte{1, 2, 3},
+		},
+	}
+}
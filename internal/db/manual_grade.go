@@ -0,0 +1,124 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Manual grades are read, modified, and re-saved,
+// so make sure two graders do not update a grade at the same time.
+var manualGradeLock sync.Mutex
+
+// Get all the manual grades for an assignment, keyed by user email.
+func GetManualGrades(assignment *model.Assignment) (map[string]*model.ManualGrade, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetManualGrades(assignment)
+}
+
+// Get a user's manual grade for an assignment.
+// Returns (nil, nil) if the user has not been manually graded.
+func GetManualGrade(assignment *model.Assignment, email string) (*model.ManualGrade, error) {
+	grades, err := GetManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return grades[email], nil
+}
+
+// Set the given rubric items in a user's manual grade (creating the grade if necessary).
+// Items not in the given map are left unchanged.
+// Returns the full updated grade.
+func UpdateManualGrade(assignment *model.Assignment, email string, items map[string]*model.ManualGradeItem) (*model.ManualGrade, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	for id, item := range items {
+		if (item != nil) && item.Time.IsZero() {
+			item.Time = timestamp.Now()
+		}
+
+		err := assignment.CheckManualGradeItem(id, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manualGradeLock.Lock()
+	defer manualGradeLock.Unlock()
+
+	grade, err := GetManualGrade(assignment, email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get existing manual grade: '%w'.", err)
+	}
+
+	if grade == nil {
+		grade = &model.ManualGrade{
+			CourseID:     assignment.GetCourse().GetID(),
+			AssignmentID: assignment.GetID(),
+			User:         email,
+		}
+	}
+
+	if grade.Items == nil {
+		grade.Items = make(map[string]*model.ManualGradeItem, len(items))
+	}
+
+	for id, item := range items {
+		grade.Items[id] = item
+	}
+
+	err = grade.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Refusing to save invalid manual grade: '%w'.", err)
+	}
+
+	err = backend.SaveManualGrade(assignment, grade)
+	if err != nil {
+		return nil, err
+	}
+
+	return grade, nil
+}
+
+// Add manual grades into scoring infos (for assignments with a manual rubric).
+// Users that have a manual grade but no submission will get a scoring info with only their manual score.
+func addManualScores(assignment *model.Assignment, scoringInfos map[string]*model.ScoringInfo) error {
+	if !assignment.HasManualRubric() {
+		return nil
+	}
+
+	grades, err := backend.GetManualGrades(assignment)
+	if err != nil {
+		return fmt.Errorf("Failed to get manual grades: '%w'.", err)
+	}
+
+	for email, scoringInfo := range scoringInfos {
+		grade := grades[email]
+
+		if scoringInfo == nil {
+			if grade == nil {
+				continue
+			}
+
+			scoringInfo = &model.ScoringInfo{
+				AutograderStructVersion: model.SCORING_INFO_STRUCT_VERSION,
+			}
+
+			scoringInfos[email] = scoringInfo
+		}
+
+		scoringInfo.AutograderScore = scoringInfo.RawScore
+		scoringInfo.ManualScore = assignment.ComputeManualScore(grade)
+		scoringInfo.RawScore = scoringInfo.AutograderScore + scoringInfo.ManualScore
+	}
+
+	return nil
+}
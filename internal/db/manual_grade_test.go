@@ -0,0 +1,106 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestManualGrades(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+	assignment.ManualRubric = []*model.RubricItem{
+		&model.RubricItem{ID: "style", MaxPoints: 2.0},
+		&model.RubricItem{ID: "report", MaxPoints: 3.0},
+	}
+	MustSaveAssignment(assignment)
+
+	grader := "course-grader@test.edulinq.org"
+
+	// A student with a submission.
+	_, err := UpdateManualGrade(assignment, "course-student@test.edulinq.org", map[string]*model.ManualGradeItem{
+		"style": &model.ManualGradeItem{Score: 1.5, Comment: " Long lines. ", Grader: grader},
+	})
+	if err != nil {
+		test.Fatalf("Failed to update manual grade: '%v'.", err)
+	}
+
+	grade, err := UpdateManualGrade(assignment, "course-student@test.edulinq.org", map[string]*model.ManualGradeItem{
+		"report": &model.ManualGradeItem{Score: 3.0, Grader: grader},
+	})
+	if err != nil {
+		test.Fatalf("Failed to update second manual grade item: '%v'.", err)
+	}
+
+	if (len(grade.Items) != 2) || (grade.Items["style"].Comment != "Long lines.") || grade.Items["report"].Time.IsZero() {
+		test.Fatalf("Unexpected manual grade: '%s'.", util.MustToJSONIndent(grade))
+	}
+
+	// A student without a submission.
+	_, err = UpdateManualGrade(assignment, "course-other@test.edulinq.org", map[string]*model.ManualGradeItem{
+		"report": &model.ManualGradeItem{Score: 2.0, Grader: grader},
+	})
+	if err != nil {
+		test.Fatalf("Failed to update manual grade for other: '%v'.", err)
+	}
+
+	grades, err := GetManualGrades(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get manual grades: '%v'.", err)
+	}
+
+	if len(grades) != 2 {
+		test.Fatalf("Unexpected number of manual grades. Expected: 2, Actual: %d.", len(grades))
+	}
+
+	scoringInfos, err := GetScoringInfos(assignment, model.CourseRoleUnknown)
+	if err != nil {
+		test.Fatalf("Failed to get scoring infos: '%v'.", err)
+	}
+
+	testCases := []struct {
+		email      string
+		autograder float64
+		manual     float64
+	}{
+		{"course-student@test.edulinq.org", 2.0, 4.5},
+		{"course-other@test.edulinq.org", 0.0, 2.0},
+	}
+
+	for i, testCase := range testCases {
+		info := scoringInfos[testCase.email]
+		if info == nil {
+			test.Errorf("Case %d: Missing scoring info.", i)
+			continue
+		}
+
+		if (info.AutograderScore != testCase.autograder) || (info.ManualScore != testCase.manual) || (info.RawScore != (testCase.autograder + testCase.manual)) {
+			test.Errorf("Case %d: Unexpected scoring info: '%s'.", i, util.MustToJSONIndent(info))
+			continue
+		}
+	}
+
+	// Users without any grade stay nil.
+	if scoringInfos["course-admin@test.edulinq.org"] != nil {
+		test.Fatalf("Got a scoring info for a user without a submission or manual grade.")
+	}
+
+	// Bad items.
+	badItems := []map[string]*model.ManualGradeItem{
+		{"zzz": &model.ManualGradeItem{Score: 1.0, Grader: grader}},
+		{"style": &model.ManualGradeItem{Score: 2.5, Grader: grader}},
+		{"style": &model.ManualGradeItem{Score: -1.0, Grader: grader}},
+		{"style": &model.ManualGradeItem{Score: 1.0}},
+		{"style": nil},
+	}
+
+	for i, items := range badItems {
+		_, err = UpdateManualGrade(assignment, "course-student@test.edulinq.org", items)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error on a bad manual grade item.", i)
+		}
+	}
+}
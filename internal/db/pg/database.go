@@ -1,18 +1,54 @@
+// A database backend that stores data in a Postgres server.
+// Meant for larger deployments that want a shared relational store
+// that can be replicated and backed up with standard tooling.
 package pg
 
 import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
 )
 
+// An arbitrary (but fixed) key used to make sure only one server is migrating the schema at a time.
+const MIGRATION_LOCK_KEY = 8675309
+
+// All the tables (except the schema version), in the order they should be cleared.
+var tableNames []string = []string{
+	"submission_files",
+	"submissions",
+	"assignments",
+	"courses",
+	"course_users",
+	"users",
+	"tasks",
+	"grading_jobs",
+	"manual_grades",
+	"extensions",
+	"teams",
+	"final_submissions",
+	"logs",
+	"system_stats",
+	"course_stats",
+}
+
 type backend struct {
 	pool *pgxpool.Pool
 }
 
+// The common query interface of *pgxpool.Pool and pgx.Tx,
+// so helpers can be used both inside and outside of transactions.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 func Open() (*backend, error) {
 	uri := config.DB_PG_URI.Get()
 	if uri == "" {
@@ -24,6 +60,14 @@ func Open() (*backend, error) {
 		return nil, fmt.Errorf("Failed to open connection pool to Postgres database at '%s': %w.", uri, err)
 	}
 
+	err = pool.Ping(context.Background())
+	if err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("Failed to connect to Postgres database: '%w'.", err)
+	}
+
+	log.Debug("Opened Postgres database.")
+
 	return &backend{pool}, nil
 }
 
@@ -31,3 +75,48 @@ func (this *backend) Close() error {
 	this.pool.Close()
 	return nil
 }
+
+func (this *backend) EnsureTables() error {
+	return this.migrate()
+}
+
+func (this *backend) Clear() error {
+	return this.transaction(func(tx pgx.Tx) error {
+		for _, tableName := range tableNames {
+			_, err := tx.Exec(context.Background(), fmt.Sprintf("DELETE FROM %s", tableName))
+			if err != nil {
+				return fmt.Errorf("Failed to clear table '%s': '%w'.", tableName, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Run the given function inside a transaction.
+// The transaction will be committed if the function returns nil, and rolled back otherwise.
+func (this *backend) transaction(operation func(tx pgx.Tx) error) error {
+	tx, err := this.pool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to start transaction: '%w'.", err)
+	}
+
+	err = operation(tx)
+	if err != nil {
+		tx.Rollback(context.Background())
+		return err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to commit transaction: '%w'.", err)
+	}
+
+	return nil
+}
+
+// Add an argument to a dynamically built query and get the placeholder that refers to it.
+func addArg(args *[]any, value any) string {
+	*args = append(*args, value)
+	return fmt.Sprintf("$%d", len(*args))
+}
@@ -0,0 +1,68 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) SaveExtension(assignment *model.Assignment, extension *model.Extension) error {
+	data, err := util.ToJSON(extension)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize extension for '%s' on '%s': '%w'.", extension.GetKey(), assignment.FullID(), err)
+	}
+
+	_, err = this.pool.Exec(context.Background(), `
+		INSERT INTO extensions (course_id, assignment_id, user_email, data) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (course_id, assignment_id, user_email) DO UPDATE SET data = excluded.data
+	`, assignment.GetCourse().GetID(), assignment.GetID(), extension.GetKey(), data)
+	if err != nil {
+		return fmt.Errorf("Failed to save extension for '%s' on '%s': '%w'.", extension.GetKey(), assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveExtension(assignment *model.Assignment, key string) (bool, error) {
+	tag, err := this.pool.Exec(context.Background(), `
+		DELETE FROM extensions
+		WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3
+	`, assignment.GetCourse().GetID(), assignment.GetID(), key)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove extension for '%s' on '%s': '%w'.", key, assignment.FullID(), err)
+	}
+
+	return (tag.RowsAffected() > 0), nil
+}
+
+func (this *backend) GetExtensions(assignment *model.Assignment) (map[string]*model.Extension, error) {
+	rows, err := this.pool.Query(context.Background(), `
+		SELECT data FROM extensions
+		WHERE course_id = $1 AND assignment_id = $2
+	`, assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query extensions for '%s': '%w'.", assignment.FullID(), err)
+	}
+	defer rows.Close()
+
+	extensions := make(map[string]*model.Extension)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read extension: '%w'.", err)
+		}
+
+		var extension model.Extension
+		err = util.JSONFromBytes(data, &extension)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to deserialize extension: '%w'.", err)
+		}
+
+		extensions[extension.GetKey()] = &extension
+	}
+
+	return extensions, rows.Err()
+}
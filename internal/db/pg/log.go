@@ -0,0 +1,75 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) LogDirect(record *log.Record) error {
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize log record: '%w'.", err)
+	}
+
+	_, err = this.pool.Exec(context.Background(), `
+		INSERT INTO logs (level, timestamp, course_id, assignment_id, user_email, data)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, int32(record.Level), int64(record.Timestamp), record.Course, record.Assignment, record.User, data)
+	if err != nil {
+		return fmt.Errorf("Failed to write log record: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetLogRecords(query log.ParsedLogQuery) ([]*log.Record, error) {
+	args := make([]any, 0)
+	sqlQuery := fmt.Sprintf("SELECT data FROM logs WHERE level >= %s AND timestamp >= %s",
+		addArg(&args, int32(query.Level)), addArg(&args, int64(query.After)))
+
+	if query.CourseID != "" {
+		sqlQuery += " AND course_id = " + addArg(&args, query.CourseID)
+
+		// Assignment ID will only be matched on if the course ID also matches.
+		if query.AssignmentID != "" {
+			sqlQuery += " AND assignment_id = " + addArg(&args, query.AssignmentID)
+		}
+	} else if query.AssignmentID != "" {
+		// An assignment without a course cannot match anything.
+		return make([]*log.Record, 0), nil
+	}
+
+	if query.UserEmail != "" {
+		sqlQuery += " AND user_email = " + addArg(&args, query.UserEmail)
+	}
+
+	sqlQuery += " ORDER BY id"
+
+	rows, err := this.pool.Query(context.Background(), sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query log records: '%w'.", err)
+	}
+	defer rows.Close()
+
+	records := make([]*log.Record, 0)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read log record: '%w'.", err)
+		}
+
+		var record log.Record
+		err = util.JSONFromBytes(data, &record)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize log record: '%w'.", err)
+		}
+
+		records = append(records, &record)
+	}
+
+	return records, rows.Err()
+}
@@ -0,0 +1,215 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/log"
+)
+
+// The schema is managed as an ordered list of migrations.
+// The number of applied migrations is stored in the schema_version table,
+// and EnsureTables() applies any migrations that have not yet been applied.
+// To change the schema, append a new migration.
+// Existing migrations must NEVER be edited, since they may have already been applied to a live database.
+var migrations []string = []string{
+	// 1: Initial schema.
+	`
+	CREATE TABLE courses (
+		id TEXT PRIMARY KEY,
+		data JSONB NOT NULL
+	);
+
+	CREATE TABLE assignments (
+		course_id TEXT NOT NULL,
+		id TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, id)
+	);
+
+	CREATE TABLE users (
+		email TEXT PRIMARY KEY,
+		data JSONB NOT NULL
+	);
+
+	CREATE TABLE course_users (
+		course_id TEXT NOT NULL,
+		email TEXT NOT NULL REFERENCES users (email) ON DELETE CASCADE,
+		PRIMARY KEY (course_id, email)
+	);
+
+	CREATE TABLE submissions (
+		id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		-- Short IDs are compared bytewise to match the ordering of the other backends.
+		short_id TEXT COLLATE "C" NOT NULL,
+		grading_start_time BIGINT NOT NULL,
+		info JSONB NOT NULL,
+		stdout TEXT NOT NULL,
+		stderr TEXT NOT NULL,
+		UNIQUE (course_id, assignment_id, user_email, short_id)
+	);
+
+	CREATE TABLE submission_files (
+		submission_id BIGINT NOT NULL REFERENCES submissions (id) ON DELETE CASCADE,
+		is_output BOOLEAN NOT NULL,
+		path TEXT NOT NULL,
+		contents BYTEA NOT NULL,
+		PRIMARY KEY (submission_id, is_output, path)
+	);
+
+	CREATE TABLE tasks (
+		hash TEXT PRIMARY KEY,
+		source TEXT NOT NULL,
+		course_id TEXT NOT NULL,
+		next_run_time BIGINT NOT NULL,
+		data JSONB NOT NULL
+	);
+
+	CREATE INDEX tasks_course_index ON tasks (course_id);
+	CREATE INDEX tasks_next_run_time_index ON tasks (next_run_time);
+
+	CREATE TABLE logs (
+		id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+		level INTEGER NOT NULL,
+		timestamp BIGINT NOT NULL,
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		data JSONB NOT NULL
+	);
+
+	CREATE INDEX logs_timestamp_index ON logs (timestamp);
+	CREATE INDEX logs_course_index ON logs (course_id, assignment_id);
+	CREATE INDEX logs_user_index ON logs (user_email);
+
+	CREATE TABLE system_stats (
+		id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+		timestamp BIGINT NOT NULL,
+		data JSONB NOT NULL
+	);
+
+	CREATE INDEX system_stats_timestamp_index ON system_stats (timestamp);
+
+	CREATE TABLE course_stats (
+		id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+		timestamp BIGINT NOT NULL,
+		type TEXT NOT NULL,
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		data JSONB NOT NULL
+	);
+
+	CREATE INDEX course_stats_course_index ON course_stats (course_id, timestamp);
+	`,
+
+	// 2: Grading jobs.
+	`
+	CREATE TABLE grading_jobs (
+		id TEXT PRIMARY KEY,
+		status TEXT NOT NULL,
+		create_time BIGINT NOT NULL,
+		data JSONB NOT NULL
+	);
+
+	CREATE INDEX grading_jobs_status_index ON grading_jobs (status, create_time);
+	`,
+
+	// 3: Manual grades.
+	`
+	CREATE TABLE manual_grades (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, user_email)
+	);
+	`,
+
+	// 4: Extensions.
+	`
+	CREATE TABLE extensions (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, user_email)
+	);
+	`,
+
+	// 5: Teams.
+	`
+	CREATE TABLE teams (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		team_id TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, team_id)
+	);
+	`,
+
+	// 6: Final submissions.
+	`
+	CREATE TABLE final_submissions (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, user_email)
+	);
+	`,
+}
+
+// Bring the schema up-to-date.
+// Multiple servers may share a database, so migrations are done under an advisory lock.
+func (this *backend) migrate() error {
+	return this.transaction(func(tx pgx.Tx) error {
+		ctx := context.Background()
+
+		_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", MIGRATION_LOCK_KEY)
+		if err != nil {
+			return fmt.Errorf("Failed to acquire schema migration lock: '%w'.", err)
+		}
+
+		_, err = tx.Exec(ctx, "CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)")
+		if err != nil {
+			return fmt.Errorf("Failed to create schema version table: '%w'.", err)
+		}
+
+		var version int
+		err = tx.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+		if err != nil {
+			return fmt.Errorf("Failed to get current schema version: '%w'.", err)
+		}
+
+		if version > len(migrations) {
+			return fmt.Errorf("Database schema version (%d) is newer than this server supports (%d).", version, len(migrations))
+		}
+
+		for i := version; i < len(migrations); i++ {
+			_, err = tx.Exec(ctx, migrations[i])
+			if err != nil {
+				return fmt.Errorf("Failed to apply schema migration %d: '%w'.", i+1, err)
+			}
+
+			log.Info("Applied database schema migration.", log.NewAttr("version", i+1))
+		}
+
+		_, err = tx.Exec(ctx, "DELETE FROM schema_version")
+		if err != nil {
+			return fmt.Errorf("Failed to clear schema version: '%w'.", err)
+		}
+
+		_, err = tx.Exec(ctx, "INSERT INTO schema_version (version) VALUES ($1)", len(migrations))
+		if err != nil {
+			return fmt.Errorf("Failed to record schema version: '%w'.", err)
+		}
+
+		return nil
+	})
+}
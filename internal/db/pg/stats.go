@@ -0,0 +1,126 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) StoreSystemStats(record *stats.SystemMetrics) error {
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize system stats: '%w'.", err)
+	}
+
+	_, err = this.pool.Exec(context.Background(), "INSERT INTO system_stats (timestamp, data) VALUES ($1, $2)", int64(record.Timestamp), data)
+	if err != nil {
+		return fmt.Errorf("Failed to write system stats: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetSystemStats(query stats.Query) ([]*stats.SystemMetrics, error) {
+	args := make([]any, 0)
+	sqlQuery := "SELECT data FROM system_stats"
+
+	// Push down time bounds when we know them, the query's Match() is still always applied.
+	baseQuery, ok := query.(stats.BaseQuery)
+	if ok {
+		sqlQuery += " WHERE " + timeClause(baseQuery, &args)
+	}
+
+	sqlQuery += " ORDER BY id"
+
+	return queryMetrics(this, sqlQuery, args, func(record *stats.SystemMetrics) bool {
+		return query.Match(record)
+	})
+}
+
+func (this *backend) StoreCourseMetric(record *stats.CourseMetric) error {
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize course metric: '%w'.", err)
+	}
+
+	_, err = this.pool.Exec(context.Background(), `
+		INSERT INTO course_stats (timestamp, type, course_id, assignment_id, user_email, data)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, int64(record.Timestamp), string(record.Type), record.CourseID, record.AssignmentID, record.UserEmail, data)
+	if err != nil {
+		return fmt.Errorf("Failed to write course metric: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetCourseMetrics(query stats.CourseMetricQuery) ([]*stats.CourseMetric, error) {
+	if query.CourseID == "" {
+		return nil, fmt.Errorf("When querying for course metrics, course ID must not be empty.")
+	}
+
+	args := make([]any, 0)
+	sqlQuery := "SELECT data FROM course_stats WHERE course_id = " + addArg(&args, query.CourseID)
+	sqlQuery += " AND " + timeClause(query.BaseQuery, &args)
+
+	if query.Type != stats.CourseMetricTypeUnknown {
+		sqlQuery += " AND type = " + addArg(&args, string(query.Type))
+	}
+
+	if query.AssignmentID != "" {
+		sqlQuery += " AND assignment_id = " + addArg(&args, query.AssignmentID)
+	}
+
+	if query.UserEmail != "" {
+		sqlQuery += " AND user_email = " + addArg(&args, query.UserEmail)
+	}
+
+	sqlQuery += " ORDER BY id"
+
+	return queryMetrics(this, sqlQuery, args, func(record *stats.CourseMetric) bool {
+		return query.Match(record)
+	})
+}
+
+// Get the WHERE clause (without the keyword) for the time bounds of a base query.
+// See stats.BaseQuery for the semantics.
+func timeClause(query stats.BaseQuery, args *[]any) string {
+	clause := "timestamp > " + addArg(args, int64(query.After))
+
+	if !query.Before.IsZero() {
+		clause += " AND timestamp < " + addArg(args, int64(query.Before))
+	}
+
+	return clause
+}
+
+func queryMetrics[T any](this *backend, query string, args []any, match func(*T) bool) ([]*T, error) {
+	rows, err := this.pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query stats: '%w'.", err)
+	}
+	defer rows.Close()
+
+	records := make([]*T, 0)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read stats: '%w'.", err)
+		}
+
+		var record T
+		err = util.JSONFromBytes(data, &record)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize stats: '%w'.", err)
+		}
+
+		if match(&record) {
+			records = append(records, &record)
+		}
+	}
+
+	return records, rows.Err()
+}
@@ -0,0 +1,104 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetActiveCourseTasks(course *model.Course) (map[string]*model.FullScheduledTask, error) {
+	return this.getTasks("WHERE source = $1 AND course_id = $2", string(model.TaskSourceCourse), course.ID)
+}
+
+func (this *backend) GetActiveTasks() (map[string]*model.FullScheduledTask, error) {
+	return this.getTasks("")
+}
+
+func (this *backend) GetNextActiveTask() (*model.FullScheduledTask, error) {
+	var data []byte
+	err := this.pool.QueryRow(context.Background(), "SELECT data FROM tasks ORDER BY next_run_time LIMIT 1").Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get next active task: '%w'.", err)
+	}
+
+	var task model.FullScheduledTask
+	err = util.JSONFromBytes(data, &task)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to deserialize task: '%w'.", err)
+	}
+
+	return &task, nil
+}
+
+func (this *backend) UpsertActiveTasks(upsertTasks map[string]*model.FullScheduledTask) error {
+	return this.transaction(func(tx pgx.Tx) error {
+		for hash, upsertTask := range upsertTasks {
+			if upsertTask == nil {
+				_, err := tx.Exec(context.Background(), "DELETE FROM tasks WHERE hash = $1", hash)
+				if err != nil {
+					return fmt.Errorf("Failed to remove task '%s': '%w'.", hash, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertTask)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize task '%s': '%w'.", hash, err)
+			}
+
+			_, err = tx.Exec(context.Background(), `
+				INSERT INTO tasks (hash, source, course_id, next_run_time, data) VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (hash) DO UPDATE SET
+					source = excluded.source,
+					course_id = excluded.course_id,
+					next_run_time = excluded.next_run_time,
+					data = excluded.data
+			`, hash, string(upsertTask.Source), upsertTask.CourseID, int64(upsertTask.NextRunTime), data)
+			if err != nil {
+				return fmt.Errorf("Failed to save task '%s': '%w'.", hash, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Get tasks (keyed by hash) using the given WHERE clause.
+func (this *backend) getTasks(query string, args ...any) (map[string]*model.FullScheduledTask, error) {
+	rows, err := this.pool.Query(context.Background(), "SELECT hash, data FROM tasks "+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query tasks: '%w'.", err)
+	}
+	defer rows.Close()
+
+	tasks := make(map[string]*model.FullScheduledTask)
+	for rows.Next() {
+		var hash string
+		var data []byte
+
+		err = rows.Scan(&hash, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read task: '%w'.", err)
+		}
+
+		var task model.FullScheduledTask
+		err = util.JSONFromBytes(data, &task)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize task '%s': '%w'.", hash, err)
+		}
+
+		tasks[hash] = &task
+	}
+
+	return tasks, rows.Err()
+}
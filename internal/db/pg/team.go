@@ -0,0 +1,68 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) SaveTeam(assignment *model.Assignment, team *model.Team) error {
+	data, err := util.ToJSON(team)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize team '%s' on '%s': '%w'.", team.ID, assignment.FullID(), err)
+	}
+
+	_, err = this.pool.Exec(context.Background(), `
+		INSERT INTO teams (course_id, assignment_id, team_id, data) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (course_id, assignment_id, team_id) DO UPDATE SET data = excluded.data
+	`, assignment.GetCourse().GetID(), assignment.GetID(), team.ID, data)
+	if err != nil {
+		return fmt.Errorf("Failed to save team '%s' on '%s': '%w'.", team.ID, assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveTeam(assignment *model.Assignment, teamID string) (bool, error) {
+	tag, err := this.pool.Exec(context.Background(), `
+		DELETE FROM teams
+		WHERE course_id = $1 AND assignment_id = $2 AND team_id = $3
+	`, assignment.GetCourse().GetID(), assignment.GetID(), teamID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove team '%s' on '%s': '%w'.", teamID, assignment.FullID(), err)
+	}
+
+	return (tag.RowsAffected() > 0), nil
+}
+
+func (this *backend) GetTeams(assignment *model.Assignment) (map[string]*model.Team, error) {
+	rows, err := this.pool.Query(context.Background(), `
+		SELECT data FROM teams
+		WHERE course_id = $1 AND assignment_id = $2
+	`, assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query teams for '%s': '%w'.", assignment.FullID(), err)
+	}
+	defer rows.Close()
+
+	teams := make(map[string]*model.Team)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read team: '%w'.", err)
+		}
+
+		var team model.Team
+		err = util.JSONFromBytes(data, &team)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to deserialize team: '%w'.", err)
+		}
+
+		teams[team.ID] = &team
+	}
+
+	return teams, rows.Err()
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Score overrides read, modify, and re-save a submission,
+// so make sure two overrides are not applied at the same time.
+var scoreOverrideLock sync.Mutex
+
+// Apply a score override to a submission (see model.GradingInfo.ApplyScoreOverride()).
+// The submission ID may be empty to use the user's most recent submission.
+// Returns the updated submission result, or (nil, nil) if the submission does not exist.
+func ApplyScoreOverride(assignment *model.Assignment, email string, submissionID string, override *model.ScoreOverride) (*model.GradingInfo, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	scoreOverrideLock.Lock()
+	defer scoreOverrideLock.Unlock()
+
+	shortSubmissionID := common.GetShortSubmissionID(submissionID)
+	submission, err := backend.GetSubmissionContents(assignment, email, shortSubmissionID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get submission: '%w'.", err)
+	}
+
+	if submission == nil {
+		return nil, nil
+	}
+
+	err = submission.Info.ApplyScoreOverride(override)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to apply score override: '%w'.", err)
+	}
+
+	err = backend.SaveSubmissions(assignment.GetCourse(), []*model.GradingResult{submission})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to save submission '%s': '%w'.", submission.Info.ID, err)
+	}
+
+	return submission.Info, nil
+}
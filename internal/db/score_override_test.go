@@ -0,0 +1,110 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestApplyScoreOverride(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+	email := "course-student@test.edulinq.org"
+
+	override := &model.ScoreOverride{
+		Question: "Q1",
+		Score:    0.5,
+		Author:   "course-grader@test.edulinq.org",
+		Reason:   "Bad style.",
+	}
+
+	info, err := ApplyScoreOverride(assignment, email, "", override)
+	if err != nil {
+		test.Fatalf("Failed to apply override: '%v'.", err)
+	}
+
+	if info == nil {
+		test.Fatalf("Did not find submission.")
+	}
+
+	// The override should be saved.
+	info, err = GetSubmissionResult(assignment, email, "")
+	if err != nil {
+		test.Fatalf("Failed to get submission result: '%v'.", err)
+	}
+
+	question := info.GetQuestion("Q1")
+	if (question.Score != 0.5) || (question.GraderScore == nil) || (*question.GraderScore != 1.0) {
+		test.Fatalf("Unexpected question after override: '%s'.", util.MustToJSONIndent(question))
+	}
+
+	if info.Score != 1.5 {
+		test.Fatalf("Unexpected submission score. Expected: 1.5, Actual: %f.", info.Score)
+	}
+
+	scoringInfos, err := GetScoringInfos(assignment, model.CourseRoleStudent)
+	if err != nil {
+		test.Fatalf("Failed to get scoring infos: '%v'.", err)
+	}
+
+	scoringInfo := scoringInfos[email]
+	if (scoringInfo.RawScore != 1.5) || !scoringInfo.ScoreOverridden {
+		test.Fatalf("Unexpected scoring info: '%s'.", util.MustToJSONIndent(scoringInfo))
+	}
+
+	// Remove the override.
+	override = &model.ScoreOverride{
+		Question: "Q1",
+		Remove:   true,
+		Author:   "course-admin@test.edulinq.org",
+		Reason:   "Style is fine.",
+	}
+
+	info, err = ApplyScoreOverride(assignment, email, info.ID, override)
+	if err != nil {
+		test.Fatalf("Failed to remove override: '%v'.", err)
+	}
+
+	info, err = GetSubmissionResult(assignment, email, "")
+	if err != nil {
+		test.Fatalf("Failed to get submission result after removal: '%v'.", err)
+	}
+
+	if (info.Score != 2.0) || info.HasScoreOverrides() {
+		test.Fatalf("Override was not removed: '%s'.", util.MustToJSONIndent(info))
+	}
+
+	if len(info.ScoreOverrides) != 2 {
+		test.Fatalf("Unexpected override history length. Expected: 2, Actual: %d.", len(info.ScoreOverrides))
+	}
+
+	if (info.ScoreOverrides[0].PreviousScore != 1.0) || (info.ScoreOverrides[1].PreviousScore != 0.5) {
+		test.Fatalf("Unexpected override history: '%s'.", util.MustToJSONIndent(info.ScoreOverrides))
+	}
+
+	// Bad question.
+	override = &model.ScoreOverride{
+		Question: "ZZZ",
+		Score:    1.0,
+		Author:   "course-grader@test.edulinq.org",
+		Reason:   "Bad question.",
+	}
+
+	_, err = ApplyScoreOverride(assignment, email, "", override)
+	if err == nil {
+		test.Fatalf("Did not get an error for an unknown question.")
+	}
+
+	// Missing submission.
+	info, err = ApplyScoreOverride(assignment, email, "ZZZ", override)
+	if err != nil {
+		test.Fatalf("Got an error for a missing submission: '%v'.", err)
+	}
+
+	if info != nil {
+		test.Fatalf("Got a result for a missing submission.")
+	}
+}
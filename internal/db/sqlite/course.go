@@ -0,0 +1,325 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	DUMP_ASSIGNMENTS_DIR       = "assignments"
+	DUMP_COURSE_STATS_FILENAME = "course-stats.jsonl"
+	DUMP_MANUAL_GRADES_DIR     = "manual-grades"
+	DUMP_EXTENSIONS_DIR        = "extensions"
+	DUMP_TEAMS_DIR             = "teams"
+	DUMP_FINAL_SUBMISSIONS_DIR = "final-submissions"
+)
+
+func (this *backend) ClearCourse(course *model.Course) error {
+	return this.transaction(func(tx *sql.Tx) error {
+		statements := []string{
+			"DELETE FROM submissions WHERE course_id = ?",
+			"DELETE FROM manual_grades WHERE course_id = ?",
+			"DELETE FROM extensions WHERE course_id = ?",
+			"DELETE FROM teams WHERE course_id = ?",
+			"DELETE FROM final_submissions WHERE course_id = ?",
+			"DELETE FROM assignments WHERE course_id = ?",
+			"DELETE FROM course_stats WHERE course_id = ?",
+			"DELETE FROM courses WHERE id = ?",
+		}
+
+		for _, statement := range statements {
+			_, err := tx.Exec(statement, course.GetID())
+			if err != nil {
+				return fmt.Errorf("Failed to clear course '%s': '%w'.", course.GetID(), err)
+			}
+		}
+
+		err := this.clearCourseUsers(tx, course)
+		if err != nil {
+			return fmt.Errorf("Failed to drop users from removed course: '%w'.", err)
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) AddTestCourse(path string) (*model.Course, error) {
+	path = util.ShouldAbs(path)
+
+	course, submissions, err := model.FullLoadCourseFromPath(path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = this.transaction(func(tx *sql.Tx) error {
+		err := this.saveCourse(tx, course)
+		if err != nil {
+			return err
+		}
+
+		return this.saveSubmissions(tx, submissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (this *backend) SaveCourse(course *model.Course) error {
+	return this.transaction(func(tx *sql.Tx) error {
+		return this.saveCourse(tx, course)
+	})
+}
+
+func (this *backend) saveCourse(tx querier, course *model.Course) error {
+	data, err := util.ToJSON(course)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize course '%s': '%w'.", course.GetID(), err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO courses (id, data) VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET data = excluded.data
+	`, course.GetID(), data)
+	if err != nil {
+		return fmt.Errorf("Failed to save course '%s': '%w'.", course.GetID(), err)
+	}
+
+	for _, assignment := range course.Assignments {
+		err = this.saveAssignment(tx, assignment)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dump the course into the same layout that the disk database uses.
+func (this *backend) DumpCourse(course *model.Course, targetDir string) error {
+	dbCourse, err := this.GetCourse(course.GetID())
+	if err != nil {
+		return err
+	}
+
+	if dbCourse == nil {
+		return fmt.Errorf("Unable to find course '%s' to dump.", course.GetID())
+	}
+
+	err = util.ToJSONFileIndent(dbCourse, filepath.Join(targetDir, model.COURSE_CONFIG_FILENAME))
+	if err != nil {
+		return fmt.Errorf("Failed to dump course config for '%s': '%w'.", course.GetID(), err)
+	}
+
+	for _, assignment := range dbCourse.Assignments {
+		assignmentDir := filepath.Join(targetDir, DUMP_ASSIGNMENTS_DIR, assignment.GetID())
+
+		err = util.MkDir(assignmentDir)
+		if err != nil {
+			return fmt.Errorf("Failed to make assignment dump dir '%s': '%w'.", assignmentDir, err)
+		}
+
+		err = util.ToJSONFileIndent(assignment, filepath.Join(assignmentDir, model.ASSIGNMENT_CONFIG_FILENAME))
+		if err != nil {
+			return fmt.Errorf("Failed to dump assignment config for '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		submissions, err := this.getSubmissions(this.db, `
+			WHERE course_id = ? AND assignment_id = ?
+			ORDER BY user_email, short_id
+		`, course.GetID(), assignment.GetID())
+		if err != nil {
+			return err
+		}
+
+		for _, submission := range submissions {
+			err = dumpSubmission(targetDir, submission)
+			if err != nil {
+				return err
+			}
+		}
+
+		grades, err := this.GetManualGrades(assignment)
+		if err != nil {
+			return err
+		}
+
+		for email, grade := range grades {
+			path := filepath.Join(targetDir, DUMP_MANUAL_GRADES_DIR, assignment.GetID(), email+".json")
+
+			err = util.MkDir(filepath.Dir(path))
+			if err != nil {
+				return fmt.Errorf("Failed to make manual grades dump dir: '%w'.", err)
+			}
+
+			err = util.ToJSONFileIndent(grade, path)
+			if err != nil {
+				return fmt.Errorf("Failed to dump manual grade for '%s' on '%s': '%w'.", email, assignment.FullID(), err)
+			}
+		}
+
+		extensions, err := this.GetExtensions(assignment)
+		if err != nil {
+			return err
+		}
+
+		for email, extension := range extensions {
+			path := filepath.Join(targetDir, DUMP_EXTENSIONS_DIR, assignment.GetID(), email+".json")
+
+			err = util.MkDir(filepath.Dir(path))
+			if err != nil {
+				return fmt.Errorf("Failed to make extensions dump dir: '%w'.", err)
+			}
+
+			err = util.ToJSONFileIndent(extension, path)
+			if err != nil {
+				return fmt.Errorf("Failed to dump extension for '%s' on '%s': '%w'.", email, assignment.FullID(), err)
+			}
+		}
+
+		teams, err := this.GetTeams(assignment)
+		if err != nil {
+			return err
+		}
+
+		for teamID, team := range teams {
+			path := filepath.Join(targetDir, DUMP_TEAMS_DIR, assignment.GetID(), teamID+".json")
+
+			err = util.MkDir(filepath.Dir(path))
+			if err != nil {
+				return fmt.Errorf("Failed to make teams dump dir: '%w'.", err)
+			}
+
+			err = util.ToJSONFileIndent(team, path)
+			if err != nil {
+				return fmt.Errorf("Failed to dump team '%s' on '%s': '%w'.", teamID, assignment.FullID(), err)
+			}
+		}
+
+		finals, err := this.GetFinalSubmissions(assignment)
+		if err != nil {
+			return err
+		}
+
+		for email, final := range finals {
+			path := filepath.Join(targetDir, DUMP_FINAL_SUBMISSIONS_DIR, assignment.GetID(), email+".json")
+
+			err = util.MkDir(filepath.Dir(path))
+			if err != nil {
+				return fmt.Errorf("Failed to make final submissions dump dir: '%w'.", err)
+			}
+
+			err = util.ToJSONFileIndent(final, path)
+			if err != nil {
+				return fmt.Errorf("Failed to dump final submission for '%s' on '%s': '%w'.", email, assignment.FullID(), err)
+			}
+		}
+	}
+
+	metrics, err := this.GetCourseMetrics(stats.CourseMetricQuery{CourseID: course.GetID()})
+	if err != nil {
+		return fmt.Errorf("Failed to get course metrics for dump: '%w'.", err)
+	}
+
+	statsPath := filepath.Join(targetDir, DUMP_COURSE_STATS_FILENAME)
+	for _, metric := range metrics {
+		err = util.AppendJSONLFile(statsPath, metric)
+		if err != nil {
+			return fmt.Errorf("Failed to dump course metric: '%w'.", err)
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) GetCourse(courseID string) (*model.Course, error) {
+	return this.getCourse(this.db, courseID)
+}
+
+func (this *backend) getCourse(q querier, courseID string) (*model.Course, error) {
+	var data []byte
+	err := q.QueryRow("SELECT data FROM courses WHERE id = ?", courseID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course '%s': '%w'.", courseID, err)
+	}
+
+	course, err := model.ReadCourseConfigFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query("SELECT data FROM assignments WHERE course_id = ? ORDER BY id", courseID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get assignments for course '%s': '%w'.", courseID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assignmentData []byte
+		err = rows.Scan(&assignmentData)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read assignment for course '%s': '%w'.", courseID, err)
+		}
+
+		_, err = model.ReadAssignmentConfigFromJSON(course, assignmentData)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load assignment for course '%s': '%w'.", courseID, err)
+		}
+	}
+
+	return course, rows.Err()
+}
+
+func (this *backend) GetCourses() (map[string]*model.Course, error) {
+	courseIDs, err := queryStrings(this.db, "SELECT id FROM courses ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course ids: '%w'.", err)
+	}
+
+	courses := make(map[string]*model.Course, len(courseIDs))
+	for _, courseID := range courseIDs {
+		course, err := this.GetCourse(courseID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load course '%s': '%w'", courseID, err)
+		}
+
+		if course != nil {
+			courses[course.GetID()] = course
+		}
+	}
+
+	return courses, nil
+}
+
+// Run a query that returns a single string column.
+func queryStrings(q querier, query string, args ...any) ([]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]string, 0)
+	for rows.Next() {
+		var value string
+		err = rows.Scan(&value)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
@@ -0,0 +1,269 @@
+// A database backend that lives in a single embedded SQLite file.
+// Meant for small deployments that want indexed queries and real transactions
+// without running a separate database server.
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	DB_FILENAME = "autograder.sqlite3"
+
+	// How long (in MS) a connection will wait on a locked database before giving up.
+	BUSY_TIMEOUT_MS = 10 * 1000
+)
+
+// All the tables, in the order they should be cleared.
+var tableNames []string = []string{
+	"submission_files",
+	"submissions",
+	"assignments",
+	"courses",
+	"course_users",
+	"users",
+	"tasks",
+	"grading_jobs",
+	"manual_grades",
+	"extensions",
+	"teams",
+	"final_submissions",
+	"logs",
+	"system_stats",
+	"course_stats",
+}
+
+const SCHEMA = `
+CREATE TABLE IF NOT EXISTS courses (
+    id TEXT PRIMARY KEY,
+    data TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS assignments (
+    course_id TEXT NOT NULL,
+    id TEXT NOT NULL,
+    data TEXT NOT NULL,
+    PRIMARY KEY (course_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    email TEXT PRIMARY KEY,
+    data TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS course_users (
+    course_id TEXT NOT NULL,
+    email TEXT NOT NULL REFERENCES users (email) ON DELETE CASCADE,
+    PRIMARY KEY (course_id, email)
+);
+
+CREATE TABLE IF NOT EXISTS submissions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id TEXT NOT NULL,
+    assignment_id TEXT NOT NULL,
+    user_email TEXT NOT NULL,
+    short_id TEXT NOT NULL,
+    grading_start_time INTEGER NOT NULL,
+    info TEXT NOT NULL,
+    stdout TEXT NOT NULL,
+    stderr TEXT NOT NULL,
+    UNIQUE (course_id, assignment_id, user_email, short_id)
+);
+
+CREATE TABLE IF NOT EXISTS submission_files (
+    submission_id INTEGER NOT NULL REFERENCES submissions (id) ON DELETE CASCADE,
+    is_output INTEGER NOT NULL,
+    path TEXT NOT NULL,
+    contents BLOB NOT NULL,
+    PRIMARY KEY (submission_id, is_output, path)
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    hash TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    course_id TEXT NOT NULL,
+    next_run_time INTEGER NOT NULL,
+    data TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS tasks_course_index ON tasks (course_id);
+CREATE INDEX IF NOT EXISTS tasks_next_run_time_index ON tasks (next_run_time);
+
+CREATE TABLE IF NOT EXISTS grading_jobs (
+    id TEXT PRIMARY KEY,
+    status TEXT NOT NULL,
+    create_time INTEGER NOT NULL,
+    data TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS grading_jobs_status_index ON grading_jobs (status, create_time);
+
+CREATE TABLE IF NOT EXISTS manual_grades (
+    course_id TEXT NOT NULL,
+    assignment_id TEXT NOT NULL,
+    user_email TEXT NOT NULL,
+    data TEXT NOT NULL,
+    PRIMARY KEY (course_id, assignment_id, user_email)
+);
+
+CREATE TABLE IF NOT EXISTS extensions (
+    course_id TEXT NOT NULL,
+    assignment_id TEXT NOT NULL,
+    user_email TEXT NOT NULL,
+    data TEXT NOT NULL,
+    PRIMARY KEY (course_id, assignment_id, user_email)
+);
+
+CREATE TABLE IF NOT EXISTS teams (
+    course_id TEXT NOT NULL,
+    assignment_id TEXT NOT NULL,
+    team_id TEXT NOT NULL,
+    data TEXT NOT NULL,
+    PRIMARY KEY (course_id, assignment_id, team_id)
+);
+
+CREATE TABLE IF NOT EXISTS final_submissions (
+    course_id TEXT NOT NULL,
+    assignment_id TEXT NOT NULL,
+    user_email TEXT NOT NULL,
+    data TEXT NOT NULL,
+    PRIMARY KEY (course_id, assignment_id, user_email)
+);
+
+CREATE TABLE IF NOT EXISTS logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    level INTEGER NOT NULL,
+    timestamp INTEGER NOT NULL,
+    course_id TEXT NOT NULL,
+    assignment_id TEXT NOT NULL,
+    user_email TEXT NOT NULL,
+    data TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS logs_timestamp_index ON logs (timestamp);
+CREATE INDEX IF NOT EXISTS logs_course_index ON logs (course_id, assignment_id);
+CREATE INDEX IF NOT EXISTS logs_user_index ON logs (user_email);
+
+CREATE TABLE IF NOT EXISTS system_stats (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp INTEGER NOT NULL,
+    data TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS system_stats_timestamp_index ON system_stats (timestamp);
+
+CREATE TABLE IF NOT EXISTS course_stats (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    course_id TEXT NOT NULL,
+    assignment_id TEXT NOT NULL,
+    user_email TEXT NOT NULL,
+    data TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS course_stats_course_index ON course_stats (course_id, timestamp);
+`
+
+type backend struct {
+	path string
+	db   *sql.DB
+}
+
+// The common query interface of *sql.DB and *sql.Tx,
+// so helpers can be used both inside and outside of transactions.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func Open() (*backend, error) {
+	path := config.DB_SQLITE_PATH.Get()
+	if path == "" {
+		path = filepath.Join(config.GetDatabaseDir(), DB_FILENAME)
+	}
+
+	path = util.ShouldAbs(path)
+
+	err := util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to make db dir '%s': '%w'.", filepath.Dir(path), err)
+	}
+
+	// Transactions are started immediately (instead of deferred) so that
+	// read-then-write transactions wait on the busy timeout instead of failing.
+	dsn := fmt.Sprintf("%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_txlock=immediate",
+		path, BUSY_TIMEOUT_MS)
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open SQLite database at '%s': '%w'.", path, err)
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Failed to connect to SQLite database at '%s': '%w'.", path, err)
+	}
+
+	log.Debug("Opened SQLite database.", log.NewAttr("path", path))
+
+	return &backend{path: path, db: db}, nil
+}
+
+func (this *backend) Close() error {
+	return this.db.Close()
+}
+
+func (this *backend) EnsureTables() error {
+	_, err := this.db.Exec(SCHEMA)
+	if err != nil {
+		return fmt.Errorf("Failed to create SQLite tables: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) Clear() error {
+	return this.transaction(func(tx *sql.Tx) error {
+		for _, tableName := range tableNames {
+			_, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", tableName))
+			if err != nil {
+				return fmt.Errorf("Failed to clear table '%s': '%w'.", tableName, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Run the given function inside a transaction.
+// The transaction will be committed if the function returns nil, and rolled back otherwise.
+func (this *backend) transaction(operation func(tx *sql.Tx) error) error {
+	tx, err := this.db.Begin()
+	if err != nil {
+		return fmt.Errorf("Failed to start transaction: '%w'.", err)
+	}
+
+	err = operation(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Failed to commit transaction: '%w'.", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) SaveExtension(assignment *model.Assignment, extension *model.Extension) error {
+	data, err := util.ToJSON(extension)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize extension for '%s' on '%s': '%w'.", extension.GetKey(), assignment.FullID(), err)
+	}
+
+	_, err = this.db.Exec(`
+		INSERT INTO extensions (course_id, assignment_id, user_email, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (course_id, assignment_id, user_email) DO UPDATE SET data = excluded.data
+	`, assignment.GetCourse().GetID(), assignment.GetID(), extension.GetKey(), data)
+	if err != nil {
+		return fmt.Errorf("Failed to save extension for '%s' on '%s': '%w'.", extension.GetKey(), assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveExtension(assignment *model.Assignment, key string) (bool, error) {
+	result, err := this.db.Exec(`
+		DELETE FROM extensions
+		WHERE course_id = ? AND assignment_id = ? AND user_email = ?
+	`, assignment.GetCourse().GetID(), assignment.GetID(), key)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove extension for '%s' on '%s': '%w'.", key, assignment.FullID(), err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to count removed extensions: '%w'.", err)
+	}
+
+	return (count > 0), nil
+}
+
+func (this *backend) GetExtensions(assignment *model.Assignment) (map[string]*model.Extension, error) {
+	rows, err := this.db.Query(`
+		SELECT data FROM extensions
+		WHERE course_id = ? AND assignment_id = ?
+	`, assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query extensions for '%s': '%w'.", assignment.FullID(), err)
+	}
+	defer rows.Close()
+
+	extensions := make(map[string]*model.Extension)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read extension: '%w'.", err)
+		}
+
+		var extension model.Extension
+		err = util.JSONFromBytes(data, &extension)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to deserialize extension: '%w'.", err)
+		}
+
+		extensions[extension.GetKey()] = &extension
+	}
+
+	return extensions, rows.Err()
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) SaveFinalSubmission(assignment *model.Assignment, final *model.FinalSubmission) error {
+	data, err := util.ToJSON(final)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize final submission for '%s' on '%s': '%w'.", final.User, assignment.FullID(), err)
+	}
+
+	_, err = this.db.Exec(`
+		INSERT INTO final_submissions (course_id, assignment_id, user_email, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (course_id, assignment_id, user_email) DO UPDATE SET data = excluded.data
+	`, assignment.GetCourse().GetID(), assignment.GetID(), final.User, data)
+	if err != nil {
+		return fmt.Errorf("Failed to save final submission for '%s' on '%s': '%w'.", final.User, assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveFinalSubmission(assignment *model.Assignment, email string) (bool, error) {
+	result, err := this.db.Exec(`
+		DELETE FROM final_submissions
+		WHERE course_id = ? AND assignment_id = ? AND user_email = ?
+	`, assignment.GetCourse().GetID(), assignment.GetID(), email)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove final submission for '%s' on '%s': '%w'.", email, assignment.FullID(), err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to count removed final submissions: '%w'.", err)
+	}
+
+	return (count > 0), nil
+}
+
+func (this *backend) GetFinalSubmissions(assignment *model.Assignment) (map[string]*model.FinalSubmission, error) {
+	rows, err := this.db.Query(`
+		SELECT data FROM final_submissions
+		WHERE course_id = ? AND assignment_id = ?
+	`, assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query final submissions for '%s': '%w'.", assignment.FullID(), err)
+	}
+	defer rows.Close()
+
+	finals := make(map[string]*model.FinalSubmission)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read final submission: '%w'.", err)
+		}
+
+		var final model.FinalSubmission
+		err = util.JSONFromBytes(data, &final)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to deserialize final submission: '%w'.", err)
+		}
+
+		finals[final.User] = &final
+	}
+
+	return finals, rows.Err()
+}
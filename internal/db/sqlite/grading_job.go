@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) SaveGradingJob(job *model.GradingJob) error {
+	return saveGradingJob(this.db, job)
+}
+
+func (this *backend) GetGradingJob(jobID string) (*model.GradingJob, error) {
+	return getGradingJob(this.db, "WHERE id = ?", jobID)
+}
+
+func (this *backend) GetGradingJobs(status model.GradingJobStatus) ([]*model.GradingJob, error) {
+	rows, err := this.db.Query("SELECT data FROM grading_jobs WHERE status = ? ORDER BY create_time, id", string(status))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query grading jobs: '%w'.", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*model.GradingJob, 0)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read grading job: '%w'.", err)
+		}
+
+		var job model.GradingJob
+		err = util.JSONFromBytes(data, &job)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to deserialize grading job: '%w'.", err)
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (this *backend) ClaimNextGradingJob(startTime timestamp.Timestamp) (*model.GradingJob, error) {
+	var job *model.GradingJob
+
+	err := this.transaction(func(tx *sql.Tx) error {
+		var err error
+		job, err = getGradingJob(tx, "WHERE status = ? ORDER BY create_time, id LIMIT 1", string(model.GradingJobStatusPending))
+		if (err != nil) || (job == nil) {
+			return err
+		}
+
+		job.Status = model.GradingJobStatusRunning
+		job.StartTime = startTime
+
+		return saveGradingJob(tx, job)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func saveGradingJob(q querier, job *model.GradingJob) error {
+	data, err := util.ToJSON(job)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize grading job '%s': '%w'.", job.ID, err)
+	}
+
+	_, err = q.Exec(`
+		INSERT INTO grading_jobs (id, status, create_time, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			status = excluded.status,
+			create_time = excluded.create_time,
+			data = excluded.data
+	`, job.ID, string(job.Status), int64(job.CreateTime), data)
+	if err != nil {
+		return fmt.Errorf("Failed to save grading job '%s': '%w'.", job.ID, err)
+	}
+
+	return nil
+}
+
+// Get the first grading job matching the given WHERE (and ORDER) clause.
+func getGradingJob(q querier, query string, args ...any) (*model.GradingJob, error) {
+	var data []byte
+	err := q.QueryRow("SELECT data FROM grading_jobs "+query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get grading job: '%w'.", err)
+	}
+
+	var job model.GradingJob
+	err = util.JSONFromBytes(data, &job)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to deserialize grading job: '%w'.", err)
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) LogDirect(record *log.Record) error {
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize log record: '%w'.", err)
+	}
+
+	_, err = this.db.Exec(`
+		INSERT INTO logs (level, timestamp, course_id, assignment_id, user_email, data)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, int32(record.Level), int64(record.Timestamp), record.Course, record.Assignment, record.User, data)
+	if err != nil {
+		return fmt.Errorf("Failed to write log record: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetLogRecords(query log.ParsedLogQuery) ([]*log.Record, error) {
+	sqlQuery := "SELECT data FROM logs WHERE level >= ? AND timestamp >= ?"
+	args := []any{int32(query.Level), int64(query.After)}
+
+	if query.CourseID != "" {
+		sqlQuery += " AND course_id = ?"
+		args = append(args, query.CourseID)
+
+		// Assignment ID will only be matched on if the course ID also matches.
+		if query.AssignmentID != "" {
+			sqlQuery += " AND assignment_id = ?"
+			args = append(args, query.AssignmentID)
+		}
+	} else if query.AssignmentID != "" {
+		// An assignment without a course cannot match anything.
+		return make([]*log.Record, 0), nil
+	}
+
+	if query.UserEmail != "" {
+		sqlQuery += " AND user_email = ?"
+		args = append(args, query.UserEmail)
+	}
+
+	sqlQuery += " ORDER BY id"
+
+	rows, err := this.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query log records: '%w'.", err)
+	}
+	defer rows.Close()
+
+	records := make([]*log.Record, 0)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read log record: '%w'.", err)
+		}
+
+		var record log.Record
+		err = util.JSONFromBytes(data, &record)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize log record: '%w'.", err)
+		}
+
+		records = append(records, &record)
+	}
+
+	return records, rows.Err()
+}
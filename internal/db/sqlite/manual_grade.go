@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) SaveManualGrade(assignment *model.Assignment, grade *model.ManualGrade) error {
+	data, err := util.ToJSON(grade)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize manual grade for '%s' on '%s': '%w'.", grade.User, assignment.FullID(), err)
+	}
+
+	_, err = this.db.Exec(`
+		INSERT INTO manual_grades (course_id, assignment_id, user_email, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (course_id, assignment_id, user_email) DO UPDATE SET data = excluded.data
+	`, assignment.GetCourse().GetID(), assignment.GetID(), grade.User, data)
+	if err != nil {
+		return fmt.Errorf("Failed to save manual grade for '%s' on '%s': '%w'.", grade.User, assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetManualGrades(assignment *model.Assignment) (map[string]*model.ManualGrade, error) {
+	rows, err := this.db.Query(`
+		SELECT data FROM manual_grades
+		WHERE course_id = ? AND assignment_id = ?
+	`, assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query manual grades for '%s': '%w'.", assignment.FullID(), err)
+	}
+	defer rows.Close()
+
+	grades := make(map[string]*model.ManualGrade)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read manual grade: '%w'.", err)
+		}
+
+		var grade model.ManualGrade
+		err = util.JSONFromBytes(data, &grade)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to deserialize manual grade: '%w'.", err)
+		}
+
+		grades[grade.User] = &grade
+	}
+
+	return grades, rows.Err()
+}
@@ -0,0 +1,470 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const SUBMISSION_COLUMNS = "id, info, stdout, stderr"
+
+func (this *backend) SaveSubmissions(course *model.Course, submissions []*model.GradingResult) error {
+	return this.transaction(func(tx *sql.Tx) error {
+		return this.saveSubmissions(tx, submissions)
+	})
+}
+
+func (this *backend) saveSubmissions(tx querier, submissions []*model.GradingResult) error {
+	var errs error = nil
+
+	for _, submission := range submissions {
+		errs = errors.Join(errs, this.saveSubmission(tx, submission))
+	}
+
+	return errs
+}
+
+func (this *backend) saveSubmission(tx querier, submission *model.GradingResult) error {
+	info := submission.Info
+
+	data, err := util.ToJSON(info)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize submission result '%s': '%w'.", info.ID, err)
+	}
+
+	// Saving over an existing submission replaces it (and its files).
+	_, err = tx.Exec(`
+		DELETE FROM submissions
+		WHERE course_id = ? AND assignment_id = ? AND user_email = ? AND short_id = ?
+	`, info.CourseID, info.AssignmentID, info.User, info.ShortID)
+	if err != nil {
+		return fmt.Errorf("Failed to remove old submission '%s': '%w'.", info.ID, err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO submissions
+			(course_id, assignment_id, user_email, short_id, grading_start_time, info, stdout, stderr)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, info.CourseID, info.AssignmentID, info.User, info.ShortID, int64(info.GradingStartTime), data, submission.Stdout, submission.Stderr)
+	if err != nil {
+		return fmt.Errorf("Failed to write submission result '%s': '%w'.", info.ID, err)
+	}
+
+	rowID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("Failed to get row id for submission '%s': '%w'.", info.ID, err)
+	}
+
+	err = saveSubmissionFiles(tx, rowID, false, submission.InputFilesGZip)
+	if err != nil {
+		return fmt.Errorf("Failed to write submission input files: '%w'.", err)
+	}
+
+	err = saveSubmissionFiles(tx, rowID, true, submission.OutputFilesGZip)
+	if err != nil {
+		return fmt.Errorf("Failed to write submission output files: '%w'.", err)
+	}
+
+	return nil
+}
+
+func saveSubmissionFiles(tx querier, rowID int64, isOutput bool, files map[string][]byte) error {
+	for path, contents := range files {
+		if contents == nil {
+			contents = []byte{}
+		}
+
+		_, err := tx.Exec("INSERT INTO submission_files (submission_id, is_output, path, contents) VALUES (?, ?, ?, ?)",
+			rowID, isOutput, path, contents)
+		if err != nil {
+			return fmt.Errorf("Failed to write submission file '%s': '%w'.", path, err)
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) GetNextSubmissionID(assignment *model.Assignment, email string) (string, error) {
+	submissionID := time.Now().Unix()
+
+	for {
+		var count int
+		err := this.db.QueryRow(`
+			SELECT COUNT(*) FROM submissions
+			WHERE course_id = ? AND assignment_id = ? AND user_email = ? AND short_id = ?
+		`, assignment.GetCourse().GetID(), assignment.GetID(), email, fmt.Sprintf("%d", submissionID)).Scan(&count)
+		if err != nil {
+			return "", fmt.Errorf("Failed to check for existing submission id: '%w'.", err)
+		}
+
+		if count == 0 {
+			break
+		}
+
+		// This ID has been used.
+		submissionID++
+	}
+
+	return fmt.Sprintf("%d", submissionID), nil
+}
+
+func (this *backend) GetSubmissionResult(assignment *model.Assignment, email string, shortSubmissionID string) (*model.GradingInfo, error) {
+	query, args := submissionSelector(assignment, email, shortSubmissionID)
+
+	var data []byte
+	err := this.db.QueryRow("SELECT info FROM submissions "+query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get submission result: '%w'.", err)
+	}
+
+	var gradingInfo model.GradingInfo
+	err = util.JSONFromBytes(data, &gradingInfo)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to deserialize grading info: '%w'.", err)
+	}
+
+	return &gradingInfo, nil
+}
+
+func (this *backend) GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.SubmissionHistoryItem, error) {
+	gradingInfos, err := this.getGradingInfos(`
+		WHERE course_id = ? AND assignment_id = ? AND user_email = ?
+		ORDER BY short_id
+	`, assignment.GetCourse().GetID(), assignment.GetID(), email)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*model.SubmissionHistoryItem, 0, len(gradingInfos))
+	for _, gradingInfo := range gradingInfos {
+		history = append(history, gradingInfo.ToHistoryItem())
+	}
+
+	return history, nil
+}
+
+func (this *backend) GetRecentSubmissions(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.GradingInfo, error) {
+	gradingInfos := make(map[string]*model.GradingInfo)
+
+	users, err := this.GetCourseUsers(assignment.Course)
+	if err != nil {
+		return nil, err
+	}
+
+	for email, user := range users {
+		if (filterRole != model.CourseRoleUnknown) && (filterRole != user.Role) {
+			continue
+		}
+
+		gradingInfos[email] = nil
+	}
+
+	recentInfos, err := this.getGradingInfos(`
+		WHERE
+			course_id = ?
+			AND assignment_id = ?
+			AND short_id = (
+				SELECT MAX(recent.short_id)
+				FROM submissions recent
+				WHERE
+					recent.course_id = submissions.course_id
+					AND recent.assignment_id = submissions.assignment_id
+					AND recent.user_email = submissions.user_email
+			)
+	`, assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, gradingInfo := range recentInfos {
+		_, ok := gradingInfos[gradingInfo.User]
+		if ok {
+			gradingInfos[gradingInfo.User] = gradingInfo
+		}
+	}
+
+	return gradingInfos, nil
+}
+
+func (this *backend) GetScoringInfos(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.ScoringInfo, error) {
+	scoringInfos := make(map[string]*model.ScoringInfo)
+
+	submissionResults, err := this.GetRecentSubmissions(assignment, filterRole)
+	if err != nil {
+		return nil, err
+	}
+
+	for email, submissionResult := range submissionResults {
+		if submissionResult == nil {
+			scoringInfos[email] = nil
+		} else {
+			scoringInfos[email] = submissionResult.ToScoringInfo()
+		}
+	}
+
+	return scoringInfos, nil
+}
+
+func (this *backend) GetRecentSubmissionSurvey(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.SubmissionHistoryItem, error) {
+	results := make(map[string]*model.SubmissionHistoryItem)
+
+	submissionResults, err := this.GetRecentSubmissions(assignment, filterRole)
+	if err != nil {
+		return nil, err
+	}
+
+	for email, submissionResult := range submissionResults {
+		if submissionResult == nil {
+			results[email] = nil
+		} else {
+			results[email] = submissionResult.ToHistoryItem()
+		}
+	}
+
+	return results, nil
+}
+
+func (this *backend) GetSubmissionContents(assignment *model.Assignment, email string, shortSubmissionID string) (*model.GradingResult, error) {
+	query, args := submissionSelector(assignment, email, shortSubmissionID)
+
+	submissions, err := this.getSubmissions(this.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(submissions) == 0 {
+		return nil, nil
+	}
+
+	return submissions[0], nil
+}
+
+func (this *backend) GetRecentSubmissionContents(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.GradingResult, error) {
+	results := make(map[string]*model.GradingResult)
+
+	users, err := this.GetCourseUsers(assignment.Course)
+	if err != nil {
+		return nil, err
+	}
+
+	for email, user := range users {
+		if (filterRole != model.CourseRoleUnknown) && (filterRole != user.Role) {
+			continue
+		}
+
+		result, err := this.GetSubmissionContents(assignment, email, "")
+		if err != nil {
+			return nil, err
+		}
+
+		results[email] = result
+	}
+
+	return results, nil
+}
+
+func (this *backend) RemoveSubmission(assignment *model.Assignment, email string, shortSubmissionID string) (bool, error) {
+	query, args := submissionSelector(assignment, email, shortSubmissionID)
+
+	result, err := this.db.Exec("DELETE FROM submissions WHERE id IN (SELECT id FROM submissions "+query+")", args...)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove submission '%s': '%w'", shortSubmissionID, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get the number of removed submissions: '%w'", err)
+	}
+
+	return (count > 0), nil
+}
+
+func (this *backend) GetSubmissionAttempts(assignment *model.Assignment, email string) ([]*model.GradingResult, error) {
+	return this.getSubmissions(this.db, `
+		WHERE course_id = ? AND assignment_id = ? AND user_email = ?
+		ORDER BY short_id
+	`, assignment.GetCourse().GetID(), assignment.GetID(), email)
+}
+
+func (this *backend) GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+	return queryStrings(this.db, `
+		SELECT DISTINCT user_email
+		FROM submissions
+		WHERE course_id = ? AND assignment_id = ?
+		ORDER BY user_email
+	`, assignment.GetCourse().GetID(), assignment.GetID())
+}
+
+// Get the WHERE/ORDER clause (and args) that select a specific (or the most recent) submission.
+func submissionSelector(assignment *model.Assignment, email string, shortSubmissionID string) (string, []any) {
+	args := []any{assignment.GetCourse().GetID(), assignment.GetID(), email}
+	query := "WHERE course_id = ? AND assignment_id = ? AND user_email = ?"
+
+	if shortSubmissionID != "" {
+		query += " AND short_id = ?"
+		args = append(args, shortSubmissionID)
+	}
+
+	query += " ORDER BY short_id DESC LIMIT 1"
+
+	return query, args
+}
+
+// Get just the grading infos for submissions matching the given WHERE (and ORDER) clause.
+func (this *backend) getGradingInfos(query string, args ...any) ([]*model.GradingInfo, error) {
+	rows, err := this.db.Query("SELECT info FROM submissions "+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query submissions: '%w'.", err)
+	}
+	defer rows.Close()
+
+	gradingInfos := make([]*model.GradingInfo, 0)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read submission: '%w'.", err)
+		}
+
+		var gradingInfo model.GradingInfo
+		err = util.JSONFromBytes(data, &gradingInfo)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to deserialize grading info: '%w'.", err)
+		}
+
+		gradingInfos = append(gradingInfos, &gradingInfo)
+	}
+
+	return gradingInfos, rows.Err()
+}
+
+// Get full submissions (including files) matching the given WHERE (and ORDER) clause.
+func (this *backend) getSubmissions(q querier, query string, args ...any) ([]*model.GradingResult, error) {
+	rowIDs, submissions, err := func() ([]int64, []*model.GradingResult, error) {
+		rows, err := q.Query("SELECT "+SUBMISSION_COLUMNS+" FROM submissions "+query, args...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to query submissions: '%w'.", err)
+		}
+		defer rows.Close()
+
+		rowIDs := make([]int64, 0)
+		submissions := make([]*model.GradingResult, 0)
+
+		for rows.Next() {
+			var rowID int64
+			var data []byte
+			var submission model.GradingResult
+
+			err = rows.Scan(&rowID, &data, &submission.Stdout, &submission.Stderr)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed to read submission: '%w'.", err)
+			}
+
+			var gradingInfo model.GradingInfo
+			err = util.JSONFromBytes(data, &gradingInfo)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Unable to deserialize grading info: '%w'.", err)
+			}
+
+			submission.Info = &gradingInfo
+
+			rowIDs = append(rowIDs, rowID)
+			submissions = append(submissions, &submission)
+		}
+
+		return rowIDs, submissions, rows.Err()
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, submission := range submissions {
+		submission.InputFilesGZip, submission.OutputFilesGZip, err = getSubmissionFiles(q, rowIDs[i])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get files for submission '%s': '%w'.", submission.Info.ID, err)
+		}
+	}
+
+	return submissions, nil
+}
+
+// Returns: (input files, output files, error).
+func getSubmissionFiles(q querier, rowID int64) (map[string][]byte, map[string][]byte, error) {
+	rows, err := q.Query("SELECT is_output, path, contents FROM submission_files WHERE submission_id = ?", rowID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	inputFiles := make(map[string][]byte)
+	outputFiles := make(map[string][]byte)
+
+	for rows.Next() {
+		var isOutput bool
+		var path string
+		var contents []byte
+
+		err = rows.Scan(&isOutput, &path, &contents)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if isOutput {
+			outputFiles[path] = contents
+		} else {
+			inputFiles[path] = contents
+		}
+	}
+
+	return inputFiles, outputFiles, rows.Err()
+}
+
+// Write a submission into the standard submission directory layout (rooted at the given course dir).
+func dumpSubmission(courseDir string, submission *model.GradingResult) error {
+	info := submission.Info
+	baseDir := filepath.Join(courseDir, model.SUBMISSIONS_DIRNAME, info.AssignmentID, info.User, info.ShortID)
+
+	err := util.MkDir(baseDir)
+	if err != nil {
+		return fmt.Errorf("Failed to make submission dir '%s': '%w'.", baseDir, err)
+	}
+
+	resultPath := filepath.Join(baseDir, model.SUBMISSION_RESULT_FILENAME)
+	err = util.ToJSONFileIndent(info, resultPath)
+	if err != nil {
+		return fmt.Errorf("Failed to write submission result '%s': '%w'.", resultPath, err)
+	}
+
+	err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_INPUT_DIRNAME), submission.InputFilesGZip)
+	if err != nil {
+		return fmt.Errorf("Failed to write submission input files: '%w'.", err)
+	}
+
+	err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_OUTPUT_DIRNAME), submission.OutputFilesGZip)
+	if err != nil {
+		return fmt.Errorf("Failed to write submission output files: '%w'.", err)
+	}
+
+	err = util.WriteFile(submission.Stdout, filepath.Join(baseDir, common.SUBMISSION_STDOUT_FILENAME))
+	if err != nil {
+		return fmt.Errorf("Failed to write submission stdout file: '%w'.", err)
+	}
+
+	err = util.WriteFile(submission.Stderr, filepath.Join(baseDir, common.SUBMISSION_STDERR_FILENAME))
+	if err != nil {
+		return fmt.Errorf("Failed to write submission stderr file: '%w'.", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetActiveCourseTasks(course *model.Course) (map[string]*model.FullScheduledTask, error) {
+	return this.getTasks("WHERE source = ? AND course_id = ?", string(model.TaskSourceCourse), course.ID)
+}
+
+func (this *backend) GetActiveTasks() (map[string]*model.FullScheduledTask, error) {
+	return this.getTasks("")
+}
+
+func (this *backend) GetNextActiveTask() (*model.FullScheduledTask, error) {
+	var data []byte
+	err := this.db.QueryRow("SELECT data FROM tasks ORDER BY next_run_time LIMIT 1").Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get next active task: '%w'.", err)
+	}
+
+	var task model.FullScheduledTask
+	err = util.JSONFromBytes(data, &task)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to deserialize task: '%w'.", err)
+	}
+
+	return &task, nil
+}
+
+func (this *backend) UpsertActiveTasks(upsertTasks map[string]*model.FullScheduledTask) error {
+	return this.transaction(func(tx *sql.Tx) error {
+		for hash, upsertTask := range upsertTasks {
+			if upsertTask == nil {
+				_, err := tx.Exec("DELETE FROM tasks WHERE hash = ?", hash)
+				if err != nil {
+					return fmt.Errorf("Failed to remove task '%s': '%w'.", hash, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertTask)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize task '%s': '%w'.", hash, err)
+			}
+
+			_, err = tx.Exec(`
+				INSERT INTO tasks (hash, source, course_id, next_run_time, data) VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (hash) DO UPDATE SET
+					source = excluded.source,
+					course_id = excluded.course_id,
+					next_run_time = excluded.next_run_time,
+					data = excluded.data
+			`, hash, string(upsertTask.Source), upsertTask.CourseID, int64(upsertTask.NextRunTime), data)
+			if err != nil {
+				return fmt.Errorf("Failed to save task '%s': '%w'.", hash, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Get tasks (keyed by hash) using the given WHERE clause.
+func (this *backend) getTasks(query string, args ...any) (map[string]*model.FullScheduledTask, error) {
+	rows, err := this.db.Query("SELECT hash, data FROM tasks "+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query tasks: '%w'.", err)
+	}
+	defer rows.Close()
+
+	tasks := make(map[string]*model.FullScheduledTask)
+	for rows.Next() {
+		var hash string
+		var data []byte
+
+		err = rows.Scan(&hash, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read task: '%w'.", err)
+		}
+
+		var task model.FullScheduledTask
+		err = util.JSONFromBytes(data, &task)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize task '%s': '%w'.", hash, err)
+		}
+
+		tasks[hash] = &task
+	}
+
+	return tasks, rows.Err()
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) SaveTeam(assignment *model.Assignment, team *model.Team) error {
+	data, err := util.ToJSON(team)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize team '%s' on '%s': '%w'.", team.ID, assignment.FullID(), err)
+	}
+
+	_, err = this.db.Exec(`
+		INSERT INTO teams (course_id, assignment_id, team_id, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (course_id, assignment_id, team_id) DO UPDATE SET data = excluded.data
+	`, assignment.GetCourse().GetID(), assignment.GetID(), team.ID, data)
+	if err != nil {
+		return fmt.Errorf("Failed to save team '%s' on '%s': '%w'.", team.ID, assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveTeam(assignment *model.Assignment, teamID string) (bool, error) {
+	result, err := this.db.Exec(`
+		DELETE FROM teams
+		WHERE course_id = ? AND assignment_id = ? AND team_id = ?
+	`, assignment.GetCourse().GetID(), assignment.GetID(), teamID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove team '%s' on '%s': '%w'.", teamID, assignment.FullID(), err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to count removed teams: '%w'.", err)
+	}
+
+	return (count > 0), nil
+}
+
+func (this *backend) GetTeams(assignment *model.Assignment) (map[string]*model.Team, error) {
+	rows, err := this.db.Query(`
+		SELECT data FROM teams
+		WHERE course_id = ? AND assignment_id = ?
+	`, assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query teams for '%s': '%w'.", assignment.FullID(), err)
+	}
+	defer rows.Close()
+
+	teams := make(map[string]*model.Team)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read team: '%w'.", err)
+		}
+
+		var team model.Team
+		err = util.JSONFromBytes(data, &team)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to deserialize team: '%w'.", err)
+		}
+
+		teams[team.ID] = &team
+	}
+
+	return teams, rows.Err()
+}
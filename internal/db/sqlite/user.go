@@ -0,0 +1,249 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetServerUsers() (map[string]*model.ServerUser, error) {
+	return this.getUsers(this.db, "SELECT data FROM users")
+}
+
+func (this *backend) GetCourseUsers(course *model.Course) (map[string]*model.CourseUser, error) {
+	users, err := this.getUsers(this.db, `
+		SELECT users.data
+		FROM users
+			JOIN course_users ON course_users.email = users.email
+		WHERE course_users.course_id = ?
+	`, course.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	courseUsers := make(map[string]*model.CourseUser)
+	for email, user := range users {
+		// Don't include root as a course user.
+		if email == model.RootUserEmail {
+			continue
+		}
+
+		courseUser, err := user.ToCourseUser(course.ID, false)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid user '%s': '%w'.", email, err)
+		}
+
+		if courseUser != nil {
+			courseUsers[courseUser.Email] = courseUser
+		}
+	}
+
+	return courseUsers, nil
+}
+
+func (this *backend) GetServerUser(email string) (*model.ServerUser, error) {
+	return this.getUser(this.db, email)
+}
+
+func (this *backend) UpsertUsers(upsertUsers map[string]*model.ServerUser) error {
+	return this.transaction(func(tx *sql.Tx) error {
+		for email, upsertUser := range upsertUsers {
+			if upsertUser == nil {
+				continue
+			}
+
+			user, err := this.getUser(tx, email)
+			if err != nil {
+				return fmt.Errorf("Failed to get user '%s' to merge before saving: '%w'.", email, err)
+			}
+
+			if user != nil {
+				_, err = user.Merge(upsertUser)
+				if err != nil {
+					return fmt.Errorf("User '%s' could not be merged with existing user: '%w'.", email, err)
+				}
+			} else {
+				user = upsertUser
+			}
+
+			err = this.writeUser(tx, user)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) DeleteUser(email string) error {
+	_, err := this.db.Exec("DELETE FROM users WHERE email = ?", email)
+	if err != nil {
+		return fmt.Errorf("Failed to delete user '%s': '%w'.", email, err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveUserFromCourse(course *model.Course, email string) error {
+	return this.transaction(func(tx *sql.Tx) error {
+		user, err := this.getUser(tx, email)
+		if err != nil {
+			return fmt.Errorf("Failed to get user when removing user '%s' from course: '%w'.", email, err)
+		}
+
+		if user == nil {
+			return nil
+		}
+
+		_, enrolled := user.CourseInfo[course.ID]
+		if !enrolled {
+			return nil
+		}
+
+		delete(user.CourseInfo, course.ID)
+
+		return this.writeUser(tx, user)
+	})
+}
+
+func (this *backend) DeleteUserToken(email string, tokenID string) (bool, error) {
+	removed := false
+
+	err := this.transaction(func(tx *sql.Tx) error {
+		user, err := this.getUser(tx, email)
+		if err != nil {
+			return fmt.Errorf("Failed to get user when deleting user token '%s': '%w'.", email, err)
+		}
+
+		if user == nil {
+			return nil
+		}
+
+		for i, token := range user.Tokens {
+			if tokenID == token.ID {
+				user.Tokens = slices.Delete(user.Tokens, i, i+1)
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			return nil
+		}
+
+		return this.writeUser(tx, user)
+	})
+
+	return removed, err
+}
+
+func (this *backend) getUser(q querier, email string) (*model.ServerUser, error) {
+	users, err := this.getUsers(q, "SELECT data FROM users WHERE email = ?", email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, exists := users[email]
+	if !exists {
+		return nil, nil
+	}
+
+	return user, nil
+}
+
+// Get the users from a query that selects a single column of user data.
+// Like the disk backend, all users will be validated and any validation errors will be returned with the users.
+func (this *backend) getUsers(q querier, query string, args ...any) (map[string]*model.ServerUser, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query users: '%w'.", err)
+	}
+	defer rows.Close()
+
+	users := make(map[string]*model.ServerUser)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read user: '%w'.", err)
+		}
+
+		var user model.ServerUser
+		err = util.JSONFromBytes(data, &user)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize user: '%w'.", err)
+		}
+
+		users[user.Email] = &user
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read users: '%w'.", err)
+	}
+
+	var errs error = nil
+	for _, user := range users {
+		errs = errors.Join(errs, user.Validate())
+	}
+
+	return users, errs
+}
+
+// Write a full user (and their course enrollments).
+func (this *backend) writeUser(tx querier, user *model.ServerUser) error {
+	data, err := util.ToJSON(user)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize user '%s': '%w'.", user.Email, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO users (email, data) VALUES (?, ?)
+		ON CONFLICT (email) DO UPDATE SET data = excluded.data
+	`, user.Email, data)
+	if err != nil {
+		return fmt.Errorf("Failed to save user '%s': '%w'.", user.Email, err)
+	}
+
+	_, err = tx.Exec("DELETE FROM course_users WHERE email = ?", user.Email)
+	if err != nil {
+		return fmt.Errorf("Failed to clear course enrollments for user '%s': '%w'.", user.Email, err)
+	}
+
+	for courseID, _ := range user.CourseInfo {
+		_, err = tx.Exec("INSERT INTO course_users (course_id, email) VALUES (?, ?)", courseID, user.Email)
+		if err != nil {
+			return fmt.Errorf("Failed to save course enrollment for user '%s' in course '%s': '%w'.", user.Email, courseID, err)
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) clearCourseUsers(tx querier, course *model.Course) error {
+	users, err := this.getUsers(tx, `
+		SELECT users.data
+		FROM users
+			JOIN course_users ON course_users.email = users.email
+		WHERE course_users.course_id = ?
+	`, course.GetID())
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		delete(user.CourseInfo, course.ID)
+
+		err = this.writeUser(tx, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
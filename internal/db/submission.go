@@ -65,12 +65,31 @@ func GetExistingScoringInfos(assignment *model.Assignment, filterRole model.Cour
 	return info, nil
 }
 
+// Get the scoring infos for the submission that counts for each user (see model.SubmissionSelectionPolicy).
 func GetScoringInfos(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.ScoringInfo, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
-	return backend.GetScoringInfos(assignment, filterRole)
+	var scoringInfos map[string]*model.ScoringInfo
+	var err error
+
+	if usesLatestSelection(assignment) {
+		scoringInfos, err = backend.GetScoringInfos(assignment, filterRole)
+	} else {
+		scoringInfos, err = getSelectedScoringInfos(assignment, filterRole)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = addManualScores(assignment, scoringInfos)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add manual scores for assignment '%s': '%w'.", assignment.FullID(), err)
+	}
+
+	return scoringInfos, nil
 }
 
 func GetRecentSubmissions(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.GradingInfo, error) {
@@ -81,6 +100,110 @@ func GetRecentSubmissions(assignment *model.Assignment, filterRole model.CourseU
 	return backend.GetRecentSubmissions(assignment, filterRole)
 }
 
+// Get the submission that counts for each user of the given role (see model.SubmissionSelectionPolicy).
+// Users without a submission (but with a matching role) will be represented with a nil map value.
+func GetSelectedSubmissions(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.GradingInfo, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	gradingInfos, err := backend.GetRecentSubmissions(assignment, filterRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if usesLatestSelection(assignment) {
+		return gradingInfos, nil
+	}
+
+	extensions, err := GetUserExtensions(assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get extensions: '%w'.", err)
+	}
+
+	finals, err := backend.GetFinalSubmissions(assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get final submissions: '%w'.", err)
+	}
+
+	for email, recentInfo := range gradingInfos {
+		if recentInfo == nil {
+			continue
+		}
+
+		history, err := backend.GetSubmissionHistory(assignment, email)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get submission history for '%s': '%w'.", email, err)
+		}
+
+		selected := assignment.SelectSubmission(history, extensions[email], finals[email])
+		if (selected == nil) || (selected.ShortID == recentInfo.ShortID) {
+			continue
+		}
+
+		gradingInfo, err := backend.GetSubmissionResult(assignment, email, selected.ShortID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get selected submission '%s' for '%s': '%w'.", selected.ShortID, email, err)
+		}
+
+		if gradingInfo != nil {
+			gradingInfos[email] = gradingInfo
+		}
+	}
+
+	return gradingInfos, nil
+}
+
+// Get an overview of the submission that counts for each user of the given role (see model.SubmissionSelectionPolicy).
+// Users without a submission (but with a matching role) will be represented with a nil map value.
+func GetSelectedSubmissionSurvey(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.SubmissionHistoryItem, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	if usesLatestSelection(assignment) {
+		return backend.GetRecentSubmissionSurvey(assignment, filterRole)
+	}
+
+	gradingInfos, err := GetSelectedSubmissions(assignment, filterRole)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*model.SubmissionHistoryItem, len(gradingInfos))
+	for email, gradingInfo := range gradingInfos {
+		if gradingInfo == nil {
+			results[email] = nil
+		} else {
+			results[email] = gradingInfo.ToHistoryItem()
+		}
+	}
+
+	return results, nil
+}
+
+func getSelectedScoringInfos(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.ScoringInfo, error) {
+	gradingInfos, err := GetSelectedSubmissions(assignment, filterRole)
+	if err != nil {
+		return nil, err
+	}
+
+	scoringInfos := make(map[string]*model.ScoringInfo, len(gradingInfos))
+	for email, gradingInfo := range gradingInfos {
+		if gradingInfo == nil {
+			scoringInfos[email] = nil
+		} else {
+			scoringInfos[email] = gradingInfo.ToScoringInfo()
+		}
+	}
+
+	return scoringInfos, nil
+}
+
+func usesLatestSelection(assignment *model.Assignment) bool {
+	return (assignment.SubmissionSelection == "") || (assignment.SubmissionSelection == model.SelectLatest)
+}
+
 func GetRecentSubmissionSurvey(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.SubmissionHistoryItem, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
@@ -122,3 +245,11 @@ func GetSubmissionAttempts(assignment *model.Assignment, email string) ([]*model
 
 	return backend.GetSubmissionAttempts(assignment, email)
 }
+
+func GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetSubmissionUsers(assignment)
+}
@@ -169,6 +169,37 @@ func (this *DBTests) DBTestGetSubmissionContentsStdoutStderr(test *testing.T) {
 	}
 }
 
+func (this *DBTests) DBTestGetSubmissionUsers(test *testing.T) {
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	emails, err := GetSubmissionUsers(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get submission users: '%v'.", err)
+	}
+
+	expected := []string{"course-student@test.edulinq.org"}
+	if !reflect.DeepEqual(expected, emails) {
+		test.Fatalf("Submission users do not match. Expected: '%v', Actual: '%v'.", expected, emails)
+	}
+
+	// Users removed from the course still have their submissions.
+	_, _, err = RemoveUserFromCourse(assignment.GetCourse(), "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to remove user from course: '%v'.", err)
+	}
+
+	emails, err = GetSubmissionUsers(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get submission users after removal: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, emails) {
+		test.Fatalf("Submission users do not match after removal. Expected: '%v', Actual: '%v'.", expected, emails)
+	}
+}
+
 const baseExpectedStdout string = `
 Autograder transcript for assignment: HW0.
 Grading started at 2023-11-11 22:13 and ended at 2023-11-11 22:13.
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Save (insert or replace) a team for an assignment.
+// Every member must be a student in the course, and may not already be on a different team for the assignment.
+func SaveTeam(assignment *model.Assignment, team *model.Team) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	if assignment.Teams == nil {
+		return fmt.Errorf("Assignment '%s' does not use teams.", assignment.FullID())
+	}
+
+	err := assignment.Teams.ValidateTeam(team)
+	if err != nil {
+		return fmt.Errorf("Refusing to save invalid team: '%w'.", err)
+	}
+
+	users, err := GetCourseUsers(assignment.GetCourse())
+	if err != nil {
+		return fmt.Errorf("Failed to get course users: '%w'.", err)
+	}
+
+	teams, err := GetTeams(assignment)
+	if err != nil {
+		return err
+	}
+
+	err = checkTeamMembers(assignment, team, users, teams)
+	if err != nil {
+		return err
+	}
+
+	return backend.SaveTeam(assignment, team)
+}
+
+// Check a team's members against the course roster and the assignment's other teams.
+func checkTeamMembers(assignment *model.Assignment, team *model.Team, users map[string]*model.CourseUser, teams map[string]*model.Team) error {
+	for _, member := range team.Members {
+		user := users[member]
+		if user == nil {
+			return fmt.Errorf("Team member '%s' is not in course '%s'.", member, assignment.GetCourse().GetID())
+		}
+
+		if user.Role != model.CourseRoleStudent {
+			return fmt.Errorf("Team member '%s' is not a student in course '%s' (role: '%s').", member, assignment.GetCourse().GetID(), user.Role.String())
+		}
+
+		for _, otherTeam := range teams {
+			if (otherTeam.ID != team.ID) && otherTeam.HasMember(member) {
+				return fmt.Errorf("Team member '%s' is already on team '%s'.", member, otherTeam.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Remove a stored team from an assignment.
+// Teams defined in the assignment config cannot be removed this way.
+// Returns true if a team was removed.
+func RemoveTeam(assignment *model.Assignment, teamID string) (bool, error) {
+	if backend == nil {
+		return false, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.RemoveTeam(assignment, teamID)
+}
+
+// Get all the teams for an assignment, keyed by team ID.
+// This includes teams from the assignment config, which are replaced by stored teams with the same ID.
+// Config teams are checked against the course roster (like stored teams are when they are saved),
+// and config teams that fail these checks are skipped.
+func GetTeams(assignment *model.Assignment) (map[string]*model.Team, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	teams := make(map[string]*model.Team)
+	if assignment.Teams == nil {
+		return teams, nil
+	}
+
+	storedTeams, err := backend.GetTeams(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(assignment.Teams.Teams) > 0 {
+		users, err := GetCourseUsers(assignment.GetCourse())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get course users: '%w'.", err)
+		}
+
+		for _, team := range assignment.Teams.Teams {
+			if storedTeams[team.ID] != nil {
+				continue
+			}
+
+			err = checkTeamMembers(assignment, team, users, storedTeams)
+			if err != nil {
+				log.Warn("Skipping invalid config team.", assignment, log.NewAttr("team", team.ID), err)
+				continue
+			}
+
+			teams[team.ID] = team
+		}
+	}
+
+	for id, team := range storedTeams {
+		teams[id] = team
+	}
+
+	return teams, nil
+}
+
+// Get the team a user is on for an assignment.
+// Returns (nil, nil) if the user is not on a team (or the assignment does not use teams).
+func GetUserTeam(assignment *model.Assignment, email string) (*model.Team, error) {
+	teams, err := GetTeams(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, team := range teams {
+		if team.HasMember(email) {
+			return team, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestTeams(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	// Teams cannot be saved for assignments without teams.
+	err := SaveTeam(assignment, &model.Team{ID: "a", Members: []string{"course-student@test.edulinq.org"}})
+	if err == nil {
+		test.Fatalf("Did not get an error when saving a team to an assignment without teams.")
+	}
+
+	MustSetTestStudents(assignment.GetCourse(), "course-other@test.edulinq.org", "course-grader@test.edulinq.org")
+
+	assignment.Teams = &model.TeamInfo{
+		MaxSize: 2,
+		Teams: []*model.Team{
+			&model.Team{ID: "config", Members: []string{"course-grader@test.edulinq.org"}},
+			// Config teams with members that are not students in the course are skipped.
+			&model.Team{ID: "config-not-student", Members: []string{"course-admin@test.edulinq.org"}},
+			&model.Team{ID: "config-not-enrolled", Members: []string{"ZZZ@test.edulinq.org"}},
+		},
+	}
+
+	err = assignment.Teams.Validate()
+	if err != nil {
+		test.Fatalf("Failed to validate teams: '%v'.", err)
+	}
+
+	MustSaveAssignment(assignment)
+
+	err = SaveTeam(assignment, &model.Team{ID: "a", Members: []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"}})
+	if err != nil {
+		test.Fatalf("Failed to save team: '%v'.", err)
+	}
+
+	invalidTeams := []*model.Team{
+		// Too big.
+		&model.Team{ID: "b", Members: []string{"course-admin@test.edulinq.org", "course-owner@test.edulinq.org", "server-admin@test.edulinq.org"}},
+		// Not in the course.
+		&model.Team{ID: "b", Members: []string{"ZZZ@test.edulinq.org"}},
+		// Not a student.
+		&model.Team{ID: "b", Members: []string{"course-admin@test.edulinq.org"}},
+		// Already on a stored team.
+		&model.Team{ID: "b", Members: []string{"course-student@test.edulinq.org"}},
+		// Already on a config team.
+		&model.Team{ID: "b", Members: []string{"course-grader@test.edulinq.org"}},
+	}
+
+	for i, team := range invalidTeams {
+		err = SaveTeam(assignment, team)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error when saving an invalid team.", i)
+		}
+	}
+
+	teams, err := GetTeams(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get teams: '%v'.", err)
+	}
+
+	if (len(teams) != 2) || (teams["config"].Source != model.TEAM_SOURCE_CONFIG) || (teams["a"].Source != model.TEAM_SOURCE_API) {
+		test.Fatalf("Unexpected teams: '%s'.", util.MustToJSONIndent(teams))
+	}
+
+	team, err := GetUserTeam(assignment, "course-other@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get user team: '%v'.", err)
+	}
+
+	if (team == nil) || (team.ID != "a") {
+		test.Fatalf("Unexpected user team: '%s'.", util.MustToJSONIndent(team))
+	}
+
+	removed, err := RemoveTeam(assignment, "a")
+	if err != nil {
+		test.Fatalf("Failed to remove team: '%v'.", err)
+	}
+
+	if !removed {
+		test.Fatalf("Team was not removed.")
+	}
+
+	// Config teams are not stored, so they cannot be removed.
+	removed, err = RemoveTeam(assignment, "config")
+	if err != nil {
+		test.Fatalf("Failed to remove config team: '%v'.", err)
+	}
+
+	if removed {
+		test.Fatalf("Config team was reported as removed.")
+	}
+
+	team, err = GetUserTeam(assignment, "course-other@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get removed user team: '%v'.", err)
+	}
+
+	if team != nil {
+		test.Fatalf("Found a removed team: '%s'.", util.MustToJSONIndent(team))
+	}
+}
@@ -31,6 +31,26 @@ func MustGetTestSubmissionAssignment() *model.Assignment {
 	return MustGetAssignment(TEST_SUBMISSION_COURSE_ID, TEST_SUBMISSION_ASSIGNMENT_ID)
 }
 
+// Make existing users students in a course (e.g., so they can be put on a team together).
+func MustSetTestStudents(course *model.Course, emails ...string) {
+	for _, email := range emails {
+		user := MustGetServerUser(email)
+		if user == nil {
+			log.Fatal("Test user does not exist.", course, log.NewUserAttr(email))
+		}
+
+		info := user.CourseInfo[course.GetID()]
+		if info == nil {
+			info = &model.UserCourseInfo{}
+			user.CourseInfo[course.GetID()] = info
+		}
+
+		info.Role = model.CourseRoleStudent
+
+		MustUpsertUser(user)
+	}
+}
+
 // Perform the standard actions that prep for a package's testing main.
 // Callers should make sure to cleanup after testing:
 // `defer db.CleanupTestingMain();`.
@@ -27,6 +27,9 @@ const (
 
 type BuildOptions struct {
 	Rebuild bool `help:"Rebuild images ignoring caches." default:"false"`
+
+	// Called right before an image is actually built (i.e., not when a build is skipped because nothing changed).
+	OnBuild func() `json:"-" kong:"-"`
 }
 
 func NewBuildOptions() *BuildOptions {
@@ -81,7 +84,52 @@ func BuildImageWithOptions(imageSource ImageSource, options *BuildOptions) error
 	return buildImage(imageSource, buildOptions, tar)
 }
 
-func buildImage(imageSource ImageSource, buildOptions types.ImageBuildOptions, tar io.ReadCloser) error {
+// Get a full docker build context (see writeDockerContext()) for an image as gzipped bytes (see util.GzipDirectoryToBytes()).
+// This allows the image to be built on another machine (see BuildImageFromContext()).
+func GetBuildContextGZip(imageInfo *ImageInfo) (map[string][]byte, error) {
+	tempDir, err := util.MkDirTemp(TEMPDIR_PREFIX + imageInfo.Name + "-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create temp build directory for '%s': '%w'.", imageInfo.Name, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	err = writeDockerContext(imageInfo, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.GzipDirectoryToBytes(tempDir)
+}
+
+// Build an image from a build context created by GetBuildContextGZip().
+func BuildImageFromContext(logId log.Loggable, imageName string, contextGZip map[string][]byte) error {
+	tempDir, err := util.MkDirTemp(TEMPDIR_PREFIX + imageName + "-")
+	if err != nil {
+		return fmt.Errorf("Failed to create temp build directory for '%s': '%w'.", imageName, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	err = util.GzipBytesToDirectory(tempDir, contextGZip)
+	if err != nil {
+		return fmt.Errorf("Failed to write build context for image '%s': '%w'.", imageName, err)
+	}
+
+	buildOptions := types.ImageBuildOptions{
+		Tags:        []string{imageName},
+		Dockerfile:  "Dockerfile",
+		Remove:      true,
+		ForceRemove: true,
+	}
+
+	tar, err := archive.TarWithOptions(tempDir, &archive.TarOptions{})
+	if err != nil {
+		return fmt.Errorf("Failed to create tar build context for image '%s': '%w'.", imageName, err)
+	}
+
+	return buildImage(logId, buildOptions, tar)
+}
+
+func buildImage(logId log.Loggable, buildOptions types.ImageBuildOptions, tar io.ReadCloser) error {
 	docker, err := getDockerClient()
 	if err != nil {
 		return err
@@ -93,8 +141,8 @@ func buildImage(imageSource ImageSource, buildOptions types.ImageBuildOptions, t
 		return fmt.Errorf("Failed to run docker image build command: '%w'.", err)
 	}
 
-	output, err := collectBuildOutput(imageSource, response)
-	log.Trace("Image Build Output", imageSource, log.NewAttr("image-build-output", output), err)
+	output, err := collectBuildOutput(response)
+	log.Trace("Image Build Output", logId, log.NewAttr("image-build-output", output), err)
 	if err != nil {
 		return fmt.Errorf("Found error(s) in Docker build output: '%w'.", err)
 	}
@@ -104,7 +152,7 @@ func buildImage(imageSource ImageSource, buildOptions types.ImageBuildOptions, t
 
 // Try to get the build output from a build response.
 // Note that the response may be from a failure.
-func collectBuildOutput(imageSource ImageSource, response types.ImageBuildResponse) (string, error) {
+func collectBuildOutput(response types.ImageBuildResponse) (string, error) {
 	if response.Body == nil {
 		return "", nil
 	}
@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/docker/docker/client"
+)
+
+// By convention, a process killed by a signal exits with this offset plus the signal number.
+const SIGNAL_EXIT_CODE_OFFSET = 128
+
+// How a grader (container or process) exited.
+type ExitStatus struct {
+	ExitCode  int  `json:"exit-code"`
+	OOMKilled bool `json:"oom-killed,omitempty"`
+	// The signal that killed the grader (zero if it was not killed by a signal).
+	Signal int `json:"signal,omitempty"`
+}
+
+// Did the grader exit with anything other than a clean, zero exit?
+func (this *ExitStatus) Failed() bool {
+	if this == nil {
+		return false
+	}
+
+	return (this.ExitCode != 0) || this.OOMKilled || (this.Signal != 0)
+}
+
+// Get a human-readable name for the signal that killed the grader, e.g., "killed" or "segmentation fault".
+func (this *ExitStatus) SignalName() string {
+	if (this == nil) || (this.Signal == 0) {
+		return ""
+	}
+
+	return syscall.Signal(this.Signal).String()
+}
+
+func (this *ExitStatus) String() string {
+	if this == nil {
+		return "<unknown>"
+	}
+
+	return fmt.Sprintf("exit code: %d, oom killed: %v, signal: %d", this.ExitCode, this.OOMKilled, this.Signal)
+}
+
+// Build an exit status from a container's exit code.
+// Containers do not directly report signals, so they are inferred from the exit code.
+func NewExitStatusFromCode(exitCode int, oomKilled bool) *ExitStatus {
+	status := &ExitStatus{
+		ExitCode:  exitCode,
+		OOMKilled: oomKilled,
+	}
+
+	if exitCode > SIGNAL_EXIT_CODE_OFFSET {
+		status.Signal = exitCode - SIGNAL_EXIT_CODE_OFFSET
+	}
+
+	return status
+}
+
+// Inspect a container that has stopped running to see how it exited.
+func getContainerExitStatus(docker *client.Client, containerID string, statusCode int64) (*ExitStatus, error) {
+	// Note that we are not using the grading context (it may already be done).
+	info, err := docker.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return NewExitStatusFromCode(int(statusCode), false), fmt.Errorf("Failed to inspect container '%s': '%w'.", containerID, err)
+	}
+
+	if info.State == nil {
+		return NewExitStatusFromCode(int(statusCode), false), nil
+	}
+
+	return NewExitStatusFromCode(info.State.ExitCode, info.State.OOMKilled), nil
+}
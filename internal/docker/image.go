@@ -52,6 +52,10 @@ func BuildImageFromSource(imageSource ImageSource, force bool, quick bool, optio
 		return nil
 	}
 
+	if options.OnBuild != nil {
+		options.OnBuild()
+	}
+
 	buildErr := BuildImageWithOptions(imageSource, options)
 
 	// Always try to store the result of cache building.
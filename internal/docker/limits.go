@@ -0,0 +1,143 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// The ulimits that can be set on a grader.
+// These are supported both by Docker and by the non-Docker grader.
+var ULIMIT_NAMES []string = []string{
+	"core",
+	"cpu",
+	"data",
+	"fsize",
+	"nofile",
+	"nproc",
+	"stack",
+}
+
+const BYTES_PER_MB = 1024 * 1024
+
+// Resource limits for a grader.
+// A zero value means that there is no limit.
+// MaxOutputMB is not applied to containers, the output is checked after the grader finishes.
+type ResourceLimits struct {
+	MaxMemoryMB int       `json:"max-memory-mb,omitempty"`
+	MaxCPUs     float64   `json:"max-cpus,omitempty"`
+	MaxPIDs     int       `json:"max-pids,omitempty"`
+	MaxTmpfsMB  int       `json:"max-tmpfs-mb,omitempty"`
+	MaxOutputMB int       `json:"max-output-mb,omitempty"`
+	Ulimits     []*Ulimit `json:"ulimits,omitempty"`
+}
+
+type Ulimit struct {
+	Name string `json:"name"`
+	Soft int64  `json:"soft"`
+	Hard int64  `json:"hard"`
+}
+
+func (this *ResourceLimits) Validate() error {
+	if this.MaxMemoryMB < 0 {
+		return fmt.Errorf("Max memory must be non-negative, found: %d.", this.MaxMemoryMB)
+	}
+
+	if this.MaxCPUs < 0 {
+		return fmt.Errorf("Max CPUs must be non-negative, found: %f.", this.MaxCPUs)
+	}
+
+	if this.MaxPIDs < 0 {
+		return fmt.Errorf("Max PIDs must be non-negative, found: %d.", this.MaxPIDs)
+	}
+
+	if this.MaxTmpfsMB < 0 {
+		return fmt.Errorf("Max tmpfs size must be non-negative, found: %d.", this.MaxTmpfsMB)
+	}
+
+	if this.MaxOutputMB < 0 {
+		return fmt.Errorf("Max output size must be non-negative, found: %d.", this.MaxOutputMB)
+	}
+
+	seenNames := make(map[string]bool, len(this.Ulimits))
+	for i, ulimit := range this.Ulimits {
+		if ulimit == nil {
+			return fmt.Errorf("Ulimit at index %d is nil.", i)
+		}
+
+		err := ulimit.Validate()
+		if err != nil {
+			return err
+		}
+
+		if seenNames[ulimit.Name] {
+			return fmt.Errorf("Ulimit '%s' is set multiple times.", ulimit.Name)
+		}
+
+		seenNames[ulimit.Name] = true
+	}
+
+	return nil
+}
+
+func (this *Ulimit) Validate() error {
+	found := false
+	for _, name := range ULIMIT_NAMES {
+		if this.Name == name {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("Unknown ulimit '%s', must be one of: '%v'.", this.Name, ULIMIT_NAMES)
+	}
+
+	if (this.Soft < 0) || (this.Hard < 0) {
+		return fmt.Errorf("Ulimit '%s' values must be non-negative, found soft: %d, hard: %d.", this.Name, this.Soft, this.Hard)
+	}
+
+	if this.Soft > this.Hard {
+		return fmt.Errorf("Ulimit '%s' soft value (%d) is greater than its hard value (%d).", this.Name, this.Soft, this.Hard)
+	}
+
+	return nil
+}
+
+// Apply these limits to the config for a container.
+// The max output size is not a container setting (the output dir is a host mount), see MaxOutputMB.
+func (this *ResourceLimits) applyToHostConfig(hostConfig *container.HostConfig) {
+	if this == nil {
+		return
+	}
+
+	if this.MaxMemoryMB > 0 {
+		hostConfig.Memory = int64(this.MaxMemoryMB) * BYTES_PER_MB
+
+		// Setting swap to the same value as memory disables swap.
+		hostConfig.MemorySwap = hostConfig.Memory
+	}
+
+	if this.MaxCPUs > 0 {
+		hostConfig.NanoCPUs = int64(this.MaxCPUs * 1e9)
+	}
+
+	if this.MaxPIDs > 0 {
+		pids := int64(this.MaxPIDs)
+		hostConfig.PidsLimit = &pids
+	}
+
+	if this.MaxTmpfsMB > 0 {
+		hostConfig.Tmpfs = map[string]string{
+			"/tmp": fmt.Sprintf("rw,exec,nosuid,size=%dm", this.MaxTmpfsMB),
+		}
+	}
+
+	for _, ulimit := range this.Ulimits {
+		hostConfig.Ulimits = append(hostConfig.Ulimits, &container.Ulimit{
+			Name: ulimit.Name,
+			Soft: ulimit.Soft,
+			Hard: ulimit.Hard,
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestResourceLimitsValidate(test *testing.T) {
+	testCases := []struct {
+		limits  ResourceLimits
+		isValid bool
+	}{
+		{ResourceLimits{}, true},
+		{ResourceLimits{MaxMemoryMB: 1, MaxCPUs: 0.5, MaxPIDs: 1, MaxTmpfsMB: 1, MaxOutputMB: 1}, true},
+		{ResourceLimits{Ulimits: []*Ulimit{&Ulimit{"nofile", 10, 20}, &Ulimit{"nproc", 10, 10}}}, true},
+
+		{ResourceLimits{MaxMemoryMB: -1}, false},
+		{ResourceLimits{MaxCPUs: -0.5}, false},
+		{ResourceLimits{MaxPIDs: -1}, false},
+		{ResourceLimits{MaxTmpfsMB: -1}, false},
+		{ResourceLimits{MaxOutputMB: -1}, false},
+
+		{ResourceLimits{Ulimits: []*Ulimit{nil}}, false},
+		{ResourceLimits{Ulimits: []*Ulimit{&Ulimit{"ZZZ", 10, 20}}}, false},
+		{ResourceLimits{Ulimits: []*Ulimit{&Ulimit{"nofile", 20, 10}}}, false},
+		{ResourceLimits{Ulimits: []*Ulimit{&Ulimit{"nofile", -1, 10}}}, false},
+		{ResourceLimits{Ulimits: []*Ulimit{&Ulimit{"nofile", 10, 20}, &Ulimit{"nofile", 10, 20}}}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.limits.Validate()
+		if testCase.isValid && (err != nil) {
+			test.Errorf("Case %d: Valid limits failed validation: '%v'.", i, err)
+		} else if !testCase.isValid && (err == nil) {
+			test.Errorf("Case %d: Invalid limits passed validation: '%s'.", i, util.MustToJSON(testCase.limits))
+		}
+	}
+}
+
+func TestResourceLimitsApplyToHostConfig(test *testing.T) {
+	limits := &ResourceLimits{
+		MaxMemoryMB: 2,
+		MaxCPUs:     1.5,
+		MaxPIDs:     3,
+		MaxTmpfsMB:  4,
+		MaxOutputMB: 5,
+		Ulimits:     []*Ulimit{&Ulimit{"nofile", 6, 7}},
+	}
+
+	pids := int64(3)
+	expected := &container.HostConfig{
+		Resources: container.Resources{
+			Memory:     2 * BYTES_PER_MB,
+			MemorySwap: 2 * BYTES_PER_MB,
+			NanoCPUs:   1500000000,
+			PidsLimit:  &pids,
+			Ulimits:    []*container.Ulimit{&container.Ulimit{Name: "nofile", Soft: 6, Hard: 7}},
+		},
+		Tmpfs: map[string]string{"/tmp": "rw,exec,nosuid,size=4m"},
+	}
+
+	actual := &container.HostConfig{}
+	limits.applyToHostConfig(actual)
+
+	if !reflect.DeepEqual(expected, actual) {
+		test.Fatalf("Host config does not match. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+
+	// Empty limits should not change anything.
+	actual = &container.HostConfig{}
+	(&ResourceLimits{}).applyToHostConfig(actual)
+
+	if !reflect.DeepEqual(&container.HostConfig{}, actual) {
+		test.Fatalf("Empty limits changed the host config: '%s'.", util.MustToJSONIndent(actual))
+	}
+}
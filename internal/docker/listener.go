@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"context"
+	"io"
+)
+
+type runListenerKey struct{}
+
+// Something that wants to know what a container is doing while it runs.
+// Listeners are called from the goroutines that are running/reading the container,
+// so they should return quickly and be safe to call concurrently.
+type RunListener interface {
+	// The container has been started.
+	ContainerStarted()
+
+	// The container wrote some output.
+	// |stream| is either "stdout" or "stderr".
+	// Output is only reported up to the max output size (see DOCKER_MAX_OUTPUT_SIZE_KB).
+	ContainerOutput(stream string, text string)
+}
+
+// Attach a listener to a context.
+// Any container run with this context (see RunContainer()) will report to the listener.
+func WithRunListener(ctx context.Context, listener RunListener) context.Context {
+	return context.WithValue(ctx, runListenerKey{}, listener)
+}
+
+// Get the listener attached to a context, or nil if there is none.
+func GetRunListener(ctx context.Context) RunListener {
+	if ctx == nil {
+		return nil
+	}
+
+	listener, _ := ctx.Value(runListenerKey{}).(RunListener)
+	return listener
+}
+
+// A writer that passes everything it gets to a listener (as output for a specific stream).
+type listenerWriter struct {
+	listener RunListener
+	stream   string
+}
+
+func (this *listenerWriter) Write(data []byte) (int, error) {
+	if len(data) > 0 {
+		this.listener.ContainerOutput(this.stream, string(data))
+	}
+
+	return len(data), nil
+}
+
+// Get a writer that reports output for a stream to a listener.
+// The listener may be nil (in which case the writer will discard all output).
+func NewListenerWriter(listener RunListener, stream string) io.Writer {
+	if listener == nil {
+		listener = nopListener{}
+	}
+
+	return &listenerWriter{
+		listener: listener,
+		stream:   stream,
+	}
+}
+
+type nopListener struct{}
+
+func (this nopListener) ContainerStarted() {}
+
+func (this nopListener) ContainerOutput(stream string, text string) {}
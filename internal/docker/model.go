@@ -26,6 +26,8 @@ type ImageInfo struct {
 
 	MaxRuntimeSecs int `json:"max-runtime-secs,omitempty"`
 
+	ResourceLimits
+
 	// Fields that are not part of the JSON and are set after deserialization.
 
 	Name string `json:"-"`
@@ -127,5 +129,10 @@ func (this *ImageInfo) Validate() error {
 		return fmt.Errorf("Max runtime seconds must be non-negative, found: %d.", this.MaxRuntimeSecs)
 	}
 
+	err = this.ResourceLimits.Validate()
+	if err != nil {
+		return fmt.Errorf("Failed to validate resource limits: '%w'.", err)
+	}
+
 	return nil
 }
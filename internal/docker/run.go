@@ -1,7 +1,6 @@
 package docker
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -28,11 +27,12 @@ type containerOutput struct {
 }
 
 // Run a container.
-// Returns: (stdout, stderr, timeout?, canceled?, error)
-func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inputDir string, outputDir string, baseID string, maxRuntimeSecs int) (string, string, bool, bool, error) {
+// The exit status will be nil if the container did not run to completion (e.g., a timeout).
+// Returns: (stdout, stderr, exit status, timeout?, canceled?, error)
+func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inputDir string, outputDir string, baseID string, maxRuntimeSecs int, limits *ResourceLimits) (string, string, *ExitStatus, bool, bool, error) {
 	docker, err := getDockerClient()
 	if err != nil {
-		return "", "", false, false, err
+		return "", "", nil, false, false, err
 	}
 	defer docker.Close()
 
@@ -44,32 +44,36 @@ func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inp
 	timeout := false
 	canceled := false
 
+	hostConfig := &container.HostConfig{
+		Mounts: []mount.Mount{
+			mount.Mount{
+				Type:     "bind",
+				Source:   inputDir,
+				Target:   "/autograder/input",
+				ReadOnly: true,
+			},
+			mount.Mount{
+				Type:     "bind",
+				Source:   outputDir,
+				Target:   "/autograder/output",
+				ReadOnly: false,
+			},
+		},
+		LogConfig: container.LogConfig{
+			// Don't store any logs, we will copy stdout/stderr directly.
+			Type: "none",
+		},
+	}
+
+	limits.applyToHostConfig(hostConfig)
+
 	containerInstance, err := docker.ContainerCreate(
 		ctx,
 		&container.Config{
 			Image:           imageName,
 			NetworkDisabled: true,
 		},
-		&container.HostConfig{
-			Mounts: []mount.Mount{
-				mount.Mount{
-					Type:     "bind",
-					Source:   inputDir,
-					Target:   "/autograder/input",
-					ReadOnly: true,
-				},
-				mount.Mount{
-					Type:     "bind",
-					Source:   outputDir,
-					Target:   "/autograder/output",
-					ReadOnly: false,
-				},
-			},
-			LogConfig: container.LogConfig{
-				// Don't store any logs, we will copy stdout/stderr directly.
-				Type: "none",
-			},
-		},
+		hostConfig,
 		nil,
 		nil,
 		name)
@@ -78,10 +82,10 @@ func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inp
 		timeout = errors.Is(err, context.DeadlineExceeded)
 		canceled = errors.Is(err, context.Canceled)
 		if timeout || canceled {
-			return "", "", timeout, canceled, nil
+			return "", "", nil, timeout, canceled, nil
 		}
 
-		return "", "", false, false, fmt.Errorf("Failed to create container '%s': '%w'.", name, err)
+		return "", "", nil, false, false, fmt.Errorf("Failed to create container '%s': '%w'.", name, err)
 	}
 
 	// Ensure the container is removed.
@@ -112,10 +116,10 @@ func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inp
 		timeout = errors.Is(err, context.DeadlineExceeded)
 		canceled = errors.Is(err, context.Canceled)
 		if timeout || canceled {
-			return "", "", timeout, canceled, nil
+			return "", "", nil, timeout, canceled, nil
 		}
 
-		return "", "", false, false, fmt.Errorf("Failed to attach to container '%s' (%s): '%w'.", name, containerInstance.ID, err)
+		return "", "", nil, false, false, fmt.Errorf("Failed to attach to container '%s' (%s): '%w'.", name, containerInstance.ID, err)
 	}
 	defer connection.Conn.Close()
 
@@ -123,17 +127,22 @@ func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inp
 	outputWaitGroup := &sync.WaitGroup{}
 	outputWaitGroup.Add(1)
 	output := &containerOutput{}
-	go handleContainerOutput(output, outputWaitGroup, connection.Reader)
+	listener := GetRunListener(ctx)
+	go handleContainerOutput(output, outputWaitGroup, connection.Reader, listener)
 
 	err = docker.ContainerStart(ctx, containerInstance.ID, container.StartOptions{})
 	if err != nil {
 		timeout = errors.Is(err, context.DeadlineExceeded)
 		canceled = errors.Is(err, context.Canceled)
 		if timeout || canceled {
-			return "", "", timeout, canceled, nil
+			return "", "", nil, timeout, canceled, nil
 		}
 
-		return "", "", false, false, fmt.Errorf("Failed to start container '%s' (%s): '%w'.", name, containerInstance.ID, err)
+		return "", "", nil, false, false, fmt.Errorf("Failed to start container '%s' (%s): '%w'.", name, containerInstance.ID, err)
+	}
+
+	if listener != nil {
+		listener.ContainerStarted()
 	}
 
 	// Set a timeout for the container.
@@ -144,6 +153,7 @@ func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inp
 	}
 
 	// Wait for the container to finish.
+	var exitStatus *ExitStatus = nil
 	statusChan, errorChan := docker.ContainerWait(ctx, containerInstance.ID, container.WaitConditionNotRunning)
 	select {
 	case err := <-errorChan:
@@ -156,10 +166,16 @@ func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inp
 				break
 			}
 
-			return "", "", false, false, fmt.Errorf("Got an error when running container '%s' (%s): '%w'.", name, containerInstance.ID, err)
+			return "", "", nil, false, false, fmt.Errorf("Got an error when running container '%s' (%s): '%w'.", name, containerInstance.ID, err)
+		}
+	case status := <-statusChan:
+		// Waiting is complete, see how the container exited.
+		exitStatus, err = getContainerExitStatus(docker, containerInstance.ID, status.StatusCode)
+		if err != nil {
+			log.Warn("Failed to get container exit status.",
+				err, logId,
+				log.NewAttr("container-name", name), log.NewAttr("container-id", containerInstance.ID))
 		}
-	case <-statusChan:
-		// Waiting is complete.
 	}
 
 	// Wait for output to get copied.
@@ -173,11 +189,12 @@ func RunContainer(ctx context.Context, logId log.Loggable, imageName string, inp
 		log.NewAttr("stderr", output.Stderr),
 		log.NewAttr("timeout", timeout),
 		log.NewAttr("canceled", canceled),
+		log.NewAttr("exit-status", exitStatus),
 		log.NewAttr("output-truncated", output.Truncated),
 		output.Err,
 	)
 
-	return output.Stdout, output.Stderr, timeout, canceled, nil
+	return output.Stdout, output.Stderr, exitStatus, timeout, canceled, nil
 }
 
 func cleanContainerName(text string) string {
@@ -193,17 +210,23 @@ func cleanContainerName(text string) string {
 }
 
 // Read a maximum amount from the container's stdout/stderr, parse the two from the common stream, and signal completion.
-func handleContainerOutput(output *containerOutput, outputWaitGroup *sync.WaitGroup, containerStream io.Reader) {
+// Output is passed to the listener (if not nil) as it is read.
+func handleContainerOutput(output *containerOutput, outputWaitGroup *sync.WaitGroup, containerStream io.Reader, listener RunListener) {
 	defer outputWaitGroup.Done()
 
 	maxSizeKB := config.DOCKER_MAX_OUTPUT_SIZE_KB.Get()
-	bufferLen := maxSizeKB * 1024
+	bufferLen := int64(maxSizeKB * 1024)
+
+	outBuffer := new(strings.Builder)
+	errBuffer := new(strings.Builder)
 
-	// Make the first full (or short) read.
-	buffer := make([]byte, bufferLen)
-	_, err := io.ReadFull(containerStream, buffer)
-	if (err != nil) && (err != io.EOF) && (err != io.ErrUnexpectedEOF) {
-		output.Err = fmt.Errorf("Failed to read container output into temporary buffer: '%w'.", err)
+	outWriter := io.MultiWriter(outBuffer, NewListenerWriter(listener, "stdout"))
+	errWriter := io.MultiWriter(errBuffer, NewListenerWriter(listener, "stderr"))
+
+	// Parse stdout and stderr out of the output stream as it comes in (up to the max size).
+	_, err := stdcopy.StdCopy(outWriter, errWriter, io.LimitReader(containerStream, bufferLen))
+	if err != nil {
+		output.Err = fmt.Errorf("Failed to read container output: '%w'.", err)
 		return
 	}
 
@@ -214,12 +237,6 @@ func handleContainerOutput(output *containerOutput, outputWaitGroup *sync.WaitGr
 		output.Truncated = true
 	}
 
-	// Parse stdout and stderr out of the output stream.
-	outBuffer := new(strings.Builder)
-	errBuffer := new(strings.Builder)
-
-	stdcopy.StdCopy(outBuffer, errBuffer, bytes.NewReader(buffer))
-
 	// Denote truncated streams.
 	if output.Truncated {
 		message := fmt.Sprintf("\n\nCombined output (stdout + stderr) exceeds maximum size (%d KB), output has been truncated.", maxSizeKB)
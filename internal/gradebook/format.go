@@ -0,0 +1,139 @@
+package gradebook
+
+import (
+	"encoding/csv"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Email, name, LMS ID, and then the score for each assignment (using autograder IDs).
+func writeGeneric(gradebook *Gradebook, writer *csv.Writer) error {
+	header := []string{"email", "name", "lms-id"}
+	for _, assignment := range gradebook.Assignments {
+		header = append(header, assignment.ID)
+	}
+
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range gradebook.Users {
+		row := []string{user.Email, user.Name, user.LMSID}
+		for _, assignment := range gradebook.Assignments {
+			row = append(row, getScoreString(user, assignment))
+		}
+
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The Canvas gradebook import format.
+// Canvas matches assignment columns by the ID in parentheses (so assignments should use their Canvas ID as their LMS ID),
+// and matches users by the SIS login ID (email) or ID (LMS ID).
+func writeCanvas(gradebook *Gradebook, writer *csv.Writer) error {
+	header := []string{"Student", "ID", "SIS User ID", "SIS Login ID", "Section"}
+	pointsPossible := []string{"    Points Possible", "", "", "", ""}
+
+	for _, assignment := range gradebook.Assignments {
+		name := assignment.Name
+		if name == "" {
+			name = assignment.ID
+		}
+
+		if assignment.LMSID != "" {
+			name = fmt.Sprintf("%s (%s)", name, assignment.LMSID)
+		}
+
+		header = append(header, name)
+		pointsPossible = append(pointsPossible, util.FloatToStr(assignment.MaxPoints))
+	}
+
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Write(pointsPossible)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range gradebook.Users {
+		row := []string{user.GetName(), user.LMSID, "", user.Email, ""}
+		for _, assignment := range gradebook.Assignments {
+			row = append(row, getScoreString(user, assignment))
+		}
+
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The Gradescope gradebook export format.
+// Each assignment has columns for its score, max points, submission time, and lateness.
+func writeGradescope(gradebook *Gradebook, writer *csv.Writer) error {
+	header := []string{"Name", "SID", "Email"}
+	for _, assignment := range gradebook.Assignments {
+		name := assignment.Name
+		if name == "" {
+			name = assignment.ID
+		}
+
+		header = append(header, name, name+" - Max Points", name+" - Submission Time", name+" - Lateness (H:M:S)")
+	}
+
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range gradebook.Users {
+		row := []string{user.GetName(), user.LMSID, user.Email}
+		for _, assignment := range gradebook.Assignments {
+			score := user.Scores[assignment.ID]
+			if score == nil {
+				row = append(row, "", util.FloatToStr(assignment.MaxPoints), "", "")
+				continue
+			}
+
+			submissionTime := ""
+			if !score.SubmissionTime.IsZero() {
+				submissionTime = score.SubmissionTime.SafeString()
+			}
+
+			row = append(row,
+				util.FloatToStr(score.Score),
+				util.FloatToStr(assignment.MaxPoints),
+				submissionTime,
+				fmt.Sprintf("%02d:00:00", max(0, score.NumDaysLate)*24))
+		}
+
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get the score (or empty string) for a user on an assignment.
+func getScoreString(user *User, assignment *Assignment) string {
+	score := user.Scores[assignment.ID]
+	if score == nil {
+		return ""
+	}
+
+	return util.FloatToStr(score.Score)
+}
@@ -0,0 +1,93 @@
+package gradebook
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// The scores of a set of users on a set of assignments.
+// Gradebooks can be written as CSV in several common formats (see model.GRADEBOOK_FORMATS).
+type Gradebook struct {
+	Assignments []*Assignment
+	Users       []*User
+}
+
+type Assignment struct {
+	// The autograder ID of the assignment.
+	ID        string
+	Name      string
+	LMSID     string
+	MaxPoints float64
+}
+
+type User struct {
+	Email string
+	Name  string
+	LMSID string
+
+	// Keyed by assignment ID.
+	// Users without a score for an assignment will not have an entry.
+	Scores map[string]*Score
+}
+
+type Score struct {
+	Score          float64
+	SubmissionTime timestamp.Timestamp
+	NumDaysLate    int
+}
+
+type formatWriter func(gradebook *Gradebook, writer *csv.Writer) error
+
+var formatWriters map[string]formatWriter = map[string]formatWriter{
+	model.GRADEBOOK_FORMAT_GENERIC:    writeGeneric,
+	model.GRADEBOOK_FORMAT_CANVAS:     writeCanvas,
+	model.GRADEBOOK_FORMAT_GRADESCOPE: writeGradescope,
+}
+
+func (this *Gradebook) WriteCSV(writer io.Writer, format string) error {
+	err := model.ValidateGradebookFormat(format)
+	if err != nil {
+		return err
+	}
+
+	csvWriter := csv.NewWriter(writer)
+
+	err = formatWriters[format](this, csvWriter)
+	if err != nil {
+		return fmt.Errorf("Failed to write '%s' gradebook: '%w'.", format, err)
+	}
+
+	csvWriter.Flush()
+
+	err = csvWriter.Error()
+	if err != nil {
+		return fmt.Errorf("Failed to write '%s' gradebook: '%w'.", format, err)
+	}
+
+	return nil
+}
+
+func (this *Gradebook) ToCSV(format string) (string, error) {
+	var buffer bytes.Buffer
+
+	err := this.WriteCSV(&buffer, format)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// Get the display name for a user (falling back to their email).
+func (this *User) GetName() string {
+	if this.Name != "" {
+		return this.Name
+	}
+
+	return this.Email
+}
@@ -0,0 +1,89 @@
+package gradebook
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestGradebookToCSV(test *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+		hasError bool
+	}{
+		{
+			model.GRADEBOOK_FORMAT_GENERIC,
+			"email,name,lms-id,hw0,hw1\n" +
+				"alice@test.edulinq.org,Alice,lms-alice,1.5,\n" +
+				"bob@test.edulinq.org,,lms-bob,,10\n",
+			false,
+		},
+		{
+			model.GRADEBOOK_FORMAT_CANVAS,
+			"Student,ID,SIS User ID,SIS Login ID,Section,Homework 0 (001),hw1\n" +
+				"\"    Points Possible\",,,,,2,10\n" +
+				"Alice,lms-alice,,alice@test.edulinq.org,,1.5,\n" +
+				"bob@test.edulinq.org,lms-bob,,bob@test.edulinq.org,,,10\n",
+			false,
+		},
+		{
+			model.GRADEBOOK_FORMAT_GRADESCOPE,
+			"Name,SID,Email," +
+				"Homework 0,Homework 0 - Max Points,Homework 0 - Submission Time,Homework 0 - Lateness (H:M:S)," +
+				"hw1,hw1 - Max Points,hw1 - Submission Time,hw1 - Lateness (H:M:S)\n" +
+				"Alice,lms-alice,alice@test.edulinq.org,1.5,2,2024-01-01T00:00:00Z,48:00:00,,10,,\n" +
+				"bob@test.edulinq.org,lms-bob,bob@test.edulinq.org,,2,,,10,10,,00:00:00\n",
+			false,
+		},
+		{"zzz", "", true},
+	}
+
+	for i, testCase := range testCases {
+		actual, err := getTestGradebook().ToCSV(testCase.format)
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Failed to write gradebook: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if actual != testCase.expected {
+			test.Errorf("Case %d: Unexpected CSV. Expected: \n'%s', \nActual: \n'%s'.", i, testCase.expected, actual)
+			continue
+		}
+	}
+}
+
+func getTestGradebook() *Gradebook {
+	return &Gradebook{
+		Assignments: []*Assignment{
+			&Assignment{ID: "hw0", Name: "Homework 0", LMSID: "001", MaxPoints: 2},
+			&Assignment{ID: "hw1", MaxPoints: 10},
+		},
+		Users: []*User{
+			&User{
+				Email: "alice@test.edulinq.org",
+				Name:  "Alice",
+				LMSID: "lms-alice",
+				Scores: map[string]*Score{
+					"hw0": &Score{Score: 1.5, SubmissionTime: timestamp.FromMSecs(1704067200000), NumDaysLate: 2},
+				},
+			},
+			&User{
+				Email: "bob@test.edulinq.org",
+				LMSID: "lms-bob",
+				Scores: map[string]*Score{
+					"hw1": &Score{Score: 10},
+				},
+			},
+		},
+	}
+}
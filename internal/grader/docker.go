@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/docker"
@@ -19,11 +18,11 @@ import (
 //   - output -- Passed in directory that will be mounted at DOCKER_OUTPUT_DIR.
 //   - work -- Should already be created inside the docker image, will only exist within the container.
 //
-// Returns: (result, file contents, stdout, stderr, failure message (soft failure), error (hard failure)).
-func runDockerGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (*model.GradingInfo, map[string][]byte, string, string, string, error) {
+// Returns: (result, file contents, stdout, stderr, exit status, failure message (soft failure), error (hard failure)).
+func runDockerGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (*model.GradingInfo, map[string][]byte, string, string, *docker.ExitStatus, string, error) {
 	tempDir, inputDir, outputDir, _, err := common.PrepTempGradingDir("docker")
 	if err != nil {
-		return nil, nil, "", "", "", err
+		return nil, nil, "", "", nil, "", err
 	}
 
 	if !options.LeaveTempDir {
@@ -35,38 +34,18 @@ func runDockerGrader(ctx context.Context, assignment *model.Assignment, submissi
 	// Copy over submission files to the temp input dir.
 	err = util.CopyDirent(submissionPath, inputDir, true)
 	if err != nil {
-		return nil, nil, "", "", "", fmt.Errorf("Failed to copy over submission/input contents: '%w'.", err)
+		return nil, nil, "", "", nil, "", fmt.Errorf("Failed to copy over submission/input contents: '%w'.", err)
 	}
 
-	stdout, stderr, timeout, canceled, err := docker.RunContainer(ctx, assignment, assignment.ImageName(), inputDir, outputDir, fullSubmissionID, assignment.MaxRuntimeSecs)
-	if err != nil {
-		return nil, nil, stdout, stderr, "", err
-	}
-
-	if timeout {
-		return nil, nil, stdout, stderr, getTimeoutMessage(assignment), nil
-	}
-
-	if canceled {
-		return nil, nil, stdout, stderr, getCanceledMessage(assignment), nil
-	}
-
-	resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME)
-	if !util.PathExists(resultPath) {
-		return nil, nil, stdout, stderr, "",
-			fmt.Errorf("Cannot find output file ('%s') after the grading container (%s) was run.", resultPath, assignment.ImageName())
-	}
+	stopWatch := watchGraderResult(ctx, outputDir)
+	stdout, stderr, exitStatus, timeout, canceled, err := docker.RunContainer(ctx, assignment, assignment.ImageName(), inputDir, outputDir, fullSubmissionID, assignment.MaxRuntimeSecs, assignment.GetEffectiveResourceLimits())
+	stopWatch()
 
-	var gradingInfo model.GradingInfo
-	err = util.JSONFromFile(resultPath, &gradingInfo)
 	if err != nil {
-		return nil, nil, stdout, stderr, "", err
+		return nil, nil, stdout, stderr, exitStatus, "", err
 	}
 
-	fileContents, err := util.GzipDirectoryToBytes(outputDir)
-	if err != nil {
-		return nil, nil, stdout, stderr, "", fmt.Errorf("Failed to copy grading output '%s': '%w'.", outputDir, err)
-	}
+	gradingInfo, fileContents, softGradingError, err := collectGraderOutput(assignment, outputDir, exitStatus, timeout, canceled)
 
-	return &gradingInfo, fileContents, stdout, stderr, "", nil
+	return gradingInfo, fileContents, stdout, stderr, exitStatus, softGradingError, err
 }
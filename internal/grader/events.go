@@ -0,0 +1,157 @@
+package grader
+
+// Report what is happening while a submission is being graded (see model.GradingEvent).
+// Events are reported to a listener attached to the grading context with WithEventListener().
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// How often to check a running grader's result file for new question results.
+var resultWatchIntervalMS int = 500
+
+// A function that gets called for each grading event.
+// Events are sent one at a time (in order), and the listener should return quickly (it blocks grading).
+type GradingEventListener func(event *model.GradingEvent)
+
+type gradingEventsKey struct{}
+
+// The event state for a single grading.
+// All methods are safe to call on a nil instance (they just do nothing).
+type gradingEvents struct {
+	listener GradingEventListener
+
+	lock sync.Mutex
+	// The number of questions that have already been reported.
+	questionCount int
+}
+
+// Attach a listener to a context.
+// Grading with this context (see Grade()) will report events to the listener.
+// Any containers run with this context will also report their status and output.
+func WithEventListener(ctx context.Context, listener GradingEventListener) context.Context {
+	events := &gradingEvents{
+		listener: listener,
+	}
+
+	ctx = context.WithValue(ctx, gradingEventsKey{}, events)
+	return docker.WithRunListener(ctx, events)
+}
+
+func getGradingEvents(ctx context.Context) *gradingEvents {
+	events, _ := ctx.Value(gradingEventsKey{}).(*gradingEvents)
+	return events
+}
+
+func (this *gradingEvents) send(event *model.GradingEvent) {
+	if this == nil {
+		return
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.listener(event)
+}
+
+func (this *gradingEvents) sendType(eventType model.GradingEventType) {
+	this.send(model.NewGradingEvent(eventType))
+}
+
+// Send an event for each question that has not already been reported.
+// Questions are identified by their position (graders add questions to their result as they are graded).
+func (this *gradingEvents) sendQuestions(questions []*model.GradedQuestion) {
+	if this == nil {
+		return
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for ; this.questionCount < len(questions); this.questionCount++ {
+		question := questions[this.questionCount]
+		if question == nil {
+			continue
+		}
+
+		event := model.NewGradingEvent(model.GradingEventTypeQuestion)
+		event.Question = question
+
+		this.listener(event)
+	}
+}
+
+func (this *gradingEvents) ContainerStarted() {
+	this.sendType(model.GradingEventTypeStarted)
+}
+
+func (this *gradingEvents) ContainerOutput(stream string, text string) {
+	if this == nil {
+		return
+	}
+
+	event := model.NewGradingEvent(model.GradingEventTypeOutput)
+	event.Stream = stream
+	event.Text = text
+
+	this.send(event)
+}
+
+// Watch a running grader's output dir and report new question results as the grader writes them.
+// Returns a function that stops the watch (and waits for it to stop).
+// The final result is not reported here (see Grade()), so questions written right before the grader exits are not lost.
+func watchGraderResult(ctx context.Context, outputDir string) func() {
+	events := getGradingEvents(ctx)
+	if events == nil {
+		return func() {}
+	}
+
+	resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME)
+
+	done := make(chan any)
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(1)
+
+	go func() {
+		defer waitGroup.Done()
+
+		ticker := time.NewTicker(time.Duration(resultWatchIntervalMS) * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !util.PathExists(resultPath) {
+				continue
+			}
+
+			// The grader may be in the middle of writing the file, just try again later.
+			var gradingInfo model.GradingInfo
+			err := util.JSONFromFile(resultPath, &gradingInfo)
+			if err != nil {
+				continue
+			}
+
+			events.sendQuestions(gradingInfo.Questions)
+		}
+	}()
+
+	return func() {
+		close(done)
+		waitGroup.Wait()
+	}
+}
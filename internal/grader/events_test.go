@@ -0,0 +1,235 @@
+package grader
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type testEventCollector struct {
+	lock   sync.Mutex
+	events []*model.GradingEvent
+}
+
+func (this *testEventCollector) listen(event *model.GradingEvent) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.events = append(this.events, event)
+}
+
+// Get the types of all non-output events (output can come at any point).
+func (this *testEventCollector) types() []model.GradingEventType {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	types := make([]model.GradingEventType, 0, len(this.events))
+	for _, event := range this.events {
+		if event.Type != model.GradingEventTypeOutput {
+			types = append(types, event.Type)
+		}
+	}
+
+	return types
+}
+
+func (this *testEventCollector) output(stream string) string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	text := ""
+	for _, event := range this.events {
+		if (event.Type == model.GradingEventTypeOutput) && (event.Stream == stream) {
+			text += event.Text
+		}
+	}
+
+	return text
+}
+
+func TestGradeEventsBase(test *testing.T) {
+	oldDockerVal := config.DOCKER_DISABLE.Get()
+	config.DOCKER_DISABLE.Set(true)
+	defer config.DOCKER_DISABLE.Set(oldDockerVal)
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	submissionDir := filepath.Join(assignment.GetSourceDir(), "test-submissions", "solution")
+
+	collector := &testEventCollector{}
+	ctx := WithEventListener(context.Background(), collector.listen)
+
+	options := GetDefaultGradeOptions()
+	options.NoDocker = true
+	options.Remote = false
+	options.DryRun = true
+
+	result, reject, softError, err := Grade(ctx, assignment, submissionDir, BASE_TEST_USER, TEST_MESSAGE, false, options)
+	if err != nil {
+		test.Fatalf("Failed to grade: '%v'.", err)
+	}
+
+	if (reject != nil) || (softError != "") {
+		test.Fatalf("Grading was not successful: reject: '%v', soft error: '%s'.", reject, softError)
+	}
+
+	expected := []model.GradingEventType{
+		model.GradingEventTypeQueued,
+		model.GradingEventTypeStarted,
+		model.GradingEventTypeQuestion,
+		model.GradingEventTypeFinished,
+	}
+
+	actual := collector.types()
+	if !slices.Equal(expected, actual) {
+		test.Fatalf("Unexpected event types. Expected: '%v', Actual: '%v'.", expected, actual)
+	}
+
+	var question *model.GradedQuestion
+	for _, event := range collector.events {
+		if event.Type == model.GradingEventTypeQuestion {
+			question = event.Question
+		}
+	}
+
+	if (question == nil) || (question.Name != result.Info.Questions[0].Name) || (question.Score != result.Info.Questions[0].Score) {
+		test.Fatalf("Unexpected question event. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(result.Info.Questions[0]), util.MustToJSONIndent(question))
+	}
+}
+
+// Grading without a listener should not send events anywhere (or break).
+func TestGradeEventsNoListener(test *testing.T) {
+	events := getGradingEvents(context.Background())
+	if events != nil {
+		test.Fatalf("Got events for a context without a listener.")
+	}
+
+	// Should be a no-op.
+	events.sendType(model.GradingEventTypeQueued)
+	events.sendQuestions([]*model.GradedQuestion{&model.GradedQuestion{Name: "Q1"}})
+	events.ContainerOutput("stdout", "foo")
+
+	stopWatch := watchGraderResult(context.Background(), "")
+	stopWatch()
+}
+
+func TestRunCMDEventsOutput(test *testing.T) {
+	collector := &testEventCollector{}
+	ctx := WithEventListener(context.Background(), collector.listen)
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", "echo 'foo'; echo 'bar' 1>&2")
+
+	stdout, stderr, exitStatus, _, _, err := runCMD(ctx, cmd, nil)
+	if err != nil {
+		test.Fatalf("Failed to run command: '%v'.", err)
+	}
+
+	if (exitStatus == nil) || (exitStatus.ExitCode != 0) {
+		test.Fatalf("Unexpected exit status: '%v'.", exitStatus)
+	}
+
+	if (stdout != collector.output("stdout")) || (stdout != "foo\n") {
+		test.Fatalf("Unexpected stdout. Expected: 'foo\\n', Returned: '%s', Event: '%s'.", stdout, collector.output("stdout"))
+	}
+
+	if (stderr != collector.output("stderr")) || (stderr != "bar\n") {
+		test.Fatalf("Unexpected stderr. Expected: 'bar\\n', Returned: '%s', Event: '%s'.", stderr, collector.output("stderr"))
+	}
+
+	expected := []model.GradingEventType{model.GradingEventTypeStarted}
+	if !slices.Equal(expected, collector.types()) {
+		test.Fatalf("Unexpected event types. Expected: '%v', Actual: '%v'.", expected, collector.types())
+	}
+
+	// The listener should also be reachable through docker.
+	if docker.GetRunListener(ctx) == nil {
+		test.Fatalf("Could not find run listener in context.")
+	}
+}
+
+func TestWatchGraderResultBase(test *testing.T) {
+	oldInterval := resultWatchIntervalMS
+	resultWatchIntervalMS = 10
+	defer func() {
+		resultWatchIntervalMS = oldInterval
+	}()
+
+	outputDir := util.MustMkDirTemp("test-watch-result-")
+	defer os.RemoveAll(outputDir)
+
+	resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME)
+
+	collector := &testEventCollector{}
+	ctx := WithEventListener(context.Background(), collector.listen)
+
+	stopWatch := watchGraderResult(ctx, outputDir)
+	defer stopWatch()
+
+	// A partial write should be ignored.
+	err := util.WriteFile(`{"questions": [`, resultPath)
+	if err != nil {
+		test.Fatalf("Failed to write partial result: '%v'.", err)
+	}
+
+	writeResult := func(names ...string) {
+		info := model.GradingInfo{}
+		for _, name := range names {
+			info.Questions = append(info.Questions, &model.GradedQuestion{Name: name})
+		}
+
+		err := util.ToJSONFile(info, resultPath)
+		if err != nil {
+			test.Fatalf("Failed to write result: '%v'.", err)
+		}
+	}
+
+	writeResult("Q1")
+	waitForEventCount(test, collector, 1)
+
+	writeResult("Q1", "Q2", "Q3")
+	waitForEventCount(test, collector, 3)
+
+	// Give the watcher a chance to (incorrectly) send questions again.
+	time.Sleep(50 * time.Millisecond)
+
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	names := make([]string, 0, len(collector.events))
+	for _, event := range collector.events {
+		if event.Type != model.GradingEventTypeQuestion {
+			test.Fatalf("Got a non-question event: '%s'.", util.MustToJSONIndent(event))
+		}
+
+		names = append(names, event.Question.Name)
+	}
+
+	expected := []string{"Q1", "Q2", "Q3"}
+	if !slices.Equal(expected, names) {
+		test.Fatalf("Unexpected questions. Expected: '%v', Actual: '%v'.", expected, names)
+	}
+}
+
+func waitForEventCount(test *testing.T, collector *testEventCollector, count int) {
+	for i := 0; i < 200; i++ {
+		if len(collector.types()) >= count {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	test.Fatalf("Timed out waiting for %d events, found %d.", count, len(collector.types()))
+}
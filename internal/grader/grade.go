@@ -3,11 +3,13 @@ package grader
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/stats"
 	"github.com/edulinq/autograder/internal/timestamp"
@@ -16,13 +18,27 @@ import (
 
 type GradeOptions struct {
 	NoDocker     bool
+	Remote       bool
 	LeaveTempDir bool
 	AllowLate    bool
+
+	// Do not save the grading result.
+	DryRun bool
+
+	// When the submission was made (if it was made before grading started, e.g., it was queued).
+	// Used for the release window, late, and submission limit checks and as the grading start time.
+	// A zero value means the submission was made when grading starts.
+	SubmitTime timestamp.Timestamp
+
+	// The submission being regraded (if any).
+	// The result will be tagged as a regrade and keep the grading times of the original submission.
+	RegradeOf *model.GradingInfo
 }
 
 func GetDefaultGradeOptions() GradeOptions {
 	return GradeOptions{
 		NoDocker:     config.DOCKER_DISABLE.Get(),
+		Remote:       config.GRADING_REMOTE_ENABLE.Get(),
 		LeaveTempDir: config.KEEP_BUILD_DIRS.Get(),
 		AllowLate:    false,
 	}
@@ -39,8 +55,13 @@ func GradeDefault(assignment *model.Assignment, submissionPath string, user stri
 // Full success is only when ((reject == nil) && (softGradingError == "") && (error == nil)).
 func Grade(ctx context.Context, assignment *model.Assignment, submissionPath string, user string, message string, checkRejection bool, options GradeOptions) (
 	*model.GradingResult, RejectReason, string, error) {
+	submitTimestamp := options.SubmitTime
+	if submitTimestamp.IsZero() {
+		submitTimestamp = timestamp.Now()
+	}
+
 	if checkRejection {
-		reject, err := checkForRejection(assignment, submissionPath, user, message, options.AllowLate)
+		reject, err := checkForRejection(assignment, submissionPath, user, message, options.AllowLate, submitTimestamp)
 		if err != nil {
 			return nil, nil, "", fmt.Errorf("Failed to check for rejection: '%w'.", err)
 		}
@@ -50,16 +71,42 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 		}
 	}
 
+	team, err := getSubmissionTeam(assignment, user, options)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	events := getGradingEvents(ctx)
+	events.sendType(model.GradingEventTypeQueued)
+	defer events.sendType(model.GradingEventTypeFinished)
+
 	gradingKey := fmt.Sprintf("%s::%s::%s", assignment.GetCourse().GetID(), assignment.GetID(), user)
+	if team != nil {
+		gradingKey = fmt.Sprintf("%s::%s::team::%s", assignment.GetCourse().GetID(), assignment.GetID(), team.ID)
+	}
 
 	// Get the grading start time right before we acquire the user's lock.
 	startTimestamp := timestamp.Now()
 
-	// Ensure the user can only have one submission (of each assignment) running at a time.
+	// Ensure the user (or team) can only have one submission (of each assignment) running at a time.
 	common.Lock(gradingKey)
 	defer common.Unlock(gradingKey)
 
-	submissionID, inputFileContents, err := prepForGrading(assignment, submissionPath, user)
+	// Wait for a free grading slot (so the server does not get overloaded with graders).
+	// Remote graders do not run on this server, so they do not need a slot.
+	if !options.Remote {
+		slotWaitStartTimestamp := timestamp.Now()
+
+		releaseSlot, err := scheduler.acquire(ctx, assignment.GetCourse().GetID())
+		if err != nil {
+			return nil, nil, getCanceledMessage(assignment), nil
+		}
+		defer releaseSlot()
+
+		stats.AsyncStoreCourseGradingQueueWait(slotWaitStartTimestamp, timestamp.Now(), assignment.GetCourse().GetID(), assignment.GetID(), user)
+	}
+
+	submissionID, inputFileContents, err := prepForGrading(assignment, submissionPath, user, events, options)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("Failed to prep for grading: '%w'.", err)
 	}
@@ -73,19 +120,23 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 	var outputFileContents map[string][]byte
 	var stdout string
 	var stderr string
+	var exitStatus *docker.ExitStatus
 
 	softGradingError := ""
-	if options.NoDocker {
-		gradingInfo, outputFileContents, stdout, stderr, softGradingError, err = runNoDockerGrader(ctx, assignment, submissionPath, options, fullSubmissionID)
+	if options.Remote {
+		gradingInfo, outputFileContents, stdout, stderr, exitStatus, softGradingError, err = runRemoteGrader(ctx, assignment, submissionPath, options, fullSubmissionID)
+	} else if options.NoDocker {
+		gradingInfo, outputFileContents, stdout, stderr, exitStatus, softGradingError, err = runNoDockerGrader(ctx, assignment, submissionPath, options, fullSubmissionID)
 	} else {
-		gradingInfo, outputFileContents, stdout, stderr, softGradingError, err = runDockerGrader(ctx, assignment, submissionPath, options, fullSubmissionID)
+		gradingInfo, outputFileContents, stdout, stderr, exitStatus, softGradingError, err = runDockerGrader(ctx, assignment, submissionPath, options, fullSubmissionID)
 	}
 
 	endTimestamp := timestamp.Now()
 
-	// Copy over stdout and stderr even if an error occured.
+	// Copy over stdout, stderr, and the exit status even if an error occured.
 	gradingResult.Stdout = stdout
 	gradingResult.Stderr = stderr
+	gradingResult.ExitStatus = exitStatus
 
 	// Check for hard grading errors.
 	if err != nil {
@@ -105,30 +156,86 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 	gradingInfo.User = user
 	gradingInfo.Message = message
 
+	if team != nil {
+		gradingInfo.Team = team.ID
+		gradingInfo.Submitter = user
+	}
+
 	gradingInfo.GradingStartTime = startTimestamp
 	gradingInfo.GradingEndTime = endTimestamp
 
+	// Queued submissions are considered to have started grading when they were submitted.
+	if !options.SubmitTime.IsZero() {
+		gradingInfo.GradingStartTime = options.SubmitTime
+	}
+
+	if options.RegradeOf != nil {
+		// Regrades of regrades still point to the original submission.
+		gradingInfo.RegradeOf = options.RegradeOf.ID
+		if options.RegradeOf.IsRegrade() {
+			gradingInfo.RegradeOf = options.RegradeOf.RegradeOf
+		}
+
+		gradingInfo.RegradeTime = startTimestamp
+		gradingInfo.GradingStartTime = options.RegradeOf.GradingStartTime
+		gradingInfo.GradingEndTime = options.RegradeOf.GradingEndTime
+	}
+
+	// Score overrides can only be made by course staff.
+	gradingInfo.ClearScoreOverrides()
+
 	gradingInfo.ComputePoints()
 
+	if options.RegradeOf != nil {
+		carryScoreOverrides(gradingInfo, options.RegradeOf)
+	}
+
+	// Report any questions that were not seen while the grader was running.
+	events.sendQuestions(gradingInfo.Questions)
+
 	gradingResult.Info = gradingInfo
 	gradingResult.OutputFilesGZip = outputFileContents
 
+	if options.DryRun {
+		return &gradingResult, nil, "", nil
+	}
+
 	err = db.SaveSubmission(assignment, &gradingResult)
 	if err != nil {
 		return &gradingResult, nil, "", fmt.Errorf("Failed to save grading result: '%w'.", err)
 	}
 
+	if team != nil {
+		teamResults, err := makeTeamCopies(assignment, team, &gradingResult)
+		if err != nil {
+			return &gradingResult, nil, "", err
+		}
+
+		err = db.SaveSubmissions(assignment.GetCourse(), teamResults)
+		if err != nil {
+			return &gradingResult, nil, "", fmt.Errorf("Failed to save team grading results: '%w'.", err)
+		}
+	}
+
 	// Store stats for this grading (when everything is successful).
 	stats.AsyncStoreCourseGradingTime(startTimestamp, endTimestamp, gradingInfo.CourseID, gradingInfo.AssignmentID, gradingInfo.User)
 
 	return &gradingResult, nil, "", nil
 }
 
-func prepForGrading(assignment *model.Assignment, submissionPath string, user string) (string, map[string][]byte, error) {
+func prepForGrading(assignment *model.Assignment, submissionPath string, user string, events *gradingEvents, options GradeOptions) (string, map[string][]byte, error) {
 	// Ensure the assignment docker image is built.
-	err := docker.BuildImageFromSourceQuick(assignment)
-	if err != nil {
-		return "", nil, fmt.Errorf("Failed to build assignment '%s' docker image: '%w'.", assignment.FullID(), err)
+	// Remote workers build their own images (from the build context sent with each task).
+	if !options.Remote {
+		buildOptions := docker.NewBuildOptions()
+		buildOptions.OnBuild = func() {
+			events.sendType(model.GradingEventTypeBuildingImage)
+		}
+
+		err := docker.BuildImageFromSource(assignment, false, true, buildOptions)
+		if err != nil {
+			return "", nil, fmt.Errorf("Failed to build assignment '%s' docker image: '%w'.", assignment.FullID(), err)
+		}
 	}
 
 	submissionID, err := db.GetNextSubmissionID(assignment, user)
@@ -144,10 +251,140 @@ func prepForGrading(assignment *model.Assignment, submissionPath string, user st
 	return submissionID, fileContents, nil
 }
 
+// Keep the score overrides that are in effect on the original submission of a regrade.
+// Overrides for questions that the new grader no longer has are dropped.
+func carryScoreOverrides(gradingInfo *model.GradingInfo, original *model.GradingInfo) {
+	for _, override := range original.GetActiveScoreOverrides() {
+		newOverride := *override
+
+		err := gradingInfo.ApplyScoreOverride(&newOverride)
+		if err != nil {
+			log.Warn("Failed to carry score override into regrade.", err,
+				log.NewAttr("submission", original.ID), log.NewAttr("question", override.Question))
+		}
+	}
+}
+
 func getTimeoutMessage(assignment *model.Assignment) string {
 	return fmt.Sprintf("Submission has ran for too long and was killed. Max assignment runtime is %d seconds (server hard limit is %d seconds). Check for infinite loops/recursion and consult with your instructors/TAs.", assignment.MaxRuntimeSecs, config.GRADING_RUNTIME_MAX_SECS.Get())
 }
 
+func getOutputTooLargeMessage(assignment *model.Assignment, sizeBytes int64) string {
+	return fmt.Sprintf("Grader output is too large (%.2f MB). Max assignment output size is %d MB. Check for code that writes large or many files and consult with your instructors/TAs.",
+		float64(sizeBytes)/docker.BYTES_PER_MB, assignment.GetEffectiveResourceLimits().MaxOutputMB)
+}
+
+// Check that a grader's output is within the assignment's limits.
+// Returns a soft error message if the output is too large.
+func checkOutputSize(assignment *model.Assignment, outputDir string) (string, error) {
+	maxOutputMB := assignment.GetEffectiveResourceLimits().MaxOutputMB
+	if maxOutputMB <= 0 {
+		return "", nil
+	}
+
+	size, err := util.GetDirentSize(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get size of grading output '%s': '%w'.", outputDir, err)
+	}
+
+	if size > (int64(maxOutputMB) * docker.BYTES_PER_MB) {
+		return getOutputTooLargeMessage(assignment, size), nil
+	}
+
+	return "", nil
+}
+
+func getOutOfMemoryMessage(assignment *model.Assignment) string {
+	limit := ""
+	maxMemoryMB := assignment.GetEffectiveResourceLimits().MaxMemoryMB
+	if maxMemoryMB > 0 {
+		limit = fmt.Sprintf(" Max assignment memory is %d MB.", maxMemoryMB)
+	}
+
+	return fmt.Sprintf("Submission used too much memory and was killed.%s Check for large data structures or infinite recursion and consult with your instructors/TAs.", limit)
+}
+
+func getSignalMessage(assignment *model.Assignment, exitStatus *docker.ExitStatus) string {
+	return fmt.Sprintf("Grader was killed by signal %d (%s) before producing a result. Check for crashes (e.g., segmentation faults) and consult with your instructors/TAs.",
+		exitStatus.Signal, exitStatus.SignalName())
+}
+
+func getExitCodeMessage(assignment *model.Assignment, exitStatus *docker.ExitStatus) string {
+	return fmt.Sprintf("Grader exited with a non-zero exit code (%d) before producing a result. Check that your submission compiles/runs and consult with your instructors/TAs.", exitStatus.ExitCode)
+}
+
+func getMissingResultMessage(assignment *model.Assignment) string {
+	return "Grader finished without producing a result. Check that your submission does not exit the grader early and consult with your instructors/TAs."
+}
+
+// Interpret the output of a grader that has already been run.
+// Returns: (result, file contents, failure message (soft failure), error (hard failure)).
+func collectGraderOutput(assignment *model.Assignment, outputDir string, exitStatus *docker.ExitStatus, timeout bool, canceled bool) (
+	*model.GradingInfo, map[string][]byte, string, error) {
+	if timeout {
+		return nil, nil, getTimeoutMessage(assignment), nil
+	}
+
+	if canceled {
+		return nil, nil, getCanceledMessage(assignment), nil
+	}
+
+	outputMessage, err := checkOutputSize(assignment, outputDir)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if outputMessage != "" {
+		return nil, nil, outputMessage, nil
+	}
+
+	resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME)
+	exitMessage := checkGraderExit(assignment, exitStatus, resultPath)
+	if exitMessage != "" {
+		return nil, nil, exitMessage, nil
+	}
+
+	var gradingInfo model.GradingInfo
+	err = util.JSONFromFile(resultPath, &gradingInfo)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	fileContents, err := util.GzipDirectoryToBytes(outputDir)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("Failed to copy grading output '%s': '%w'.", outputDir, err)
+	}
+
+	return &gradingInfo, fileContents, "", nil
+}
+
+// Check how a grader exited and if it produced a result.
+// A grader that runs out of memory always fails, otherwise a grader that produced a result is fine regardless of its exit.
+// Returns a soft error message if the grader failed.
+func checkGraderExit(assignment *model.Assignment, exitStatus *docker.ExitStatus, resultPath string) string {
+	if (exitStatus != nil) && exitStatus.OOMKilled {
+		return getOutOfMemoryMessage(assignment)
+	}
+
+	if util.PathExists(resultPath) {
+		return ""
+	}
+
+	if exitStatus == nil {
+		return getMissingResultMessage(assignment)
+	}
+
+	if exitStatus.Signal != 0 {
+		return getSignalMessage(assignment, exitStatus)
+	}
+
+	if exitStatus.ExitCode != 0 {
+		return getExitCodeMessage(assignment, exitStatus)
+	}
+
+	return getMissingResultMessage(assignment)
+}
+
 func getCanceledMessage(assignment *model.Assignment) string {
 	return "Grading has been canceled (usually by a broken HTTP connection)."
 }
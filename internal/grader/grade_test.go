@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/docker"
@@ -298,3 +300,235 @@ func testTruncatedOutput(test *testing.T, sizeKB int, expectedTruncated bool) {
 		}
 	}
 }
+
+func TestGetProcessLimits(test *testing.T) {
+	testCases := []struct {
+		limits          *docker.ResourceLimits
+		effectiveLimits *docker.ResourceLimits
+		expected        []*docker.Ulimit
+	}{
+		{nil, nil, []*docker.Ulimit{}},
+		{&docker.ResourceLimits{}, nil, []*docker.Ulimit{}},
+
+		// Server defaults are not applied.
+		{&docker.ResourceLimits{}, &docker.ResourceLimits{MaxMemoryMB: 1, MaxOutputMB: 2, MaxPIDs: 3}, []*docker.Ulimit{}},
+
+		// Limits derived from memory/output.
+		{
+			&docker.ResourceLimits{MaxMemoryMB: 1, MaxOutputMB: 2, MaxPIDs: 3},
+			nil,
+			[]*docker.Ulimit{
+				&docker.Ulimit{Name: "data", Soft: 1 * docker.BYTES_PER_MB, Hard: 1 * docker.BYTES_PER_MB},
+				&docker.Ulimit{Name: "fsize", Soft: 2 * docker.BYTES_PER_MB, Hard: 2 * docker.BYTES_PER_MB},
+			},
+		},
+
+		// Explicit limits take precedence.
+		{
+			&docker.ResourceLimits{
+				MaxMemoryMB: 1,
+				Ulimits: []*docker.Ulimit{
+					&docker.Ulimit{Name: "nofile", Soft: 10, Hard: 20},
+					&docker.Ulimit{Name: "data", Soft: 30, Hard: 40},
+				},
+			},
+			nil,
+			[]*docker.Ulimit{
+				&docker.Ulimit{Name: "data", Soft: 30, Hard: 40},
+				&docker.Ulimit{Name: "nofile", Soft: 10, Hard: 20},
+			},
+		},
+
+		// Explicit limits cannot go over the other limits.
+		{
+			&docker.ResourceLimits{
+				MaxMemoryMB: 1,
+				MaxPIDs:     5,
+				Ulimits: []*docker.Ulimit{
+					&docker.Ulimit{Name: "data", Soft: 1, Hard: 2 * docker.BYTES_PER_MB},
+					&docker.Ulimit{Name: "nproc", Soft: 10, Hard: 20},
+				},
+			},
+			nil,
+			[]*docker.Ulimit{
+				&docker.Ulimit{Name: "data", Soft: 1, Hard: 1 * docker.BYTES_PER_MB},
+				&docker.Ulimit{Name: "nproc", Soft: 5, Hard: 5},
+			},
+		},
+
+		// Explicit limits cannot go over the server limits.
+		{
+			&docker.ResourceLimits{
+				Ulimits: []*docker.Ulimit{
+					&docker.Ulimit{Name: "fsize", Soft: 1, Hard: 3 * docker.BYTES_PER_MB},
+				},
+			},
+			&docker.ResourceLimits{MaxOutputMB: 2},
+			[]*docker.Ulimit{
+				&docker.Ulimit{Name: "fsize", Soft: 1, Hard: 2 * docker.BYTES_PER_MB},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		actual := getProcessLimits(testCase.limits, testCase.effectiveLimits)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected limits. Expected: '%s', Actual: '%s'.", i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+		}
+	}
+}
+
+func TestGradeOutputTooLarge(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	assignment.MaxOutputMB = 1
+
+	outputDir := util.MustMkDirTemp("test-grade-output-")
+	defer util.RemoveDirent(outputDir)
+
+	message, err := checkOutputSize(assignment, outputDir)
+	if err != nil {
+		test.Fatalf("Failed to check empty output: '%v'.", err)
+	}
+
+	if message != "" {
+		test.Fatalf("Got a message for empty output: '%s'.", message)
+	}
+
+	err = util.WriteBinaryFile(make([]byte, docker.BYTES_PER_MB+1), filepath.Join(outputDir, "big.bin"))
+	if err != nil {
+		test.Fatalf("Failed to write output: '%v'.", err)
+	}
+
+	message, err = checkOutputSize(assignment, outputDir)
+	if err != nil {
+		test.Fatalf("Failed to check large output: '%v'.", err)
+	}
+
+	if !strings.Contains(message, "Grader output is too large") {
+		test.Fatalf("Did not get a message for large output: '%s'.", message)
+	}
+}
+
+func TestGradeGraderExitDocker(test *testing.T) {
+	if config.DOCKER_DISABLE.Get() {
+		test.Skip("Docker is disabled, skipping test.")
+	}
+
+	if !docker.CanAccessDocker() {
+		test.Fatal("Could not access docker.")
+	}
+
+	testGradeGraderExit(test, false)
+}
+
+func TestGradeGraderExitNoDocker(test *testing.T) {
+	testGradeGraderExit(test, true)
+}
+
+func testGradeGraderExit(test *testing.T, noDocker bool) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		submission        string
+		expectedStatus    *docker.ExitStatus
+		expectedSubstring string
+	}{
+		{"bash-exit", &docker.ExitStatus{ExitCode: 7}, "non-zero exit code (7)"},
+		{"bash-signal", &docker.ExitStatus{ExitCode: 137, Signal: 9}, "killed by signal 9 (killed)"},
+		{"bash-noresult", &docker.ExitStatus{ExitCode: 0}, "without producing a result"},
+	}
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	options := GetDefaultGradeOptions()
+	options.NoDocker = noDocker
+
+	for i, testCase := range testCases {
+		submissionDir := filepath.Join(util.ShouldGetThisDir(), "testdata", testCase.submission)
+
+		result, reject, softError, err := Grade(context.Background(), assignment, submissionDir, "course-student@test.edulinq.org", "", false, options)
+		if err != nil {
+			test.Errorf("Case %d (%s): Failed to grade assignment: '%v'.", i, testCase.submission, err)
+			continue
+		}
+
+		if reject != nil {
+			test.Errorf("Case %d (%s): Submission was rejected: '%s'.", i, testCase.submission, reject.String())
+			continue
+		}
+
+		if !strings.Contains(softError, testCase.expectedSubstring) {
+			test.Errorf("Case %d (%s): Submission did not get the correct soft error. Expected substring: '%s', Actual string: '%s'.",
+				i, testCase.submission, testCase.expectedSubstring, softError)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expectedStatus, result.ExitStatus) {
+			test.Errorf("Case %d (%s): Unexpected exit status. Expected: '%s', Actual: '%s'.",
+				i, testCase.submission, testCase.expectedStatus, result.ExitStatus)
+			continue
+		}
+	}
+}
+
+func TestCheckGraderExit(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	assignment.MaxMemoryMB = 123
+
+	outputDir := util.MustMkDirTemp("test-grade-exit-")
+	defer util.RemoveDirent(outputDir)
+
+	resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME)
+	missingPath := filepath.Join(outputDir, "missing.json")
+
+	err := util.WriteFile("{}", resultPath)
+	if err != nil {
+		test.Fatalf("Failed to write result: '%v'.", err)
+	}
+
+	testCases := []struct {
+		status            *docker.ExitStatus
+		path              string
+		expectedSubstring string
+	}{
+		// Results are used regardless of the exit.
+		{&docker.ExitStatus{ExitCode: 0}, resultPath, ""},
+		{&docker.ExitStatus{ExitCode: 1}, resultPath, ""},
+		{docker.NewExitStatusFromCode(139, false), resultPath, ""},
+		{nil, resultPath, ""},
+
+		// Running out of memory is always an error.
+		{docker.NewExitStatusFromCode(137, true), resultPath, "Max assignment memory is 123 MB"},
+		{docker.NewExitStatusFromCode(137, true), missingPath, "used too much memory"},
+
+		// Missing results.
+		{docker.NewExitStatusFromCode(139, false), missingPath, "killed by signal 11 (segmentation fault)"},
+		{&docker.ExitStatus{ExitCode: 2}, missingPath, "non-zero exit code (2)"},
+		{&docker.ExitStatus{ExitCode: 0}, missingPath, "without producing a result"},
+		{nil, missingPath, "without producing a result"},
+	}
+
+	for i, testCase := range testCases {
+		message := checkGraderExit(assignment, testCase.status, testCase.path)
+
+		if testCase.expectedSubstring == "" {
+			if message != "" {
+				test.Errorf("Case %d: Got an unexpected message: '%s'.", i, message)
+			}
+
+			continue
+		}
+
+		if !strings.Contains(message, testCase.expectedSubstring) {
+			test.Errorf("Case %d: Did not get the correct message. Expected substring: '%s', Actual string: '%s'.", i, testCase.expectedSubstring, message)
+			continue
+		}
+	}
+}
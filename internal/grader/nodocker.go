@@ -5,16 +5,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/docker"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
-	"github.com/edulinq/autograder/internal/util"
 )
 
 const PYTHON_AUTOGRADER_INVOCATION = "python3 -m autograder.cli.grading.grade-dir --grader <grader> --dir <basedir> --outpath <outpath>"
@@ -24,17 +26,17 @@ const PYTHON_DOCKER_IMAGE_BASENAME = "edulinq/grader.python"
 // A small delay to wait for a process to finish ater already timing out.
 var noDockerTimeoutWaitDelayMS int = 10 * 1000
 
-// Returns: (result, file contents, stdout, stderr, failure message (soft failure), error (hard failure)).
+// Returns: (result, file contents, stdout, stderr, exit status, failure message (soft failure), error (hard failure)).
 func runNoDockerGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (
-	*model.GradingInfo, map[string][]byte, string, string, string, error) {
+	*model.GradingInfo, map[string][]byte, string, string, *docker.ExitStatus, string, error) {
 	imageInfo := assignment.GetImageInfo()
 	if imageInfo == nil {
-		return nil, nil, "", "", "", fmt.Errorf("No image information associated with assignment: '%s'.", assignment.FullID())
+		return nil, nil, "", "", nil, "", fmt.Errorf("No image information associated with assignment: '%s'.", assignment.FullID())
 	}
 
 	tempDir, inputDir, outputDir, workDir, err := common.PrepTempGradingDir("nodocker")
 	if err != nil {
-		return nil, nil, "", "", "", err
+		return nil, nil, "", "", nil, "", err
 	}
 
 	if !options.LeaveTempDir {
@@ -45,80 +47,164 @@ func runNoDockerGrader(ctx context.Context, assignment *model.Assignment, submis
 
 	ctx, cmd, err := getAssignmentInvocation(ctx, assignment, tempDir, inputDir, outputDir, workDir)
 	if err != nil {
-		return nil, nil, "", "", "", err
+		return nil, nil, "", "", nil, "", err
 	}
 
 	// Copy over the static files (and do any file ops).
 	err = common.CopyFileSpecs(imageInfo.BaseDirFunc(), workDir, tempDir,
 		imageInfo.StaticFiles, false, imageInfo.PreStaticFileOperations, imageInfo.PostStaticFileOperations)
 	if err != nil {
-		return nil, nil, "", "", "", fmt.Errorf("Failed to copy static assignment files: '%w'.", err)
+		return nil, nil, "", "", nil, "", fmt.Errorf("Failed to copy static assignment files: '%w'.", err)
 	}
 
 	// Copy over the submission files (and do any file ops).
 	err = common.CopyFileSpecs(submissionPath, inputDir, tempDir,
 		[]*common.FileSpec{common.GetPathFileSpec(".")}, true, []common.FileOperation{}, imageInfo.PostSubmissionFileOperations)
 	if err != nil {
-		return nil, nil, "", "", "", fmt.Errorf("Failed to copy submission ssignment files: '%w'.", err)
+		return nil, nil, "", "", nil, "", fmt.Errorf("Failed to copy submission ssignment files: '%w'.", err)
 	}
 
-	stdout, stderr, timeout, canceled, err := runCMD(ctx, cmd)
+	stopWatch := watchGraderResult(ctx, outputDir)
+	ulimits := getProcessLimits(&assignment.ResourceLimits, assignment.GetEffectiveResourceLimits())
+	stdout, stderr, exitStatus, timeout, canceled, err := runCMD(ctx, cmd, ulimits)
+	stopWatch()
+
 	if err != nil {
-		return nil, nil, stdout, stderr, "",
+		return nil, nil, stdout, stderr, exitStatus, "",
 			fmt.Errorf("Failed to run non-docker grader for assignment '%s': '%w'.", assignment.FullID(), err)
 	}
 
-	if timeout {
-		return nil, nil, stdout, stderr, getTimeoutMessage(assignment), nil
-	}
+	gradingInfo, fileContents, softGradingError, err := collectGraderOutput(assignment, outputDir, exitStatus, timeout, canceled)
+
+	return gradingInfo, fileContents, stdout, stderr, exitStatus, softGradingError, err
+}
+
+// Run a grader process.
+// Like a container, the process will report to any run listener attached to the context (see docker.WithRunListener()).
+// A process that exits unsuccessfully is not an error, see the returned exit status instead.
+// The exit status will be nil if the process did not run to completion (e.g., a timeout).
+// Returns: (stdout, stderr, exit status, timeout?, canceled?, error)
+func runCMD(ctx context.Context, cmd *exec.Cmd, ulimits []*docker.Ulimit) (string, string, *docker.ExitStatus, bool, bool, error) {
+	var outBuffer bytes.Buffer
+	var errBuffer bytes.Buffer
 
-	if canceled {
-		return nil, nil, stdout, stderr, getCanceledMessage(assignment), nil
+	listener := docker.GetRunListener(ctx)
+
+	cmd.Stdout = io.MultiWriter(&outBuffer, docker.NewListenerWriter(listener, "stdout"))
+	cmd.Stderr = io.MultiWriter(&errBuffer, docker.NewListenerWriter(listener, "stderr"))
+
+	timeout := false
+	canceled := false
+
+	err := applyProcessLimits(cmd, ulimits)
+	if err != nil {
+		return "", "", nil, false, false, fmt.Errorf("Failed to apply resource limits: '%w'.", err)
 	}
 
-	resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME)
-	if !util.PathExists(resultPath) {
-		return nil, nil, stdout, stderr, "", fmt.Errorf("Cannot find output file ('%s') after non-docker grading.", resultPath)
+	err = cmd.Start()
+	if err == nil {
+		if listener != nil {
+			listener.ContainerStarted()
+		}
+
+		err = cmd.Wait()
 	}
 
-	var gradingInfo model.GradingInfo
-	err = util.JSONFromFile(resultPath, &gradingInfo)
 	if err != nil {
-		return nil, nil, stdout, stderr, "", err
+		timeout = errors.Is(ctx.Err(), context.DeadlineExceeded)
+		canceled = errors.Is(ctx.Err(), context.Canceled)
+	}
+
+	stdout := outBuffer.String()
+	stderr := errBuffer.String()
+
+	if timeout || canceled {
+		return stdout, stderr, nil, timeout, canceled, nil
+	}
+
+	// A process that ran but exited unsuccessfully is reported through its exit status.
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		err = nil
 	}
 
-	fileContents, err := util.GzipDirectoryToBytes(outputDir)
 	if err != nil {
-		return nil, nil, stdout, stderr, "", fmt.Errorf("Failed to copy grading output '%s': '%w'.", outputDir, err)
+		return stdout, stderr, nil, false, false, err
 	}
 
-	return &gradingInfo, fileContents, stdout, stderr, "", nil
+	return stdout, stderr, getProcessExitStatus(cmd.ProcessState), false, false, nil
 }
 
-func runCMD(ctx context.Context, cmd *exec.Cmd) (string, string, bool, bool, error) {
-	var outBuffer bytes.Buffer
-	var errBuffer bytes.Buffer
+func getProcessExitStatus(state *os.ProcessState) *docker.ExitStatus {
+	if state == nil {
+		return nil
+	}
 
-	cmd.Stdout = &outBuffer
-	cmd.Stderr = &errBuffer
+	status := &docker.ExitStatus{
+		ExitCode: state.ExitCode(),
+	}
 
-	timeout := false
-	canceled := false
+	// Match the container convention for processes killed by a signal.
+	waitStatus, ok := state.Sys().(syscall.WaitStatus)
+	if ok && waitStatus.Signaled() {
+		status.Signal = int(waitStatus.Signal())
+		status.ExitCode = docker.SIGNAL_EXIT_CODE_OFFSET + status.Signal
+	}
 
-	err := cmd.Run()
-	if err != nil {
-		timeout = errors.Is(ctx.Err(), context.DeadlineExceeded)
-		canceled = errors.Is(ctx.Err(), context.Canceled)
+	return status
+}
+
+// Get the ulimits to apply to a non-docker grader.
+// Only the limits that the assignment sets are applied
+// (server defaults, e.g., a data limit from the server's max memory, can break graders that reserve a lot of virtual memory).
+// Explicit ulimits take precedence over limits derived from the other resource limits,
+// but cannot go over the effective limits (see model.Assignment.GetEffectiveResourceLimits()).
+func getProcessLimits(limits *docker.ResourceLimits, effectiveLimits *docker.ResourceLimits) []*docker.Ulimit {
+	ulimits := make([]*docker.Ulimit, 0)
+	if limits == nil {
+		return ulimits
 	}
 
-	if timeout || canceled {
-		err = nil
+	if effectiveLimits == nil {
+		effectiveLimits = limits
 	}
 
-	stdout := outBuffer.String()
-	stderr := errBuffer.String()
+	derived := map[string]int{
+		"data":  limits.MaxMemoryMB,
+		"fsize": limits.MaxOutputMB,
+	}
+
+	caps := map[string]int64{
+		"data":  int64(effectiveLimits.MaxMemoryMB) * docker.BYTES_PER_MB,
+		"fsize": int64(effectiveLimits.MaxOutputMB) * docker.BYTES_PER_MB,
+		"nproc": int64(effectiveLimits.MaxPIDs),
+	}
+
+	explicit := make(map[string]*docker.Ulimit, len(limits.Ulimits))
+	for _, ulimit := range limits.Ulimits {
+		explicit[ulimit.Name] = ulimit
+	}
+
+	for _, name := range docker.ULIMIT_NAMES {
+		ulimit, ok := explicit[name]
+		if ok {
+			maxValue := caps[name]
+			if maxValue > 0 {
+				ulimit = &docker.Ulimit{Name: name, Soft: min(ulimit.Soft, maxValue), Hard: min(ulimit.Hard, maxValue)}
+			}
+
+			ulimits = append(ulimits, ulimit)
+			continue
+		}
+
+		sizeMB := derived[name]
+		if sizeMB > 0 {
+			bytes := int64(sizeMB) * docker.BYTES_PER_MB
+			ulimits = append(ulimits, &docker.Ulimit{Name: name, Soft: bytes, Hard: bytes})
+		}
+	}
 
-	return stdout, stderr, timeout, canceled, err
+	return ulimits
 }
 
 // Get a command to invoke the non-docker grader.
@@ -0,0 +1,277 @@
+package grader
+
+// An asynchronous grading queue.
+// Submissions are stored in the database as grading jobs and graded in the background by a pool of workers.
+// Since jobs live in the database, pending (and interrupted) jobs will be picked back up when the server restarts.
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	QUEUE_POLL_MSECS = 1000
+
+	JOB_INTERNAL_ERROR_MESSAGE = "The autograder encountered an internal error while grading this submission. Contact your course staff."
+)
+
+var (
+	queueLock    sync.Mutex
+	queueCancel  context.CancelFunc = nil
+	queueWorkers sync.WaitGroup
+
+	// Signal that a new job is available.
+	// Buffered so that enqueuing never blocks.
+	queueWake chan bool = make(chan bool, 1)
+)
+
+// Check a submission for rejection and (if it is not rejected) add it to the grading queue.
+// Return (job, reject, error).
+// A job is only returned if the submission was not rejected.
+// The submission will be checked for rejection again when it is graded
+// (using the time it was enqueued, so submissions are not penalized for waiting in the queue).
+func EnqueueGrading(assignment *model.Assignment, submissionPath string, user string, message string, allowLate bool) (
+	*model.GradingJob, RejectReason, error) {
+	now := timestamp.Now()
+
+	reject, err := checkForRejection(assignment, submissionPath, user, message, allowLate, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to check for rejection: '%w'.", err)
+	}
+
+	if reject != nil {
+		return nil, reject, nil
+	}
+
+	fileContents, err := util.GzipDirectoryToBytes(submissionPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to copy submission files from '%s': '%w'.", submissionPath, err)
+	}
+
+	job := &model.GradingJob{
+		ID:             util.UUID(),
+		CourseID:       assignment.GetCourse().GetID(),
+		AssignmentID:   assignment.GetID(),
+		User:           user,
+		Message:        message,
+		AllowLate:      allowLate,
+		Status:         model.GradingJobStatusPending,
+		CreateTime:     now,
+		InputFilesGZip: fileContents,
+	}
+
+	err = db.SaveGradingJob(job)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to save grading job: '%w'.", err)
+	}
+
+	log.Debug("Grading job enqueued.", log.NewAttr("job-id", job.ID), assignment, log.NewUserAttr(user))
+
+	select {
+	case queueWake <- true:
+	default:
+	}
+
+	return job, nil, nil
+}
+
+// Start the workers that grade queued jobs.
+// Any jobs that were running when the queue was last stopped will be graded again.
+func StartGradingQueue() error {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	if queueCancel != nil {
+		return nil
+	}
+
+	err := requeueRunningJobs()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queueCancel = cancel
+
+	numWorkers := max(1, config.GRADING_QUEUE_WORKERS.Get())
+	for i := 0; i < numWorkers; i++ {
+		queueWorkers.Add(1)
+		go runQueueWorker(ctx)
+	}
+
+	log.Debug("Grading queue started.", log.NewAttr("workers", numWorkers))
+
+	return nil
+}
+
+// Stop all the queue workers.
+// Any in-progress grading will be canceled, and those jobs will go back into the queue.
+// Once this returns, no workers will be running.
+func StopGradingQueue() {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	if queueCancel == nil {
+		return
+	}
+
+	queueCancel()
+	queueWorkers.Wait()
+
+	queueCancel = nil
+
+	log.Debug("Grading queue stopped.")
+}
+
+// Claim and grade the next pending job.
+// Returns the job that was run, or nil if there were no pending jobs.
+func RunNextGradingJob(ctx context.Context) (*model.GradingJob, error) {
+	job, err := db.ClaimNextGradingJob()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to claim next grading job: '%w'.", err)
+	}
+
+	if job == nil {
+		return nil, nil
+	}
+
+	err = runGradingJob(ctx, job)
+	if err != nil {
+		return job, fmt.Errorf("Failed to run grading job '%s': '%w'.", job.ID, err)
+	}
+
+	return job, nil
+}
+
+func runQueueWorker(ctx context.Context) {
+	defer queueWorkers.Done()
+
+	for ctx.Err() == nil {
+		job, err := RunNextGradingJob(ctx)
+		if err != nil {
+			log.Error("Failed to run grading job.", err)
+		}
+
+		// Keep going while there is work to do.
+		if job != nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-queueWake:
+		case <-time.After(time.Duration(QUEUE_POLL_MSECS) * time.Millisecond):
+		}
+	}
+}
+
+func runGradingJob(ctx context.Context, job *model.GradingJob) error {
+	assignment, err := db.GetAssignment(job.CourseID, job.AssignmentID)
+	if err != nil {
+		log.Error("Failed to get assignment for grading job.", err, log.NewAttr("job-id", job.ID),
+			log.NewCourseAttr(job.CourseID), log.NewAssignmentAttr(job.AssignmentID))
+		return completeJob(job, JOB_INTERNAL_ERROR_MESSAGE)
+	}
+
+	tempDir, err := util.MkDirTemp("autograder-grading-job-")
+	if err != nil {
+		log.Error("Failed to create temp dir for grading job.", err, log.NewAttr("job-id", job.ID), assignment)
+		return completeJob(job, JOB_INTERNAL_ERROR_MESSAGE)
+	}
+	defer util.RemoveDirent(tempDir)
+
+	err = util.GzipBytesToDirectory(tempDir, job.InputFilesGZip)
+	if err != nil {
+		log.Error("Failed to write grading job files.", err, log.NewAttr("job-id", job.ID), assignment)
+		return completeJob(job, JOB_INTERNAL_ERROR_MESSAGE)
+	}
+
+	gradeOptions := GetDefaultGradeOptions()
+	gradeOptions.AllowLate = job.AllowLate
+	gradeOptions.SubmitTime = job.CreateTime
+
+	result, reject, failureMessage, err := Grade(ctx, assignment, tempDir, job.User, job.Message, true, gradeOptions)
+	if err != nil {
+		stdout := ""
+		stderr := ""
+
+		if (result != nil) && (result.HasTextOutput()) {
+			stdout = result.Stdout
+			stderr = result.Stderr
+		}
+
+		log.LogToSplitLevels(log.LevelDebug, log.LevelInfo, "Grading job failed internally.", err, assignment,
+			log.NewAttr("job-id", job.ID), log.NewAttr("stdout", stdout), log.NewAttr("stderr", stderr), log.NewUserAttr(job.User))
+
+		return completeJob(job, JOB_INTERNAL_ERROR_MESSAGE)
+	}
+
+	// The queue is being stopped, put the job back so it will get graded later.
+	if (ctx.Err() != nil) && (failureMessage == getCanceledMessage(assignment)) {
+		job.Status = model.GradingJobStatusPending
+		job.StartTime = timestamp.Zero()
+
+		return db.SaveGradingJob(job)
+	}
+
+	if reject != nil {
+		log.LogToSplitLevels(log.LevelTrace, log.LevelDebug, "Grading job rejected.", assignment,
+			log.NewAttr("job-id", job.ID), log.NewAttr("reason", reject.String()), log.NewUserAttr(job.User))
+
+		job.Rejected = true
+		return completeJob(job, reject.String())
+	}
+
+	if failureMessage != "" {
+		log.LogToSplitLevels(log.LevelTrace, log.LevelDebug, "Grading job got a soft error.", assignment,
+			log.NewAttr("job-id", job.ID), log.NewAttr("message", failureMessage), log.NewAttr("exit-status", result.GetExitStatus()), log.NewUserAttr(job.User))
+
+		return completeJob(job, failureMessage)
+	}
+
+	job.GradingSuccess = true
+	job.SubmissionID = result.Info.ID
+
+	return completeJob(job, "")
+}
+
+func completeJob(job *model.GradingJob, message string) error {
+	job.Status = model.GradingJobStatusComplete
+	job.EndTime = timestamp.Now()
+	job.ResultMessage = message
+	job.InputFilesGZip = nil
+
+	return db.SaveGradingJob(job)
+}
+
+// Put any jobs that were interrupted (e.g., by a server crash) back into the queue.
+func requeueRunningJobs() error {
+	jobs, err := db.GetGradingJobs(model.GradingJobStatusRunning)
+	if err != nil {
+		return fmt.Errorf("Failed to get running grading jobs: '%w'.", err)
+	}
+
+	for _, job := range jobs {
+		job.Status = model.GradingJobStatusPending
+		job.StartTime = timestamp.Zero()
+
+		err = db.SaveGradingJob(job)
+		if err != nil {
+			return fmt.Errorf("Failed to requeue grading job '%s': '%w'.", job.ID, err)
+		}
+
+		log.Info("Requeued interrupted grading job.", log.NewAttr("job-id", job.ID),
+			log.NewCourseAttr(job.CourseID), log.NewAssignmentAttr(job.AssignmentID), log.NewUserAttr(job.User))
+	}
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package grader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGradingQueueBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer setNoDockerForTesting()()
+
+	assignment := db.MustGetAssignment(TEST_COURSE_ID, TEST_ASSIGNMENT_ID)
+
+	job := enqueueForTesting(test, assignment, true)
+
+	ranJob, err := RunNextGradingJob(context.Background())
+	if err != nil {
+		test.Fatalf("Failed to run job: '%v'.", err)
+	}
+
+	if (ranJob == nil) || (ranJob.ID != job.ID) {
+		test.Fatalf("Did not run the expected job. Expected: '%s', Actual: '%v'.", job.ID, ranJob)
+	}
+
+	job = getJobForTesting(test, job.ID)
+
+	if !job.GradingSuccess || job.Rejected || (job.ResultMessage != "") {
+		test.Fatalf("Job was not successful: '%v'.", util.MustToJSONIndent(job))
+	}
+
+	if job.InputFilesGZip != nil {
+		test.Fatalf("Complete job still has input files.")
+	}
+
+	result, err := db.GetSubmissionResult(assignment, BASE_TEST_USER, job.SubmissionID)
+	if err != nil {
+		test.Fatalf("Failed to get submission result: '%v'.", err)
+	}
+
+	if (result == nil) || (result.ID != job.SubmissionID) {
+		test.Fatalf("Did not find the job's submission. Expected: '%s', Actual: '%v'.", job.SubmissionID, result)
+	}
+
+	ranJob, err = RunNextGradingJob(context.Background())
+	if err != nil {
+		test.Fatalf("Failed to run job on empty queue: '%v'.", err)
+	}
+
+	if ranJob != nil {
+		test.Fatalf("Ran a job from an empty queue: '%v'.", ranJob)
+	}
+}
+
+func TestGradingQueueEnqueueReject(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment(TEST_COURSE_ID, TEST_ASSIGNMENT_ID)
+	dueDate := timestamp.FromMSecs(0)
+	assignment.DueDate = &dueDate
+
+	submissionPath := filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)
+
+	job, reject, err := EnqueueGrading(assignment, submissionPath, BASE_TEST_USER, TEST_MESSAGE, false)
+	if err != nil {
+		test.Fatalf("Failed to enqueue: '%v'.", err)
+	}
+
+	if job != nil {
+		test.Fatalf("Got a job for a rejected submission: '%v'.", job)
+	}
+
+	if reject == nil {
+		test.Fatalf("Submission was not rejected.")
+	}
+
+	jobs, err := db.GetGradingJobs(model.GradingJobStatusPending)
+	if err != nil {
+		test.Fatalf("Failed to get pending jobs: '%v'.", err)
+	}
+
+	if len(jobs) != 0 {
+		test.Fatalf("Rejected submission was queued: '%v'.", jobs)
+	}
+}
+
+// Submissions are checked for rejection again when they are graded.
+func TestGradingQueueGradeReject(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer setNoDockerForTesting()()
+
+	assignment := db.MustGetAssignment(TEST_COURSE_ID, TEST_ASSIGNMENT_ID)
+
+	assignment.DueDate = nil
+	job := enqueueForTesting(test, assignment, false)
+
+	// The assignment becomes due while the job is in the queue.
+	dueDate := timestamp.FromMSecs(0)
+	assignment.DueDate = &dueDate
+	db.MustSaveAssignment(assignment)
+
+	_, err := RunNextGradingJob(context.Background())
+	if err != nil {
+		test.Fatalf("Failed to run job: '%v'.", err)
+	}
+
+	job = getJobForTesting(test, job.ID)
+
+	if job.GradingSuccess || !job.Rejected || (job.ResultMessage == "") || (job.SubmissionID != "") {
+		test.Fatalf("Job was not rejected: '%v'.", util.MustToJSONIndent(job))
+	}
+}
+
+// Submissions that were enqueued before the due date are not late, even if they are graded after it.
+func TestGradingQueueGradeAfterDueDate(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer setNoDockerForTesting()()
+
+	assignment := db.MustGetAssignment(TEST_COURSE_ID, TEST_ASSIGNMENT_ID)
+
+	assignment.DueDate = nil
+	job := enqueueForTesting(test, assignment, false)
+
+	// The job was enqueued an hour ago and the assignment was due half an hour ago.
+	now := timestamp.Now()
+	job.CreateTime = now - timestamp.FromMSecs(60*60*1000)
+	err := db.SaveGradingJob(job)
+	if err != nil {
+		test.Fatalf("Failed to save job: '%v'.", err)
+	}
+
+	dueDate := now - timestamp.FromMSecs(30*60*1000)
+	assignment.DueDate = &dueDate
+	db.MustSaveAssignment(assignment)
+
+	_, err = RunNextGradingJob(context.Background())
+	if err != nil {
+		test.Fatalf("Failed to run job: '%v'.", err)
+	}
+
+	job = getJobForTesting(test, job.ID)
+
+	if !job.GradingSuccess || job.Rejected || (job.ResultMessage != "") {
+		test.Fatalf("Job was not successful: '%v'.", util.MustToJSONIndent(job))
+	}
+
+	result, err := db.GetSubmissionResult(assignment, BASE_TEST_USER, job.SubmissionID)
+	if err != nil {
+		test.Fatalf("Failed to get submission result: '%v'.", err)
+	}
+
+	if result.GradingStartTime != job.CreateTime {
+		test.Fatalf("Unexpected grading start time. Expected: '%s', Actual: '%s'.", job.CreateTime.SafeString(), result.GradingStartTime.SafeString())
+	}
+
+	if result.GradingStartTime > dueDate {
+		test.Fatalf("Submission is late.")
+	}
+}
+
+// Jobs that fail before grading starts are still completed (so they do not stay running).
+func TestGradingQueueTempDirFailure(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer setNoDockerForTesting()()
+
+	assignment := db.MustGetAssignment(TEST_COURSE_ID, TEST_ASSIGNMENT_ID)
+	job := enqueueForTesting(test, assignment, true)
+
+	util.SetTempDirForTesting(os.DevNull)
+	defer util.SetTempDirForTesting("")
+
+	_, err := RunNextGradingJob(context.Background())
+	if err != nil {
+		test.Fatalf("Failed to run job: '%v'.", err)
+	}
+
+	job = getJobForTesting(test, job.ID)
+
+	if (job.Status != model.GradingJobStatusComplete) || job.GradingSuccess || (job.ResultMessage != JOB_INTERNAL_ERROR_MESSAGE) {
+		test.Fatalf("Job was not completed with an error: '%v'.", util.MustToJSONIndent(job))
+	}
+}
+
+func TestGradingQueueWorkers(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer setNoDockerForTesting()()
+
+	assignment := db.MustGetAssignment(TEST_COURSE_ID, TEST_ASSIGNMENT_ID)
+
+	// A job that was interrupted (left running) should be picked back up.
+	interruptedJob := enqueueForTesting(test, assignment, true)
+	_, err := db.ClaimNextGradingJob()
+	if err != nil {
+		test.Fatalf("Failed to claim job: '%v'.", err)
+	}
+
+	err = StartGradingQueue()
+	if err != nil {
+		test.Fatalf("Failed to start queue: '%v'.", err)
+	}
+	defer StopGradingQueue()
+
+	job := enqueueForTesting(test, assignment, true)
+
+	for _, id := range []string{interruptedJob.ID, job.ID} {
+		job := waitForJobForTesting(test, id)
+		if !job.GradingSuccess {
+			test.Fatalf("Job '%s' was not successful: '%v'.", id, util.MustToJSONIndent(job))
+		}
+	}
+
+	StopGradingQueue()
+
+	// Stopping twice is fine.
+	StopGradingQueue()
+}
+
+func enqueueForTesting(test *testing.T, assignment *model.Assignment, allowLate bool) *model.GradingJob {
+	submissionPath := filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)
+
+	job, reject, err := EnqueueGrading(assignment, submissionPath, BASE_TEST_USER, TEST_MESSAGE, allowLate)
+	if err != nil {
+		test.Fatalf("Failed to enqueue: '%v'.", err)
+	}
+
+	if reject != nil {
+		test.Fatalf("Submission was rejected: '%s'.", reject.String())
+	}
+
+	if job.Status != model.GradingJobStatusPending {
+		test.Fatalf("New job is not pending: '%s'.", job.Status)
+	}
+
+	return job
+}
+
+func getJobForTesting(test *testing.T, id string) *model.GradingJob {
+	job, err := db.GetGradingJob(id)
+	if err != nil {
+		test.Fatalf("Failed to get job '%s': '%v'.", id, err)
+	}
+
+	if job == nil {
+		test.Fatalf("Could not find job '%s'.", id)
+	}
+
+	return job
+}
+
+func waitForJobForTesting(test *testing.T, id string) *model.GradingJob {
+	for i := 0; i < 100; i++ {
+		job := getJobForTesting(test, id)
+		if job.IsDone() {
+			return job
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	test.Fatalf("Timeout waiting for job '%s'.", id)
+	return nil
+}
+
+// Return a function that will restore the docker setting.
+func setNoDockerForTesting() func() {
+	oldDockerVal := config.DOCKER_DISABLE.Get()
+	config.DOCKER_DISABLE.Set(true)
+
+	return func() {
+		config.DOCKER_DISABLE.Set(oldDockerVal)
+	}
+}
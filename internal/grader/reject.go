@@ -2,6 +2,8 @@ package grader
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/edulinq/autograder/internal/common"
@@ -24,9 +26,16 @@ func (this *RejectMaxAttempts) String() string {
 	return fmt.Sprintf("Reached the number of max attempts: %d.", this.Max)
 }
 
+// A rejection that will clear up at a known time.
+type timedRejectReason interface {
+	RejectReason
+	nextAllowedTime() timestamp.Timestamp
+}
+
 type RejectWindowMax struct {
-	Max                int
-	WindowDuration     common.DurationSpec
+	Max            int
+	WindowDuration common.DurationSpec
+	// The submission that needs to leave the window before another submission is allowed.
 	EarliestSubmission timestamp.Timestamp
 }
 
@@ -35,14 +44,58 @@ func (this *RejectWindowMax) String() string {
 }
 
 func (this *RejectWindowMax) fullString(now timestamp.Timestamp) string {
-	nextTime := timestamp.FromMSecs(this.EarliestSubmission.ToMSecs() + this.WindowDuration.TotalMSecs())
-	deltaMS := nextTime.ToMSecs() - now.ToMSecs()
-	deltaString := time.Duration(deltaMS * int64(time.Millisecond)).String()
+	nextTime := this.nextAllowedTime()
 
 	return fmt.Sprintf("Reached the number of max attempts (%d) within submission window (%s)."+
 		" Next allowed submission time is %s (in %s).",
 		this.Max, this.WindowDuration.ShortString(),
-		nextTime.SafeMessage(), deltaString)
+		nextTime.SafeMessage(), getDeltaString(now, nextTime))
+}
+
+func (this *RejectWindowMax) nextAllowedTime() timestamp.Timestamp {
+	return timestamp.FromMSecs(this.EarliestSubmission.ToMSecs() + this.WindowDuration.TotalMSecs())
+}
+
+type RejectDailyMax struct {
+	Max int
+	// The start of the next day (in the course's timezone).
+	NextDay timestamp.Timestamp
+}
+
+func (this *RejectDailyMax) String() string {
+	return this.fullString(timestamp.Now())
+}
+
+func (this *RejectDailyMax) fullString(now timestamp.Timestamp) string {
+	return fmt.Sprintf("Reached the number of max attempts per day (%d)."+
+		" Next allowed submission time is %s (in %s).",
+		this.Max, this.NextDay.SafeMessage(), getDeltaString(now, this.NextDay))
+}
+
+func (this *RejectDailyMax) nextAllowedTime() timestamp.Timestamp {
+	return this.NextDay
+}
+
+type RejectCooldown struct {
+	Cooldown       common.DurationSpec
+	LastSubmission timestamp.Timestamp
+}
+
+func (this *RejectCooldown) String() string {
+	return this.fullString(timestamp.Now())
+}
+
+func (this *RejectCooldown) fullString(now timestamp.Timestamp) string {
+	nextTime := this.nextAllowedTime()
+	cooldownString := time.Duration(this.Cooldown.TotalMSecs() * int64(time.Millisecond)).String()
+
+	return fmt.Sprintf("Submissions must be at least %s apart."+
+		" Next allowed submission time is %s (in %s).",
+		cooldownString, nextTime.SafeMessage(), getDeltaString(now, nextTime))
+}
+
+func (this *RejectCooldown) nextAllowedTime() timestamp.Timestamp {
+	return timestamp.FromMSecs(this.LastSubmission.ToMSecs() + this.Cooldown.TotalMSecs())
 }
 
 type RejectLate struct {
@@ -61,7 +114,45 @@ func (this *RejectLate) String() string {
 		this.AssignmentName, this.DueDate.SafeMessage(), deltaString)
 }
 
-func checkForRejection(assignment *model.Assignment, submissionPath string, email string, message string, allowLate bool) (RejectReason, error) {
+type RejectHidden struct {
+	AssignmentName string
+}
+
+func (this *RejectHidden) String() string {
+	return fmt.Sprintf("Assignment (%s) is not available for submission.", this.AssignmentName)
+}
+
+type RejectNotOpen struct {
+	AssignmentName string
+	OpenDate       timestamp.Timestamp
+}
+
+func (this *RejectNotOpen) String() string {
+	return fmt.Sprintf("Assignment (%s) is not open for submission until %s.", this.AssignmentName, this.OpenDate.SafeMessage())
+}
+
+type RejectClosed struct {
+	AssignmentName string
+	CloseDate      timestamp.Timestamp
+}
+
+func (this *RejectClosed) String() string {
+	return fmt.Sprintf("Assignment (%s) closed on %s and no longer accepts submissions (even late ones).",
+		this.AssignmentName, this.CloseDate.SafeMessage())
+}
+
+type RejectInvalidFiles struct {
+	AssignmentName string
+	Problems       []string
+}
+
+func (this *RejectInvalidFiles) String() string {
+	return fmt.Sprintf("Submission for assignment (%s) has invalid files:\n - %s\nFix these problems and submit again (this submission was not graded or counted).",
+		this.AssignmentName, strings.Join(this.Problems, "\n - "))
+}
+
+// |now| is the time the submission was made (which may be earlier than when it is graded, e.g., for queued submissions).
+func checkForRejection(assignment *model.Assignment, submissionPath string, email string, message string, allowLate bool, now timestamp.Timestamp) (RejectReason, error) {
 	user, err := db.GetServerUser(email)
 	if err != nil {
 		return nil, err
@@ -76,29 +167,108 @@ func checkForRejection(assignment *model.Assignment, submissionPath string, emai
 		return nil, nil
 	}
 
-	reason := checkLateSubmission(assignment, allowLate)
+	extension, err := db.GetExtension(assignment, email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get extension: '%w'.", err)
+	}
+
+	reason, err := checkReleaseWindow(assignment, extension, email, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason != nil {
+		return reason, nil
+	}
+
+	reason = checkLateSubmission(assignment, extension, allowLate, now)
+	if reason != nil {
+		return reason, nil
+	}
+
+	reason, err = checkSubmissionFiles(assignment, submissionPath)
+	if err != nil {
+		return nil, err
+	}
+
 	if reason != nil {
 		return reason, nil
 	}
 
-	return checkSubmissionLimit(assignment, email)
+	return checkSubmissionLimit(assignment, extension, email, now)
 }
 
-func checkLateSubmission(assignment *model.Assignment, allowLate bool) RejectReason {
-	if assignment.DueDate == nil {
-		return nil
+// The extension may be nil.
+func checkReleaseWindow(assignment *model.Assignment, extension *model.Extension, email string, now timestamp.Timestamp) (RejectReason, error) {
+	closeDate := assignment.GetEffectiveCloseDate(extension)
+	if (assignment.OpenDate == nil) && (closeDate == nil) && !assignment.Hidden {
+		return nil, nil
+	}
+
+	// Note that server admins were already checked for in checkForRejection(),
+	// so we don't need to worry about escalation here.
+	user, err := db.GetCourseUser(assignment.GetCourse(), email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf("Unable to find user: '%s'.", email)
 	}
 
-	now := timestamp.Now()
+	// Users that are >= grader can submit to any assignment at any time.
+	if user.Role >= model.CourseRoleGrader {
+		return nil, nil
+	}
+
+	if assignment.Hidden {
+		return &RejectHidden{assignment.Name}, nil
+	}
+
+	if (assignment.OpenDate != nil) && (now < *assignment.OpenDate) {
+		return &RejectNotOpen{assignment.Name, *assignment.OpenDate}, nil
+	}
+
+	if (closeDate != nil) && (now > *closeDate) {
+		return &RejectClosed{assignment.Name, *closeDate}, nil
+	}
+
+	return nil, nil
+}
+
+// The extension may be nil.
+func checkLateSubmission(assignment *model.Assignment, extension *model.Extension, allowLate bool, now timestamp.Timestamp) RejectReason {
+	dueDate := assignment.GetEffectiveDueDate(extension)
+	if dueDate == nil {
+		return nil
+	}
 
-	if (now > *assignment.DueDate) && !allowLate {
-		return &RejectLate{assignment.Name, *assignment.DueDate}
+	if (now > *dueDate) && !allowLate {
+		return &RejectLate{assignment.Name, *dueDate}
 	}
 
 	return nil
 }
 
-func checkSubmissionLimit(assignment *model.Assignment, email string) (RejectReason, error) {
+func checkSubmissionFiles(assignment *model.Assignment, submissionPath string) (RejectReason, error) {
+	if assignment.SubmissionFiles == nil {
+		return nil, nil
+	}
+
+	problems, err := assignment.SubmissionFiles.Check(submissionPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to check submission files: '%w'.", err)
+	}
+
+	if len(problems) == 0 {
+		return nil, nil
+	}
+
+	return &RejectInvalidFiles{assignment.Name, problems}, nil
+}
+
+// The extension may be nil.
+func checkSubmissionLimit(assignment *model.Assignment, extension *model.Extension, email string, now timestamp.Timestamp) (RejectReason, error) {
 	// Do not check for submission limits in testing mode.
 	if config.UNIT_TESTING_MODE.Get() {
 		return nil, nil
@@ -120,61 +290,156 @@ func checkSubmissionLimit(assignment *model.Assignment, email string) (RejectRea
 		return nil, nil
 	}
 
-	limit := assignment.GetSubmissionLimit()
+	limit := assignment.GetEffectiveSubmissionLimit(extension)
 	if limit == nil {
 		return nil, nil
 	}
 
-	now := timestamp.Now()
-
 	history, err := db.GetSubmissionHistory(assignment, email)
 	if err != nil {
 		return nil, err
 	}
 
-	if *limit.Max >= 0 {
+	// Regrades were not submitted by the user, so they do not count against the limits.
+	history = slices.DeleteFunc(history, func(item *model.SubmissionHistoryItem) bool {
+		return item.IsRegrade()
+	})
+
+	dueDate := assignment.GetEffectiveDueDate(extension)
+
+	limit, afterDue := limit.GetActiveLimit(dueDate, now)
+	if afterDue {
+		// After due date limits only count late submissions.
+		history = slices.DeleteFunc(history, func(item *model.SubmissionHistoryItem) bool {
+			return item.GradingStartTime <= *dueDate
+		})
+	}
+
+	return checkSubmissionLimitInfo(limit, history, now, assignment.GetCourse().GetLocation()), nil
+}
+
+// Check all the limits in a (single, already active) submission limit.
+// When multiple timed limits trigger, the one that clears up last is returned
+// (so the next allowed submission time is accurate).
+func checkSubmissionLimitInfo(limit *model.SubmissionLimitInfo,
+	history []*model.SubmissionHistoryItem, now timestamp.Timestamp, location *time.Location) RejectReason {
+	if (limit.Max != nil) && (*limit.Max >= 0) {
 		if len(history) >= *limit.Max {
-			return &RejectMaxAttempts{*limit.Max}, nil
+			return &RejectMaxAttempts{*limit.Max}
 		}
 	}
 
-	if limit.Window != nil {
-		reason, err := checkSubmissionLimitWindow(limit.Window, history, now)
-		if err != nil {
-			return nil, err
+	reasons := make([]timedRejectReason, 0)
+
+	if limit.Cooldown != nil {
+		reason := checkSubmissionLimitCooldown(*limit.Cooldown, history, now)
+		if reason != nil {
+			reasons = append(reasons, reason)
 		}
+	}
 
+	if limit.DailyMax > 0 {
+		reason := checkSubmissionLimitDaily(limit.DailyMax, history, now, location)
 		if reason != nil {
-			return reason, nil
+			reasons = append(reasons, reason)
 		}
 	}
 
-	return nil, nil
+	for _, window := range limit.GetWindows() {
+		reason := checkSubmissionLimitWindow(window, history, now)
+		if reason != nil {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	var result timedRejectReason = nil
+	for _, reason := range reasons {
+		if (result == nil) || (reason.nextAllowedTime() > result.nextAllowedTime()) {
+			result = reason
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return result
 }
 
 func checkSubmissionLimitWindow(window *model.SubmittionLimitWindow,
-	history []*model.SubmissionHistoryItem, now timestamp.Timestamp) (RejectReason, error) {
+	history []*model.SubmissionHistoryItem, now timestamp.Timestamp) timedRejectReason {
 	if len(history) < window.AllowedAttempts {
-		return nil, nil
+		return nil
 	}
 
 	windowStart := timestamp.FromMSecs(now.ToMSecs() - window.Duration.TotalMSecs())
-	earliestTime := timestamp.Zero()
 
-	windowCount := 0
+	windowTimes := make([]timestamp.Timestamp, 0, len(history))
 	for _, item := range history {
 		if item.GradingStartTime > windowStart {
-			windowCount++
+			windowTimes = append(windowTimes, item.GradingStartTime)
+		}
+	}
+
+	if len(windowTimes) < window.AllowedAttempts {
+		return nil
+	}
+
+	// Enough submissions need to leave the window to get back under the limit.
+	slices.Sort(windowTimes)
+	leavingTime := windowTimes[len(windowTimes)-window.AllowedAttempts]
+
+	return &RejectWindowMax{window.AllowedAttempts, window.Duration, leavingTime}
+}
+
+func checkSubmissionLimitDaily(maxAttempts int,
+	history []*model.SubmissionHistoryItem, now timestamp.Timestamp, location *time.Location) timedRejectReason {
+	if len(history) < maxAttempts {
+		return nil
+	}
 
-			if earliestTime.IsZero() || (earliestTime > item.GradingStartTime) {
-				earliestTime = item.GradingStartTime
-			}
+	goNow := now.ToGoTime().In(location)
+	dayStart := time.Date(goNow.Year(), goNow.Month(), goNow.Day(), 0, 0, 0, 0, location)
+	nextDayStart := dayStart.AddDate(0, 0, 1)
+
+	startTime := timestamp.FromGoTime(dayStart)
+
+	count := 0
+	for _, item := range history {
+		if item.GradingStartTime >= startTime {
+			count++
 		}
 	}
 
-	if windowCount >= window.AllowedAttempts {
-		return &RejectWindowMax{window.AllowedAttempts, window.Duration, earliestTime}, nil
+	if count < maxAttempts {
+		return nil
 	}
 
-	return nil, nil
+	return &RejectDailyMax{maxAttempts, timestamp.FromGoTime(nextDayStart)}
+}
+
+func checkSubmissionLimitCooldown(cooldown common.DurationSpec,
+	history []*model.SubmissionHistoryItem, now timestamp.Timestamp) timedRejectReason {
+	if (len(history) == 0) || cooldown.IsEmpty() {
+		return nil
+	}
+
+	lastTime := timestamp.Zero()
+	for _, item := range history {
+		if item.GradingStartTime > lastTime {
+			lastTime = item.GradingStartTime
+		}
+	}
+
+	reason := &RejectCooldown{cooldown, lastTime}
+	if now >= reason.nextAllowedTime() {
+		return nil
+	}
+
+	return reason
+}
+
+func getDeltaString(now timestamp.Timestamp, nextTime timestamp.Timestamp) string {
+	deltaMS := nextTime.ToMSecs() - now.ToMSecs()
+	return time.Duration(deltaMS * int64(time.Millisecond)).String()
 }
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/config"
@@ -104,6 +105,159 @@ func TestRejectWindowMaxMessage(test *testing.T) {
 	}
 }
 
+func TestRejectDailyMaxMessage(test *testing.T) {
+	now := timestamp.Timestamp(0)
+	reason := RejectDailyMax{2, timestamp.Timestamp(7200000)}
+
+	expected := "Reached the number of max attempts per day (2). Next allowed submission time is <timestamp:7200000> (in 2h0m0s)."
+	actual := reason.fullString(now)
+	if expected != actual {
+		test.Fatalf("Message does not match. Expected: '%s', Actual: '%s'.", expected, actual)
+	}
+}
+
+func TestRejectCooldownMessage(test *testing.T) {
+	now := timestamp.Timestamp(60000)
+	reason := RejectCooldown{common.DurationSpec{Minutes: 5}, timestamp.Timestamp(0)}
+
+	expected := "Submissions must be at least 5m0s apart. Next allowed submission time is <timestamp:300000> (in 4m0s)."
+	actual := reason.fullString(now)
+	if expected != actual {
+		test.Fatalf("Message does not match. Expected: '%s', Actual: '%s'.", expected, actual)
+	}
+}
+
+func TestCheckSubmissionLimitInfo(test *testing.T) {
+	hour := int64(60 * 60 * 1000)
+	location := time.FixedZone("UTC-5", -5*60*60)
+
+	// 2024-01-02 07:00 UTC, which is 2024-01-02 02:00 in the course timezone.
+	now := timestamp.FromGoTime(time.Date(2024, 1, 2, 7, 0, 0, 0, time.UTC))
+	nextDay := timestamp.FromGoTime(time.Date(2024, 1, 3, 0, 0, 0, 0, location))
+
+	// Submissions 1, 3, and 5 hours ago.
+	history := []*model.SubmissionHistoryItem{
+		&model.SubmissionHistoryItem{GradingStartTime: timestamp.FromMSecs(now.ToMSecs() - 5*hour)},
+		&model.SubmissionHistoryItem{GradingStartTime: timestamp.FromMSecs(now.ToMSecs() - 3*hour)},
+		&model.SubmissionHistoryItem{GradingStartTime: timestamp.FromMSecs(now.ToMSecs() - 1*hour)},
+	}
+
+	maxValue := 3
+
+	testCases := []struct {
+		limit    model.SubmissionLimitInfo
+		expected RejectReason
+	}{
+		{
+			model.SubmissionLimitInfo{},
+			nil,
+		},
+		{
+			model.SubmissionLimitInfo{Max: &maxValue},
+			&RejectMaxAttempts{3},
+		},
+
+		// Cooldown.
+		{
+			model.SubmissionLimitInfo{Cooldown: &common.DurationSpec{Minutes: 30}},
+			nil,
+		},
+		{
+			model.SubmissionLimitInfo{Cooldown: &common.DurationSpec{Hours: 2}},
+			&RejectCooldown{common.DurationSpec{Hours: 2}, history[2].GradingStartTime},
+		},
+
+		// Daily (all submissions are on the same day in UTC, but only the last one is in the course timezone).
+		{
+			model.SubmissionLimitInfo{DailyMax: 2},
+			nil,
+		},
+		{
+			model.SubmissionLimitInfo{DailyMax: 1},
+			&RejectDailyMax{1, nextDay},
+		},
+
+		// Windows.
+		{
+			model.SubmissionLimitInfo{Windows: []*model.SubmittionLimitWindow{
+				&model.SubmittionLimitWindow{AllowedAttempts: 3, Duration: common.DurationSpec{Hours: 4}},
+			}},
+			nil,
+		},
+		{
+			model.SubmissionLimitInfo{Windows: []*model.SubmittionLimitWindow{
+				&model.SubmittionLimitWindow{AllowedAttempts: 3, Duration: common.DurationSpec{Hours: 6}},
+			}},
+			&RejectWindowMax{3, common.DurationSpec{Hours: 6}, history[0].GradingStartTime},
+		},
+		{
+			// Two submissions need to leave the window.
+			model.SubmissionLimitInfo{Windows: []*model.SubmittionLimitWindow{
+				&model.SubmittionLimitWindow{AllowedAttempts: 2, Duration: common.DurationSpec{Hours: 6}},
+			}},
+			&RejectWindowMax{2, common.DurationSpec{Hours: 6}, history[1].GradingStartTime},
+		},
+		{
+			// The window that clears last is reported.
+			model.SubmissionLimitInfo{
+				Window: &model.SubmittionLimitWindow{AllowedAttempts: 1, Duration: common.DurationSpec{Hours: 2}},
+				Windows: []*model.SubmittionLimitWindow{
+					&model.SubmittionLimitWindow{AllowedAttempts: 3, Duration: common.DurationSpec{Hours: 10}},
+				},
+			},
+			&RejectWindowMax{3, common.DurationSpec{Hours: 10}, history[0].GradingStartTime},
+		},
+
+		// Mixed, the daily limit clears last.
+		{
+			model.SubmissionLimitInfo{
+				DailyMax: 1,
+				Cooldown: &common.DurationSpec{Hours: 2},
+			},
+			&RejectDailyMax{1, nextDay},
+		},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.limit.Validate()
+		if err != nil {
+			test.Errorf("Case %d: Failed to validate limit: '%v'.", i, err)
+			continue
+		}
+
+		actual := checkSubmissionLimitInfo(&testCase.limit, history, now, location)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected rejection. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, actual)
+			continue
+		}
+	}
+}
+
+func TestRejectSubmissionAfterDueLimit(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+
+	// Only one late submission is allowed (there are no limits before the due date).
+	dueDate := timestamp.FromMSecs(timestamp.Now().ToMSecs() - 1000)
+	assignment.DueDate = &dueDate
+
+	maxValue := 1
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{
+		AfterDue: &model.SubmissionLimitInfo{Max: &maxValue},
+	}
+
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", true, nil)
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", true, &RejectMaxAttempts{1})
+
+	// Before the due date, all submissions go through.
+	dueDate = timestamp.FromMSecs(timestamp.Now().ToMSecs() + 1000*60*60)
+	assignment.DueDate = &dueDate
+
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", false, nil)
+}
+
 func TestRejectLateSubmissionWithoutAllow(test *testing.T) {
 	db.ResetForTesting()
 	defer db.ResetForTesting()
@@ -136,6 +290,181 @@ func TestRejectLateSubmissionWithAllow(test *testing.T) {
 	submitForRejection(test, assignment, "course-other@test.edulinq.org", true, nil)
 }
 
+// An extension moves the due date for just one user.
+func TestRejectLateSubmissionExtension(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+
+	// Set a dummy submission limit.
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{}
+
+	// Set the due date to be the Unix epoch.
+	dueDate := timestamp.Zero()
+	assignment.DueDate = &dueDate
+
+	extensionDueDate := timestamp.Now() + timestamp.FromMSecs(1000*60*60)
+	saveTestExtension(test, assignment, "course-student@test.edulinq.org", &model.Extension{DueDate: &extensionDueDate})
+
+	submitForRejection(test, assignment, "course-student@test.edulinq.org", false, nil)
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", false, &RejectLate{assignment.Name, *assignment.DueDate})
+}
+
+// An extension's submission limit replaces the assignment's limit for just one user.
+func TestRejectSubmissionMaxAttemptsExtension(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	assignment.DueDate = nil
+
+	// Set the max submissions to zero.
+	maxValue := 0
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{Max: &maxValue}
+
+	// Give one user an unlimited number of submissions.
+	saveTestExtension(test, assignment, "course-student@test.edulinq.org", &model.Extension{SubmissionLimit: &model.SubmissionLimitInfo{}})
+
+	submitForRejection(test, assignment, "course-student@test.edulinq.org", false, nil)
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", false, &RejectMaxAttempts{0})
+}
+
+func saveTestExtension(test *testing.T, assignment *model.Assignment, user string, extension *model.Extension) {
+	extension.CourseID = assignment.GetCourse().GetID()
+	extension.AssignmentID = assignment.GetID()
+	extension.User = user
+	extension.Author = "course-admin@test.edulinq.org"
+
+	err := db.SaveExtension(assignment, extension)
+	if err != nil {
+		test.Fatalf("Failed to save extension: '%v'.", err)
+	}
+}
+
+func TestRejectSubmissionReleaseWindow(test *testing.T) {
+	past := timestamp.Now() - timestamp.FromMSecs(1000*60*60)
+	future := timestamp.Now() + timestamp.FromMSecs(1000*60*60)
+
+	testCases := []struct {
+		user      string
+		openDate  *timestamp.Timestamp
+		closeDate *timestamp.Timestamp
+		hidden    bool
+		extension *model.Extension
+		expected  func(*model.Assignment) RejectReason
+	}{
+		// Open.
+		{"course-other@test.edulinq.org", &past, &future, false, nil, nil},
+
+		// Hidden.
+		{"course-other@test.edulinq.org", nil, nil, true, nil, func(assignment *model.Assignment) RejectReason {
+			return &RejectHidden{assignment.Name}
+		}},
+
+		// Not yet open.
+		{"course-other@test.edulinq.org", &future, nil, false, nil, func(assignment *model.Assignment) RejectReason {
+			return &RejectNotOpen{assignment.Name, future}
+		}},
+
+		// Closed.
+		{"course-other@test.edulinq.org", nil, &past, false, nil, func(assignment *model.Assignment) RejectReason {
+			return &RejectClosed{assignment.Name, past}
+		}},
+
+		// Closed, but with an extension past the close date.
+		{"course-other@test.edulinq.org", nil, &past, false, &model.Extension{DueDate: &future}, nil},
+
+		// Staff are not subject to release windows.
+		{"course-grader@test.edulinq.org", &future, &past, true, nil, nil},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		assignment := db.MustGetTestSubmissionAssignment()
+		assignment.DueDate = nil
+		assignment.SubmissionLimit = &model.SubmissionLimitInfo{}
+		assignment.OpenDate = testCase.openDate
+		assignment.CloseDate = testCase.closeDate
+		assignment.Hidden = testCase.hidden
+
+		if testCase.extension != nil {
+			saveTestExtension(test, assignment, testCase.user, testCase.extension)
+		}
+
+		var expected RejectReason
+		if testCase.expected != nil {
+			expected = testCase.expected(assignment)
+		}
+
+		test.Logf("Case %d.", i)
+		submitForRejection(test, assignment, testCase.user, true, expected)
+	}
+
+	db.ResetForTesting()
+}
+
+func TestRejectSubmissionInvalidFiles(test *testing.T) {
+	testCases := []struct {
+		files    *model.SubmissionFilesInfo
+		problems []string
+	}{
+		// Valid.
+		{&model.SubmissionFilesInfo{}, nil},
+		{&model.SubmissionFilesInfo{Required: []string{"assignment.sh"}, Allowed: []string{"*.sh", "*.json"}, Forbidden: []string{"*.exe"}, MaxSizeKB: 1}, nil},
+
+		// Invalid.
+		{&model.SubmissionFilesInfo{Required: []string{"assignment.sh", "README.md"}}, []string{
+			"Required file 'README.md' is missing.",
+		}},
+		{&model.SubmissionFilesInfo{Allowed: []string{"*.sh"}, Forbidden: []string{"assignment.*"}}, []string{
+			"File 'assignment.sh' is forbidden (matches 'assignment.*').",
+			"File 'test-submission.json' is not allowed (allowed files: *.sh).",
+		}},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		assignment := db.MustGetTestSubmissionAssignment()
+		assignment.DueDate = nil
+
+		// A single attempt, which rejected submissions should not use up.
+		maxValue := 1
+		assignment.SubmissionLimit = &model.SubmissionLimitInfo{Max: &maxValue}
+		assignment.SubmissionFiles = testCase.files
+
+		err := assignment.SubmissionFiles.Validate()
+		if err != nil {
+			test.Fatalf("Case %d: Failed to validate submission files: '%v'.", i, err)
+		}
+
+		var expected RejectReason
+		if testCase.problems != nil {
+			expected = &RejectInvalidFiles{assignment.Name, testCase.problems}
+		}
+
+		test.Logf("Case %d.", i)
+		submitForRejection(test, assignment, "course-other@test.edulinq.org", false, expected)
+
+		if expected == nil {
+			continue
+		}
+
+		history, err := db.GetSubmissionHistory(assignment, "course-other@test.edulinq.org")
+		if err != nil {
+			test.Fatalf("Case %d: Failed to get submission history: '%v'.", i, err)
+		}
+
+		if len(history) != 0 {
+			test.Fatalf("Case %d: Rejected submission was recorded.", i)
+		}
+	}
+
+	db.ResetForTesting()
+}
+
 func testMaxWindowAttempts(test *testing.T, user string, expectReject bool) {
 	db.ResetForTesting()
 	defer db.ResetForTesting()
@@ -0,0 +1,472 @@
+package grader
+
+// Grading on remote grading workers (see cmd/grading-worker).
+// When remote grading is enabled, graders are not run on this server.
+// Instead, each grader run becomes a task that waits (in memory) until a worker claims it.
+// The worker runs the grader and sends back the raw output, which is then interpreted just like the output of a local grader.
+// Workers send regular heartbeats, and a worker that has not been heard from in a while is considered dead.
+// Tasks that were assigned to a dead worker (or that a worker failed to run) are given to another worker.
+// Remote graders do not build images or take grading slots on this server, workers build their own images and limit their own concurrency.
+// Note that tasks (pending or assigned) only live in memory and are lost when the server restarts.
+// Submissions from the grading queue will be graded again (since running jobs are requeued on startup),
+// but any other submissions waiting on a remote grader will fail.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The longest that a worker's claim will wait for a task to become available.
+const REMOTE_CLAIM_WAIT_SECS = 10
+
+type remoteTask struct {
+	task             *model.RemoteGradingTask
+	imageContextGZip map[string][]byte
+
+	workerID  string
+	claimTime timestamp.Timestamp
+	attempts  int
+
+	// Gets exactly one result (buffered so that sending never blocks).
+	result chan *model.RemoteGradingResult
+}
+
+type remoteDispatcher struct {
+	lock     sync.Mutex
+	workers  map[string]*model.RemoteWorkerInfo
+	pending  []*remoteTask
+	assigned map[string]*remoteTask
+
+	// Closed (and replaced) when a task becomes pending to wake up any waiting claims.
+	taskAdded chan bool
+}
+
+var dispatcher *remoteDispatcher = newRemoteDispatcher()
+
+func newRemoteDispatcher() *remoteDispatcher {
+	return &remoteDispatcher{
+		workers:   make(map[string]*model.RemoteWorkerInfo),
+		pending:   make([]*remoteTask, 0),
+		assigned:  make(map[string]*remoteTask),
+		taskAdded: make(chan bool),
+	}
+}
+
+// Register a new remote grading worker and return its information.
+// The returned ID is used by the worker for all further communication.
+func RegisterRemoteWorker(name string) *model.RemoteWorkerInfo {
+	return dispatcher.register(name)
+}
+
+// Record a heartbeat from a worker.
+// Returns false if the worker is unknown (e.g., it was considered dead), in which case it should register again.
+func RemoteWorkerHeartbeat(workerID string) bool {
+	return dispatcher.heartbeat(workerID)
+}
+
+// Claim the next available task for a worker, waiting a short time for one to become available.
+// Image build contexts are only included for images that the worker does not already know.
+// Returns (task, known worker?), the task will be nil if none became available.
+func ClaimRemoteTask(ctx context.Context, workerID string, knownImages []string) (*model.RemoteGradingTask, bool) {
+	return dispatcher.claim(ctx, workerID, knownImages, time.Duration(REMOTE_CLAIM_WAIT_SECS)*time.Second)
+}
+
+// Report the result of a task.
+// Returns false if the result was not accepted (e.g., the task had already been given to another worker).
+func CompleteRemoteTask(workerID string, result *model.RemoteGradingResult) (bool, error) {
+	return dispatcher.complete(workerID, result)
+}
+
+// Get information on all the live workers.
+func GetRemoteWorkers() []*model.RemoteWorkerInfo {
+	return dispatcher.getWorkers()
+}
+
+// Forget all workers and tasks.
+func ResetRemoteWorkersForTesting() {
+	dispatcher = newRemoteDispatcher()
+}
+
+// Returns: (result, file contents, stdout, stderr, exit status, failure message (soft failure), error (hard failure)).
+func runRemoteGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (
+	*model.GradingInfo, map[string][]byte, string, string, *docker.ExitStatus, string, error) {
+	imageInfo := assignment.GetImageInfo()
+	if imageInfo == nil {
+		return nil, nil, "", "", nil, "", fmt.Errorf("No image information associated with assignment: '%s'.", assignment.FullID())
+	}
+
+	imageContextGZip, err := docker.GetBuildContextGZip(imageInfo)
+	if err != nil {
+		return nil, nil, "", "", nil, "", fmt.Errorf("Failed to create image build context: '%w'.", err)
+	}
+
+	imageHash, err := util.MD5StringHex(util.MustToJSON(imageContextGZip))
+	if err != nil {
+		return nil, nil, "", "", nil, "", fmt.Errorf("Failed to hash image build context: '%w'.", err)
+	}
+
+	inputFileContents, err := util.GzipDirectoryToBytes(submissionPath)
+	if err != nil {
+		return nil, nil, "", "", nil, "", fmt.Errorf("Failed to copy submission files from '%s': '%w'.", submissionPath, err)
+	}
+
+	task := &remoteTask{
+		task: &model.RemoteGradingTask{
+			ID:               util.UUID(),
+			FullSubmissionID: fullSubmissionID,
+			ImageName:        imageInfo.Name,
+			ImageHash:        imageHash,
+			InputFilesGZip:   inputFileContents,
+			MaxRuntimeSecs:   assignment.MaxRuntimeSecs,
+			ResourceLimits:   *assignment.GetEffectiveResourceLimits(),
+		},
+		imageContextGZip: imageContextGZip,
+		result:           make(chan *model.RemoteGradingResult, 1),
+	}
+
+	err = dispatcher.submit(task)
+	if err != nil {
+		return nil, nil, "", "", nil, "", err
+	}
+
+	result := dispatcher.wait(ctx, task)
+	if result == nil {
+		return nil, nil, "", "", nil, getCanceledMessage(assignment), nil
+	}
+
+	if result.Error != "" {
+		return nil, nil, result.Stdout, result.Stderr, result.ExitStatus, "",
+			fmt.Errorf("Remote grading failed for assignment '%s': '%s'.", assignment.FullID(), result.Error)
+	}
+
+	tempDir, _, outputDir, _, err := common.PrepTempGradingDir("remote")
+	if err != nil {
+		return nil, nil, result.Stdout, result.Stderr, result.ExitStatus, "", err
+	}
+
+	if !options.LeaveTempDir {
+		defer os.RemoveAll(tempDir)
+	} else {
+		log.Debug("Leaving behind temp grading dir.", assignment, log.NewAttr("path", tempDir))
+	}
+
+	err = util.GzipBytesToDirectory(outputDir, result.OutputFilesGZip)
+	if err != nil {
+		return nil, nil, result.Stdout, result.Stderr, result.ExitStatus, "", fmt.Errorf("Failed to write remote grading output: '%w'.", err)
+	}
+
+	gradingInfo, fileContents, softGradingError, err := collectGraderOutput(assignment, outputDir, result.ExitStatus, result.Timeout, false)
+
+	return gradingInfo, fileContents, result.Stdout, result.Stderr, result.ExitStatus, softGradingError, err
+}
+
+func (this *remoteDispatcher) register(name string) *model.RemoteWorkerInfo {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	now := timestamp.Now()
+
+	worker := &model.RemoteWorkerInfo{
+		ID:            util.UUID(),
+		Name:          name,
+		RegisterTime:  now,
+		LastHeartbeat: now,
+		Tasks:         make([]string, 0),
+	}
+
+	this.workers[worker.ID] = worker
+
+	log.Info("Remote grading worker registered.", log.NewAttr("worker-id", worker.ID), log.NewAttr("worker-name", name))
+
+	return copyWorkerInfo(worker)
+}
+
+func (this *remoteDispatcher) heartbeat(workerID string) bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	worker, ok := this.workers[workerID]
+	if !ok {
+		return false
+	}
+
+	worker.LastHeartbeat = timestamp.Now()
+
+	return true
+}
+
+// Add a task to the pending tasks.
+// Fails if there are no live workers (since the task would never be run).
+func (this *remoteDispatcher) submit(task *remoteTask) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.checkWorkersLocked()
+
+	if len(this.workers) == 0 {
+		return fmt.Errorf("No remote grading workers are available.")
+	}
+
+	this.addPendingLocked(task, false)
+
+	return nil
+}
+
+// Wait for the result of a task.
+// Returns nil if the context is canceled first (the task will be abandoned).
+func (this *remoteDispatcher) wait(ctx context.Context, task *remoteTask) *model.RemoteGradingResult {
+	checkInterval := time.Duration(max(1, config.GRADING_REMOTE_TIMEOUT_SECS.Get()/2)) * time.Second
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case result := <-task.result:
+			return result
+		case <-ctx.Done():
+			this.abandon(task)
+			return nil
+		case <-ticker.C:
+			this.checkWorkers()
+		}
+	}
+}
+
+func (this *remoteDispatcher) claim(ctx context.Context, workerID string, knownImages []string, maxWait time.Duration) (*model.RemoteGradingTask, bool) {
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+
+	for {
+		this.lock.Lock()
+
+		worker, ok := this.workers[workerID]
+		if !ok {
+			this.lock.Unlock()
+			return nil, false
+		}
+
+		worker.LastHeartbeat = timestamp.Now()
+
+		if len(this.pending) > 0 {
+			task := this.pending[0]
+			this.pending = this.pending[1:]
+
+			task.workerID = workerID
+			task.claimTime = timestamp.Now()
+			task.attempts++
+
+			this.assigned[task.task.ID] = task
+			worker.Tasks = append(worker.Tasks, task.task.ID)
+
+			this.lock.Unlock()
+
+			// Tasks are never modified after creation, so a shallow copy is safe.
+			claimedTask := *task.task
+			if !slices.Contains(knownImages, claimedTask.ImageHash) {
+				claimedTask.ImageContextGZip = task.imageContextGZip
+			}
+
+			log.Debug("Remote grading task claimed.", &claimedTask, log.NewAttr("worker-id", workerID), log.NewAttr("attempt", task.attempts))
+
+			return &claimedTask, true
+		}
+
+		taskAdded := this.taskAdded
+		this.lock.Unlock()
+
+		select {
+		case <-taskAdded:
+			// Try again.
+		case <-ctx.Done():
+			return nil, true
+		case <-timer.C:
+			return nil, true
+		}
+	}
+}
+
+func (this *remoteDispatcher) complete(workerID string, result *model.RemoteGradingResult) (bool, error) {
+	if result == nil {
+		return false, fmt.Errorf("No remote grading result provided.")
+	}
+
+	err := result.Validate()
+	if err != nil {
+		return false, err
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	worker, ok := this.workers[workerID]
+	if ok {
+		worker.LastHeartbeat = timestamp.Now()
+	}
+
+	task, ok := this.assigned[result.TaskID]
+	if !ok || (task.workerID != workerID) {
+		log.Debug("Ignoring stale remote grading result.", log.NewAttr("worker-id", workerID), log.NewAttr("task-id", result.TaskID))
+		return false, nil
+	}
+
+	this.unassignLocked(task)
+
+	if result.Error != "" {
+		log.Warn("Remote grading worker failed to run task.", task.task,
+			log.NewAttr("worker-id", workerID), log.NewAttr("error", result.Error))
+		this.retryLocked(task, result)
+		return true, nil
+	}
+
+	task.result <- result
+
+	return true, nil
+}
+
+// Remove a task that is no longer wanted (e.g., the grading was canceled).
+func (this *remoteDispatcher) abandon(task *remoteTask) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.pending = slices.DeleteFunc(this.pending, func(other *remoteTask) bool {
+		return other == task
+	})
+
+	_, ok := this.assigned[task.task.ID]
+	if ok {
+		this.unassignLocked(task)
+	}
+}
+
+func (this *remoteDispatcher) checkWorkers() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.checkWorkersLocked()
+}
+
+// Remove dead workers and reassign tasks from dead workers or that have been running for too long.
+// The caller must hold the lock.
+func (this *remoteDispatcher) checkWorkersLocked() {
+	timeoutMS := int64(config.GRADING_REMOTE_TIMEOUT_SECS.Get()) * 1000
+	now := timestamp.Now()
+
+	for id, worker := range this.workers {
+		if (now.ToMSecs() - worker.LastHeartbeat.ToMSecs()) <= timeoutMS {
+			continue
+		}
+
+		log.Warn("Remote grading worker has not sent a heartbeat and is considered dead.",
+			log.NewAttr("worker-id", id), log.NewAttr("worker-name", worker.Name), log.NewAttr("last-heartbeat", worker.LastHeartbeat))
+
+		delete(this.workers, id)
+
+		for _, taskID := range worker.Tasks {
+			task, ok := this.assigned[taskID]
+			if !ok {
+				continue
+			}
+
+			delete(this.assigned, taskID)
+			this.retryLocked(task, &model.RemoteGradingResult{TaskID: taskID, Error: "Remote grading worker died."})
+		}
+	}
+
+	// A worker may still be alive, but stuck on a task.
+	for _, task := range this.assigned {
+		runtimeMS := int64(task.task.MaxRuntimeSecs) * 1000
+		if runtimeMS <= 0 {
+			runtimeMS = int64(config.GRADING_RUNTIME_MAX_SECS.Get()) * 1000
+		}
+
+		if (now.ToMSecs() - task.claimTime.ToMSecs()) <= (runtimeMS + timeoutMS) {
+			continue
+		}
+
+		this.unassignLocked(task)
+		this.retryLocked(task, &model.RemoteGradingResult{TaskID: task.task.ID, Error: "Remote grading worker ran out of time."})
+	}
+}
+
+// Put a task that failed back into the pending tasks, or fail it if it has already been tried too many times.
+// The caller must hold the lock.
+func (this *remoteDispatcher) retryLocked(task *remoteTask, result *model.RemoteGradingResult) {
+	if task.attempts >= config.GRADING_REMOTE_MAX_ATTEMPTS.Get() {
+		task.result <- result
+		return
+	}
+
+	log.Debug("Reassigning remote grading task.", task.task, log.NewAttr("reason", result.Error), log.NewAttr("attempts", task.attempts))
+
+	// The task has already been waiting, so it goes to the front of the line.
+	this.addPendingLocked(task, true)
+}
+
+// The caller must hold the lock.
+func (this *remoteDispatcher) addPendingLocked(task *remoteTask, front bool) {
+	task.workerID = ""
+
+	if front {
+		this.pending = slices.Insert(this.pending, 0, task)
+	} else {
+		this.pending = append(this.pending, task)
+	}
+
+	close(this.taskAdded)
+	this.taskAdded = make(chan bool)
+}
+
+// The caller must hold the lock.
+func (this *remoteDispatcher) unassignLocked(task *remoteTask) {
+	delete(this.assigned, task.task.ID)
+
+	worker, ok := this.workers[task.workerID]
+	if ok {
+		worker.Tasks = slices.DeleteFunc(worker.Tasks, func(id string) bool {
+			return id == task.task.ID
+		})
+	}
+
+	task.workerID = ""
+}
+
+func (this *remoteDispatcher) getWorkers() []*model.RemoteWorkerInfo {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	workers := make([]*model.RemoteWorkerInfo, 0, len(this.workers))
+	for _, worker := range this.workers {
+		workers = append(workers, copyWorkerInfo(worker))
+	}
+
+	slices.SortFunc(workers, func(a *model.RemoteWorkerInfo, b *model.RemoteWorkerInfo) int {
+		if a.RegisterTime != b.RegisterTime {
+			return int(a.RegisterTime - b.RegisterTime)
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return workers
+}
+
+func copyWorkerInfo(worker *model.RemoteWorkerInfo) *model.RemoteWorkerInfo {
+	workerCopy := *worker
+	workerCopy.Tasks = slices.Clone(worker.Tasks)
+
+	return &workerCopy
+}
@@ -0,0 +1,432 @@
+package grader
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRemoteGradeBase(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	submissionDir := filepath.Join(config.GetTestdataDir(), "course-languages", "bash", "test-submissions", "solution")
+
+	workerID := RegisterRemoteWorker("test-worker").ID
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seenImages := make(chan bool, 1)
+	go runFakeRemoteWorker(test, ctx, workerID, seenImages)
+
+	options := GetDefaultGradeOptions()
+	options.Remote = true
+
+	result, reject, softError, err := Grade(context.Background(), assignment, submissionDir, BASE_TEST_USER, TEST_MESSAGE, false, options)
+	if err != nil {
+		test.Fatalf("Failed to grade assignment: '%v'.", err)
+	}
+
+	if reject != nil {
+		test.Fatalf("Submission was rejected: '%s'.", reject.String())
+	}
+
+	if softError != "" {
+		test.Fatalf("Submission got a soft error: '%s'.", softError)
+	}
+
+	if result.Info.Score != result.Info.MaxPoints {
+		test.Fatalf("Unexpected score. Expected: %f, Actual: %f.", result.Info.MaxPoints, result.Info.Score)
+	}
+
+	if !<-seenImages {
+		test.Fatalf("Worker did not get the image build context for an unknown image.")
+	}
+}
+
+// Remote graders do not use this server's grading slots.
+func TestRemoteGradeNoLocalSlot(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer setConcurrencyLimitsForTesting(1, 0)()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	submissionDir := filepath.Join(config.GetTestdataDir(), "course-languages", "bash", "test-submissions", "solution")
+
+	// Take the only local slot.
+	releaseSlot, err := scheduler.acquire(context.Background(), assignment.GetCourse().GetID())
+	if err != nil {
+		test.Fatalf("Failed to acquire slot: '%v'.", err)
+	}
+	defer releaseSlot()
+
+	workerID := RegisterRemoteWorker("test-worker").ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	seenImages := make(chan bool, 1)
+	go runFakeRemoteWorker(test, ctx, workerID, seenImages)
+
+	options := GetDefaultGradeOptions()
+	options.Remote = true
+
+	result, _, softError, err := Grade(ctx, assignment, submissionDir, BASE_TEST_USER, TEST_MESSAGE, false, options)
+	if err != nil {
+		test.Fatalf("Failed to grade assignment: '%v'.", err)
+	}
+
+	if softError != "" {
+		test.Fatalf("Submission got a soft error: '%s'.", softError)
+	}
+
+	if result.Info.Score != result.Info.MaxPoints {
+		test.Fatalf("Unexpected score. Expected: %f, Actual: %f.", result.Info.MaxPoints, result.Info.Score)
+	}
+}
+
+func TestRemoteGradeNoWorkers(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	submissionDir := filepath.Join(config.GetTestdataDir(), "course-languages", "bash", "test-submissions", "solution")
+
+	options := GetDefaultGradeOptions()
+	options.Remote = true
+
+	_, _, _, err := Grade(context.Background(), assignment, submissionDir, BASE_TEST_USER, TEST_MESSAGE, false, options)
+	if err == nil {
+		test.Fatalf("Did not get an error when grading without any workers.")
+	}
+
+	if !strings.Contains(err.Error(), "No remote grading workers are available.") {
+		test.Fatalf("Did not get the expected error, got: '%v'.", err)
+	}
+}
+
+func TestRemoteClaimKnownImage(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	workerID := RegisterRemoteWorker("test-worker").ID
+	task := submitRemoteTaskForTesting(test)
+
+	claimed, known := ClaimRemoteTask(context.Background(), workerID, []string{task.task.ImageHash})
+	if !known {
+		test.Fatalf("Worker is not known.")
+	}
+
+	if claimed == nil {
+		test.Fatalf("Did not claim a task.")
+	}
+
+	if claimed.ImageContextGZip != nil {
+		test.Fatalf("Got an image context for a known image.")
+	}
+
+	// The dispatcher's copy of the task should never be modified.
+	if task.task.ImageContextGZip != nil {
+		test.Fatalf("Task was modified when claimed.")
+	}
+}
+
+func TestRemoteClaimUnknownWorker(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	claimed, known := ClaimRemoteTask(context.Background(), "not-a-worker", nil)
+	if known {
+		test.Fatalf("Unknown worker is known.")
+	}
+
+	if claimed != nil {
+		test.Fatalf("Unknown worker claimed a task.")
+	}
+
+	if RemoteWorkerHeartbeat("not-a-worker") {
+		test.Fatalf("Unknown worker heartbeat was accepted.")
+	}
+}
+
+func TestRemoteClaimCanceled(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	workerID := RegisterRemoteWorker("test-worker").ID
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	claimed, known := ClaimRemoteTask(ctx, workerID, nil)
+	if !known {
+		test.Fatalf("Worker is not known.")
+	}
+
+	if claimed != nil {
+		test.Fatalf("Claimed a task when there are none.")
+	}
+}
+
+func TestRemoteCompleteStale(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	workerID := RegisterRemoteWorker("test-worker").ID
+	otherWorkerID := RegisterRemoteWorker("other-worker").ID
+	task := submitRemoteTaskForTesting(test)
+
+	claimed, _ := ClaimRemoteTask(context.Background(), workerID, nil)
+	if claimed == nil {
+		test.Fatalf("Did not claim a task.")
+	}
+
+	// Results from a worker that does not have the task are ignored.
+	accepted, err := CompleteRemoteTask(otherWorkerID, &model.RemoteGradingResult{TaskID: claimed.ID})
+	if err != nil {
+		test.Fatalf("Failed to complete task: '%v'.", err)
+	}
+
+	if accepted {
+		test.Fatalf("Result from the wrong worker was accepted.")
+	}
+
+	accepted, err = CompleteRemoteTask(workerID, &model.RemoteGradingResult{TaskID: claimed.ID, Stdout: "abc"})
+	if err != nil {
+		test.Fatalf("Failed to complete task: '%v'.", err)
+	}
+
+	if !accepted {
+		test.Fatalf("Result from the correct worker was not accepted.")
+	}
+
+	result := <-task.result
+	if result.Stdout != "abc" {
+		test.Fatalf("Got the wrong result: '%s'.", util.MustToJSONIndent(result))
+	}
+
+	// A task can only be completed once.
+	accepted, err = CompleteRemoteTask(workerID, &model.RemoteGradingResult{TaskID: claimed.ID})
+	if err != nil {
+		test.Fatalf("Failed to complete task: '%v'.", err)
+	}
+
+	if accepted {
+		test.Fatalf("Result for a completed task was accepted.")
+	}
+}
+
+func TestRemoteCompleteBadResult(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	workerID := RegisterRemoteWorker("test-worker").ID
+
+	testCases := []*model.RemoteGradingResult{
+		nil,
+		&model.RemoteGradingResult{},
+		&model.RemoteGradingResult{TaskID: "abc", OutputFilesGZip: map[string][]byte{"../result.json": nil}},
+		&model.RemoteGradingResult{TaskID: "abc", OutputFilesGZip: map[string][]byte{"/result.json": nil}},
+	}
+
+	for i, testCase := range testCases {
+		_, err := CompleteRemoteTask(workerID, testCase)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error on a bad result.", i)
+		}
+	}
+}
+
+func TestRemoteRetryErrors(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	oldValue := config.GRADING_REMOTE_MAX_ATTEMPTS.Get()
+	config.GRADING_REMOTE_MAX_ATTEMPTS.Set(2)
+	defer config.GRADING_REMOTE_MAX_ATTEMPTS.Set(oldValue)
+
+	workerID := RegisterRemoteWorker("test-worker").ID
+	task := submitRemoteTaskForTesting(test)
+
+	for i := 0; i < 2; i++ {
+		claimed, _ := ClaimRemoteTask(context.Background(), workerID, nil)
+		if claimed == nil {
+			test.Fatalf("Attempt %d: Did not claim a task.", i)
+		}
+
+		if claimed.ID != task.task.ID {
+			test.Fatalf("Attempt %d: Claimed the wrong task. Expected: '%s', Actual: '%s'.", i, task.task.ID, claimed.ID)
+		}
+
+		accepted, err := CompleteRemoteTask(workerID, &model.RemoteGradingResult{TaskID: claimed.ID, Error: "test error"})
+		if err != nil {
+			test.Fatalf("Attempt %d: Failed to complete task: '%v'.", i, err)
+		}
+
+		if !accepted {
+			test.Fatalf("Attempt %d: Result was not accepted.", i)
+		}
+	}
+
+	select {
+	case result := <-task.result:
+		if result.Error != "test error" {
+			test.Fatalf("Got the wrong result: '%s'.", util.MustToJSONIndent(result))
+		}
+	default:
+		test.Fatalf("Task did not fail after the max number of attempts.")
+	}
+
+	if len(dispatcher.pending) != 0 {
+		test.Fatalf("Failed task is still pending.")
+	}
+}
+
+func TestRemoteDeadWorker(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	deadWorkerID := RegisterRemoteWorker("dead-worker").ID
+	task := submitRemoteTaskForTesting(test)
+
+	claimed, _ := ClaimRemoteTask(context.Background(), deadWorkerID, nil)
+	if claimed == nil {
+		test.Fatalf("Did not claim a task.")
+	}
+
+	liveWorkerID := RegisterRemoteWorker("live-worker").ID
+
+	// Make the first worker look like it has not been heard from in a while.
+	timeoutMS := int64(config.GRADING_REMOTE_TIMEOUT_SECS.Get()) * 1000
+	dispatcher.workers[deadWorkerID].LastHeartbeat = timestamp.FromMSecs(timestamp.Now().ToMSecs() - timeoutMS - 1000)
+
+	dispatcher.checkWorkers()
+
+	workers := GetRemoteWorkers()
+	if (len(workers) != 1) || (workers[0].ID != liveWorkerID) {
+		test.Fatalf("Unexpected workers after check: '%s'.", util.MustToJSONIndent(workers))
+	}
+
+	if RemoteWorkerHeartbeat(deadWorkerID) {
+		test.Fatalf("Dead worker is still known.")
+	}
+
+	claimed, _ = ClaimRemoteTask(context.Background(), liveWorkerID, nil)
+	if claimed == nil {
+		test.Fatalf("Task was not reassigned.")
+	}
+
+	if claimed.ID != task.task.ID {
+		test.Fatalf("Claimed the wrong task. Expected: '%s', Actual: '%s'.", task.task.ID, claimed.ID)
+	}
+
+	// The dead worker's result is no longer wanted.
+	accepted, _ := CompleteRemoteTask(deadWorkerID, &model.RemoteGradingResult{TaskID: claimed.ID})
+	if accepted {
+		test.Fatalf("Result from a dead worker was accepted.")
+	}
+
+	accepted, _ = CompleteRemoteTask(liveWorkerID, &model.RemoteGradingResult{TaskID: claimed.ID})
+	if !accepted {
+		test.Fatalf("Result from the live worker was not accepted.")
+	}
+}
+
+func TestRemoteAbandon(test *testing.T) {
+	ResetRemoteWorkersForTesting()
+	defer ResetRemoteWorkersForTesting()
+
+	workerID := RegisterRemoteWorker("test-worker").ID
+	task := submitRemoteTaskForTesting(test)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := dispatcher.wait(ctx, task)
+	if result != nil {
+		test.Fatalf("Got a result for a canceled task.")
+	}
+
+	claimed, _ := dispatcher.claim(context.Background(), workerID, nil, time.Millisecond)
+	if claimed != nil {
+		test.Fatalf("Claimed an abandoned task.")
+	}
+}
+
+// Act like a worker by running the grader locally (without docker).
+// Sends whether the task came with an image build context on the given channel.
+func runFakeRemoteWorker(test *testing.T, ctx context.Context, workerID string, seenImages chan bool) {
+	task, _ := ClaimRemoteTask(ctx, workerID, nil)
+	if task == nil {
+		seenImages <- false
+		return
+	}
+
+	seenImages <- (task.ImageContextGZip != nil)
+
+	result := &model.RemoteGradingResult{
+		TaskID: task.ID,
+	}
+
+	submissionDir := util.MustMkDirTemp("test-remote-worker-")
+	defer util.RemoveDirent(submissionDir)
+
+	err := util.GzipBytesToDirectory(submissionDir, task.InputFilesGZip)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		assignment := db.MustGetAssignment("course-languages", "bash")
+
+		options := GetDefaultGradeOptions()
+		options.NoDocker = true
+
+		_, result.OutputFilesGZip, result.Stdout, result.Stderr, result.ExitStatus, _, err = runNoDockerGrader(ctx, assignment, submissionDir, options, task.FullSubmissionID)
+		if err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	_, err = CompleteRemoteTask(workerID, result)
+	if err != nil {
+		test.Errorf("Failed to complete task: '%v'.", err)
+	}
+}
+
+func submitRemoteTaskForTesting(test *testing.T) *remoteTask {
+	task := &remoteTask{
+		task: &model.RemoteGradingTask{
+			ID:        util.UUID(),
+			ImageName: "test-image",
+			ImageHash: "test-hash",
+		},
+		imageContextGZip: map[string][]byte{"Dockerfile": []byte("")},
+		result:           make(chan *model.RemoteGradingResult, 1),
+	}
+
+	err := dispatcher.submit(task)
+	if err != nil {
+		test.Fatalf("Failed to submit task: '%v'.", err)
+	}
+
+	return task
+}
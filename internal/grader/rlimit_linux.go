@@ -0,0 +1,49 @@
+//go:build linux
+
+package grader
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/log"
+)
+
+const PRLIMIT_COMMAND = "prlimit"
+
+// Set resource limits (rlimits) on a non-docker grader before it is started.
+// The command is wrapped with prlimit (from util-linux), which sets the limits on itself and then execs the grader,
+// so the limits are in place before the grader (or any children it creates) runs.
+// If prlimit is not available, then a warning is logged and the grader runs without limits.
+// PID limits are not applied (since RLIMIT_NPROC counts all the processes for the server's user),
+// an explicit "nproc" ulimit should be used instead.
+func applyProcessLimits(cmd *exec.Cmd, ulimits []*docker.Ulimit) error {
+	if len(ulimits) == 0 {
+		return nil
+	}
+
+	if cmd.Err != nil {
+		return cmd.Err
+	}
+
+	prlimitPath, err := exec.LookPath(PRLIMIT_COMMAND)
+	if err != nil {
+		log.Warn("Unable to find prlimit (from util-linux), the non-docker grader will run without resource limits.",
+			err, log.NewAttr("command", PRLIMIT_COMMAND))
+		return nil
+	}
+
+	args := []string{prlimitPath}
+	for _, ulimit := range ulimits {
+		args = append(args, fmt.Sprintf("--%s=%d:%d", ulimit.Name, ulimit.Soft, ulimit.Hard))
+	}
+
+	args = append(args, "--", cmd.Path)
+	args = append(args, cmd.Args[1:]...)
+
+	cmd.Path = prlimitPath
+	cmd.Args = args
+
+	return nil
+}
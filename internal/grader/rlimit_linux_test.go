@@ -0,0 +1,81 @@
+//go:build linux
+
+package grader
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/docker"
+)
+
+// The limits must already be set when the grader starts.
+func TestApplyProcessLimits(test *testing.T) {
+	limits := &docker.ResourceLimits{
+		MaxMemoryMB: 100,
+		Ulimits:     []*docker.Ulimit{&docker.Ulimit{Name: "nofile", Soft: 50, Hard: 60}},
+	}
+
+	cmd := exec.Command("cat", "/proc/self/limits")
+
+	stdout, stderr, exitStatus, _, _, err := runCMD(context.Background(), cmd, getProcessLimits(limits, nil))
+	if err != nil {
+		test.Fatalf("Failed to run command: '%v'.", err)
+	}
+
+	if (exitStatus == nil) || (exitStatus.ExitCode != 0) {
+		test.Fatalf("Command did not exit cleanly. Status: '%v', Stderr: '%s'.", exitStatus, stderr)
+	}
+
+	testCases := []struct {
+		name string
+		soft string
+		hard string
+	}{
+		{"Max data size", "104857600", "104857600"},
+		{"Max open files", "50", "60"},
+	}
+
+	for i, testCase := range testCases {
+		found := false
+		for _, line := range strings.Split(stdout, "\n") {
+			if !strings.HasPrefix(line, testCase.name) {
+				continue
+			}
+
+			found = true
+
+			fields := strings.Fields(strings.TrimPrefix(line, testCase.name))
+			if (len(fields) < 2) || (testCase.soft != fields[0]) || (testCase.hard != fields[1]) {
+				test.Errorf("Case %d: Unexpected limit. Expected: (%s, %s), Actual: '%s'.", i, testCase.soft, testCase.hard, line)
+			}
+		}
+
+		if !found {
+			test.Errorf("Case %d: Could not find limit '%s': '%s'.", i, testCase.name, stdout)
+		}
+	}
+}
+
+// A grader still runs (without limits) when prlimit is not available.
+func TestApplyProcessLimitsMissingPrlimit(test *testing.T) {
+	test.Setenv("PATH", "")
+
+	limits := &docker.ResourceLimits{MaxMemoryMB: 100}
+	cmd := exec.Command("/bin/true")
+
+	_, _, exitStatus, _, _, err := runCMD(context.Background(), cmd, getProcessLimits(limits, nil))
+	if err != nil {
+		test.Fatalf("Failed to run command: '%v'.", err)
+	}
+
+	if (exitStatus == nil) || (exitStatus.ExitCode != 0) {
+		test.Fatalf("Command did not exit cleanly. Status: '%v'.", exitStatus)
+	}
+
+	if cmd.Path != "/bin/true" {
+		test.Fatalf("Command was wrapped without prlimit: '%s'.", cmd.Path)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package grader
+
+import (
+	"os/exec"
+
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/log"
+)
+
+// Resource limits for non-docker graders are only supported on Linux.
+func applyProcessLimits(cmd *exec.Cmd, ulimits []*docker.Ulimit) error {
+	if len(ulimits) > 0 {
+		log.Warn("Resource limits for non-docker graders are not supported on this platform, the grader will run without limits.")
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package grader
+
+// Limit how many graders can run at the same time.
+// There is a global limit (across all courses) and a per-course limit.
+// When a slot opens up, it goes to a waiting grader from the course that currently has the fewest running graders
+// (ties are broken by wait time), so one busy course cannot starve the others.
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/stats"
+)
+
+type slotRequest struct {
+	courseID string
+	ready    chan bool
+	granted  bool
+}
+
+type gradingScheduler struct {
+	lock         sync.Mutex
+	waiting      []*slotRequest
+	running      map[string]int
+	totalRunning int
+}
+
+var scheduler gradingScheduler = gradingScheduler{
+	running: make(map[string]int),
+}
+
+// Wait for a free grading slot for the given course.
+// On success, the returned function must be called to release the slot.
+// If the context is canceled while waiting, the context's error is returned and no slot is held.
+func (this *gradingScheduler) acquire(ctx context.Context, courseID string) (func(), error) {
+	request := &slotRequest{
+		courseID: courseID,
+		ready:    make(chan bool),
+	}
+
+	this.lock.Lock()
+	this.waiting = append(this.waiting, request)
+	this.dispatch()
+	this.lock.Unlock()
+
+	select {
+	case <-request.ready:
+	case <-ctx.Done():
+		this.lock.Lock()
+		defer this.lock.Unlock()
+
+		// The slot may have been granted right as the context was canceled.
+		if request.granted {
+			this.releaseLocked(courseID)
+		} else {
+			this.waiting = slices.DeleteFunc(this.waiting, func(other *slotRequest) bool {
+				return other == request
+			})
+			this.updateStats()
+		}
+
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			this.lock.Lock()
+			defer this.lock.Unlock()
+
+			this.releaseLocked(courseID)
+		})
+	}
+
+	return release, nil
+}
+
+func (this *gradingScheduler) releaseLocked(courseID string) {
+	this.running[courseID]--
+	if this.running[courseID] <= 0 {
+		delete(this.running, courseID)
+	}
+
+	this.totalRunning--
+
+	this.dispatch()
+}
+
+// Hand out as many free slots as possible.
+// The caller must hold the lock.
+func (this *gradingScheduler) dispatch() {
+	maxTotal, maxCourse := getConcurrencyLimits()
+
+	for this.totalRunning < maxTotal {
+		nextIndex := -1
+		for i, request := range this.waiting {
+			running := this.running[request.courseID]
+			if (maxCourse > 0) && (running >= maxCourse) {
+				continue
+			}
+
+			// Requests are in arrival order, so only a strictly less busy course can take priority.
+			if (nextIndex == -1) || (running < this.running[this.waiting[nextIndex].courseID]) {
+				nextIndex = i
+			}
+		}
+
+		if nextIndex == -1 {
+			break
+		}
+
+		request := this.waiting[nextIndex]
+		this.waiting = slices.Delete(this.waiting, nextIndex, nextIndex+1)
+
+		this.running[request.courseID]++
+		this.totalRunning++
+
+		request.granted = true
+		close(request.ready)
+	}
+
+	this.updateStats()
+}
+
+func (this *gradingScheduler) updateStats() {
+	stats.SetGradingQueueState(len(this.waiting), this.totalRunning)
+}
+
+func getConcurrencyLimits() (int, int) {
+	maxTotal := config.GRADING_MAX_CONCURRENT.Get()
+	if maxTotal <= 0 {
+		maxTotal = runtime.NumCPU()
+	}
+
+	return maxTotal, config.GRADING_MAX_COURSE.Get()
+}
@@ -0,0 +1,181 @@
+package grader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+)
+
+const SCHEDULER_TEST_WAIT_MSECS = 50
+
+func TestSchedulerGlobalLimit(test *testing.T) {
+	defer setConcurrencyLimitsForTesting(2, 0)()
+	testScheduler := newTestScheduler()
+
+	granted := make(chan string, 10)
+
+	releaseA1 := mustAcquireForTesting(test, testScheduler, "A")
+	mustAcquireForTesting(test, testScheduler, "A")
+
+	acquireAsyncForTesting(testScheduler, "A", "A3", granted)
+	acquireAsyncForTesting(testScheduler, "A", "A4", granted)
+
+	checkNoGrantForTesting(test, granted)
+	checkSchedulerCountsForTesting(test, testScheduler, 2, 2)
+
+	releaseA1()
+
+	checkGrantForTesting(test, granted, "A3")
+	checkNoGrantForTesting(test, granted)
+	checkSchedulerCountsForTesting(test, testScheduler, 1, 2)
+
+	// Releasing twice does nothing.
+	releaseA1()
+
+	checkNoGrantForTesting(test, granted)
+	checkSchedulerCountsForTesting(test, testScheduler, 1, 2)
+}
+
+func TestSchedulerCourseLimit(test *testing.T) {
+	defer setConcurrencyLimitsForTesting(3, 1)()
+	testScheduler := newTestScheduler()
+
+	granted := make(chan string, 10)
+
+	releaseA1 := mustAcquireForTesting(test, testScheduler, "A")
+
+	// A is at its limit, but B can still run.
+	acquireAsyncForTesting(testScheduler, "A", "A2", granted)
+	checkNoGrantForTesting(test, granted)
+
+	mustAcquireForTesting(test, testScheduler, "B")
+	checkSchedulerCountsForTesting(test, testScheduler, 1, 2)
+
+	releaseA1()
+
+	checkGrantForTesting(test, granted, "A2")
+	checkSchedulerCountsForTesting(test, testScheduler, 0, 2)
+}
+
+func TestSchedulerFairShare(test *testing.T) {
+	defer setConcurrencyLimitsForTesting(2, 0)()
+	testScheduler := newTestScheduler()
+
+	granted := make(chan string, 10)
+
+	releaseA1 := mustAcquireForTesting(test, testScheduler, "A")
+	mustAcquireForTesting(test, testScheduler, "A")
+
+	acquireAsyncForTesting(testScheduler, "A", "A3", granted)
+	checkNoGrantForTesting(test, granted)
+
+	acquireAsyncForTesting(testScheduler, "B", "B1", granted)
+	checkNoGrantForTesting(test, granted)
+
+	// B arrived after A3, but A already has a running grader and B has none.
+	releaseA1()
+
+	checkGrantForTesting(test, granted, "B1")
+	checkNoGrantForTesting(test, granted)
+	checkSchedulerCountsForTesting(test, testScheduler, 1, 2)
+}
+
+func TestSchedulerCancel(test *testing.T) {
+	defer setConcurrencyLimitsForTesting(1, 0)()
+	testScheduler := newTestScheduler()
+
+	release := mustAcquireForTesting(test, testScheduler, "A")
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(SCHEDULER_TEST_WAIT_MSECS * time.Millisecond)
+		cancelFunc()
+	}()
+
+	_, err := testScheduler.acquire(ctx, "B")
+	if err == nil {
+		test.Fatalf("Did not get an error on a canceled acquire.")
+	}
+
+	checkSchedulerCountsForTesting(test, testScheduler, 0, 1)
+
+	release()
+	checkSchedulerCountsForTesting(test, testScheduler, 0, 0)
+}
+
+func newTestScheduler() *gradingScheduler {
+	return &gradingScheduler{
+		running: make(map[string]int),
+	}
+}
+
+func setConcurrencyLimitsForTesting(maxTotal int, maxCourse int) func() {
+	oldMaxTotal := config.GRADING_MAX_CONCURRENT.Get()
+	oldMaxCourse := config.GRADING_MAX_COURSE.Get()
+
+	config.GRADING_MAX_CONCURRENT.Set(maxTotal)
+	config.GRADING_MAX_COURSE.Set(maxCourse)
+
+	return func() {
+		config.GRADING_MAX_CONCURRENT.Set(oldMaxTotal)
+		config.GRADING_MAX_COURSE.Set(oldMaxCourse)
+	}
+}
+
+func mustAcquireForTesting(test *testing.T, testScheduler *gradingScheduler, courseID string) func() {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), SCHEDULER_TEST_WAIT_MSECS*time.Millisecond)
+	defer cancelFunc()
+
+	release, err := testScheduler.acquire(ctx, courseID)
+	if err != nil {
+		test.Fatalf("Failed to acquire slot for course '%s': '%v'.", courseID, err)
+	}
+
+	return release
+}
+
+func acquireAsyncForTesting(testScheduler *gradingScheduler, courseID string, label string, granted chan string) {
+	go func() {
+		_, err := testScheduler.acquire(context.Background(), courseID)
+		if err == nil {
+			granted <- label
+		}
+	}()
+
+	// Give the request time to get in line.
+	time.Sleep(SCHEDULER_TEST_WAIT_MSECS * time.Millisecond)
+}
+
+func checkGrantForTesting(test *testing.T, granted chan string, expected string) {
+	select {
+	case actual := <-granted:
+		if expected != actual {
+			test.Fatalf("Unexpected slot grant. Expected: '%s', Actual: '%s'.", expected, actual)
+		}
+	case <-time.After(SCHEDULER_TEST_WAIT_MSECS * 10 * time.Millisecond):
+		test.Fatalf("Timeout waiting for slot grant '%s'.", expected)
+	}
+}
+
+func checkNoGrantForTesting(test *testing.T, granted chan string) {
+	select {
+	case actual := <-granted:
+		test.Fatalf("Unexpected slot grant: '%s'.", actual)
+	case <-time.After(SCHEDULER_TEST_WAIT_MSECS * time.Millisecond):
+	}
+}
+
+func checkSchedulerCountsForTesting(test *testing.T, testScheduler *gradingScheduler, expectedWaiting int, expectedRunning int) {
+	testScheduler.lock.Lock()
+	defer testScheduler.lock.Unlock()
+
+	if expectedWaiting != len(testScheduler.waiting) {
+		test.Fatalf("Unexpected number of waiting graders. Expected: %d, Actual: %d.", expectedWaiting, len(testScheduler.waiting))
+	}
+
+	if expectedRunning != testScheduler.totalRunning {
+		test.Fatalf("Unexpected number of running graders. Expected: %d, Actual: %d.", expectedRunning, testScheduler.totalRunning)
+	}
+}
@@ -0,0 +1,61 @@
+package grader
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Get the team a new submission is made for.
+// Regrades are made for a single user (every member has their own copy to regrade),
+// so they never have a team.
+func getSubmissionTeam(assignment *model.Assignment, user string, options GradeOptions) (*model.Team, error) {
+	if (assignment.Teams == nil) || (options.RegradeOf != nil) {
+		return nil, nil
+	}
+
+	team, err := db.GetUserTeam(assignment, user)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get team for user '%s': '%w'.", user, err)
+	}
+
+	return team, nil
+}
+
+// Make a copy of a team submission for every other member of the team.
+// Each copy gets the member's next submission ID, so all the normal per-user operations
+// (history, peek, submission limits, and scoring) see the team's submissions.
+func makeTeamCopies(assignment *model.Assignment, team *model.Team, result *model.GradingResult) ([]*model.GradingResult, error) {
+	copies := make([]*model.GradingResult, 0, len(team.Members))
+
+	for _, member := range team.Members {
+		if member == result.Info.Submitter {
+			continue
+		}
+
+		var info model.GradingInfo
+		err := util.JSONFromString(util.MustToJSON(result.Info), &info)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to copy grading info for team member '%s': '%w'.", member, err)
+		}
+
+		shortID, err := db.GetNextSubmissionID(assignment, member)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to get next submission id for team member '%s': '%w'.", member, err)
+		}
+
+		info.ID = common.CreateFullSubmissionID(assignment.GetCourse().GetID(), assignment.GetID(), member, shortID)
+		info.ShortID = shortID
+		info.User = member
+
+		memberResult := *result
+		memberResult.Info = &info
+
+		copies = append(copies, &memberResult)
+	}
+
+	return copies, nil
+}
@@ -0,0 +1,75 @@
+package grader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGradeTeamSubmission(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	oldDockerVal := config.DOCKER_DISABLE.Get()
+	config.DOCKER_DISABLE.Set(true)
+	defer config.DOCKER_DISABLE.Set(oldDockerVal)
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	db.MustSetTestStudents(assignment.GetCourse(), "course-student@test.edulinq.org", "course-other@test.edulinq.org")
+
+	assignment.Teams = &model.TeamInfo{
+		Teams: []*model.Team{
+			&model.Team{ID: "team-a", Members: []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"}},
+		},
+	}
+
+	err := assignment.Teams.Validate()
+	if err != nil {
+		test.Fatalf("Failed to validate teams: '%v'.", err)
+	}
+
+	submissionPath := filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)
+
+	gradeOptions := GetDefaultGradeOptions()
+	gradeOptions.AllowLate = true
+
+	result, reject, softError, err := Grade(context.Background(), assignment, submissionPath, "course-other@test.edulinq.org", TEST_MESSAGE, true, gradeOptions)
+	if err != nil {
+		test.Fatalf("Failed to grade: '%v'.", err)
+	}
+
+	if (reject != nil) || (softError != "") {
+		test.Fatalf("Submission was not successful. Reject: '%v', Soft Error: '%s'.", reject, softError)
+	}
+
+	for _, member := range []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"} {
+		history, err := db.GetSubmissionHistory(assignment, member)
+		if err != nil {
+			test.Fatalf("Failed to get history for '%s': '%v'.", member, err)
+		}
+
+		if len(history) != 1 {
+			test.Fatalf("Unexpected history for '%s': '%s'.", member, util.MustToJSONIndent(history))
+		}
+
+		item := history[0]
+		if (item.User != member) || (item.Team != "team-a") || (item.Submitter != "course-other@test.edulinq.org") || (item.Score != result.Info.Score) {
+			test.Fatalf("Unexpected history item for '%s': '%s'.", member, util.MustToJSONIndent(item))
+		}
+	}
+
+	// Users not on a team only get their own submissions.
+	history, err := db.GetSubmissionHistory(assignment, "course-grader@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get history for non-member: '%v'.", err)
+	}
+
+	if len(history) != 0 {
+		test.Fatalf("Non-member has a team submission: '%s'.", util.MustToJSONIndent(history))
+	}
+}
@@ -3,7 +3,6 @@ package canvas
 import (
 	"fmt"
 
-	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -17,9 +16,7 @@ func (this *CanvasBackend) FetchAssignment(assignmentID string) (*lmstypes.Assig
 		this.CourseID, assignmentID)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-	body, _, err := common.GetWithHeaders(url, headers)
-
+	body, _, err := this.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to fetch assignment: '%w'.", err)
 	}
@@ -46,29 +43,13 @@ func (this *CanvasBackend) fetchAssignments(rewriteLinks bool) ([]*lmstypes.Assi
 		this.CourseID, PAGE_SIZE)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-
 	assignments := make([]*lmstypes.Assignment, 0)
 
-	for url != "" {
-		var err error
-
-		if rewriteLinks {
-			url, err = this.rewriteLink(url)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		body, responseHeaders, err := common.GetWithHeaders(url, headers)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to fetch users: '%w'.", err)
-		}
-
+	err := this.fetchPages(url, rewriteLinks, func(body string) error {
 		var pageAssignments []*Assignment
-		err = util.JSONFromString(body, &pageAssignments)
+		err := util.JSONFromString(body, &pageAssignments)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to unmarshal assignments page: '%w'.", err)
+			return fmt.Errorf("Failed to unmarshal assignments page: '%w'.", err)
 		}
 
 		for _, assignment := range pageAssignments {
@@ -79,7 +60,11 @@ func (this *CanvasBackend) fetchAssignments(rewriteLinks bool) ([]*lmstypes.Assi
 			assignments = append(assignments, assignment.ToLMSType())
 		}
 
-		url = fetchNextCanvasLink(responseHeaders)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err)
 	}
 
 	return assignments, nil
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 )
 
@@ -35,9 +34,7 @@ func (this *CanvasBackend) UpdateComment(assignmentID string, comment *lmstypes.
 	form := make(map[string]string, 1)
 	form["comment"] = comment.Text
 
-	headers := this.standardHeaders()
-	_, _, err := common.PutWithHeaders(url, form, headers)
-
+	_, _, err := this.put(url, form)
 	if err != nil {
 		return fmt.Errorf("Failed to update comments: '%w'.", err)
 	}
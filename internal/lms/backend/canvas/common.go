@@ -14,6 +14,15 @@ const (
 	POST_PAGE_SIZE        int    = 75
 	HEADER_LINK           string = "Link"
 	UPLOAD_SLEEP_TIME_SEC        = int64(0.5 * float64(time.Second))
+
+	PROGRESS_STATE_COMPLETED string = "completed"
+	PROGRESS_STATE_FAILED    string = "failed"
+)
+
+// These are variables (instead of constants) so they can be changed for testing.
+var (
+	progressPollInterval time.Duration = 1 * time.Second
+	progressTimeout      time.Duration = 10 * time.Minute
 )
 
 func (this *CanvasBackend) getAPILock() {
@@ -38,29 +47,101 @@ func (this *CanvasBackend) standardHeaders() map[string][]string {
 	}
 }
 
+// Fetch every page of a paginated Canvas endpoint (following the "next" links in the Link headers).
+// |handlePage| is called with the body of each page (in order).
+// When |rewriteLinks| is true, links are rewritten to use the backend's base URL (see rewriteLink()).
+// The caller must hold the API lock.
+func (this *CanvasBackend) fetchPages(url string, rewriteLinks bool, handlePage func(body string) error) error {
+	seenURLs := make(map[string]bool)
+
+	for url != "" {
+		var err error
+
+		if rewriteLinks {
+			url, err = this.rewriteLink(url)
+			if err != nil {
+				return err
+			}
+		}
+
+		if seenURLs[url] {
+			return fmt.Errorf("Canvas pagination looped back to an already fetched page '%s'.", url)
+		}
+		seenURLs[url] = true
+
+		body, responseHeaders, err := this.get(url)
+		if err != nil {
+			return err
+		}
+
+		err = handlePage(body)
+		if err != nil {
+			return err
+		}
+
+		url = fetchNextCanvasLink(responseHeaders)
+	}
+
+	return nil
+}
+
 // See if the response headers have a next link.
 // Returns the link or an empty string.
 func fetchNextCanvasLink(headers map[string][]string) string {
-	values, ok := headers[HEADER_LINK]
-	if !ok {
-		return ""
-	}
+	return parseLinkHeaders(headers[HEADER_LINK])["next"]
+}
+
+// Parse Link headers (RFC 8288), e.g., `<https://a.com/b?page=2>; rel="next", <https://a.com/b?page=1>; rel="first"`.
+// Returns: {rel: URL, ...}.
+func parseLinkHeaders(values []string) map[string]string {
+	links := make(map[string]string)
 
 	for _, value := range values {
-		links := strings.Split(value, ",")
-		for _, link := range links {
-			parts := strings.Split(link, ";")
-			if len(parts) < 2 {
-				continue
+		for value != "" {
+			start := strings.Index(value, "<")
+			if start < 0 {
+				break
 			}
 
-			if strings.TrimSpace(parts[1]) == `rel="next"` {
-				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			end := strings.Index(value[start:], ">")
+			if end < 0 {
+				break
+			}
+			end += start
+
+			url := value[(start + 1):end]
+
+			// The params run until the next link (a comma outside of the angle brackets).
+			value = value[(end + 1):]
+			params := value
+			nextLink := strings.Index(value, "<")
+			if nextLink >= 0 {
+				params = value[:nextLink]
+				value = value[nextLink:]
+			} else {
+				value = ""
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				name, paramValue, found := strings.Cut(param, "=")
+				if !found || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+
+				paramValue = strings.Trim(strings.TrimSpace(paramValue), `",`)
+
+				// A rel may have multiple space-separated types.
+				for _, rel := range strings.Fields(paramValue) {
+					rel = strings.ToLower(rel)
+					if _, ok := links[rel]; !ok {
+						links[rel] = url
+					}
+				}
 			}
 		}
 	}
 
-	return ""
+	return links
 }
 
 // Rewrite a URL that appears in a LINK header for testing.
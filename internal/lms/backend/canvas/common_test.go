@@ -0,0 +1,106 @@
+package canvas
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestParseLinkHeaders(test *testing.T) {
+	testCases := []struct {
+		values   []string
+		expected map[string]string
+	}{
+		{
+			nil,
+			map[string]string{},
+		},
+		{
+			[]string{""},
+			map[string]string{},
+		},
+		{
+			[]string{`<https://a.com/b?page=2>; rel="next"`},
+			map[string]string{"next": "https://a.com/b?page=2"},
+		},
+		{
+			[]string{`<https://a.com/b?page=2>; rel="next", <https://a.com/b?page=1>; rel="first", <https://a.com/b?page=5>; rel="last"`},
+			map[string]string{
+				"next":  "https://a.com/b?page=2",
+				"first": "https://a.com/b?page=1",
+				"last":  "https://a.com/b?page=5",
+			},
+		},
+
+		// No next link.
+		{
+			[]string{`<https://a.com/b?page=1>; rel="current",<https://a.com/b?page=1>; rel="first"`},
+			map[string]string{
+				"current": "https://a.com/b?page=1",
+				"first":   "https://a.com/b?page=1",
+			},
+		},
+
+		// Multiple params, unquoted/cased rel, and multiple rels.
+		{
+			[]string{`<https://a.com/b?page=2&x=1,2>; title="Next;Page"; REL=Next, <https://a.com/b?page=1>; rel="first prev"`},
+			map[string]string{
+				"next":  "https://a.com/b?page=2&x=1,2",
+				"first": "https://a.com/b?page=1",
+				"prev":  "https://a.com/b?page=1",
+			},
+		},
+
+		// Multiple header values (the first rel wins).
+		{
+			[]string{`<https://a.com/b?page=2>; rel="next"`, `<https://a.com/b?page=3>; rel="next"`},
+			map[string]string{"next": "https://a.com/b?page=2"},
+		},
+
+		// Malformed.
+		{
+			[]string{`https://a.com/b?page=2; rel="next"`, `<https://a.com/b?page=2; rel="next"`},
+			map[string]string{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		actual := parseLinkHeaders(testCase.values)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected links. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+			continue
+		}
+	}
+}
+
+// A next link that points back to an already fetched page should not loop forever.
+func TestFetchPagesLoop(test *testing.T) {
+	sleeps := setTestSleep()
+	defer sleeps()
+
+	headers := map[string]string{
+		HEADER_LINK: `<http://canvas.test.edulinq.org/api/v1/test?page=1>; rel="next"`,
+	}
+
+	testServer, requestCount := startScriptedServer([]testResponse{testResponse{http.StatusOK, headers, "[]"}})
+	defer testServer.Close()
+
+	backend := &CanvasBackend{CourseID: TEST_COURSE_ID, APIToken: TEST_TOKEN, BaseURL: testServer.URL}
+
+	pageCount := 0
+	err := backend.fetchPages(testServer.URL+"/api/v1/test?page=1", true, func(body string) error {
+		pageCount++
+		return nil
+	})
+
+	if err == nil {
+		test.Fatalf("Did not get an expected error.")
+	}
+
+	if (pageCount != 1) || (*requestCount != 1) {
+		test.Fatalf("Unexpected number of pages/requests. Expected: 1/1, Actual: %d/%d.", pageCount, *requestCount)
+	}
+}
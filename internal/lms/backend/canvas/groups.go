@@ -0,0 +1,88 @@
+package canvas
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Fetch all the groups (and their members) in a group set (a "group category" in Canvas).
+func (this *CanvasBackend) FetchGroups(groupSetID string) ([]*lmstypes.Group, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	apiEndpoint := fmt.Sprintf(
+		"/api/v1/group_categories/%s/groups?per_page=%d",
+		groupSetID, PAGE_SIZE)
+	url := this.BaseURL + apiEndpoint
+
+	groups := make([]*lmstypes.Group, 0)
+
+	err := this.fetchPages(url, false, func(body string) error {
+		var pageGroups []*Group
+		err := util.JSONFromString(body, &pageGroups)
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal groups page: '%w'.", err)
+		}
+
+		for _, group := range pageGroups {
+			if group == nil {
+				continue
+			}
+
+			members, err := this.fetchGroupMembers(group.ID)
+			if err != nil {
+				return err
+			}
+
+			groups = append(groups, &lmstypes.Group{
+				ID:      group.ID,
+				Name:    group.Name,
+				Members: members,
+			})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch groups for group set '%s': '%w'.", groupSetID, err)
+	}
+
+	return groups, nil
+}
+
+// The caller must hold the API lock.
+func (this *CanvasBackend) fetchGroupMembers(groupID string) ([]*lmstypes.User, error) {
+	apiEndpoint := fmt.Sprintf(
+		"/api/v1/groups/%s/users?per_page=%d",
+		groupID, PAGE_SIZE)
+	url := this.BaseURL + apiEndpoint
+
+	users := make([]*lmstypes.User, 0)
+
+	err := this.fetchPages(url, false, func(body string) error {
+		var pageUsers []*User
+		err := util.JSONFromString(body, &pageUsers)
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal group members page: '%w'.", err)
+		}
+
+		for _, user := range pageUsers {
+			if user == nil {
+				continue
+			}
+
+			users = append(users, user.ToLMSType())
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch members of group '%s': '%w'.", groupID, err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,40 @@
+package canvas
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestCanvasGroupsGetBase(test *testing.T) {
+	expected := []*lmstypes.Group{
+		&lmstypes.Group{
+			ID:   "111",
+			Name: "Team A",
+			Members: []*lmstypes.User{
+				&lmstypes.User{ID: "00040", Name: "course-student", Email: "course-student@test.edulinq.org", Role: model.CourseRoleOther},
+				&lmstypes.User{ID: "00050", Name: "course-other", Email: "course-other@test.edulinq.org", Role: model.CourseRoleOther},
+			},
+		},
+		&lmstypes.Group{
+			ID:   "222",
+			Name: "Team B",
+			Members: []*lmstypes.User{
+				&lmstypes.User{ID: "00030", Name: "course-grader", Email: "course-grader@test.edulinq.org", Role: model.CourseRoleOther},
+			},
+		},
+	}
+
+	groups, err := testBackend.FetchGroups("55555")
+	if err != nil {
+		test.Fatalf("Failed to fetch groups: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, groups) {
+		test.Fatalf("Groups not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(groups))
+	}
+}
@@ -19,6 +19,11 @@ type User struct {
 	LoginID string `json:"login_id"`
 }
 
+type Group struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 type SubmissionScore struct {
 	UserID   string               `json:"user_id"`
 	Score    float64              `json:"score"`
@@ -41,6 +46,14 @@ type Assignment struct {
 	MaxPoints      float64    `json:"points_possible"`
 }
 
+// An asynchronous job (e.g., a bulk grade update).
+type Progress struct {
+	ID            string   `json:"id"`
+	WorkflowState string   `json:"workflow_state"`
+	Completion    *float64 `json:"completion"`
+	Message       string   `json:"message"`
+}
+
 type Enrollment struct {
 	ID              string `json:"id"`
 	Type            string `json:"type"`
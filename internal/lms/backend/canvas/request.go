@@ -0,0 +1,182 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/log"
+)
+
+const (
+	HEADER_RATE_LIMIT_REMAINING string = "X-Rate-Limit-Remaining"
+	HEADER_RETRY_AFTER          string = "Retry-After"
+
+	// The total number of times a request will be tried before giving up.
+	MAX_REQUEST_ATTEMPTS int = 6
+
+	// Canvas's rate limit is a "bucket" (of about 700 units) that requests drain and that refills over time.
+	// When the bucket gets below this level, requests will be slowed down.
+	RATE_LIMIT_LOW_WATER float64 = 200.0
+
+	// Canvas responds to throttled requests with a 403 and this body.
+	RATE_LIMIT_EXCEEDED_MESSAGE string = "Rate Limit Exceeded"
+)
+
+// These are variables (instead of constants) so they can be changed for testing.
+var (
+	retryBaseBackoff time.Duration = 1 * time.Second
+	retryMaxBackoff  time.Duration = 60 * time.Second
+
+	// The longest a request will be delayed when the rate limit is low (when the bucket is empty).
+	maxThrottleSleep time.Duration = 5 * time.Second
+
+	sleep func(duration time.Duration) = time.Sleep
+)
+
+// Returns: (body, headers (response), error)
+// The caller must hold the API lock.
+func (this *CanvasBackend) get(url string) (string, map[string][]string, error) {
+	return this.request(http.MethodGet, url, nil)
+}
+
+// Returns: (body, headers (response), error)
+// The caller must hold the API lock.
+func (this *CanvasBackend) post(url string, form map[string]string) (string, map[string][]string, error) {
+	return this.request(http.MethodPost, url, form)
+}
+
+// Returns: (body, headers (response), error)
+// The caller must hold the API lock.
+func (this *CanvasBackend) put(url string, form map[string]string) (string, map[string][]string, error) {
+	return this.request(http.MethodPut, url, form)
+}
+
+// Make a request to Canvas.
+// Requests that are rate limited (429, or 403 with a rate limit message) or have server errors (5XX)
+// are retried with exponential backoff (respecting any Retry-After header).
+// After a successful request, the remaining rate limit is checked and the backend will
+// slow down (sleep) if the rate limit is getting low.
+// The caller must hold the API lock (so the throttling applies to all requests using the same token).
+func (this *CanvasBackend) request(verb string, url string, form map[string]string) (string, map[string][]string, error) {
+	headers := this.standardHeaders()
+
+	for attempt := 1; ; attempt++ {
+		var body string
+		var responseHeaders map[string][]string
+		var err error
+
+		switch verb {
+		case http.MethodGet:
+			body, responseHeaders, err = common.GetWithHeaders(url, headers)
+		case http.MethodPost:
+			body, responseHeaders, err = common.PostWithHeaders(url, form, headers)
+		case http.MethodPut:
+			body, responseHeaders, err = common.PutWithHeaders(url, form, headers)
+		default:
+			return "", nil, fmt.Errorf("Unsupported Canvas request method '%s'.", verb)
+		}
+
+		if err == nil {
+			throttle(responseHeaders)
+			return body, responseHeaders, nil
+		}
+
+		var statusErr *common.HTTPStatusError
+		if !errors.As(err, &statusErr) || !isRetryable(statusErr) {
+			return "", nil, err
+		}
+
+		if attempt >= MAX_REQUEST_ATTEMPTS {
+			return "", nil, fmt.Errorf("Canvas request failed after %d attempts: '%w'.", attempt, err)
+		}
+
+		backoff := getBackoff(attempt, statusErr.Headers)
+
+		log.Warn("Canvas request failed, retrying.",
+			log.NewAttr("url", url), log.NewAttr("code", statusErr.Code),
+			log.NewAttr("attempt", attempt), log.NewAttr("backoff", backoff.String()))
+
+		sleep(backoff)
+	}
+}
+
+func isRetryable(statusErr *common.HTTPStatusError) bool {
+	if statusErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+
+	if (statusErr.Code == http.StatusForbidden) && strings.Contains(statusErr.Body, RATE_LIMIT_EXCEEDED_MESSAGE) {
+		return true
+	}
+
+	return statusErr.Code >= 500
+}
+
+// Get how long to wait before the next attempt (attempts start at 1).
+// A Retry-After header (in seconds) takes precedence over the exponential backoff.
+func getBackoff(attempt int, headers map[string][]string) time.Duration {
+	retryAfter := getHeader(headers, HEADER_RETRY_AFTER)
+	if retryAfter != "" {
+		seconds, err := strconv.ParseFloat(retryAfter, 64)
+		if (err == nil) && (seconds >= 0) {
+			return min(time.Duration(seconds*float64(time.Second)), retryMaxBackoff)
+		}
+	}
+
+	backoff := retryBaseBackoff * time.Duration(math.Pow(2, float64(attempt-1)))
+	if (backoff <= 0) || (backoff > retryMaxBackoff) {
+		backoff = retryMaxBackoff
+	}
+
+	return backoff
+}
+
+// Sleep in proportion to how close the rate limit is to being exhausted.
+func throttle(headers map[string][]string) {
+	rawRemaining := getHeader(headers, HEADER_RATE_LIMIT_REMAINING)
+	if rawRemaining == "" {
+		return
+	}
+
+	remaining, err := strconv.ParseFloat(rawRemaining, 64)
+	if err != nil {
+		log.Warn("Canvas returned an invalid rate limit.", log.NewAttr("value", rawRemaining))
+		return
+	}
+
+	duration := getThrottleDuration(remaining)
+	if duration <= 0 {
+		return
+	}
+
+	log.Debug("Canvas rate limit is low, slowing down.",
+		log.NewAttr("remaining", remaining), log.NewAttr("sleep", duration.String()))
+
+	sleep(duration)
+}
+
+func getThrottleDuration(remaining float64) time.Duration {
+	if remaining >= RATE_LIMIT_LOW_WATER {
+		return 0
+	}
+
+	remaining = max(0.0, remaining)
+
+	return time.Duration(float64(maxThrottleSleep) * (1.0 - (remaining / RATE_LIMIT_LOW_WATER)))
+}
+
+// Get the first value of a header (using the canonical form of the name).
+func getHeader(headers map[string][]string, name string) string {
+	values := headers[http.CanonicalHeaderKey(name)]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(values[0])
+}
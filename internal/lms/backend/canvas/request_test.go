@@ -0,0 +1,253 @@
+package canvas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testResponse struct {
+	code    int
+	headers map[string]string
+	body    string
+}
+
+func TestRequestRetry(test *testing.T) {
+	ok := testResponse{http.StatusOK, nil, "ok"}
+
+	testCases := []struct {
+		responses        []testResponse
+		expectedRequests int
+		expectedSleeps   []time.Duration
+		expectError      bool
+	}{
+		// Success.
+		{
+			[]testResponse{ok},
+			1,
+			[]time.Duration{},
+			false,
+		},
+
+		// Rate limited (429), then success.
+		{
+			[]testResponse{
+				testResponse{http.StatusTooManyRequests, nil, ""},
+				ok,
+			},
+			2,
+			[]time.Duration{1 * time.Second},
+			false,
+		},
+
+		// Rate limited (403 with a message), then success.
+		{
+			[]testResponse{
+				testResponse{http.StatusForbidden, nil, "403 Forbidden (Rate Limit Exceeded)"},
+				ok,
+			},
+			2,
+			[]time.Duration{1 * time.Second},
+			false,
+		},
+
+		// Server errors with exponential backoff, then success.
+		{
+			[]testResponse{
+				testResponse{http.StatusInternalServerError, nil, ""},
+				testResponse{http.StatusBadGateway, nil, ""},
+				testResponse{http.StatusServiceUnavailable, nil, ""},
+				ok,
+			},
+			4,
+			[]time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second},
+			false,
+		},
+
+		// Retry-After takes precedence over the backoff.
+		{
+			[]testResponse{
+				testResponse{http.StatusTooManyRequests, map[string]string{HEADER_RETRY_AFTER: "7"}, ""},
+				ok,
+			},
+			2,
+			[]time.Duration{7 * time.Second},
+			false,
+		},
+
+		// Retry-After is capped.
+		{
+			[]testResponse{
+				testResponse{http.StatusTooManyRequests, map[string]string{HEADER_RETRY_AFTER: "3600"}, ""},
+				ok,
+			},
+			2,
+			[]time.Duration{60 * time.Second},
+			false,
+		},
+
+		// Invalid Retry-After falls back to the backoff.
+		{
+			[]testResponse{
+				testResponse{http.StatusTooManyRequests, map[string]string{HEADER_RETRY_AFTER: "soon"}, ""},
+				ok,
+			},
+			2,
+			[]time.Duration{1 * time.Second},
+			false,
+		},
+
+		// Out of attempts.
+		{
+			[]testResponse{
+				testResponse{http.StatusInternalServerError, nil, ""},
+			},
+			MAX_REQUEST_ATTEMPTS,
+			[]time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second},
+			true,
+		},
+
+		// Not retryable.
+		{
+			[]testResponse{
+				testResponse{http.StatusNotFound, nil, ""},
+			},
+			1,
+			[]time.Duration{},
+			true,
+		},
+		{
+			[]testResponse{
+				testResponse{http.StatusForbidden, nil, "Unauthorized"},
+			},
+			1,
+			[]time.Duration{},
+			true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		sleeps := setTestSleep()
+		testServer, requestCount := startScriptedServer(testCase.responses)
+
+		backend := &CanvasBackend{CourseID: TEST_COURSE_ID, APIToken: TEST_TOKEN, BaseURL: testServer.URL}
+
+		body, _, err := backend.get(testServer.URL + "/api/v1/test")
+		testServer.Close()
+
+		if testCase.expectError {
+			if err == nil {
+				test.Errorf("Case %d: Did not get an expected error.", i)
+			}
+		} else {
+			if err != nil {
+				test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+			} else if body != "ok" {
+				test.Errorf("Case %d: Unexpected body: '%s'.", i, body)
+			}
+		}
+
+		if testCase.expectedRequests != *requestCount {
+			test.Errorf("Case %d: Unexpected number of requests. Expected: %d, Actual: %d.", i, testCase.expectedRequests, *requestCount)
+		}
+
+		actualSleeps := sleeps()
+		if !reflect.DeepEqual(testCase.expectedSleeps, actualSleeps) {
+			test.Errorf("Case %d: Unexpected sleeps. Expected: '%v', Actual: '%v'.", i, testCase.expectedSleeps, actualSleeps)
+		}
+	}
+}
+
+func TestRequestThrottle(test *testing.T) {
+	testCases := []struct {
+		remaining      string
+		expectedSleeps []time.Duration
+	}{
+		{"", []time.Duration{}},
+		{"700.0", []time.Duration{}},
+		{"200", []time.Duration{}},
+		{"100.0", []time.Duration{2500 * time.Millisecond}},
+		{"0", []time.Duration{5 * time.Second}},
+		{"-10", []time.Duration{5 * time.Second}},
+		{"ZZZ", []time.Duration{}},
+	}
+
+	for i, testCase := range testCases {
+		sleeps := setTestSleep()
+
+		headers := map[string]string{}
+		if testCase.remaining != "" {
+			headers[HEADER_RATE_LIMIT_REMAINING] = testCase.remaining
+		}
+
+		testServer, _ := startScriptedServer([]testResponse{testResponse{http.StatusOK, headers, "ok"}})
+
+		backend := &CanvasBackend{CourseID: TEST_COURSE_ID, APIToken: TEST_TOKEN, BaseURL: testServer.URL}
+
+		_, _, err := backend.get(testServer.URL + "/api/v1/test")
+		testServer.Close()
+
+		if err != nil {
+			test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+			continue
+		}
+
+		actualSleeps := sleeps()
+		if !reflect.DeepEqual(testCase.expectedSleeps, actualSleeps) {
+			test.Errorf("Case %d: Unexpected sleeps. Expected: '%v', Actual: '%v'.", i, testCase.expectedSleeps, actualSleeps)
+			continue
+		}
+	}
+}
+
+// Replace the sleep function with one that records (but does not actually sleep).
+// Returns a function that restores the original sleep and gets the recorded sleeps.
+func setTestSleep() func() []time.Duration {
+	originalSleep := sleep
+
+	var lock sync.Mutex
+	sleeps := make([]time.Duration, 0)
+
+	sleep = func(duration time.Duration) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		sleeps = append(sleeps, duration)
+	}
+
+	return func() []time.Duration {
+		lock.Lock()
+		defer lock.Unlock()
+
+		sleep = originalSleep
+		return sleeps
+	}
+}
+
+// Start a server that responds with each of the given responses in order.
+// Once the responses run out, the last response is repeated.
+// Returns the server and a pointer to the number of requests made.
+func startScriptedServer(responses []testResponse) (*httptest.Server, *int) {
+	var lock sync.Mutex
+	count := 0
+
+	handler := func(response http.ResponseWriter, request *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		testResponse := responses[min(count, (len(responses)-1))]
+		count++
+
+		for key, value := range testResponse.headers {
+			response.Header().Set(key, value)
+		}
+
+		response.WriteHeader(testResponse.code)
+		response.Write([]byte(testResponse.body))
+	}
+
+	return httptest.NewServer(http.HandlerFunc(handler)), &count
+}
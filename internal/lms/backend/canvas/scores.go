@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -18,10 +17,9 @@ func (this *CanvasBackend) FetchAssignmentScore(assignmentID string, userID stri
 		this.CourseID, assignmentID, userID)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-	body, _, err := common.GetWithHeaders(url, headers)
+	body, _, err := this.get(url)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to fetch score.")
+		return nil, fmt.Errorf("Failed to fetch score: '%w'.", err)
 	}
 
 	var rawScore SubmissionScore
@@ -46,30 +44,13 @@ func (this *CanvasBackend) fetchAssignmentScores(assignmentID string, rewriteLin
 		this.CourseID, assignmentID, PAGE_SIZE)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-
 	scores := make([]*lmstypes.SubmissionScore, 0)
 
-	for url != "" {
-		var err error
-
-		if rewriteLinks {
-			url, err = this.rewriteLink(url)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		body, responseHeaders, err := common.GetWithHeaders(url, headers)
-
-		if err != nil {
-			return nil, fmt.Errorf("Failed to fetch scores.")
-		}
-
+	err := this.fetchPages(url, rewriteLinks, func(body string) error {
 		var pageScores []*SubmissionScore
-		err = util.JSONFromString(body, &pageScores)
+		err := util.JSONFromString(body, &pageScores)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to unmarshal scores page: '%w'.", err)
+			return fmt.Errorf("Failed to unmarshal scores page: '%w'.", err)
 		}
 
 		for _, score := range pageScores {
@@ -80,22 +61,30 @@ func (this *CanvasBackend) fetchAssignmentScores(assignmentID string, rewriteLin
 			scores = append(scores, score.ToLMSType())
 		}
 
-		url = fetchNextCanvasLink(responseHeaders)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err)
 	}
 
 	return scores, nil
 }
 
+// Scores are uploaded in batches using Canvas's bulk grade update,
+// which runs asynchronously (as a "progress" job).
+// Each batch is finished (completed or failed) before the next batch is started.
 func (this *CanvasBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
 	for page := 0; (page * POST_PAGE_SIZE) < len(scores); page++ {
 		startIndex := page * POST_PAGE_SIZE
 		endIndex := min(len(scores), ((page + 1) * POST_PAGE_SIZE))
 
-		if page != 0 {
-			time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC))
+		progress, err := this.updateAssignmentScores(assignmentID, scores[startIndex:endIndex])
+		if err != nil {
+			return fmt.Errorf("Failed on page %d: '%w'.", page, err)
 		}
 
-		err := this.updateAssignmentScores(assignmentID, scores[startIndex:endIndex])
+		err = this.waitForProgress(progress)
 		if err != nil {
 			return fmt.Errorf("Failed on page %d: '%w'.", page, err)
 		}
@@ -104,12 +93,13 @@ func (this *CanvasBackend) UpdateAssignmentScores(assignmentID string, scores []
 	return nil
 }
 
-func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+// Start a bulk grade update.
+func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) (*Progress, error) {
 	this.getAPILock()
 	defer this.releaseAPILock()
 
 	if len(scores) > POST_PAGE_SIZE {
-		return fmt.Errorf("Too many score upload requests at once. Found %d, max %d.", len(scores), POST_PAGE_SIZE)
+		return nil, fmt.Errorf("Too many score upload requests at once. Found %d, max %d.", len(scores), POST_PAGE_SIZE)
 	}
 
 	apiEndpoint := fmt.Sprintf(
@@ -117,15 +107,13 @@ func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []
 		this.CourseID, assignmentID)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-
 	form := make(map[string]string)
 
 	for _, score := range scores {
 		form[fmt.Sprintf("grade_data[%s][posted_grade]", score.UserID)] = util.FloatToStr(score.Score)
 
 		if len(score.Comments) > 1 {
-			return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
+			return nil, fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
 		}
 
 		for _, comment := range score.Comments {
@@ -133,10 +121,67 @@ func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []
 		}
 	}
 
-	_, _, err := common.PostWithHeaders(url, form, headers)
+	body, _, err := this.post(url, form)
 	if err != nil {
-		return fmt.Errorf("Failed to upload scores: '%w'.", err)
+		return nil, fmt.Errorf("Failed to upload scores: '%w'.", err)
 	}
 
-	return nil
+	var progress Progress
+	err = util.JSONFromString(body, &progress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal score upload progress: '%w'.", err)
+	}
+
+	return &progress, nil
+}
+
+// Poll a progress job until it is finished.
+// The API lock is only held while checking the progress (not while waiting).
+func (this *CanvasBackend) waitForProgress(progress *Progress) error {
+	if progress.ID == "" {
+		return fmt.Errorf("Canvas did not return a progress job for the score upload.")
+	}
+
+	startTime := time.Now()
+
+	for {
+		switch progress.WorkflowState {
+		case PROGRESS_STATE_COMPLETED:
+			return nil
+		case PROGRESS_STATE_FAILED:
+			return fmt.Errorf("Canvas score upload (progress '%s') failed: '%s'.", progress.ID, progress.Message)
+		}
+
+		if time.Since(startTime) > progressTimeout {
+			return fmt.Errorf("Timed out waiting for Canvas score upload (progress '%s', state '%s').", progress.ID, progress.WorkflowState)
+		}
+
+		sleep(progressPollInterval)
+
+		var err error
+		progress, err = this.fetchProgress(progress.ID)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (this *CanvasBackend) fetchProgress(progressID string) (*Progress, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	url := this.BaseURL + fmt.Sprintf("/api/v1/progress/%s", progressID)
+
+	body, _, err := this.get(url)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch progress '%s': '%w'.", progressID, err)
+	}
+
+	var progress Progress
+	err = util.JSONFromString(body, &progress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal progress '%s': '%w'.", progressID, err)
+	}
+
+	return &progress, nil
 }
@@ -1,8 +1,11 @@
 package canvas
 
 import (
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/util"
@@ -51,3 +54,91 @@ func TestFetchAssignmentScoresBase(test *testing.T) {
 			util.MustToJSONIndent(expected), util.MustToJSONIndent(scores))
 	}
 }
+
+func TestUpdateAssignmentScoresProgress(test *testing.T) {
+	testCases := []struct {
+		responses        []testResponse
+		expectedRequests int
+		expectedSleeps   []time.Duration
+		errorSubstring   string
+	}{
+		// Completed immediately.
+		{
+			[]testResponse{
+				testResponse{http.StatusOK, nil, `{"id": "1", "workflow_state": "completed"}`},
+			},
+			1,
+			[]time.Duration{},
+			"",
+		},
+
+		// Polled until completed.
+		{
+			[]testResponse{
+				testResponse{http.StatusOK, nil, `{"id": "1", "workflow_state": "queued"}`},
+				testResponse{http.StatusOK, nil, `{"id": "1", "workflow_state": "running", "completion": 50}`},
+				testResponse{http.StatusOK, nil, `{"id": "1", "workflow_state": "completed", "completion": 100}`},
+			},
+			3,
+			[]time.Duration{progressPollInterval, progressPollInterval},
+			"",
+		},
+
+		// Polled until failed.
+		{
+			[]testResponse{
+				testResponse{http.StatusOK, nil, `{"id": "1", "workflow_state": "queued"}`},
+				testResponse{http.StatusOK, nil, `{"id": "1", "workflow_state": "failed", "message": "Bad Grade"}`},
+			},
+			2,
+			[]time.Duration{progressPollInterval},
+			"Bad Grade",
+		},
+
+		// No progress.
+		{
+			[]testResponse{
+				testResponse{http.StatusOK, nil, `{}`},
+			},
+			1,
+			[]time.Duration{},
+			"did not return a progress job",
+		},
+	}
+
+	scores := []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID: "00040",
+			Score:  100.0,
+		},
+	}
+
+	for i, testCase := range testCases {
+		sleeps := setTestSleep()
+		testServer, requestCount := startScriptedServer(testCase.responses)
+
+		backend := &CanvasBackend{CourseID: TEST_COURSE_ID, APIToken: TEST_TOKEN, BaseURL: testServer.URL}
+
+		err := backend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, scores)
+		testServer.Close()
+
+		if testCase.errorSubstring != "" {
+			if err == nil {
+				test.Errorf("Case %d: Did not get an expected error.", i)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Unexpected error. Expected substring: '%s', Actual: '%v'.", i, testCase.errorSubstring, err)
+			}
+		} else if err != nil {
+			test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+		}
+
+		if testCase.expectedRequests != *requestCount {
+			test.Errorf("Case %d: Unexpected number of requests. Expected: %d, Actual: %d.", i, testCase.expectedRequests, *requestCount)
+		}
+
+		actualSleeps := sleeps()
+		if !reflect.DeepEqual(testCase.expectedSleeps, actualSleeps) {
+			test.Errorf("Case %d: Unexpected sleeps. Expected: '%v', Actual: '%v'.", i, testCase.expectedSleeps, actualSleeps)
+		}
+	}
+}
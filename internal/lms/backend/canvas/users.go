@@ -4,7 +4,6 @@ import (
 	"fmt"
 	neturl "net/url"
 
-	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/util"
@@ -23,29 +22,13 @@ func (this *CanvasBackend) fetchUsers(rewriteLinks bool) ([]*lmstypes.User, erro
 		this.CourseID, PAGE_SIZE)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-
 	users := make([]*lmstypes.User, 0)
 
-	for url != "" {
-		var err error
-
-		if rewriteLinks {
-			url, err = this.rewriteLink(url)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		body, responseHeaders, err := common.GetWithHeaders(url, headers)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to fetch users: '%w'.", err)
-		}
-
+	err := this.fetchPages(url, rewriteLinks, func(body string) error {
 		var pageUsers []*User
-		err = util.JSONFromString(body, &pageUsers)
+		err := util.JSONFromString(body, &pageUsers)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to unmarshal users page: '%w'.", err)
+			return fmt.Errorf("Failed to unmarshal users page: '%w'.", err)
 		}
 
 		for _, user := range pageUsers {
@@ -56,7 +39,11 @@ func (this *CanvasBackend) fetchUsers(rewriteLinks bool) ([]*lmstypes.User, erro
 			users = append(users, user.ToLMSType())
 		}
 
-		url = fetchNextCanvasLink(responseHeaders)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch users: '%w'.", err)
 	}
 
 	return users, nil
@@ -71,8 +58,7 @@ func (this *CanvasBackend) FetchUser(email string) (*lmstypes.User, error) {
 		this.CourseID, neturl.QueryEscape(email))
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-	body, _, err := common.GetWithHeaders(url, headers)
+	body, _, err := this.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err)
 	}
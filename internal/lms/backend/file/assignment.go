@@ -0,0 +1,36 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Assignments come from the course (since there is no LMS to fetch them from).
+func (this *FileBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+	assignments := make([]*lmstypes.Assignment, 0, len(this.assignments))
+	for _, assignment := range this.assignments {
+		assignments = append(assignments, toLMSAssignment(assignment))
+	}
+
+	return assignments, nil
+}
+
+func (this *FileBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+	assignment := this.getAssignment(assignmentID)
+	if assignment == nil {
+		return nil, fmt.Errorf("Could not find an assignment with LMS ID '%s'.", assignmentID)
+	}
+
+	return toLMSAssignment(assignment), nil
+}
+
+func toLMSAssignment(assignment *model.Assignment) *lmstypes.Assignment {
+	return &lmstypes.Assignment{
+		ID:        assignment.GetLMSID(),
+		Name:      assignment.GetName(),
+		DueDate:   assignment.DueDate,
+		MaxPoints: assignment.MaxPoints,
+	}
+}
@@ -0,0 +1,61 @@
+package file
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// A backend for courses without an LMS.
+// The roster is read from a CSV file,
+// and scores (with comments) are kept in CSV files (one per LMS assignment).
+// Whenever scores are updated, a full gradebook (in the configured format) is also written.
+// LMS assignment IDs are the LMS IDs of the course's assignments.
+type FileBackend struct {
+	Info *model.FileGradebookInfo
+
+	// The course's assignments (sorted) that have an LMS ID.
+	assignments []*model.Assignment
+}
+
+// All file backends share a lock, since multiple courses may use the same files.
+var fileLock sync.Mutex
+
+func NewBackend(info *model.FileGradebookInfo, course *model.Course) (*FileBackend, error) {
+	if info == nil {
+		return nil, fmt.Errorf("File gradebook information (file) cannot be empty.")
+	}
+
+	err := info.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*model.Assignment, 0)
+	for _, assignment := range course.GetSortedAssignments() {
+		if assignment.GetLMSID() != "" {
+			assignments = append(assignments, assignment)
+		}
+	}
+
+	backend := FileBackend{
+		Info:        info,
+		assignments: assignments,
+	}
+
+	return &backend, nil
+}
+
+func (this *FileBackend) getAssignment(assignmentID string) *model.Assignment {
+	index := slices.IndexFunc(this.assignments, func(assignment *model.Assignment) bool {
+		return assignment.GetLMSID() == assignmentID
+	})
+
+	if index < 0 {
+		return nil
+	}
+
+	return this.assignments[index]
+}
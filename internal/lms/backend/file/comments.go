@@ -0,0 +1,45 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+)
+
+// Each score has a single comment (identified by the user's ID),
+// so updating a comment replaces the comment for that user.
+func (this *FileBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	fileLock.Lock()
+	defer fileLock.Unlock()
+
+	rows, err := this.readScores(assignmentID)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		index := -1
+		for i, row := range rows {
+			if row.UserID == comment.ID {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 {
+			return fmt.Errorf("Could not find comment '%s' for assignment '%s'.", comment.ID, assignmentID)
+		}
+
+		rows[index].Comment = comment.Text
+	}
+
+	return this.writeScores(assignmentID, rows)
+}
+
+func (this *FileBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+	return this.UpdateComments(assignmentID, []*lmstypes.SubmissionComment{comment})
+}
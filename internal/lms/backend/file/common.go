@@ -0,0 +1,96 @@
+package file
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Read a CSV file with a header.
+// Each row is returned as a map keyed by the lower-cased (and trimmed) column names.
+// A missing file is treated as an empty file.
+func readCSV(path string) ([]map[string]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make([]map[string]string, 0), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open CSV file '%s': '%w'.", path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read CSV file '%s': '%w'.", path, err)
+	}
+
+	if len(records) == 0 {
+		return make([]map[string]string, 0), nil
+	}
+
+	header := make([]string, 0, len(records[0]))
+	for _, name := range records[0] {
+		// Remove any byte order mark (common in spreadsheet exports).
+		name = strings.TrimPrefix(name, "\ufeff")
+		header = append(header, strings.ToLower(strings.TrimSpace(name)))
+	}
+
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				row[header[i]] = strings.TrimSpace(value)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Write a CSV file (creating any parent directories).
+func writeCSV(path string, records [][]string) error {
+	err := util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create dir for CSV file '%s': '%w'.", path, err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Failed to create CSV file '%s': '%w'.", path, err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+
+	err = writer.WriteAll(records)
+	if err != nil {
+		return fmt.Errorf("Failed to write CSV file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+// Get the first non-empty value from a row for any of the given column names.
+func getValue(row map[string]string, names ...string) string {
+	for _, name := range names {
+		value := row[name]
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
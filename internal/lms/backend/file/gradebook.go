@@ -0,0 +1,104 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/gradebook"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Write the full gradebook (all students on the roster and all course assignments with an LMS ID)
+// in the configured format.
+// The caller must hold the file lock.
+func (this *FileBackend) writeGradebook() error {
+	book, err := this.getGradebook()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(this.Info.Dir, GRADEBOOK_FILENAME)
+
+	err = util.MkDir(this.Info.Dir)
+	if err != nil {
+		return fmt.Errorf("Failed to create gradebook dir '%s': '%w'.", this.Info.Dir, err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Failed to create gradebook file '%s': '%w'.", path, err)
+	}
+	defer file.Close()
+
+	return book.WriteCSV(file, this.Info.Format)
+}
+
+// The caller must hold the file lock.
+func (this *FileBackend) getGradebook() (*gradebook.Gradebook, error) {
+	roster, err := this.readRoster()
+	if err != nil {
+		return nil, err
+	}
+
+	book := &gradebook.Gradebook{
+		Assignments: make([]*gradebook.Assignment, 0, len(this.assignments)),
+		Users:       make([]*gradebook.User, 0, len(roster)),
+	}
+
+	users := make(map[string]*gradebook.User, len(roster))
+	for _, rosterUser := range roster {
+		if rosterUser.Role != model.CourseRoleStudent {
+			continue
+		}
+
+		user := &gradebook.User{
+			Email:  rosterUser.Email,
+			Name:   rosterUser.Name,
+			LMSID:  rosterUser.ID,
+			Scores: make(map[string]*gradebook.Score),
+		}
+
+		book.Users = append(book.Users, user)
+		users[user.LMSID] = user
+	}
+
+	for _, assignment := range this.assignments {
+		book.Assignments = append(book.Assignments, &gradebook.Assignment{
+			ID:        assignment.GetID(),
+			Name:      assignment.GetName(),
+			LMSID:     assignment.GetLMSID(),
+			MaxPoints: assignment.MaxPoints,
+		})
+
+		rows, err := this.readScores(assignment.GetLMSID())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			user := users[row.UserID]
+			if (user == nil) || (row.Score == "") {
+				continue
+			}
+
+			score, err := row.toLMSType()
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse score for assignment '%s': '%w'.", assignment.GetID(), err)
+			}
+
+			entry := &gradebook.Score{
+				Score: score.Score,
+			}
+
+			if score.Time != nil {
+				entry.SubmissionTime = *score.Time
+			}
+
+			user.Scores[assignment.GetID()] = entry
+		}
+	}
+
+	return book, nil
+}
@@ -0,0 +1,11 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+)
+
+func (this *FileBackend) FetchGroups(groupSetID string) ([]*lmstypes.Group, error) {
+	return nil, fmt.Errorf("File gradebooks do not support fetching groups (group set '%s'). Assign teams manually instead.", groupSetID)
+}
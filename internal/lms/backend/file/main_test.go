@@ -0,0 +1,51 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TEST_LMS_ID = "hw0-lms"
+
+func TestMain(suite *testing.M) {
+	// Run inside a func so defers will run before os.Exit().
+	code := func() int {
+		db.PrepForTestingMain()
+		defer db.CleanupTestingMain()
+
+		return suite.Run()
+	}()
+
+	os.Exit(code)
+}
+
+// Get a backend (for course101, where hw0 has an LMS ID) in a new temp dir with the given roster.
+func mustGetTestBackend(test *testing.T, roster string) *FileBackend {
+	dir, err := util.MkDirTemp("test-file-lms-")
+	if err != nil {
+		test.Fatalf("Failed to make temp dir: '%v'.", err)
+	}
+
+	test.Cleanup(func() { util.RemoveDirent(dir) })
+
+	err = util.WriteFile(roster, filepath.Join(dir, model.DEFAULT_ROSTER_FILENAME))
+	if err != nil {
+		test.Fatalf("Failed to write roster: '%v'.", err)
+	}
+
+	course := db.MustGetCourse("course101")
+	course.Assignments["hw0"].LMSID = TEST_LMS_ID
+	course.Assignments["hw0"].MaxPoints = 2
+
+	backend, err := NewBackend(&model.FileGradebookInfo{Dir: dir}, course)
+	if err != nil {
+		test.Fatalf("Failed to create backend: '%v'.", err)
+	}
+
+	return backend
+}
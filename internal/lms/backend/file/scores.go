@@ -0,0 +1,199 @@
+package file
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	SCORES_DIRNAME     = "scores"
+	GRADEBOOK_FILENAME = "gradebook.csv"
+)
+
+var scoresHeader []string = []string{"id", "score", "time", "comment"}
+
+// A single row in an assignment's scores file.
+// Each user has at most one comment (identified by the user's ID).
+type scoreRow struct {
+	UserID  string
+	Score   string
+	Time    string
+	Comment string
+}
+
+func (this *FileBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+
+	rows, err := this.readScores(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]*lmstypes.SubmissionScore, 0, len(rows))
+	for _, row := range rows {
+		score, err := row.toLMSType()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse score for assignment '%s': '%w'.", assignmentID, err)
+		}
+
+		scores = append(scores, score)
+	}
+
+	return scores, nil
+}
+
+func (this *FileBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+	scores, err := this.FetchAssignmentScores(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, score := range scores {
+		if score.UserID == userID {
+			return score, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Only the last comment for each score is kept.
+func (this *FileBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+
+	rows, err := this.readScores(assignmentID)
+	if err != nil {
+		return err
+	}
+
+	for _, score := range scores {
+		row := getOrAddRow(&rows, score.UserID)
+
+		row.Score = util.FloatToStr(score.Score)
+
+		if score.Time != nil {
+			row.Time = score.Time.SafeString()
+		}
+
+		if len(score.Comments) > 0 {
+			row.Comment = score.Comments[len(score.Comments)-1].Text
+		}
+	}
+
+	err = this.writeScores(assignmentID, rows)
+	if err != nil {
+		return err
+	}
+
+	return this.writeGradebook()
+}
+
+func (this *FileBackend) getScoresPath(assignmentID string) (string, error) {
+	if (assignmentID == "") || (assignmentID == ".") || (assignmentID == "..") || strings.ContainsAny(assignmentID, `/\`) {
+		return "", fmt.Errorf("LMS assignment ID '%s' cannot be used as a file name.", assignmentID)
+	}
+
+	return filepath.Join(this.Info.Dir, SCORES_DIRNAME, assignmentID+".csv"), nil
+}
+
+// The caller must hold the file lock.
+func (this *FileBackend) readScores(assignmentID string) ([]*scoreRow, error) {
+	path, err := this.getScoresPath(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := readCSV(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*scoreRow, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, &scoreRow{
+			UserID:  record["id"],
+			Score:   record["score"],
+			Time:    record["time"],
+			Comment: record["comment"],
+		})
+	}
+
+	return rows, nil
+}
+
+// The caller must hold the file lock.
+func (this *FileBackend) writeScores(assignmentID string, rows []*scoreRow) error {
+	path, err := this.getScoresPath(assignmentID)
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(rows, func(a *scoreRow, b *scoreRow) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, scoresHeader)
+
+	for _, row := range rows {
+		records = append(records, []string{row.UserID, row.Score, row.Time, row.Comment})
+	}
+
+	return writeCSV(path, records)
+}
+
+func getOrAddRow(rows *[]*scoreRow, userID string) *scoreRow {
+	for _, row := range *rows {
+		if row.UserID == userID {
+			return row
+		}
+	}
+
+	row := &scoreRow{UserID: userID}
+	*rows = append(*rows, row)
+
+	return row
+}
+
+func (this *scoreRow) toLMSType() (*lmstypes.SubmissionScore, error) {
+	score := &lmstypes.SubmissionScore{
+		UserID:   this.UserID,
+		Comments: make([]*lmstypes.SubmissionComment, 0, 1),
+	}
+
+	if this.Score != "" {
+		value, err := util.StrToFloat(this.Score)
+		if err != nil {
+			return nil, fmt.Errorf("Score for user '%s' is not a number ('%s'): '%w'.", this.UserID, this.Score, err)
+		}
+
+		score.Score = value
+	}
+
+	if this.Time != "" {
+		time, err := timestamp.GuessFromString(this.Time)
+		if err != nil {
+			return nil, fmt.Errorf("Score time for user '%s' is invalid: '%w'.", this.UserID, err)
+		}
+
+		score.Time = &time
+	}
+
+	if this.Comment != "" {
+		score.Comments = append(score.Comments, &lmstypes.SubmissionComment{
+			ID:     this.UserID,
+			Author: this.UserID,
+			Text:   this.Comment,
+		})
+	}
+
+	return score, nil
+}
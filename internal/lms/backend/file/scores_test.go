@@ -0,0 +1,114 @@
+package file
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TEST_ROSTER = "email,name,id,role\n" +
+	"alice@test.edulinq.org,Alice,001,student\n" +
+	"bob@test.edulinq.org,Bob,002,student\n" +
+	"grader@test.edulinq.org,Grader,003,grader\n"
+
+func TestFileUpdateAssignmentScores(test *testing.T) {
+	backend := mustGetTestBackend(test, TEST_ROSTER)
+
+	submissionTime := timestamp.FromMSecs(1704067200000)
+
+	scores := []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID: "002",
+			Score:  1,
+			Comments: []*lmstypes.SubmissionComment{
+				&lmstypes.SubmissionComment{Text: "old"},
+				&lmstypes.SubmissionComment{Text: "new"},
+			},
+		},
+		&lmstypes.SubmissionScore{
+			UserID: "001",
+			Score:  1.5,
+			Time:   &submissionTime,
+		},
+	}
+
+	err := backend.UpdateAssignmentScores(TEST_LMS_ID, scores)
+	if err != nil {
+		test.Fatalf("Failed to update scores: '%v'.", err)
+	}
+
+	expected := []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID:   "001",
+			Score:    1.5,
+			Time:     &submissionTime,
+			Comments: []*lmstypes.SubmissionComment{},
+		},
+		&lmstypes.SubmissionScore{
+			UserID: "002",
+			Score:  1,
+			Comments: []*lmstypes.SubmissionComment{
+				&lmstypes.SubmissionComment{ID: "002", Author: "002", Text: "new"},
+			},
+		},
+	}
+
+	actual, err := backend.FetchAssignmentScores(TEST_LMS_ID)
+	if err != nil {
+		test.Fatalf("Failed to fetch scores: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		test.Fatalf("Unexpected scores. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+
+	// Update a comment.
+	err = backend.UpdateComment(TEST_LMS_ID, &lmstypes.SubmissionComment{ID: "002", Text: "edited"})
+	if err != nil {
+		test.Fatalf("Failed to update comment: '%v'.", err)
+	}
+
+	score, err := backend.FetchAssignmentScore(TEST_LMS_ID, "002")
+	if err != nil {
+		test.Fatalf("Failed to fetch score: '%v'.", err)
+	}
+
+	if (score == nil) || (len(score.Comments) != 1) || (score.Comments[0].Text != "edited") {
+		test.Fatalf("Comment was not updated: '%s'.", util.MustToJSONIndent(score))
+	}
+
+	err = backend.UpdateComment(TEST_LMS_ID, &lmstypes.SubmissionComment{ID: "999", Text: "missing"})
+	if err == nil {
+		test.Fatalf("Did not get an error when updating a missing comment.")
+	}
+
+	// Check the full gradebook (only students are included).
+	expectedGradebook := "email,name,lms-id,hw0\n" +
+		"alice@test.edulinq.org,Alice,001,1.5\n" +
+		"bob@test.edulinq.org,Bob,002,1\n"
+
+	actualGradebook, err := util.ReadFile(filepath.Join(backend.Info.Dir, GRADEBOOK_FILENAME))
+	if err != nil {
+		test.Fatalf("Failed to read gradebook: '%v'.", err)
+	}
+
+	if expectedGradebook != actualGradebook {
+		test.Fatalf("Unexpected gradebook. Expected: \n'%s', \nActual: \n'%s'.", expectedGradebook, actualGradebook)
+	}
+}
+
+func TestFileScoresBadAssignmentID(test *testing.T) {
+	backend := mustGetTestBackend(test, TEST_ROSTER)
+
+	for i, assignmentID := range []string{"", ".", "..", "../hw0", `a\b`} {
+		_, err := backend.FetchAssignmentScores(assignmentID)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an expected error for assignment ID '%s'.", i, assignmentID)
+			continue
+		}
+	}
+}
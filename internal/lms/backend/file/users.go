@@ -0,0 +1,98 @@
+package file
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Column names (lower case) that are recognized in rosters.
+// These cover the generic format and the rosters exported by common LMSs/tools.
+var (
+	emailColumns     = []string{"email", "email address", "sis login id", "login id"}
+	nameColumns      = []string{"name", "full name", "student"}
+	firstNameColumns = []string{"first name", "given name"}
+	lastNameColumns  = []string{"last name", "surname", "family name"}
+	idColumns        = []string{"id", "lms-id", "sid", "student id", "user id"}
+	roleColumns      = []string{"role", "course-role"}
+)
+
+func (this *FileBackend) FetchUsers() ([]*lmstypes.User, error) {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+
+	return this.readRoster()
+}
+
+func (this *FileBackend) FetchUser(email string) (*lmstypes.User, error) {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+
+	users, err := this.readRoster()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err)
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+
+	log.Warn("Did not find user in file roster.", log.NewAttr("email", email), log.NewAttr("roster", this.Info.GetRosterPath()))
+	return nil, nil
+}
+
+// Read the roster.
+// Every user must have an email.
+// Users without an ID use their email as their ID,
+// and users without a role are students.
+// The caller must hold the file lock.
+func (this *FileBackend) readRoster() ([]*lmstypes.User, error) {
+	path := this.Info.GetRosterPath()
+
+	rows, err := readCSV(path)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*lmstypes.User, 0, len(rows))
+	for i, row := range rows {
+		email := getValue(row, emailColumns...)
+		if email == "" {
+			return nil, fmt.Errorf("Roster '%s' has a user (row %d) without an email.", path, i+1)
+		}
+
+		name := getValue(row, nameColumns...)
+		if name == "" {
+			name = strings.TrimSpace(getValue(row, firstNameColumns...) + " " + getValue(row, lastNameColumns...))
+		}
+
+		id := getValue(row, idColumns...)
+		if id == "" {
+			id = email
+		}
+
+		var role model.CourseUserRole = model.CourseRoleStudent
+
+		rawRole := getValue(row, roleColumns...)
+		if rawRole != "" {
+			role = model.GetCourseUserRole(strings.ToLower(rawRole))
+			if role == model.CourseRoleUnknown {
+				return nil, fmt.Errorf("Roster '%s' has a user ('%s') with an unknown role '%s'.", path, email, rawRole)
+			}
+		}
+
+		users = append(users, &lmstypes.User{
+			ID:    id,
+			Name:  name,
+			Email: email,
+			Role:  role,
+		})
+	}
+
+	return users, nil
+}
@@ -0,0 +1,89 @@
+package file
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestFileFetchUsers(test *testing.T) {
+	testCases := []struct {
+		roster   string
+		expected []*lmstypes.User
+		hasError bool
+	}{
+		{
+			"email,name,id,role\nalice@test.edulinq.org,Alice,001,student\nbob@test.edulinq.org,Bob,002,Grader\n",
+			[]*lmstypes.User{
+				&lmstypes.User{ID: "001", Name: "Alice", Email: "alice@test.edulinq.org", Role: model.CourseRoleStudent},
+				&lmstypes.User{ID: "002", Name: "Bob", Email: "bob@test.edulinq.org", Role: model.CourseRoleGrader},
+			},
+			false,
+		},
+		{
+			"\ufeffFirst Name, Last Name ,Email Address\nAlice,Smith,alice@test.edulinq.org\n",
+			[]*lmstypes.User{
+				&lmstypes.User{ID: "alice@test.edulinq.org", Name: "Alice Smith", Email: "alice@test.edulinq.org", Role: model.CourseRoleStudent},
+			},
+			false,
+		},
+		{
+			"Student,SID,SIS Login ID\nAlice,123,alice@test.edulinq.org\n",
+			[]*lmstypes.User{
+				&lmstypes.User{ID: "123", Name: "Alice", Email: "alice@test.edulinq.org", Role: model.CourseRoleStudent},
+			},
+			false,
+		},
+		{"", []*lmstypes.User{}, false},
+		{"name,id\nAlice,001\n", nil, true},
+		{"email,role\nalice@test.edulinq.org,zzz\n", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		backend := mustGetTestBackend(test, testCase.roster)
+
+		users, err := backend.FetchUsers()
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Failed to fetch users: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, users) {
+			test.Errorf("Case %d: Unexpected users. Expected: '%s', Actual: '%s'.", i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(users))
+			continue
+		}
+	}
+}
+
+func TestFileFetchUser(test *testing.T) {
+	backend := mustGetTestBackend(test, "email,id\nalice@test.edulinq.org,001\n")
+
+	user, err := backend.FetchUser("Alice@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to fetch user: '%v'.", err)
+	}
+
+	if (user == nil) || (user.ID != "001") {
+		test.Fatalf("Unexpected user: '%s'.", util.MustToJSONIndent(user))
+	}
+
+	user, err = backend.FetchUser("bob@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to fetch missing user: '%v'.", err)
+	}
+
+	if user != nil {
+		test.Fatalf("Found a user that is not in the roster: '%s'.", util.MustToJSONIndent(user))
+	}
+}
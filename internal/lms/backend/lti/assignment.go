@@ -0,0 +1,68 @@
+package lti
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The assignment ID is the line item's URL.
+func (this *LTIBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	lineItem, err := this.fetchLineItem(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lineItem.ToLMSType(), nil
+}
+
+func (this *LTIBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	assignments := make([]*lmstypes.Assignment, 0)
+
+	err := this.getPages(this.Platform.LineItemsURL, MEDIA_TYPE_LINE_ITEM_CONTAINER, func(body string) error {
+		var pageLineItems []*LineItem
+		err := util.JSONFromString(body, &pageLineItems)
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal line items page: '%w'.", err)
+		}
+
+		for _, lineItem := range pageLineItems {
+			if lineItem == nil {
+				continue
+			}
+
+			assignments = append(assignments, lineItem.ToLMSType())
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch line items: '%w'.", err)
+	}
+
+	return assignments, nil
+}
+
+// The caller must hold the API lock.
+func (this *LTIBackend) fetchLineItem(lineItemID string) (*LineItem, error) {
+	body, err := this.get(lineItemID, MEDIA_TYPE_LINE_ITEM)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch line item '%s': '%w'.", lineItemID, err)
+	}
+
+	var lineItem LineItem
+	err = util.JSONFromString(body, &lineItem)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal line item: '%w'.", err)
+	}
+
+	return &lineItem, nil
+}
@@ -0,0 +1,44 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// A backend for LTI 1.3 platforms using the Assignment and Grade Services (AGS)
+// and the Names and Role Provisioning Services (NRPS).
+// LMS assignments are AGS line items (identified by their URL).
+type LTIBackend struct {
+	Platform *model.LTIPlatformInfo
+
+	privateKey *rsa.PrivateKey
+
+	// Rewrite the URLs the platform gives us to point at the configured line items host.
+	// Only used for testing (with saved requests).
+	rewriteLinks bool
+}
+
+func NewBackend(platform *model.LTIPlatformInfo) (*LTIBackend, error) {
+	if platform == nil {
+		return nil, fmt.Errorf("LTI platform information (lti) cannot be empty.")
+	}
+
+	err := platform.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parsePrivateKey(platform.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := LTIBackend{
+		Platform:   platform,
+		privateKey: key,
+	}
+
+	return &backend, nil
+}
@@ -0,0 +1,41 @@
+package lti
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+)
+
+func (this *LTIBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+	for i, comment := range comments {
+		err := this.UpdateComment(assignmentID, comment)
+		if err != nil {
+			return fmt.Errorf("Failed on comment %d: '%w'.", i, err)
+		}
+	}
+
+	return nil
+}
+
+// AGS only has a single comment per result (authored by the result's user, see Result.ToLMSType()).
+// Comments can only be posted along with a score, so the user's current score is posted again with the new comment.
+func (this *LTIBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	lineItem, err := this.fetchLineItem(assignmentID)
+	if err != nil {
+		return err
+	}
+
+	scores, err := this.fetchAssignmentScores(assignmentID, comment.Author)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch current score for comment update: '%w'.", err)
+	}
+
+	if len(scores) != 1 {
+		return fmt.Errorf("Could not find an LTI result for user '%s' on line item '%s'.", comment.Author, assignmentID)
+	}
+
+	return this.postScore(assignmentID, lineItem, comment.Author, scores[0].Score, comment.Text)
+}
@@ -0,0 +1,181 @@
+package lti
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+)
+
+const (
+	HEADER_LINK string = "Link"
+
+	MEDIA_TYPE_LINE_ITEM           = "application/vnd.ims.lis.v2.lineitem+json"
+	MEDIA_TYPE_LINE_ITEM_CONTAINER = "application/vnd.ims.lis.v2.lineitemcontainer+json"
+	MEDIA_TYPE_RESULT_CONTAINER    = "application/vnd.ims.lis.v2.resultcontainer+json"
+	MEDIA_TYPE_SCORE               = "application/vnd.ims.lis.v1.score+json"
+	MEDIA_TYPE_MEMBERSHIPS         = "application/vnd.ims.lti-nrps.v2.membershipcontainer+json"
+)
+
+func (this *LTIBackend) getAPILock() {
+	common.Lock(this.getLockKey())
+}
+
+func (this *LTIBackend) releaseAPILock() {
+	common.Unlock(this.getLockKey())
+}
+
+// Lock based on the client (platforms rate limit by client).
+func (this *LTIBackend) getLockKey() string {
+	return fmt.Sprintf("lti::%s::%s", this.Platform.TokenURL, this.Platform.ClientID)
+}
+
+func (this *LTIBackend) headers(accept string) (map[string][]string, error) {
+	token, err := this.getAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string][]string{
+		"Authorization": []string{fmt.Sprintf("Bearer %s", token)},
+		"Accept":        []string{accept},
+	}
+
+	return headers, nil
+}
+
+// GET a URL (following any "next" links) and call the handler on the body of each page.
+// The caller must hold the API lock.
+func (this *LTIBackend) getPages(url string, accept string, handler func(body string) error) error {
+	for url != "" {
+		var err error
+
+		url, err = this.resolveURL(url)
+		if err != nil {
+			return err
+		}
+
+		headers, err := this.headers(accept)
+		if err != nil {
+			return err
+		}
+
+		body, responseHeaders, err := common.GetWithHeaders(url, headers)
+		if err != nil {
+			return err
+		}
+
+		err = handler(body)
+		if err != nil {
+			return err
+		}
+
+		url = fetchNextLink(responseHeaders)
+	}
+
+	return nil
+}
+
+// GET a single (non-paginated) URL.
+// The caller must hold the API lock.
+func (this *LTIBackend) get(url string, accept string) (string, error) {
+	url, err := this.resolveURL(url)
+	if err != nil {
+		return "", err
+	}
+
+	headers, err := this.headers(accept)
+	if err != nil {
+		return "", err
+	}
+
+	body, _, err := common.GetWithHeaders(url, headers)
+	return body, err
+}
+
+// POST a JSON body.
+// The caller must hold the API lock.
+func (this *LTIBackend) postJSON(url string, contentType string, body string) error {
+	url, err := this.resolveURL(url)
+	if err != nil {
+		return err
+	}
+
+	headers, err := this.headers("application/json")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = common.PostBodyWithHeaders(url, body, contentType, headers)
+	return err
+}
+
+// Add a path to a service URL (keeping any query, e.g., "<line item>?type=1" -> "<line item>/results?type=1").
+func addURLPath(url string, path string, query map[string]string) (string, error) {
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse URL '%s': '%w'.", url, err)
+	}
+
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + path
+
+	if len(query) > 0 {
+		values := parsed.Query()
+		for key, value := range query {
+			values.Set(key, value)
+		}
+
+		parsed.RawQuery = values.Encode()
+	}
+
+	return parsed.String(), nil
+}
+
+// When testing, point the URLs the platform gives us (e.g., line item IDs and next links)
+// at the host of the configured line items URL.
+func (this *LTIBackend) resolveURL(url string) (string, error) {
+	if !this.rewriteLinks {
+		return url, nil
+	}
+
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse URL '%s': '%w'.", url, err)
+	}
+
+	base, err := neturl.Parse(this.Platform.LineItemsURL)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse line items URL '%s': '%w'.", this.Platform.LineItemsURL, err)
+	}
+
+	parsed.Scheme = base.Scheme
+	parsed.Host = base.Host
+
+	return parsed.String(), nil
+}
+
+// See if the response headers have a next link.
+// Returns the link or an empty string.
+func fetchNextLink(headers map[string][]string) string {
+	values, ok := headers[HEADER_LINK]
+	if !ok {
+		return ""
+	}
+
+	for _, value := range values {
+		links := strings.Split(value, ",")
+		for _, link := range links {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 {
+				continue
+			}
+
+			if strings.TrimSpace(parts[1]) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,12 @@
+package lti
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+)
+
+// The LTI 1.3 core services (AGS and NRPS) do not include groups.
+func (this *LTIBackend) FetchGroups(groupSetID string) ([]*lmstypes.Group, error) {
+	return nil, fmt.Errorf("LTI platforms do not support fetching groups (group set '%s'). Assign teams manually instead.", groupSetID)
+}
@@ -0,0 +1,12 @@
+package lti
+
+import (
+	"testing"
+)
+
+func TestLTIGroupsNotSupported(test *testing.T) {
+	_, err := testBackend.FetchGroups("111")
+	if err == nil {
+		test.Fatalf("Did not get an error when fetching groups.")
+	}
+}
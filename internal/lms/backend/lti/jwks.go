@@ -0,0 +1,148 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// How long a platform's keyset is used before it is fetched again.
+// Keysets are also fetched again when a key is not found (platforms rotate keys),
+// but not more often than JWKS_MIN_REFETCH_MSECS (so launches with unknown key IDs cannot force a fetch every time).
+const JWKS_CACHE_LIFETIME_MSECS = int64(60 * 60 * 1000)
+const JWKS_MIN_REFETCH_MSECS = int64(60 * 1000)
+
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+type jwkSet struct {
+	Keys []*jwk `json:"keys"`
+}
+
+type cachedKeySet struct {
+	Keys      map[string]*rsa.PublicKey
+	FetchTime timestamp.Timestamp
+}
+
+// Platform keysets keyed by URL.
+var keySetCache map[string]*cachedKeySet = make(map[string]*cachedKeySet)
+var keySetCacheLock sync.Mutex
+
+// Get a platform's public key from its keyset (JWKS).
+// An empty key ID is only allowed when the keyset has a single key.
+func getPlatformKey(jwksURL string, keyID string) (*rsa.PublicKey, error) {
+	keySetCacheLock.Lock()
+	defer keySetCacheLock.Unlock()
+
+	now := timestamp.Now().ToMSecs()
+
+	keySet := keySetCache[jwksURL]
+	if (keySet != nil) && ((keySet.FetchTime.ToMSecs() + JWKS_CACHE_LIFETIME_MSECS) > now) {
+		key := findKey(keySet, keyID)
+		if key != nil {
+			return key, nil
+		}
+
+		// The keyset was fetched too recently to fetch it again.
+		if (keySet.FetchTime.ToMSecs() + JWKS_MIN_REFETCH_MSECS) > now {
+			return nil, fmt.Errorf("Could not find key '%s' in the LTI platform keyset.", keyID)
+		}
+	}
+
+	keySet, err := fetchKeySet(jwksURL)
+	if err != nil {
+		return nil, err
+	}
+
+	keySetCache[jwksURL] = keySet
+
+	key := findKey(keySet, keyID)
+	if key == nil {
+		return nil, fmt.Errorf("Could not find key '%s' in the LTI platform keyset.", keyID)
+	}
+
+	return key, nil
+}
+
+func findKey(keySet *cachedKeySet, keyID string) *rsa.PublicKey {
+	if (keyID == "") && (len(keySet.Keys) == 1) {
+		for _, key := range keySet.Keys {
+			return key
+		}
+	}
+
+	return keySet.Keys[keyID]
+}
+
+func fetchKeySet(jwksURL string) (*cachedKeySet, error) {
+	headers := map[string][]string{
+		"Accept": []string{"application/json"},
+	}
+
+	body, _, err := common.GetWithHeaders(jwksURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch LTI platform keyset: '%w'.", err)
+	}
+
+	var rawKeySet jwkSet
+	err = util.JSONFromString(body, &rawKeySet)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal LTI platform keyset: '%w'.", err)
+	}
+
+	keySet := &cachedKeySet{
+		Keys:      make(map[string]*rsa.PublicKey, len(rawKeySet.Keys)),
+		FetchTime: timestamp.Now(),
+	}
+
+	for _, rawKey := range rawKeySet.Keys {
+		// Skip keys that cannot be used to verify launches.
+		if (rawKey.KeyType != "RSA") || ((rawKey.Use != "") && (rawKey.Use != "sig")) {
+			continue
+		}
+
+		key, err := rawKey.toPublicKey()
+		if err != nil {
+			return nil, err
+		}
+
+		keySet.Keys[rawKey.KeyID] = key
+	}
+
+	return keySet, nil
+}
+
+func (this *jwk) toPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(this.N)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode modulus of key '%s': '%w'.", this.KeyID, err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(this.E)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode exponent of key '%s': '%w'.", this.KeyID, err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if (len(n) == 0) || !exponent.IsInt64() || (exponent.Int64() <= 1) || (exponent.Int64() > (1 << 31)) {
+		return nil, fmt.Errorf("Key '%s' is not a valid RSA public key.", this.KeyID)
+	}
+
+	key := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}
+
+	return key, nil
+}
@@ -0,0 +1,90 @@
+package lti
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Unknown key IDs only force a new fetch once the keyset is old enough.
+func TestGetPlatformKeyRefetch(test *testing.T) {
+	var lock sync.Mutex
+	count := 0
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		count++
+		response.Write([]byte(util.MustToJSON(getTestJWKS(&testKey.PublicKey, TEST_KEY_ID))))
+	}))
+	defer jwksServer.Close()
+
+	testCases := []struct {
+		keyID         string
+		fetchAgeMSecs int64
+		expectedCount int
+		hasError      bool
+	}{
+		// Known key, no fetch.
+		{TEST_KEY_ID, 0, 0, false},
+
+		// Unknown key in a recent keyset, no fetch.
+		{"unknown-key", 0, 0, true},
+		{"unknown-key", JWKS_MIN_REFETCH_MSECS - 1000, 0, true},
+
+		// Unknown key in an older keyset, fetch.
+		{"unknown-key", JWKS_MIN_REFETCH_MSECS + 1000, 1, true},
+
+		// Expired keyset, fetch.
+		{TEST_KEY_ID, JWKS_CACHE_LIFETIME_MSECS + 1000, 1, false},
+	}
+
+	for i, testCase := range testCases {
+		keySet, err := fetchKeySet(jwksServer.URL)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to fetch keyset: '%v'.", i, err)
+		}
+
+		keySet.FetchTime = timestamp.FromMSecs(timestamp.Now().ToMSecs() - testCase.fetchAgeMSecs)
+
+		keySetCacheLock.Lock()
+		keySetCache[jwksServer.URL] = keySet
+		keySetCacheLock.Unlock()
+
+		lock.Lock()
+		count = 0
+		lock.Unlock()
+
+		key, err := getPlatformKey(jwksServer.URL, testCase.keyID)
+		if testCase.hasError {
+			if err == nil {
+				test.Errorf("Case %d: Did not get an expected error.", i)
+				continue
+			}
+		} else {
+			if err != nil {
+				test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+				continue
+			}
+
+			if key == nil {
+				test.Errorf("Case %d: Did not get a key.", i)
+				continue
+			}
+		}
+
+		lock.Lock()
+		actualCount := count
+		lock.Unlock()
+
+		if testCase.expectedCount != actualCount {
+			test.Errorf("Case %d: Unexpected number of fetches. Expected: %d, Actual: %d.", i, testCase.expectedCount, actualCount)
+			continue
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package lti
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Parse an RSA private key from PEM (PKCS #8 or PKCS #1).
+func parsePrivateKey(text string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(text))
+	if block == nil {
+		return nil, fmt.Errorf("LTI private key is not PEM encoded.")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("LTI private key is not an RSA key.")
+		}
+
+		return rsaKey, nil
+	}
+
+	rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse LTI private key: '%w'.", err)
+	}
+
+	return rsaKey, nil
+}
+
+// Create a JWT signed with RS256 (the only algorithm LTI 1.3 requires).
+func SignJWT(claims map[string]any, key *rsa.PrivateKey, keyID string) (string, error) {
+	header := map[string]any{
+		"alg": "RS256",
+		"typ": "JWT",
+	}
+
+	if keyID != "" {
+		header["kid"] = keyID
+	}
+
+	headerJSON, err := util.ToJSON(header)
+	if err != nil {
+		return "", fmt.Errorf("Failed to encode JWT header: '%w'.", err)
+	}
+
+	claimsJSON, err := util.ToJSON(claims)
+	if err != nil {
+		return "", fmt.Errorf("Failed to encode JWT claims: '%w'.", err)
+	}
+
+	encoding := base64.RawURLEncoding
+	signingInput := encoding.EncodeToString([]byte(headerJSON)) + "." + encoding.EncodeToString([]byte(claimsJSON))
+
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("Failed to sign JWT: '%w'.", err)
+	}
+
+	return signingInput + "." + encoding.EncodeToString(signature), nil
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// Verify a JWT signed with RS256 and unmarshal its claims into |claims|.
+// |getKey| is called with the key ID ("kid" header) of the JWT.
+func verifyJWT(token string, getKey func(keyID string) (*rsa.PublicKey, error), claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("JWT does not have three parts.")
+	}
+
+	encoding := base64.RawURLEncoding
+
+	headerJSON, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("Failed to decode JWT header: '%w'.", err)
+	}
+
+	var header jwtHeader
+	err = util.JSONFromBytes(headerJSON, &header)
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal JWT header: '%w'.", err)
+	}
+
+	if header.Algorithm != "RS256" {
+		return fmt.Errorf("Unsupported JWT algorithm '%s'.", header.Algorithm)
+	}
+
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("Failed to decode JWT signature: '%w'.", err)
+	}
+
+	key, err := getKey(header.KeyID)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature)
+	if err != nil {
+		return fmt.Errorf("JWT signature is invalid: '%w'.", err)
+	}
+
+	return parseJWTClaims(parts[1], claims)
+}
+
+// Unmarshal the claims of a JWT without verifying it.
+func parseUnverifiedJWT(token string, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("JWT does not have three parts.")
+	}
+
+	return parseJWTClaims(parts[1], claims)
+}
+
+func parseJWTClaims(segment string, claims any) error {
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("Failed to decode JWT claims: '%w'.", err)
+	}
+
+	err = util.JSONFromBytes(claimsJSON, claims)
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal JWT claims: '%w'.", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package lti
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSignJWT(test *testing.T) {
+	claims := map[string]any{
+		"iss": TEST_CLIENT_ID,
+		"aud": "https://lti.test.com/login/oauth2/token",
+	}
+
+	token, err := SignJWT(claims, testKey, "key-01")
+	if err != nil {
+		test.Fatalf("Failed to sign JWT: '%v'.", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		test.Fatalf("JWT does not have three parts: '%s'.", token)
+	}
+
+	var header map[string]any
+	util.MustJSONFromString(string(mustDecodeSegment(test, parts[0])), &header)
+
+	if (header["alg"] != "RS256") || (header["kid"] != "key-01") {
+		test.Fatalf("Unexpected JWT header: '%s'.", util.MustToJSONIndent(header))
+	}
+
+	var actualClaims map[string]any
+	util.MustJSONFromString(string(mustDecodeSegment(test, parts[1])), &actualClaims)
+
+	if (actualClaims["iss"] != claims["iss"]) || (actualClaims["aud"] != claims["aud"]) {
+		test.Fatalf("Unexpected JWT claims: '%s'.", util.MustToJSONIndent(actualClaims))
+	}
+
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(&testKey.PublicKey, crypto.SHA256, hash[:], mustDecodeSegment(test, parts[2]))
+	if err != nil {
+		test.Fatalf("JWT signature does not verify: '%v'.", err)
+	}
+}
+
+func TestParsePrivateKey(test *testing.T) {
+	pkcs1 := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(testKey)}))
+
+	for i, text := range []string{encodeTestKey(testKey), pkcs1} {
+		key, err := parsePrivateKey(text)
+		if err != nil {
+			test.Errorf("Case %d: Failed to parse key: '%v'.", i, err)
+			continue
+		}
+
+		if !testKey.Equal(key) {
+			test.Errorf("Case %d: Parsed key does not match.", i)
+			continue
+		}
+	}
+
+	_, err := parsePrivateKey("ZZZ")
+	if err == nil {
+		test.Fatalf("Did not get an error on a bad key.")
+	}
+}
+
+func TestVerifyJWT(test *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		test.Fatalf("Failed to generate key: '%v'.", err)
+	}
+
+	getKey := func(keyID string) (*rsa.PublicKey, error) {
+		if keyID != "key-01" {
+			return nil, fmt.Errorf("Unknown key '%s'.", keyID)
+		}
+
+		return &testKey.PublicKey, nil
+	}
+
+	claims := map[string]any{
+		"iss": "https://lti.test.com",
+		"sub": "123",
+	}
+
+	validToken, err := SignJWT(claims, testKey, "key-01")
+	if err != nil {
+		test.Fatalf("Failed to sign JWT: '%v'.", err)
+	}
+
+	otherToken, err := SignJWT(claims, otherKey, "key-01")
+	if err != nil {
+		test.Fatalf("Failed to sign JWT: '%v'.", err)
+	}
+
+	unknownKeyToken, err := SignJWT(claims, testKey, "key-02")
+	if err != nil {
+		test.Fatalf("Failed to sign JWT: '%v'.", err)
+	}
+
+	parts := strings.Split(validToken, ".")
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"key-01"}`))
+	changedClaims := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"https://lti.test.com","sub":"456"}`))
+
+	testCases := []struct {
+		token    string
+		hasError bool
+	}{
+		{validToken, false},
+		{otherToken, true},
+		{unknownKeyToken, true},
+		{noneHeader + "." + parts[1] + ".", true},
+		{parts[0] + "." + changedClaims + "." + parts[2], true},
+		{parts[0] + "." + parts[1], true},
+		{"", true},
+	}
+
+	for i, testCase := range testCases {
+		var actual map[string]any
+		err := verifyJWT(testCase.token, getKey, &actual)
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Failed to verify JWT: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if actual["sub"] != "123" {
+			test.Errorf("Case %d: Unexpected claims: '%s'.", i, util.MustToJSONIndent(actual))
+			continue
+		}
+	}
+}
+
+func mustDecodeSegment(test *testing.T, segment string) []byte {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		test.Fatalf("Failed to decode JWT segment '%s': '%v'.", segment, err)
+	}
+
+	return data
+}
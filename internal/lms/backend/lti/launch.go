@@ -0,0 +1,201 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+const (
+	MESSAGE_TYPE_RESOURCE_LINK = "LtiResourceLinkRequest"
+	LTI_VERSION                = "1.3.0"
+
+	CLAIM_MESSAGE_TYPE  = "https://purl.imsglobal.org/spec/lti/claim/message_type"
+	CLAIM_VERSION       = "https://purl.imsglobal.org/spec/lti/claim/version"
+	CLAIM_DEPLOYMENT_ID = "https://purl.imsglobal.org/spec/lti/claim/deployment_id"
+	CLAIM_ROLES         = "https://purl.imsglobal.org/spec/lti/claim/roles"
+	CLAIM_CONTEXT       = "https://purl.imsglobal.org/spec/lti/claim/context"
+	CLAIM_RESOURCE_LINK = "https://purl.imsglobal.org/spec/lti/claim/resource_link"
+	CLAIM_ENDPOINT      = "https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"
+
+	// How far (in seconds) the platform's clock is allowed to be off from ours.
+	LAUNCH_CLOCK_SKEW_SECS = int64(60)
+)
+
+// The claims of an LTI launch (the OIDC id_token sent by the platform).
+// The LTI claim names match the CLAIM_* constants.
+type LaunchClaims struct {
+	Issuer          string   `json:"iss"`
+	Subject         string   `json:"sub"`
+	Audience        audience `json:"aud"`
+	AuthorizedParty string   `json:"azp"`
+	ExpirationTime  int64    `json:"exp"`
+	IssuedAt        int64    `json:"iat"`
+	Nonce           string   `json:"nonce"`
+
+	Email string `json:"email"`
+	Name  string `json:"name"`
+
+	MessageType  string               `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version      string               `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentID string               `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	Roles        []string             `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+	Context      *LaunchContext       `json:"https://purl.imsglobal.org/spec/lti/claim/context"`
+	ResourceLink *LaunchResourceLink  `json:"https://purl.imsglobal.org/spec/lti/claim/resource_link"`
+	Endpoint     *LaunchServiceClaims `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"`
+}
+
+type LaunchContext struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Title string `json:"title"`
+}
+
+type LaunchResourceLink struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// The AGS endpoint claim.
+type LaunchServiceClaims struct {
+	LineItems string   `json:"lineitems"`
+	LineItem  string   `json:"lineitem"`
+	Scopes    []string `json:"scope"`
+}
+
+// The "aud" claim can be either a single string or a list.
+type audience []string
+
+func (this *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	err := json.Unmarshal(data, &single)
+	if err == nil {
+		*this = []string{single}
+		return nil
+	}
+
+	var multiple []string
+	err = json.Unmarshal(data, &multiple)
+	if err != nil {
+		return fmt.Errorf("JWT audience is not a string or list of strings: '%w'.", err)
+	}
+
+	*this = multiple
+	return nil
+}
+
+func (this *LaunchClaims) GetContextID() string {
+	if this.Context == nil {
+		return ""
+	}
+
+	return this.Context.ID
+}
+
+func (this *LaunchClaims) GetLineItemsURL() string {
+	if this.Endpoint == nil {
+		return ""
+	}
+
+	return this.Endpoint.LineItems
+}
+
+// Get the line item (autograder assignment LMS ID) this launch is for.
+// Not all launches are for a specific line item.
+func (this *LaunchClaims) GetLineItemURL() string {
+	if this.Endpoint == nil {
+		return ""
+	}
+
+	return this.Endpoint.LineItem
+}
+
+func (this *LaunchClaims) HasAudience(clientID string) bool {
+	return slices.Contains(this.Audience, clientID)
+}
+
+// Read the claims of a launch without verifying it.
+// This should only be used to find the platform that the launch claims to come from,
+// the launch must then be verified with ValidateLaunch().
+func ParseUnverifiedLaunch(idToken string) (*LaunchClaims, error) {
+	var claims LaunchClaims
+	err := parseUnverifiedJWT(idToken, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse LTI launch: '%w'.", err)
+	}
+
+	return &claims, nil
+}
+
+// Verify a launch (id_token) against the platform's keyset and check its claims.
+// |nonce| is the nonce sent to the platform when the login was initiated.
+func ValidateLaunch(platform *model.LTIPlatformInfo, idToken string, nonce string) (*LaunchClaims, error) {
+	if (platform == nil) || !platform.LaunchEnabled() {
+		return nil, fmt.Errorf("LTI platform is not configured for launches.")
+	}
+
+	getKey := func(keyID string) (*rsa.PublicKey, error) {
+		return getPlatformKey(platform.JWKSURL, keyID)
+	}
+
+	var claims LaunchClaims
+	err := verifyJWT(idToken, getKey, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to verify LTI launch: '%w'.", err)
+	}
+
+	err = claims.validate(platform, nonce, timestamp.Now().ToMSecs()/1000)
+	if err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+func (this *LaunchClaims) validate(platform *model.LTIPlatformInfo, nonce string, nowSecs int64) error {
+	if this.Issuer != platform.Issuer {
+		return fmt.Errorf("LTI launch has the wrong issuer. Expected '%s', found '%s'.", platform.Issuer, this.Issuer)
+	}
+
+	if !this.HasAudience(platform.ClientID) {
+		return fmt.Errorf("LTI launch is not for this tool (client ID '%s').", platform.ClientID)
+	}
+
+	if (len(this.Audience) > 1) && (this.AuthorizedParty != platform.ClientID) {
+		return fmt.Errorf("LTI launch has multiple audiences, but is not authorized for this tool (client ID '%s').", platform.ClientID)
+	}
+
+	if (this.ExpirationTime + LAUNCH_CLOCK_SKEW_SECS) < nowSecs {
+		return fmt.Errorf("LTI launch has expired.")
+	}
+
+	if (this.IssuedAt - LAUNCH_CLOCK_SKEW_SECS) > nowSecs {
+		return fmt.Errorf("LTI launch was issued in the future.")
+	}
+
+	if (nonce == "") || (this.Nonce != nonce) {
+		return fmt.Errorf("LTI launch has the wrong nonce.")
+	}
+
+	if this.MessageType != MESSAGE_TYPE_RESOURCE_LINK {
+		return fmt.Errorf("Unsupported LTI message type '%s'.", this.MessageType)
+	}
+
+	if this.Version != LTI_VERSION {
+		return fmt.Errorf("Unsupported LTI version '%s'.", this.Version)
+	}
+
+	if (platform.DeploymentID != "") && (this.DeploymentID != platform.DeploymentID) {
+		return fmt.Errorf("LTI launch is from an unknown deployment '%s'.", this.DeploymentID)
+	}
+
+	if this.Subject == "" {
+		return fmt.Errorf("LTI launch does not have a user (sub).")
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	TEST_ISSUER        = "https://lti.test.com"
+	TEST_NONCE         = "test-nonce"
+	TEST_KEY_ID        = "platform-key-01"
+	TEST_SUBJECT       = "lti-user-1234"
+	TEST_DEPLOYMENT_ID = "1:abc"
+)
+
+func TestValidateLaunch(test *testing.T) {
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Write([]byte(util.MustToJSON(getTestJWKS(&testKey.PublicKey, TEST_KEY_ID))))
+	}))
+	defer jwksServer.Close()
+
+	platform := &model.LTIPlatformInfo{
+		ClientID:     TEST_CLIENT_ID,
+		Issuer:       TEST_ISSUER,
+		JWKSURL:      jwksServer.URL,
+		AuthURL:      TEST_ISSUER + "/api/lti/authorize_redirect",
+		DeploymentID: TEST_DEPLOYMENT_ID,
+	}
+
+	nowSecs := timestamp.Now().ToMSecs() / 1000
+
+	testCases := []struct {
+		modify   func(claims map[string]any)
+		keyID    string
+		nonce    string
+		hasError bool
+	}{
+		{nil, TEST_KEY_ID, TEST_NONCE, false},
+		{func(claims map[string]any) { claims["aud"] = []string{TEST_CLIENT_ID} }, TEST_KEY_ID, TEST_NONCE, false},
+		{func(claims map[string]any) {
+			claims["aud"] = []string{"other", TEST_CLIENT_ID}
+			claims["azp"] = TEST_CLIENT_ID
+		}, TEST_KEY_ID, TEST_NONCE, false},
+
+		// Bad signature/key.
+		{nil, "unknown-key", TEST_NONCE, true},
+
+		// Bad claims.
+		{nil, TEST_KEY_ID, "other-nonce", true},
+		{nil, TEST_KEY_ID, "", true},
+		{func(claims map[string]any) { claims["iss"] = "https://other.test.com" }, TEST_KEY_ID, TEST_NONCE, true},
+		{func(claims map[string]any) { claims["aud"] = "other" }, TEST_KEY_ID, TEST_NONCE, true},
+		{func(claims map[string]any) { claims["aud"] = []string{"other", TEST_CLIENT_ID} }, TEST_KEY_ID, TEST_NONCE, true},
+		{func(claims map[string]any) { claims["exp"] = nowSecs - 600 }, TEST_KEY_ID, TEST_NONCE, true},
+		{func(claims map[string]any) { claims["iat"] = nowSecs + 600 }, TEST_KEY_ID, TEST_NONCE, true},
+		{func(claims map[string]any) { claims[CLAIM_MESSAGE_TYPE] = "LtiDeepLinkingRequest" }, TEST_KEY_ID, TEST_NONCE, true},
+		{func(claims map[string]any) { claims[CLAIM_VERSION] = "1.1" }, TEST_KEY_ID, TEST_NONCE, true},
+		{func(claims map[string]any) { claims[CLAIM_DEPLOYMENT_ID] = "2:def" }, TEST_KEY_ID, TEST_NONCE, true},
+		{func(claims map[string]any) { delete(claims, "sub") }, TEST_KEY_ID, TEST_NONCE, true},
+	}
+
+	for i, testCase := range testCases {
+		claims := map[string]any{
+			"iss":               TEST_ISSUER,
+			"sub":               TEST_SUBJECT,
+			"aud":               TEST_CLIENT_ID,
+			"iat":               nowSecs,
+			"exp":               nowSecs + 300,
+			"nonce":             TEST_NONCE,
+			"email":             "course-student@test.edulinq.org",
+			CLAIM_MESSAGE_TYPE:  MESSAGE_TYPE_RESOURCE_LINK,
+			CLAIM_VERSION:       LTI_VERSION,
+			CLAIM_DEPLOYMENT_ID: TEST_DEPLOYMENT_ID,
+			CLAIM_ROLES:         []string{ROLE_LEARNER},
+			CLAIM_ENDPOINT: map[string]any{
+				"lineitems": TEST_LINE_ITEMS_URL,
+				"lineitem":  TEST_ASSIGNMENT_ID,
+			},
+		}
+
+		if testCase.modify != nil {
+			testCase.modify(claims)
+		}
+
+		idToken, err := SignJWT(claims, testKey, testCase.keyID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to sign launch: '%v'.", i, err)
+			continue
+		}
+
+		launch, err := ValidateLaunch(platform, idToken, testCase.nonce)
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Failed to validate launch: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if launch.Subject != TEST_SUBJECT {
+			test.Errorf("Case %d: Unexpected subject. Expected: '%s', Actual: '%s'.", i, TEST_SUBJECT, launch.Subject)
+			continue
+		}
+
+		if launch.GetLineItemURL() != TEST_ASSIGNMENT_ID {
+			test.Errorf("Case %d: Unexpected line item. Expected: '%s', Actual: '%s'.", i, TEST_ASSIGNMENT_ID, launch.GetLineItemURL())
+			continue
+		}
+
+		if GetRole(launch.Roles) != model.CourseRoleStudent {
+			test.Errorf("Case %d: Unexpected role: '%s'.", i, GetRole(launch.Roles).String())
+			continue
+		}
+	}
+}
+
+// Get a JWKS (as would be served by a platform) for a key.
+func getTestJWKS(key *rsa.PublicKey, keyID string) map[string]any {
+	return map[string]any{
+		"keys": []map[string]any{
+			map[string]any{
+				"kty": "RSA",
+				"kid": keyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			},
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package lti
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"embed"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+const (
+	TEST_CLIENT_ID      = "10000000000001"
+	TEST_LINE_ITEMS_URL = "https://lti.test.com/api/lti/courses/12345/line_items"
+	TEST_ASSIGNMENT_ID  = TEST_LINE_ITEMS_URL + "/98765"
+)
+
+var server *httptest.Server
+var serverURL string
+
+//go:embed testdata/http
+var httpDataDir embed.FS
+
+var testKey *rsa.PrivateKey
+var testBackend *LTIBackend
+
+func TestMain(suite *testing.M) {
+	var err error
+
+	// Run inside a func so defers will run before os.Exit().
+	code := func() int {
+		db.PrepForTestingMain()
+		defer db.CleanupTestingMain()
+
+		err = startTestServer()
+		if err != nil {
+			panic(err)
+		}
+		defer stopTestServer()
+
+		testKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+
+		platform := &model.LTIPlatformInfo{
+			ClientID:       TEST_CLIENT_ID,
+			TokenURL:       serverURL + "/login/oauth2/token",
+			PrivateKey:     encodeTestKey(testKey),
+			LineItemsURL:   serverURL + "/api/lti/courses/12345/line_items",
+			MembershipsURL: serverURL + "/api/lti/courses/12345/names_and_roles",
+		}
+
+		testBackend, err = NewBackend(platform)
+		if err != nil {
+			panic(err)
+		}
+
+		testBackend.rewriteLinks = true
+
+		return suite.Run()
+	}()
+
+	os.Exit(code)
+}
+
+func encodeTestKey(key *rsa.PrivateKey) string {
+	bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: bytes}))
+}
+
+func startTestServer() error {
+	if server != nil {
+		return fmt.Errorf("Test server already started.")
+	}
+
+	requestsFS, err := fs.Sub(httpDataDir, "testdata/http")
+	if err != nil {
+		return fmt.Errorf("Failed to open embedded test requests: '%w'.", err)
+	}
+
+	server, err = common.StartSavedHTTPServer(requestsFS)
+	if err != nil {
+		return err
+	}
+
+	serverURL = server.URL
+
+	return nil
+}
+
+func stopTestServer() {
+	if server != nil {
+		server.Close()
+
+		server = nil
+		serverURL = ""
+	}
+}
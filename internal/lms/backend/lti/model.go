@@ -0,0 +1,149 @@
+package lti
+
+import (
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+const (
+	ROLE_PREFIX_MEMBERSHIP = "http://purl.imsglobal.org/vocab/lis/v2/membership#"
+
+	ROLE_ADMINISTRATOR      = ROLE_PREFIX_MEMBERSHIP + "Administrator"
+	ROLE_CONTENT_DEVELOPER  = ROLE_PREFIX_MEMBERSHIP + "ContentDeveloper"
+	ROLE_INSTRUCTOR         = ROLE_PREFIX_MEMBERSHIP + "Instructor"
+	ROLE_LEARNER            = ROLE_PREFIX_MEMBERSHIP + "Learner"
+	ROLE_MENTOR             = ROLE_PREFIX_MEMBERSHIP + "Mentor"
+	ROLE_TEACHING_ASSISTANT = "http://purl.imsglobal.org/vocab/lis/v2/membership/Instructor#TeachingAssistant"
+
+	MEMBER_STATUS_ACTIVE = "Active"
+)
+
+type LineItem struct {
+	ID           string     `json:"id"`
+	Label        string     `json:"label"`
+	ScoreMaximum float64    `json:"scoreMaximum"`
+	EndDateTime  *time.Time `json:"endDateTime,omitempty"`
+}
+
+type Result struct {
+	ID            string   `json:"id"`
+	UserID        string   `json:"userId"`
+	ResultScore   *float64 `json:"resultScore"`
+	ResultMaximum float64  `json:"resultMaximum"`
+	Comment       string   `json:"comment"`
+}
+
+type Score struct {
+	UserID           string  `json:"userId"`
+	ScoreGiven       float64 `json:"scoreGiven"`
+	ScoreMaximum     float64 `json:"scoreMaximum"`
+	Comment          string  `json:"comment,omitempty"`
+	Timestamp        string  `json:"timestamp"`
+	ActivityProgress string  `json:"activityProgress"`
+	GradingProgress  string  `json:"gradingProgress"`
+}
+
+type Member struct {
+	UserID string   `json:"user_id"`
+	Name   string   `json:"name"`
+	Email  string   `json:"email"`
+	Status string   `json:"status"`
+	Roles  []string `json:"roles"`
+}
+
+type membershipContainer struct {
+	Members []*Member `json:"members"`
+}
+
+// LTI context role to autograder role.
+var roleMapping map[string]model.CourseUserRole = map[string]model.CourseUserRole{
+	ROLE_ADMINISTRATOR:      model.CourseRoleAdmin,
+	ROLE_CONTENT_DEVELOPER:  model.CourseRoleOther,
+	ROLE_INSTRUCTOR:         model.CourseRoleOwner,
+	ROLE_LEARNER:            model.CourseRoleStudent,
+	ROLE_MENTOR:             model.CourseRoleOther,
+	ROLE_TEACHING_ASSISTANT: model.CourseRoleGrader,
+}
+
+// Get the autograder role for a set of LTI roles (from NRPS or a launch).
+// Simple role names (e.g., "Learner") are treated as context (membership) roles,
+// and unknown roles (e.g., institution roles) are ignored.
+// Teaching assistants are usually also given the instructor role,
+// so they are not treated as instructors.
+func GetRole(roles []string) model.CourseUserRole {
+	fullRoles := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if !strings.Contains(role, "://") {
+			role = ROLE_PREFIX_MEMBERSHIP + role
+		}
+
+		fullRoles[role] = true
+	}
+
+	if fullRoles[ROLE_TEACHING_ASSISTANT] {
+		delete(fullRoles, ROLE_INSTRUCTOR)
+	}
+
+	var maxRole model.CourseUserRole = model.CourseRoleOther
+	for role := range fullRoles {
+		maxRole = max(maxRole, roleMapping[role])
+	}
+
+	return maxRole
+}
+
+func (this *Member) IsActive() bool {
+	return (this.Status == "") || (this.Status == MEMBER_STATUS_ACTIVE)
+}
+
+func (this *Member) ToLMSType() *lmstypes.User {
+	return &lmstypes.User{
+		ID:    this.UserID,
+		Name:  this.Name,
+		Email: this.Email,
+		Role:  GetRole(this.Roles),
+	}
+}
+
+// Line items do not have a course, so the LMS course ID is left empty.
+func (this *LineItem) ToLMSType() *lmstypes.Assignment {
+	return &lmstypes.Assignment{
+		ID:        this.ID,
+		Name:      this.Label,
+		DueDate:   timestamp.FromGoTimePointer(this.EndDateTime),
+		MaxPoints: this.ScoreMaximum,
+	}
+}
+
+// Results are scaled to the line item's max score (which is what scores are uploaded with).
+// A result's comment is identified by the result, and authored by the result's user.
+func (this *Result) ToLMSType(lineItem *LineItem) *lmstypes.SubmissionScore {
+	score := 0.0
+	if this.ResultScore != nil {
+		score = *this.ResultScore
+	}
+
+	if (this.ResultMaximum > 0) && (lineItem.ScoreMaximum > 0) {
+		score = score / this.ResultMaximum * lineItem.ScoreMaximum
+	}
+
+	comments := make([]*lmstypes.SubmissionComment, 0, 1)
+	if this.Comment != "" {
+		comments = append(comments, &lmstypes.SubmissionComment{
+			ID:     this.ID,
+			Author: this.UserID,
+			Text:   this.Comment,
+		})
+	}
+
+	return &lmstypes.SubmissionScore{
+		UserID:   this.UserID,
+		Score:    score,
+		Comments: comments,
+	}
+}
@@ -0,0 +1,136 @@
+package lti
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	ACTIVITY_PROGRESS_COMPLETED = "Completed"
+	GRADING_PROGRESS_GRADED     = "FullyGraded"
+)
+
+func (this *LTIBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	scores, err := this.fetchAssignmentScores(assignmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scores) != 1 {
+		return nil, fmt.Errorf("Did not find exactly one LTI result for user '%s' on line item '%s', found %d.", userID, assignmentID, len(scores))
+	}
+
+	return scores[0], nil
+}
+
+func (this *LTIBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	return this.fetchAssignmentScores(assignmentID, "")
+}
+
+// If the user ID is empty, then all users will be fetched.
+// The caller must hold the API lock.
+func (this *LTIBackend) fetchAssignmentScores(assignmentID string, userID string) ([]*lmstypes.SubmissionScore, error) {
+	lineItem, err := this.fetchLineItem(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(map[string]string)
+	if userID != "" {
+		query["user_id"] = userID
+	}
+
+	url, err := addURLPath(assignmentID, "/results", query)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]*lmstypes.SubmissionScore, 0)
+
+	err = this.getPages(url, MEDIA_TYPE_RESULT_CONTAINER, func(body string) error {
+		var pageResults []*Result
+		err := util.JSONFromString(body, &pageResults)
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal results page: '%w'.", err)
+		}
+
+		for _, result := range pageResults {
+			if result == nil {
+				continue
+			}
+
+			scores = append(scores, result.ToLMSType(lineItem))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch results: '%w'.", err)
+	}
+
+	return scores, nil
+}
+
+// AGS does not have bulk score uploads, so each score is posted individually.
+func (this *LTIBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	lineItem, err := this.fetchLineItem(assignmentID)
+	if err != nil {
+		return err
+	}
+
+	for i, score := range scores {
+		if len(score.Comments) > 1 {
+			return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
+		}
+
+		comment := ""
+		for _, scoreComment := range score.Comments {
+			comment = scoreComment.Text
+		}
+
+		err = this.postScore(assignmentID, lineItem, score.UserID, score.Score, comment)
+		if err != nil {
+			return fmt.Errorf("Failed on score %d: '%w'.", i, err)
+		}
+	}
+
+	return nil
+}
+
+// The caller must hold the API lock.
+func (this *LTIBackend) postScore(assignmentID string, lineItem *LineItem, userID string, score float64, comment string) error {
+	url, err := addURLPath(assignmentID, "/scores", nil)
+	if err != nil {
+		return err
+	}
+
+	body := Score{
+		UserID:           userID,
+		ScoreGiven:       score,
+		ScoreMaximum:     lineItem.ScoreMaximum,
+		Comment:          comment,
+		Timestamp:        time.Now().UTC().Format(time.RFC3339Nano),
+		ActivityProgress: ACTIVITY_PROGRESS_COMPLETED,
+		GradingProgress:  GRADING_PROGRESS_GRADED,
+	}
+
+	err = this.postJSON(url, MEDIA_TYPE_SCORE, util.MustToJSON(body))
+	if err != nil {
+		return fmt.Errorf("Failed to upload score for user '%s': '%w'.", userID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package lti
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The result (0.9 out of 1) is scaled to the line item's max points.
+var testScore lmstypes.SubmissionScore = lmstypes.SubmissionScore{
+	UserID: "40",
+	Score:  90.0,
+	Time:   nil,
+	Comments: []*lmstypes.SubmissionComment{
+		&lmstypes.SubmissionComment{
+			ID:     TEST_ASSIGNMENT_ID + "/results/40",
+			Author: "40",
+			Text:   "{\n\"id\": \"course101::hw0::course-student@test.edulinq.org::1696364768\",\n\"submission-time\":1234,\n\"upload-time\":1235,\n\"raw-score\": 100,\n\"score\": 100,\n\"lock\": false,\n\"late-date-usage\": 0,\n\"num-days-late\": 0,\n\"reject\": false,\n\"__autograder__v01__\": 0\n}",
+			Time:   "",
+		},
+	},
+}
+
+func TestFetchAssignmentScoreBase(test *testing.T) {
+	score, err := testBackend.FetchAssignmentScore(TEST_ASSIGNMENT_ID, "40")
+	if err != nil {
+		test.Fatalf("Failed to fetch assignment score: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(&testScore, score) {
+		test.Fatalf("Score not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(testScore), util.MustToJSONIndent(score))
+	}
+}
+
+func TestFetchAssignmentScoresBase(test *testing.T) {
+	scores, err := testBackend.FetchAssignmentScores(TEST_ASSIGNMENT_ID)
+	if err != nil {
+		test.Fatalf("Failed to fetch assignment scores: '%v'.", err)
+	}
+
+	expected := []*lmstypes.SubmissionScore{
+		&testScore,
+		&lmstypes.SubmissionScore{
+			UserID:   "50",
+			Score:    0.0,
+			Comments: []*lmstypes.SubmissionComment{},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, scores) {
+		test.Fatalf("Scores not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(scores))
+	}
+}
+
+func TestUpdateAssignmentScoresBase(test *testing.T) {
+	scores := []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID:   "40",
+			Score:    90.0,
+			Comments: []*lmstypes.SubmissionComment{&lmstypes.SubmissionComment{Text: "Good job."}},
+		},
+		&lmstypes.SubmissionScore{
+			UserID: "50",
+			Score:  10.0,
+		},
+	}
+
+	err := testBackend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, scores)
+	if err != nil {
+		test.Fatalf("Failed to update scores: '%v'.", err)
+	}
+}
+
+func TestUpdateCommentsBase(test *testing.T) {
+	comments := []*lmstypes.SubmissionComment{
+		&lmstypes.SubmissionComment{
+			ID:     TEST_ASSIGNMENT_ID + "/results/40",
+			Author: "40",
+			Text:   "Updated.",
+		},
+	}
+
+	err := testBackend.UpdateComments(TEST_ASSIGNMENT_ID, comments)
+	if err != nil {
+		test.Fatalf("Failed to update comments: '%v'.", err)
+	}
+}
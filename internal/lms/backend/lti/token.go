@@ -0,0 +1,109 @@
+package lti
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	SCOPE_LINE_ITEM_READ = "https://purl.imsglobal.org/spec/lti-ags/scope/lineitem.readonly"
+	SCOPE_RESULT_READ    = "https://purl.imsglobal.org/spec/lti-ags/scope/result.readonly"
+	SCOPE_SCORE          = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+	SCOPE_MEMBERSHIPS    = "https://purl.imsglobal.org/spec/lti-nrps/scope/contextmembership.readonly"
+
+	CLIENT_ASSERTION_TYPE = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// How long client assertions are valid for.
+	CLIENT_ASSERTION_LIFETIME_SECS = int64(5 * 60)
+
+	// Get a new token when the current one is this close to expiring.
+	TOKEN_EXPIRY_BUFFER_MSECS = int64(60 * 1000)
+)
+
+var ALL_SCOPES []string = []string{SCOPE_LINE_ITEM_READ, SCOPE_RESULT_READ, SCOPE_SCORE, SCOPE_MEMBERSHIPS}
+
+type accessToken struct {
+	Token  string
+	Expiry timestamp.Timestamp
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Access tokens are shared by all backends for the same client.
+var tokenCache map[string]*accessToken = make(map[string]*accessToken)
+var tokenCacheLock sync.Mutex
+
+// Get an access token for LTI services (using the OAuth2 client credentials grant with a signed JWT).
+// Tokens are cached until they are close to expiring.
+func (this *LTIBackend) getAccessToken() (string, error) {
+	tokenCacheLock.Lock()
+	defer tokenCacheLock.Unlock()
+
+	cacheKey := fmt.Sprintf("%s::%s", this.Platform.TokenURL, this.Platform.ClientID)
+
+	now := timestamp.Now()
+
+	token := tokenCache[cacheKey]
+	if (token != nil) && ((token.Expiry.ToMSecs() - TOKEN_EXPIRY_BUFFER_MSECS) > now.ToMSecs()) {
+		return token.Token, nil
+	}
+
+	nowSecs := now.ToMSecs() / 1000
+	claims := map[string]any{
+		"iss": this.Platform.ClientID,
+		"sub": this.Platform.ClientID,
+		"aud": this.Platform.TokenAudience,
+		"iat": nowSecs,
+		"exp": nowSecs + CLIENT_ASSERTION_LIFETIME_SECS,
+		"jti": util.UUID(),
+	}
+
+	assertion, err := SignJWT(claims, this.privateKey, this.Platform.KeyID)
+	if err != nil {
+		return "", err
+	}
+
+	form := map[string]string{
+		"grant_type":            "client_credentials",
+		"client_assertion_type": CLIENT_ASSERTION_TYPE,
+		"client_assertion":      assertion,
+		"scope":                 strings.Join(ALL_SCOPES, " "),
+	}
+
+	headers := map[string][]string{
+		"Accept": []string{"application/json"},
+	}
+
+	body, _, err := common.PostWithHeaders(this.Platform.TokenURL, form, headers)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get LTI access token: '%w'.", err)
+	}
+
+	var response tokenResponse
+	err = util.JSONFromString(body, &response)
+	if err != nil {
+		return "", fmt.Errorf("Failed to unmarshal LTI access token: '%w'.", err)
+	}
+
+	if response.AccessToken == "" {
+		return "", fmt.Errorf("LTI platform did not return an access token.")
+	}
+
+	expiresIn := time.Duration(response.ExpiresIn) * time.Second
+	tokenCache[cacheKey] = &accessToken{
+		Token:  response.AccessToken,
+		Expiry: timestamp.FromMSecs(now.ToMSecs() + expiresIn.Milliseconds()),
+	}
+
+	return response.AccessToken, nil
+}
@@ -0,0 +1,87 @@
+package lti
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Only active members are returned.
+func (this *LTIBackend) FetchUsers() ([]*lmstypes.User, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	members, err := this.fetchMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*lmstypes.User, 0, len(members))
+	for _, member := range members {
+		users = append(users, member.ToLMSType())
+	}
+
+	return users, nil
+}
+
+// NRPS does not support searching, so all members are fetched.
+func (this *LTIBackend) FetchUser(email string) (*lmstypes.User, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	members, err := this.fetchMembers()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err)
+	}
+
+	matches := make([]*Member, 0, 1)
+	for _, member := range members {
+		if strings.EqualFold(member.Email, email) {
+			matches = append(matches, member)
+		}
+	}
+
+	if len(matches) != 1 {
+		log.Warn("Did not find exactly one matching user in LTI platform.",
+			log.NewAttr("email", email), log.NewAttr("num-results", len(matches)))
+		return nil, nil
+	}
+
+	return matches[0].ToLMSType(), nil
+}
+
+// The caller must hold the API lock.
+func (this *LTIBackend) fetchMembers() ([]*Member, error) {
+	if this.Platform.MembershipsURL == "" {
+		return nil, fmt.Errorf("Cannot fetch LTI users without a memberships URL (memberships-url).")
+	}
+
+	members := make([]*Member, 0)
+
+	err := this.getPages(this.Platform.MembershipsURL, MEDIA_TYPE_MEMBERSHIPS, func(body string) error {
+		var container membershipContainer
+		err := util.JSONFromString(body, &container)
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal memberships page: '%w'.", err)
+		}
+
+		for _, member := range container.Members {
+			if (member == nil) || !member.IsActive() {
+				continue
+			}
+
+			members = append(members, member)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch memberships: '%w'.", err)
+	}
+
+	return members, nil
+}
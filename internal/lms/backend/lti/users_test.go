@@ -0,0 +1,81 @@
+package lti
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Inactive members are not included.
+var expectedUsers []*lmstypes.User = []*lmstypes.User{
+	&lmstypes.User{ID: "10", Name: "course-owner", Email: "course-owner@test.edulinq.org", Role: model.CourseRoleOwner},
+	&lmstypes.User{ID: "20", Name: "course-admin", Email: "course-admin@test.edulinq.org", Role: model.CourseRoleAdmin},
+	&lmstypes.User{ID: "30", Name: "course-grader", Email: "course-grader@test.edulinq.org", Role: model.CourseRoleGrader},
+	&lmstypes.User{ID: "40", Name: "course-student", Email: "course-student@test.edulinq.org", Role: model.CourseRoleStudent},
+	&lmstypes.User{ID: "50", Name: "course-other", Email: "course-other@test.edulinq.org", Role: model.CourseRoleOther},
+}
+
+func TestLTIUsersGetBase(test *testing.T) {
+	users, err := testBackend.FetchUsers()
+	if err != nil {
+		test.Fatalf("Failed to fetch users: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expectedUsers, users) {
+		test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expectedUsers), util.MustToJSONIndent(users))
+	}
+}
+
+func TestLTIUserGetBase(test *testing.T) {
+	testCases := []struct {
+		email    string
+		expected *lmstypes.User
+	}{
+		{"course-grader@test.edulinq.org", expectedUsers[2]},
+		{"course-student@test.edulinq.org", expectedUsers[3]},
+		{"course-dropped@test.edulinq.org", nil},
+		{"ZZZ@test.edulinq.org", nil},
+	}
+
+	for i, testCase := range testCases {
+		user, err := testBackend.FetchUser(testCase.email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to fetch user: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, user) {
+			test.Errorf("Case %d: User not as expected. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(user))
+			continue
+		}
+	}
+}
+
+func TestGetRole(test *testing.T) {
+	testCases := []struct {
+		roles    []string
+		expected model.CourseUserRole
+	}{
+		{nil, model.CourseRoleOther},
+		{[]string{"ZZZ"}, model.CourseRoleOther},
+		{[]string{"http://purl.imsglobal.org/vocab/lis/v2/institution/person#Administrator"}, model.CourseRoleOther},
+		{[]string{"Learner"}, model.CourseRoleStudent},
+		{[]string{ROLE_LEARNER}, model.CourseRoleStudent},
+		{[]string{ROLE_INSTRUCTOR, ROLE_TEACHING_ASSISTANT}, model.CourseRoleGrader},
+		{[]string{ROLE_INSTRUCTOR}, model.CourseRoleOwner},
+		{[]string{ROLE_ADMINISTRATOR}, model.CourseRoleAdmin},
+		{[]string{ROLE_INSTRUCTOR, ROLE_ADMINISTRATOR}, model.CourseRoleOwner},
+	}
+
+	for i, testCase := range testCases {
+		actual := GetRole(testCase.roles)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected role. Expected: '%s', Actual: '%s'.", i, testCase.expected.String(), actual.String())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package moodle
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+)
+
+// Moodle does not have a function to fetch a single assignment,
+// so all the course's assignments are fetched.
+func (this *MoodleBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+	assignments, err := this.FetchAssignments()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assignment := range assignments {
+		if assignment.ID == assignmentID {
+			return assignment, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Could not find Moodle assignment '%s' in course '%s'.", assignmentID, this.CourseID)
+}
+
+func (this *MoodleBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	params := map[string]string{
+		"courseids[0]": this.CourseID,
+	}
+
+	var response assignmentsResponse
+	err := this.callFunction("mod_assign_get_assignments", params, &response)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err)
+	}
+
+	assignments := make([]*lmstypes.Assignment, 0)
+
+	for _, course := range response.Courses {
+		if course == nil {
+			continue
+		}
+
+		for _, assignment := range course.Assignments {
+			if assignment == nil {
+				continue
+			}
+
+			assignments = append(assignments, assignment.ToLMSType())
+		}
+	}
+
+	return assignments, nil
+}
@@ -0,0 +1,62 @@
+package moodle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var dueDate timestamp.Timestamp = timestamp.FromMSecs(1696364768000)
+var expectedAssignment lmstypes.Assignment = lmstypes.Assignment{
+	ID:          TEST_ASSIGNMENT_ID,
+	Name:        "Homework 0",
+	LMSCourseID: TEST_COURSE_ID,
+	DueDate:     &dueDate,
+	MaxPoints:   100.0,
+}
+
+func TestFetchAssignmentBase(test *testing.T) {
+	assignment, err := testBackend.FetchAssignment(TEST_ASSIGNMENT_ID)
+	if err != nil {
+		test.Fatalf("Failed to fetch assignment: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(&expectedAssignment, assignment) {
+		test.Fatalf("Assignment not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expectedAssignment), util.MustToJSONIndent(assignment))
+	}
+}
+
+func TestFetchAssignmentMissing(test *testing.T) {
+	assignment, err := testBackend.FetchAssignment("ZZZ")
+	if err == nil {
+		test.Fatalf("Did not get an error on a missing assignment: '%s'.", util.MustToJSONIndent(assignment))
+	}
+}
+
+func TestFetchAssignmentsBase(test *testing.T) {
+	assignments, err := testBackend.FetchAssignments()
+	if err != nil {
+		test.Fatalf("Failed to fetch assignments: '%v'.", err)
+	}
+
+	// The second assignment has no due date and is graded with a scale.
+	expected := []*lmstypes.Assignment{
+		&expectedAssignment,
+		&lmstypes.Assignment{
+			ID:          "98766",
+			Name:        "Homework 1",
+			LMSCourseID: TEST_COURSE_ID,
+			DueDate:     nil,
+			MaxPoints:   0.0,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, assignments) {
+		test.Fatalf("Assignments not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(assignments))
+	}
+}
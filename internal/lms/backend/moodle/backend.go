@@ -0,0 +1,38 @@
+package moodle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A backend for Moodle's web services (REST) API.
+// The API token must belong to a web service that has access to all the functions this backend uses.
+type MoodleBackend struct {
+	CourseID string
+	APIToken string
+	BaseURL  string
+}
+
+func NewBackend(moodleCourseID string, apiToken string, baseURL string) (*MoodleBackend, error) {
+	if moodleCourseID == "" {
+		return nil, fmt.Errorf("Moodle course ID (course-id) cannot be empty.")
+	}
+
+	if apiToken == "" {
+		return nil, fmt.Errorf("Moodle API token (api-token) cannot be empty.")
+	}
+
+	if baseURL == "" {
+		return nil, fmt.Errorf("Moodle base URL (base-url) cannot be empty.")
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	backend := MoodleBackend{
+		CourseID: moodleCourseID,
+		APIToken: apiToken,
+		BaseURL:  baseURL,
+	}
+
+	return &backend, nil
+}
@@ -0,0 +1,59 @@
+package moodle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *MoodleBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+	for i, comment := range comments {
+		if i != 0 {
+			time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC))
+		}
+
+		err := this.UpdateComment(assignmentID, comment)
+		if err != nil {
+			return fmt.Errorf("Failed on comment %d: '%w'.", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Moodle only has a single feedback comment per grade (identified by the comment's author, see GradeItem.ToLMSType()).
+// Feedback can only be saved along with a grade, so the user's current grade is saved again with the new feedback.
+func (this *MoodleBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	scores, err := this.fetchAssignmentScores(assignmentID, comment.Author)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch current score for comment update: '%w'.", err)
+	}
+
+	if len(scores) != 1 {
+		return fmt.Errorf("Could not find a Moodle score for user '%s' on assignment '%s'.", comment.Author, assignmentID)
+	}
+
+	form := map[string]string{
+		"assignmentid":  assignmentID,
+		"userid":        comment.Author,
+		"grade":         util.FloatToStr(scores[0].Score),
+		"attemptnumber": "-1",
+		"addattempt":    "0",
+		"workflowstate": "",
+		"applytoall":    "0",
+	}
+
+	addFeedbackComment(form, "plugindata", comment.Text)
+
+	err = this.callFunction("mod_assign_save_grade", form, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to update comment: '%w'.", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package moodle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	REST_PATH             string = "/webservice/rest/server.php"
+	POST_PAGE_SIZE        int    = 75
+	UPLOAD_SLEEP_TIME_SEC        = int64(0.5 * float64(time.Second))
+
+	// Moodle's text formats (see FORMAT_* in Moodle's weblib.php).
+	FORMAT_PLAIN string = "2"
+
+	FEEDBACK_COMMENTS_PLUGIN string = "assignfeedbackcomments_editor"
+)
+
+// Moodle reports errors as a normal (200) response with a JSON exception object.
+type moodleException struct {
+	Exception string `json:"exception"`
+	ErrorCode string `json:"errorcode"`
+	Message   string `json:"message"`
+}
+
+func (this *MoodleBackend) getAPILock() {
+	common.Lock(this.getLockKey())
+}
+
+func (this *MoodleBackend) releaseAPILock() {
+	common.Unlock(this.getLockKey())
+}
+
+// Lock based on the API token (like Canvas, multiple courses can share a token).
+func (this *MoodleBackend) getLockKey() string {
+	return fmt.Sprintf("moodle::%s", this.APIToken)
+}
+
+// Call a Moodle web service function and unmarshal the response into output (if not nil).
+// All calls are POSTs with the token and params sent as a form,
+// so the token never appears in a URL (which may be logged).
+// The caller must hold the API lock.
+func (this *MoodleBackend) callFunction(function string, params map[string]string, output any) error {
+	form := make(map[string]string, len(params)+3)
+	for key, value := range params {
+		form[key] = value
+	}
+
+	form["wstoken"] = this.APIToken
+	form["wsfunction"] = function
+	form["moodlewsrestformat"] = "json"
+
+	url := this.BaseURL + REST_PATH
+	headers := map[string][]string{
+		"Accept": []string{"application/json"},
+	}
+
+	body, _, err := common.PostWithHeaders(url, form, headers)
+	if err != nil {
+		return fmt.Errorf("Failed to call Moodle function '%s': '%w'.", function, err)
+	}
+
+	body = strings.TrimSpace(body)
+
+	if strings.HasPrefix(body, "{") {
+		var exception moodleException
+		err = util.JSONFromString(body, &exception)
+		if (err == nil) && (exception.Exception != "") {
+			return fmt.Errorf("Moodle function '%s' returned an error (%s): '%s'.", function, exception.ErrorCode, exception.Message)
+		}
+	}
+
+	if output == nil {
+		return nil
+	}
+
+	err = util.JSONFromString(body, output)
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal response from Moodle function '%s': '%w'.", function, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package moodle
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+)
+
+// Fetch all the groups (and their members) in a group set (a "grouping" in Moodle).
+func (this *MoodleBackend) FetchGroups(groupSetID string) ([]*lmstypes.Group, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	params := map[string]string{
+		"groupingids[0]": groupSetID,
+		"returngroups":   "1",
+	}
+
+	var groupings []*Grouping
+	err := this.callFunction("core_group_get_groupings", params, &groupings)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch grouping '%s': '%w'.", groupSetID, err)
+	}
+
+	if (len(groupings) != 1) || (groupings[0] == nil) {
+		return nil, fmt.Errorf("Could not find Moodle grouping '%s'.", groupSetID)
+	}
+
+	rawGroups := groupings[0].Groups
+	if len(rawGroups) == 0 {
+		return make([]*lmstypes.Group, 0), nil
+	}
+
+	users, err := this.fetchUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	usersByID := make(map[int64]*User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	memberIDs, err := this.fetchGroupMembers(rawGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]*lmstypes.Group, 0, len(rawGroups))
+	for _, group := range rawGroups {
+		if group == nil {
+			continue
+		}
+
+		members := make([]*lmstypes.User, 0, len(memberIDs[group.ID]))
+		for _, userID := range memberIDs[group.ID] {
+			user := usersByID[userID]
+			if user == nil {
+				// The user is no longer enrolled in the course.
+				continue
+			}
+
+			members = append(members, user.ToLMSType())
+		}
+
+		groups = append(groups, &lmstypes.Group{
+			ID:      formatID(group.ID),
+			Name:    group.Name,
+			Members: members,
+		})
+	}
+
+	return groups, nil
+}
+
+// Get the user IDs for each group (keyed by group ID).
+// The caller must hold the API lock.
+func (this *MoodleBackend) fetchGroupMembers(groups []*Group) (map[int64][]int64, error) {
+	params := make(map[string]string, len(groups))
+	for _, group := range groups {
+		if group != nil {
+			params[fmt.Sprintf("groupids[%d]", len(params))] = formatID(group.ID)
+		}
+	}
+
+	var rawMembers []*groupMembers
+	err := this.callFunction("core_group_get_group_members", params, &rawMembers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch group members: '%w'.", err)
+	}
+
+	members := make(map[int64][]int64, len(rawMembers))
+	for _, groupMembers := range rawMembers {
+		if groupMembers != nil {
+			members[groupMembers.GroupID] = groupMembers.UserIDs
+		}
+	}
+
+	return members, nil
+}
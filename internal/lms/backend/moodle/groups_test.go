@@ -0,0 +1,42 @@
+package moodle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestMoodleGroupsGetBase(test *testing.T) {
+	// User 999 is in a group, but is no longer enrolled.
+	expected := []*lmstypes.Group{
+		&lmstypes.Group{
+			ID:      "201",
+			Name:    "Team A",
+			Members: []*lmstypes.User{expectedUsers[3], expectedUsers[4]},
+		},
+		&lmstypes.Group{
+			ID:      "202",
+			Name:    "Team B",
+			Members: []*lmstypes.User{expectedUsers[2]},
+		},
+	}
+
+	groups, err := testBackend.FetchGroups("111")
+	if err != nil {
+		test.Fatalf("Failed to fetch groups: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, groups) {
+		test.Fatalf("Groups not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(groups))
+	}
+}
+
+func TestMoodleGroupsGetMissing(test *testing.T) {
+	groups, err := testBackend.FetchGroups("999")
+	if err == nil {
+		test.Fatalf("Did not get an error on a missing grouping: '%s'.", util.MustToJSONIndent(groups))
+	}
+}
@@ -0,0 +1,81 @@
+package moodle
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+)
+
+const (
+	TEST_COURSE_ID     = "12345"
+	TEST_ASSIGNMENT_ID = "98765"
+	TEST_TOKEN         = "ABC123"
+)
+
+var server *httptest.Server
+var serverURL string
+
+//go:embed testdata/http
+var httpDataDir embed.FS
+
+var testBackend *MoodleBackend
+
+func TestMain(suite *testing.M) {
+	var err error
+
+	// Run inside a func so defers will run before os.Exit().
+	code := func() int {
+		db.PrepForTestingMain()
+		defer db.CleanupTestingMain()
+
+		err = startTestServer()
+		if err != nil {
+			panic(err)
+		}
+		defer stopTestServer()
+
+		testBackend, err = NewBackend(TEST_COURSE_ID, TEST_TOKEN, serverURL)
+		if err != nil {
+			panic(err)
+		}
+
+		return suite.Run()
+	}()
+
+	os.Exit(code)
+}
+
+func startTestServer() error {
+	if server != nil {
+		return fmt.Errorf("Test server already started.")
+	}
+
+	requestsFS, err := fs.Sub(httpDataDir, "testdata/http")
+	if err != nil {
+		return fmt.Errorf("Failed to open embedded test requests: '%w'.", err)
+	}
+
+	server, err = common.StartSavedHTTPServer(requestsFS)
+	if err != nil {
+		return err
+	}
+
+	serverURL = server.URL
+
+	return nil
+}
+
+func stopTestServer() {
+	if server != nil {
+		server.Close()
+
+		server = nil
+		serverURL = ""
+	}
+}
@@ -0,0 +1,155 @@
+package moodle
+
+import (
+	"strconv"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type User struct {
+	ID       int64  `json:"id"`
+	FullName string `json:"fullname"`
+	Email    string `json:"email"`
+	Roles    []Role `json:"roles"`
+}
+
+type Role struct {
+	ID        int64  `json:"roleid"`
+	Name      string `json:"name"`
+	ShortName string `json:"shortname"`
+}
+
+type Assignment struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	CourseID int64  `json:"course"`
+	// Unix time in seconds (zero means there is no due date).
+	DueDate int64 `json:"duedate"`
+	// Negative values mean that the assignment is graded with a scale (not points).
+	MaxPoints float64 `json:"grade"`
+}
+
+type assignmentsResponse struct {
+	Courses []*struct {
+		ID          int64         `json:"id"`
+		Assignments []*Assignment `json:"assignments"`
+	} `json:"courses"`
+}
+
+type UserGrades struct {
+	UserID     int64        `json:"userid"`
+	GradeItems []*GradeItem `json:"gradeitems"`
+}
+
+type GradeItem struct {
+	ID           int64  `json:"id"`
+	ItemModule   string `json:"itemmodule"`
+	ItemInstance int64  `json:"iteminstance"`
+
+	GradeRaw *float64 `json:"graderaw"`
+	// Unix time in seconds.
+	GradeDateSubmitted *int64 `json:"gradedatesubmitted"`
+	Feedback           string `json:"feedback"`
+}
+
+type gradeItemsResponse struct {
+	UserGrades []*UserGrades `json:"usergrades"`
+}
+
+type Grouping struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Groups []*Group `json:"groups"`
+}
+
+type Group struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type groupMembers struct {
+	GroupID int64   `json:"groupid"`
+	UserIDs []int64 `json:"userids"`
+}
+
+// Moodle role (short name) to autograder role.
+// Custom roles are treated as CourseRoleOther.
+var roleMapping map[string]model.CourseUserRole = map[string]model.CourseUserRole{
+	"guest":          model.CourseRoleOther,
+	"user":           model.CourseRoleOther,
+	"student":        model.CourseRoleStudent,
+	"teacher":        model.CourseRoleGrader,
+	"editingteacher": model.CourseRoleOwner,
+	"manager":        model.CourseRoleAdmin,
+}
+
+func (this *User) GetRole() model.CourseUserRole {
+	var maxRole model.CourseUserRole = model.CourseRoleOther
+	for _, role := range this.Roles {
+		maxRole = max(maxRole, roleMapping[role.ShortName])
+	}
+
+	return maxRole
+}
+
+func (this *User) ToLMSType() *lmstypes.User {
+	return &lmstypes.User{
+		ID:    formatID(this.ID),
+		Name:  this.FullName,
+		Email: this.Email,
+		Role:  this.GetRole(),
+	}
+}
+
+func (this *Assignment) ToLMSType() *lmstypes.Assignment {
+	var dueDate *timestamp.Timestamp = nil
+	if this.DueDate > 0 {
+		value := timestamp.FromMSecs(this.DueDate * 1000)
+		dueDate = &value
+	}
+
+	return &lmstypes.Assignment{
+		ID:          formatID(this.ID),
+		Name:        this.Name,
+		LMSCourseID: formatID(this.CourseID),
+		DueDate:     dueDate,
+		MaxPoints:   max(0.0, this.MaxPoints),
+	}
+}
+
+// The feedback comment (if any) is used as the score's comment.
+// Since there is only one feedback comment for each grade, the comment is identified by its user.
+func (this *GradeItem) ToLMSType(userID int64) *lmstypes.SubmissionScore {
+	score := 0.0
+	if this.GradeRaw != nil {
+		score = *this.GradeRaw
+	}
+
+	var submissionTime *timestamp.Timestamp = nil
+	if (this.GradeDateSubmitted != nil) && (*this.GradeDateSubmitted > 0) {
+		value := timestamp.FromMSecs(*this.GradeDateSubmitted * 1000)
+		submissionTime = &value
+	}
+
+	comments := make([]*lmstypes.SubmissionComment, 0, 1)
+	if this.Feedback != "" {
+		comments = append(comments, &lmstypes.SubmissionComment{
+			ID:     formatID(userID),
+			Author: formatID(userID),
+			Text:   this.Feedback,
+		})
+	}
+
+	return &lmstypes.SubmissionScore{
+		UserID:   formatID(userID),
+		Score:    score,
+		Time:     submissionTime,
+		Comments: comments,
+	}
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
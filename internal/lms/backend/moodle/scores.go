@@ -0,0 +1,133 @@
+package moodle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *MoodleBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	scores, err := this.fetchAssignmentScores(assignmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scores) != 1 {
+		return nil, fmt.Errorf("Did not find exactly one Moodle score for user '%s' on assignment '%s', found %d.", userID, assignmentID, len(scores))
+	}
+
+	return scores[0], nil
+}
+
+func (this *MoodleBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	return this.fetchAssignmentScores(assignmentID, "")
+}
+
+// Fetch scores from the course's grade report (which includes feedback comments).
+// If the user ID is empty, then all users will be fetched.
+// The caller must hold the API lock.
+func (this *MoodleBackend) fetchAssignmentScores(assignmentID string, userID string) ([]*lmstypes.SubmissionScore, error) {
+	params := map[string]string{
+		"courseid": this.CourseID,
+	}
+
+	if userID != "" {
+		params["userid"] = userID
+	}
+
+	var response gradeItemsResponse
+	err := this.callFunction("gradereport_user_get_grade_items", params, &response)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err)
+	}
+
+	scores := make([]*lmstypes.SubmissionScore, 0, len(response.UserGrades))
+
+	for _, userGrades := range response.UserGrades {
+		if userGrades == nil {
+			continue
+		}
+
+		for _, item := range userGrades.GradeItems {
+			if (item == nil) || (item.ItemModule != "assign") || (formatID(item.ItemInstance) != assignmentID) {
+				continue
+			}
+
+			scores = append(scores, item.ToLMSType(userGrades.UserID))
+			break
+		}
+	}
+
+	return scores, nil
+}
+
+func (this *MoodleBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+	for page := 0; (page * POST_PAGE_SIZE) < len(scores); page++ {
+		startIndex := page * POST_PAGE_SIZE
+		endIndex := min(len(scores), ((page + 1) * POST_PAGE_SIZE))
+
+		if page != 0 {
+			time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC))
+		}
+
+		err := this.updateAssignmentScores(assignmentID, scores[startIndex:endIndex])
+		if err != nil {
+			return fmt.Errorf("Failed on page %d: '%w'.", page, err)
+		}
+	}
+
+	return nil
+}
+
+func (this *MoodleBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	if len(scores) > POST_PAGE_SIZE {
+		return fmt.Errorf("Too many score upload requests at once. Found %d, max %d.", len(scores), POST_PAGE_SIZE)
+	}
+
+	form := map[string]string{
+		"assignmentid": assignmentID,
+		"applytoall":   "0",
+	}
+
+	for i, score := range scores {
+		prefix := fmt.Sprintf("grades[%d]", i)
+
+		form[prefix+"[userid]"] = score.UserID
+		form[prefix+"[grade]"] = util.FloatToStr(score.Score)
+		form[prefix+"[attemptnumber]"] = "-1"
+		form[prefix+"[addattempt]"] = "0"
+		form[prefix+"[workflowstate]"] = ""
+
+		if len(score.Comments) > 1 {
+			return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
+		}
+
+		// Scores without a comment leave any existing feedback alone.
+		for _, comment := range score.Comments {
+			addFeedbackComment(form, prefix+"[plugindata]", comment.Text)
+		}
+	}
+
+	err := this.callFunction("mod_assign_save_grades", form, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to upload scores: '%w'.", err)
+	}
+
+	return nil
+}
+
+func addFeedbackComment(form map[string]string, prefix string, text string) {
+	form[fmt.Sprintf("%s[%s][text]", prefix, FEEDBACK_COMMENTS_PLUGIN)] = text
+	form[fmt.Sprintf("%s[%s][format]", prefix, FEEDBACK_COMMENTS_PLUGIN)] = FORMAT_PLAIN
+}
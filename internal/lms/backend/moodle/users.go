@@ -0,0 +1,75 @@
+package moodle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/log"
+)
+
+func (this *MoodleBackend) FetchUsers() ([]*lmstypes.User, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	rawUsers, err := this.fetchUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*lmstypes.User, 0, len(rawUsers))
+	for _, user := range rawUsers {
+		users = append(users, user.ToLMSType())
+	}
+
+	return users, nil
+}
+
+// Moodle does not have a function to search a course's users,
+// so all the course's users are fetched.
+func (this *MoodleBackend) FetchUser(email string) (*lmstypes.User, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	rawUsers, err := this.fetchUsers()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err)
+	}
+
+	matches := make([]*User, 0, 1)
+	for _, user := range rawUsers {
+		if strings.EqualFold(user.Email, email) {
+			matches = append(matches, user)
+		}
+	}
+
+	if len(matches) != 1 {
+		log.Warn("Did not find exactly one matching user in moodle.",
+			log.NewAttr("email", email), log.NewAttr("num-results", len(matches)))
+		return nil, nil
+	}
+
+	return matches[0].ToLMSType(), nil
+}
+
+// The caller must hold the API lock.
+func (this *MoodleBackend) fetchUsers() ([]*User, error) {
+	params := map[string]string{
+		"courseid": this.CourseID,
+	}
+
+	var pageUsers []*User
+	err := this.callFunction("core_enrol_get_enrolled_users", params, &pageUsers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch users: '%w'.", err)
+	}
+
+	users := make([]*User, 0, len(pageUsers))
+	for _, user := range pageUsers {
+		if user != nil {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
@@ -10,6 +10,8 @@ import (
 // Settings to help in testing.
 var failUpdateAssignmentScores bool = false
 var usersModifier FetchUsersModifier = nil
+var groups map[string][]*lmstypes.Group = nil
+var scores map[string][]*lmstypes.SubmissionScore = nil
 
 type TestLMSBackend struct {
 	CourseID string
@@ -42,6 +44,36 @@ func ClearUsersModifier() {
 	usersModifier = nil
 }
 
+// Set the groups returned from FetchGroups() for a group set.
+func SetGroups(groupSetID string, groupSetGroups []*lmstypes.Group) {
+	if groups == nil {
+		groups = make(map[string][]*lmstypes.Group)
+	}
+
+	groups[groupSetID] = groupSetGroups
+}
+
+func ClearGroups() {
+	groups = nil
+}
+
+// Set the scores returned from FetchAssignmentScores() for an assignment.
+func SetAssignmentScores(assignmentID string, assignmentScores []*lmstypes.SubmissionScore) {
+	if scores == nil {
+		scores = make(map[string][]*lmstypes.SubmissionScore)
+	}
+
+	scores[assignmentID] = assignmentScores
+}
+
+func ClearAssignmentScores() {
+	scores = nil
+}
+
+func (this *TestLMSBackend) FetchGroups(groupSetID string) ([]*lmstypes.Group, error) {
+	return groups[groupSetID], nil
+}
+
 func (this *TestLMSBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
 	return nil, nil
 }
@@ -59,9 +91,15 @@ func (this *TestLMSBackend) UpdateComment(assignmentID string, comment *lmstypes
 }
 
 func (this *TestLMSBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
-	return nil, nil
+	return scores[assignmentID], nil
 }
 
 func (this *TestLMSBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+	for _, score := range scores[assignmentID] {
+		if score.UserID == userID {
+			return score, nil
+		}
+	}
+
 	return nil, nil
 }
@@ -4,6 +4,9 @@ import (
 	"fmt"
 
 	"github.com/edulinq/autograder/internal/lms/backend/canvas"
+	"github.com/edulinq/autograder/internal/lms/backend/file"
+	"github.com/edulinq/autograder/internal/lms/backend/lti"
+	"github.com/edulinq/autograder/internal/lms/backend/moodle"
 	"github.com/edulinq/autograder/internal/lms/backend/test"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/model"
@@ -22,6 +25,8 @@ type lmsBackend interface {
 
 	FetchUsers() ([]*lmstypes.User, error)
 	FetchUser(email string) (*lmstypes.User, error)
+
+	FetchGroups(groupSetID string) ([]*lmstypes.Group, error)
 }
 
 func getBackend(course *model.Course) (lmsBackend, error) {
@@ -37,6 +42,27 @@ func getBackend(course *model.Course) (lmsBackend, error) {
 			return nil, err
 		}
 
+		return backend, nil
+	case model.LMS_TYPE_MOODLE:
+		backend, err := moodle.NewBackend(adapter.LMSCourseID, adapter.APIToken, adapter.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+
+		return backend, nil
+	case model.LMS_TYPE_LTI:
+		backend, err := lti.NewBackend(adapter.LTI)
+		if err != nil {
+			return nil, err
+		}
+
+		return backend, nil
+	case model.LMS_TYPE_FILE:
+		backend, err := file.NewBackend(adapter.File, course)
+		if err != nil {
+			return nil, err
+		}
+
 		return backend, nil
 	case model.LMS_TYPE_TEST:
 		backend, err := test.NewBackend(course.GetID())
@@ -130,3 +156,12 @@ func FetchUser(course *model.Course, email string) (*lmstypes.User, error) {
 
 	return backend.FetchUser(email)
 }
+
+func FetchGroups(course *model.Course, groupSetID string) ([]*lmstypes.Group, error) {
+	backend, err := getBackend(course)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.FetchGroups(groupSetID)
+}
@@ -21,9 +21,15 @@ func SyncLMS(course *model.Course, dryRun bool, sendEmails bool) (*model.LMSSync
 		return nil, err
 	}
 
+	teamSync, err := syncAllTeams(course, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &model.LMSSyncResult{
 		UserSync:       userSync,
 		AssignmentSync: assignmentSync,
+		TeamSync:       teamSync,
 	}
 
 	return result, nil
@@ -0,0 +1,146 @@
+package lmssync
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+const LMS_TEAM_ID_PREFIX = "lms-"
+
+// Sync the teams of every assignment that has an LMS group set.
+// Returns nil (with no error) if no assignments sync teams.
+func syncAllTeams(course *model.Course, dryRun bool) (map[string]*model.TeamSyncResult, error) {
+	var results map[string]*model.TeamSyncResult = nil
+
+	for _, assignment := range course.GetSortedAssignments() {
+		if (assignment.Teams == nil) || (assignment.Teams.LMSGroupSetID == "") {
+			continue
+		}
+
+		result, err := SyncTeams(assignment, dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sync teams for assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+
+		if results == nil {
+			results = make(map[string]*model.TeamSyncResult)
+		}
+
+		results[assignment.GetID()] = result
+	}
+
+	return results, nil
+}
+
+// Replace an assignment's LMS teams with the groups in the assignment's LMS group set.
+// Teams from other sources (config or API) are left alone,
+// and groups that conflict with them are skipped.
+func SyncTeams(assignment *model.Assignment, dryRun bool) (*model.TeamSyncResult, error) {
+	if (assignment.Teams == nil) || (assignment.Teams.LMSGroupSetID == "") {
+		return nil, fmt.Errorf("Assignment '%s' does not have an LMS group set to sync teams from.", assignment.FullID())
+	}
+
+	course := assignment.GetCourse()
+	if !course.HasLMSAdapter() {
+		return nil, fmt.Errorf("Course '%s' has no LMS.", course.GetID())
+	}
+
+	groups, err := lms.FetchGroups(course, assignment.Teams.LMSGroupSetID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch LMS groups: '%w'.", err)
+	}
+
+	users, err := db.GetCourseUsers(course)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course users: '%w'.", err)
+	}
+
+	oldTeams, err := db.GetTeams(assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get teams: '%w'.", err)
+	}
+
+	result := model.NewTeamSyncResult()
+
+	newTeams := make([]*model.Team, 0, len(groups))
+	newTeamIDs := make(map[string]bool, len(groups))
+
+	for _, group := range groups {
+		team := &model.Team{
+			ID:      LMS_TEAM_ID_PREFIX + group.ID,
+			Name:    group.Name,
+			Members: make([]string, 0, len(group.Members)),
+			Source:  model.TEAM_SOURCE_LMS,
+		}
+
+		for _, member := range group.Members {
+			user := users[member.Email]
+			if (user == nil) || (user.Role != model.CourseRoleStudent) {
+				result.UnknownUsers = append(result.UnknownUsers, member.Email)
+				continue
+			}
+
+			team.Members = append(team.Members, member.Email)
+		}
+
+		if len(team.Members) == 0 {
+			result.SkippedGroups[group.ID] = "Group has no students in the course."
+			continue
+		}
+
+		err = assignment.Teams.ValidateTeam(team)
+		if err != nil {
+			result.SkippedGroups[group.ID] = err.Error()
+			continue
+		}
+
+		newTeams = append(newTeams, team)
+		newTeamIDs[team.ID] = true
+	}
+
+	for id, team := range oldTeams {
+		if (team.Source == model.TEAM_SOURCE_LMS) && !newTeamIDs[id] {
+			result.RemovedTeams = append(result.RemovedTeams, id)
+		}
+	}
+
+	slices.Sort(result.RemovedTeams)
+	slices.Sort(result.UnknownUsers)
+	result.UnknownUsers = slices.Compact(result.UnknownUsers)
+
+	if dryRun {
+		result.SyncedTeams = newTeams
+		return result, nil
+	}
+
+	// Remove all the old LMS teams first, so members can move between teams.
+	for id, team := range oldTeams {
+		if team.Source != model.TEAM_SOURCE_LMS {
+			continue
+		}
+
+		_, err = db.RemoveTeam(assignment, id)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove old LMS team '%s': '%w'.", id, err)
+		}
+	}
+
+	for _, team := range newTeams {
+		err = db.SaveTeam(assignment, team)
+		if err != nil {
+			log.Warn("Failed to save LMS team.", assignment, log.NewAttr("team", team.ID), err)
+			result.SkippedGroups[strings.TrimPrefix(team.ID, LMS_TEAM_ID_PREFIX)] = err.Error()
+			continue
+		}
+
+		result.SyncedTeams = append(result.SyncedTeams, team)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package lmssync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSyncTeams(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer lmstest.ClearGroups()
+
+	assignment := db.MustGetTestAssignment()
+	db.MustSetTestStudents(assignment.GetCourse(), "course-other@test.edulinq.org", "course-grader@test.edulinq.org", "course-owner@test.edulinq.org")
+
+	assignment.Teams = &model.TeamInfo{
+		MaxSize:       2,
+		LMSGroupSetID: "group-set",
+		Teams: []*model.Team{
+			&model.Team{ID: "config", Members: []string{"course-grader@test.edulinq.org"}},
+		},
+	}
+
+	err := assignment.Teams.Validate()
+	if err != nil {
+		test.Fatalf("Failed to validate teams: '%v'.", err)
+	}
+
+	db.MustSaveAssignment(assignment)
+
+	// An old LMS team that is no longer in the LMS.
+	err = db.SaveTeam(assignment, &model.Team{ID: "lms-old", Members: []string{"course-owner@test.edulinq.org"}, Source: model.TEAM_SOURCE_LMS})
+	if err != nil {
+		test.Fatalf("Failed to save old LMS team: '%v'.", err)
+	}
+
+	lmstest.SetGroups("group-set", []*lmstypes.Group{
+		&lmstypes.Group{ID: "1", Name: "Team One", Members: []*lmstypes.User{
+			&lmstypes.User{Email: "course-student@test.edulinq.org"},
+			&lmstypes.User{Email: "course-other@test.edulinq.org"},
+			&lmstypes.User{Email: "ZZZ@test.edulinq.org"},
+			// Not a student.
+			&lmstypes.User{Email: "course-admin@test.edulinq.org"},
+		}},
+		&lmstypes.Group{ID: "2", Name: "Empty", Members: []*lmstypes.User{}},
+		&lmstypes.Group{ID: "3", Name: "Conflict", Members: []*lmstypes.User{
+			&lmstypes.User{Email: "course-grader@test.edulinq.org"},
+		}},
+	})
+
+	result, err := SyncTeams(assignment, false)
+	if err != nil {
+		test.Fatalf("Failed to sync teams: '%v'.", err)
+	}
+
+	expectedTeams := []*model.Team{
+		&model.Team{ID: "lms-1", Name: "Team One", Members: []string{"course-other@test.edulinq.org", "course-student@test.edulinq.org"}, Source: model.TEAM_SOURCE_LMS},
+	}
+
+	if !reflect.DeepEqual(expectedTeams, result.SyncedTeams) {
+		test.Fatalf("Unexpected synced teams. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expectedTeams), util.MustToJSONIndent(result.SyncedTeams))
+	}
+
+	if !reflect.DeepEqual([]string{"lms-old"}, result.RemovedTeams) {
+		test.Fatalf("Unexpected removed teams: '%v'.", result.RemovedTeams)
+	}
+
+	if !reflect.DeepEqual([]string{"ZZZ@test.edulinq.org", "course-admin@test.edulinq.org"}, result.UnknownUsers) {
+		test.Fatalf("Unexpected unknown users: '%v'.", result.UnknownUsers)
+	}
+
+	if (len(result.SkippedGroups) != 2) || (result.SkippedGroups["2"] == "") || (result.SkippedGroups["3"] == "") {
+		test.Fatalf("Unexpected skipped groups: '%v'.", result.SkippedGroups)
+	}
+
+	teams, err := db.GetTeams(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get teams: '%v'.", err)
+	}
+
+	if (len(teams) != 2) || (teams["lms-1"] == nil) || (teams["config"] == nil) {
+		test.Fatalf("Unexpected teams after sync: '%s'.", util.MustToJSONIndent(teams))
+	}
+}
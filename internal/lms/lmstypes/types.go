@@ -12,6 +12,13 @@ type User struct {
 	Role  model.CourseUserRole
 }
 
+// A group of users (e.g., a project team) from a group set (group category).
+type Group struct {
+	ID      string
+	Name    string
+	Members []*User
+}
+
 type SubmissionScore struct {
 	UserID   string
 	Score    float64
@@ -0,0 +1,270 @@
+package ltilaunch
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms/backend/lti"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/users"
+)
+
+// The name of the tokens created by launches (one per user per course).
+const TOKEN_NAME_PREFIX = "lti-launch::"
+
+type LaunchResult struct {
+	Course *model.Course
+
+	// The assignment the launch was for (may be nil if the launch was not for a specific assignment).
+	Assignment *model.Assignment
+
+	Email string
+	Role  model.CourseUserRole
+
+	// A new token the user can authenticate with.
+	// Care should be taken to not expose this field.
+	TokenCleartext string
+}
+
+// Handle an LTI launch (the id_token and state the platform posts to the target link URI).
+// The browser state is the state that was bound to the user's browser when the login started (see Login()),
+// and must match the launch's state.
+// The launch is verified, matched to a course (and optionally an assignment),
+// and the user is added/updated in the course (see getLaunchUser() for how users are matched).
+// A launch can raise a user's course role, but never lowers it.
+// Users with a server role above user cannot launch.
+// The result contains a new token for the user that can only be used with the launch's course.
+func Launch(idToken string, state string, browserState string) (*LaunchResult, error) {
+	if (browserState == "") || (subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1) {
+		return nil, fmt.Errorf("LTI launch was not started from this browser (state does not match).")
+	}
+
+	login := takePendingLogin(state)
+	if login == nil {
+		return nil, fmt.Errorf("LTI launch has an unknown or expired state.")
+	}
+
+	unverifiedClaims, err := lti.ParseUnverifiedLaunch(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if (unverifiedClaims.Issuer != login.Issuer) || !unverifiedClaims.HasAudience(login.ClientID) {
+		return nil, fmt.Errorf("LTI launch does not match its login.")
+	}
+
+	course, err := getLaunchCourse(unverifiedClaims, login.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := lti.ValidateLaunch(getPlatform(course), idToken, login.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	courseUser, err := getLaunchUser(course, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	email := claims.Email
+	if courseUser != nil {
+		email = courseUser.Email
+	}
+
+	if email == "" {
+		return nil, fmt.Errorf("LTI launch does not include an email, the platform must share user emails with the autograder.")
+	}
+
+	serverUser, err := db.GetServerUser(email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get LTI user '%s': '%w'.", email, err)
+	}
+
+	// Launch tokens should never be able to act with server-level permissions.
+	if (serverUser != nil) && (serverUser.Role > model.ServerRoleUser) {
+		return nil, fmt.Errorf("Refusing LTI launch for '%s', users with a server role above user ('%s') cannot use LTI launches.", email, serverUser.Role.String())
+	}
+
+	// A launch can raise a user's course role, but never lower it.
+	role := lti.GetRole(claims.Roles)
+	if (courseUser != nil) && (courseUser.Role > role) {
+		role = courseUser.Role
+	}
+
+	assignment := getLaunchAssignment(course, claims)
+
+	rawUser := &model.RawServerUserData{
+		Email:       email,
+		Name:        claims.Name,
+		Course:      course.GetID(),
+		CourseRole:  role.String(),
+		CourseLMSID: claims.Subject,
+	}
+
+	options := users.UpsertUsersOptions{
+		RawUsers:          []*model.RawServerUserData{rawUser},
+		ContextServerRole: model.ServerRoleRoot,
+	}
+
+	userResult := users.UpsertUser(options)
+	if userResult.ValidationError != nil {
+		return nil, fmt.Errorf("Failed to add LTI user '%s': '%w'.", email, userResult.ValidationError.ToError())
+	}
+
+	if userResult.SystemError != nil {
+		return nil, fmt.Errorf("Failed to add LTI user '%s': '%w'.", email, userResult.SystemError.ToError())
+	}
+
+	cleartext, err := createLaunchToken(course, email)
+	if err != nil {
+		return nil, err
+	}
+
+	logAttrs := []any{course, log.NewUserAttr(email), log.NewAttr("role", role)}
+	if assignment != nil {
+		logAttrs = append(logAttrs, assignment)
+	}
+
+	log.Info("LTI launch.", logAttrs...)
+
+	result := &LaunchResult{
+		Course:         course,
+		Assignment:     assignment,
+		Email:          email,
+		Role:           role,
+		TokenCleartext: cleartext,
+	}
+
+	return result, nil
+}
+
+// Find the single course that a launch is for.
+// Courses that set a context ID must match the launch's context,
+// other courses must match the launch's line items URL.
+func getLaunchCourse(claims *lti.LaunchClaims, clientID string) (*model.Course, error) {
+	courses, err := getPlatformCourses(claims.Issuer, clientID, claims.DeploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*model.Course, 0, 1)
+	for _, course := range courses {
+		platform := getPlatform(course)
+
+		if platform.ContextID != "" {
+			if platform.ContextID == claims.GetContextID() {
+				matches = append(matches, course)
+			}
+		} else if platform.LineItemsURL == claims.GetLineItemsURL() {
+			matches = append(matches, course)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("Could not find a course for LTI launch (context: '%s').", claims.GetContextID())
+	}
+
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("Found multiple courses (%d) for LTI launch (context: '%s').", len(matches), claims.GetContextID())
+	}
+
+	return matches[0], nil
+}
+
+// Find the existing course user for a launch (or nil if the launch is for a new user).
+// Existing users are only matched on their LMS ID in the course (the launch's subject).
+// The email in a launch is controlled by the platform and is not a stable identity,
+// so a launch is refused if its email belongs to an existing user that was not matched on LMS ID.
+// These users must first be linked to their LMS ID (e.g., with an LMS user sync).
+func getLaunchUser(course *model.Course, claims *lti.LaunchClaims) (*model.CourseUser, error) {
+	courseUsers, err := db.GetCourseUsers(course)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course users: '%w'.", err)
+	}
+
+	for _, courseUser := range courseUsers {
+		if courseUser.GetLMSID() == claims.Subject {
+			return courseUser, nil
+		}
+	}
+
+	if claims.Email == "" {
+		return nil, nil
+	}
+
+	serverUser, err := db.GetServerUser(claims.Email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get LTI user '%s': '%w'.", claims.Email, err)
+	}
+
+	if serverUser != nil {
+		return nil, fmt.Errorf("LTI launch for existing user '%s' does not match the user's LMS ID in the course, the user must be linked to their LMS ID first.", claims.Email)
+	}
+
+	return nil, nil
+}
+
+// Get the assignment whose LMS ID matches the launch's line item (or nil).
+func getLaunchAssignment(course *model.Course, claims *lti.LaunchClaims) *model.Assignment {
+	lineItem := claims.GetLineItemURL()
+	if lineItem == "" {
+		return nil
+	}
+
+	for _, assignment := range course.GetSortedAssignments() {
+		if assignment.GetLMSID() == lineItem {
+			return assignment
+		}
+	}
+
+	log.Warn("Could not find an assignment for LTI launch.", course, log.NewAttr("line-item", lineItem))
+
+	return nil
+}
+
+// Create a new token for the user that can only be used with the course.
+// Any previous launch token for the same course is replaced.
+func createLaunchToken(course *model.Course, email string) (string, error) {
+	user, err := db.GetServerUser(email)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get LTI user '%s': '%w'.", email, err)
+	}
+
+	if user == nil {
+		return "", fmt.Errorf("Could not find LTI user '%s'.", email)
+	}
+
+	name := TOKEN_NAME_PREFIX + course.GetID()
+
+	tokens := make([]*model.Token, 0, len(user.Tokens))
+	for _, token := range user.Tokens {
+		if (token.Source != model.TokenSourceLTI) || (token.Name != name) {
+			tokens = append(tokens, token)
+			continue
+		}
+
+		_, err = db.DeleteUserToken(email, token.ID)
+		if err != nil {
+			return "", fmt.Errorf("Failed to delete old token for LTI user '%s': '%w'.", email, err)
+		}
+	}
+	user.Tokens = tokens
+
+	token, cleartext, err := user.CreateRandomToken(name, model.TokenSourceLTI)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create token for LTI user '%s': '%w'.", email, err)
+	}
+
+	token.Course = course.GetID()
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		return "", fmt.Errorf("Failed to save LTI user '%s': '%w'.", email, err)
+	}
+
+	return cleartext, nil
+}
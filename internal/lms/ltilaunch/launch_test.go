@@ -0,0 +1,367 @@
+package ltilaunch
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms/backend/lti"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestLaunch(test *testing.T) {
+	testCases := []struct {
+		email              string
+		roles              []string
+		noLineItem         bool
+		expectedRole       model.CourseUserRole
+		expectedAssignment string
+	}{
+		{"course-student@test.edulinq.org", []string{lti.ROLE_LEARNER}, false, model.CourseRoleStudent, "hw0"},
+		{"course-student@test.edulinq.org", []string{"Instructor", lti.ROLE_TEACHING_ASSISTANT}, false, model.CourseRoleGrader, "hw0"},
+		{"course-owner@test.edulinq.org", []string{lti.ROLE_INSTRUCTOR}, true, model.CourseRoleOwner, ""},
+		{"course-other@test.edulinq.org", []string{"http://purl.imsglobal.org/vocab/lis/v2/institution/person#Student"}, false, model.CourseRoleOther, "hw0"},
+		{"new-user@test.edulinq.org", []string{lti.ROLE_LEARNER}, false, model.CourseRoleStudent, "hw0"},
+
+		// Launches never lower a course role.
+		{"course-grader@test.edulinq.org", []string{lti.ROLE_LEARNER}, false, model.CourseRoleGrader, "hw0"},
+		{"course-owner@test.edulinq.org", []string{lti.ROLE_TEACHING_ASSISTANT}, false, model.CourseRoleOwner, "hw0"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+		setupTestCourse()
+
+		state, nonce := mustLogin(test)
+
+		claims := getTestClaims(nonce, testCase.email, testCase.roles)
+		if testCase.noLineItem {
+			delete(claims[lti.CLAIM_ENDPOINT].(map[string]any), "lineitem")
+		}
+
+		result, err := Launch(mustSignLaunch(test, claims, platformKey), state, state)
+		if err != nil {
+			test.Errorf("Case %d: Failed to launch: '%v'.", i, err)
+			continue
+		}
+
+		if result.Course.GetID() != "course101" {
+			test.Errorf("Case %d: Unexpected course: '%s'.", i, result.Course.GetID())
+			continue
+		}
+
+		actualAssignment := ""
+		if result.Assignment != nil {
+			actualAssignment = result.Assignment.GetID()
+		}
+
+		if actualAssignment != testCase.expectedAssignment {
+			test.Errorf("Case %d: Unexpected assignment. Expected: '%s', Actual: '%s'.", i, testCase.expectedAssignment, actualAssignment)
+			continue
+		}
+
+		if result.Role != testCase.expectedRole {
+			test.Errorf("Case %d: Unexpected role. Expected: '%s', Actual: '%s'.", i, testCase.expectedRole.String(), result.Role.String())
+			continue
+		}
+
+		user := db.MustGetServerUser(testCase.email)
+		if user == nil {
+			test.Errorf("Case %d: User was not added.", i)
+			continue
+		}
+
+		if user.GetCourseRole("course101") != testCase.expectedRole {
+			test.Errorf("Case %d: Unexpected saved role. Expected: '%s', Actual: '%s'.", i, testCase.expectedRole.String(), user.GetCourseRole("course101").String())
+			continue
+		}
+
+		lmsID := user.CourseInfo["course101"].LMSID
+		if (lmsID == nil) || (*lmsID != ("lms-" + testCase.email)) {
+			test.Errorf("Case %d: Unexpected LMS ID: '%s'.", i, util.PointerToString(lmsID))
+			continue
+		}
+
+		auth, err := user.Auth(util.Sha256HexFromString(result.TokenCleartext))
+		if err != nil {
+			test.Errorf("Case %d: Failed to auth with launch token: '%v'.", i, err)
+			continue
+		}
+
+		if !auth {
+			test.Errorf("Case %d: Launch token does not authenticate.", i)
+			continue
+		}
+
+		for _, token := range user.Tokens {
+			if (token.Source == model.TokenSourceLTI) && (token.Course != "course101") {
+				test.Errorf("Case %d: Launch token is not limited to the launch's course: '%s'.", i, token.Course)
+				break
+			}
+		}
+	}
+
+	db.ResetForTesting()
+}
+
+// Users are matched on their LMS ID, not on the (platform controlled) email in the launch.
+func TestLaunchMatchLMSID(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setupTestCourse()
+
+	state, nonce := mustLogin(test)
+
+	claims := getTestClaims(nonce, "course-student@test.edulinq.org", []string{lti.ROLE_LEARNER})
+	claims["email"] = "course-admin@test.edulinq.org"
+
+	result, err := Launch(mustSignLaunch(test, claims, platformKey), state, state)
+	if err != nil {
+		test.Fatalf("Failed to launch: '%v'.", err)
+	}
+
+	if result.Email != "course-student@test.edulinq.org" {
+		test.Fatalf("Launch matched the wrong user: '%s'.", result.Email)
+	}
+
+	if result.Role != model.CourseRoleStudent {
+		test.Fatalf("Unexpected role: '%s'.", result.Role.String())
+	}
+
+	admin := db.MustGetServerUser("course-admin@test.edulinq.org")
+	for _, token := range admin.Tokens {
+		if token.Source == model.TokenSourceLTI {
+			test.Fatalf("A launch token was created for the user with the launch's email.")
+		}
+	}
+
+	student := db.MustGetServerUser("course-student@test.edulinq.org")
+	auth, err := student.Auth(util.Sha256HexFromString(result.TokenCleartext))
+	if err != nil {
+		test.Fatalf("Failed to auth with launch token: '%v'.", err)
+	}
+
+	if !auth {
+		test.Fatalf("Launch token does not authenticate.")
+	}
+}
+
+// Course users without an LMS ID are not linked by the email in a launch.
+func TestLaunchUnlinkedUser(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setupTestCourse()
+
+	email := "server-user@test.edulinq.org"
+
+	user := db.MustGetServerUser(email)
+	user.CourseInfo["course101"] = &model.UserCourseInfo{Role: model.CourseRoleStudent}
+	db.MustUpsertUser(user)
+
+	state, nonce := mustLogin(test)
+	claims := getTestClaims(nonce, email, []string{lti.ROLE_LEARNER})
+
+	_, err := Launch(mustSignLaunch(test, claims, platformKey), state, state)
+	if err == nil {
+		test.Fatalf("Did not get an error when launching for an unlinked user.")
+	}
+
+	user = db.MustGetServerUser(email)
+	if user.CourseInfo["course101"].LMSID != nil {
+		test.Fatalf("Unlinked user was linked by a launch: '%s'.", util.PointerToString(user.CourseInfo["course101"].LMSID))
+	}
+
+	for _, token := range user.Tokens {
+		if token.Source == model.TokenSourceLTI {
+			test.Fatalf("A launch token was created for an unlinked user.")
+		}
+	}
+}
+
+// A launch's state must match the state bound to the browser that started the login.
+func TestLaunchBrowserState(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setupTestCourse()
+
+	state, nonce := mustLogin(test)
+	otherState, _ := mustLogin(test)
+
+	idToken := mustSignLaunch(test, getTestClaims(nonce, "course-student@test.edulinq.org", []string{lti.ROLE_LEARNER}), platformKey)
+
+	for i, browserState := range []string{"", otherState, "unknown-state"} {
+		_, err := Launch(idToken, state, browserState)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error for a mismatched browser state.", i)
+		}
+	}
+
+	// Mismatched launches do not use up the login.
+	_, err := Launch(idToken, state, state)
+	if err != nil {
+		test.Fatalf("Failed to launch: '%v'.", err)
+	}
+}
+
+// Launching again replaces the previous launch token.
+func TestLaunchReplaceToken(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setupTestCourse()
+
+	email := "course-student@test.edulinq.org"
+	tokens := make([]string, 0, 2)
+
+	for i := 0; i < 2; i++ {
+		state, nonce := mustLogin(test)
+		claims := getTestClaims(nonce, email, []string{lti.ROLE_LEARNER})
+
+		result, err := Launch(mustSignLaunch(test, claims, platformKey), state, state)
+		if err != nil {
+			test.Fatalf("Failed to launch: '%v'.", err)
+		}
+
+		tokens = append(tokens, result.TokenCleartext)
+	}
+
+	user := db.MustGetServerUser(email)
+
+	count := 0
+	for _, token := range user.Tokens {
+		if token.Source == model.TokenSourceLTI {
+			count++
+		}
+	}
+
+	if count != 1 {
+		test.Fatalf("Unexpected number of launch tokens. Expected: 1, Actual: %d.", count)
+	}
+
+	auth, _ := user.Auth(util.Sha256HexFromString(tokens[0]))
+	if auth {
+		test.Fatalf("Old launch token still authenticates.")
+	}
+
+	auth, _ = user.Auth(util.Sha256HexFromString(tokens[1]))
+	if !auth {
+		test.Fatalf("New launch token does not authenticate.")
+	}
+}
+
+func TestLaunchErrors(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setupTestCourse()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		test.Fatalf("Failed to generate key: '%v'.", err)
+	}
+
+	email := "course-student@test.edulinq.org"
+
+	testCases := []struct {
+		modify   func(claims map[string]any)
+		key      *rsa.PrivateKey
+		badState bool
+	}{
+		{nil, platformKey, true},
+		{nil, otherKey, false},
+		{func(claims map[string]any) { claims["nonce"] = "other" }, platformKey, false},
+		{func(claims map[string]any) { claims["aud"] = "other" }, platformKey, false},
+		{func(claims map[string]any) {
+			delete(claims, "email")
+			claims["sub"] = "lms-new-user@test.edulinq.org"
+		}, platformKey, false},
+
+		// The email matches a user that is linked to a different LMS ID.
+		{func(claims map[string]any) { claims["sub"] = "lms-other" }, platformKey, false},
+
+		// The email matches a server user that is not in the course (existing users are never linked by email).
+		{func(claims map[string]any) {
+			claims["email"] = "server-user@test.edulinq.org"
+			claims["sub"] = "lms-server-user@test.edulinq.org"
+		}, platformKey, false},
+
+		// Users with a server role above user cannot launch.
+		{func(claims map[string]any) {
+			claims["email"] = "server-admin@test.edulinq.org"
+			claims["sub"] = "lms-server-admin@test.edulinq.org"
+		}, platformKey, false},
+		{func(claims map[string]any) {
+			claims[lti.CLAIM_ENDPOINT].(map[string]any)["lineitems"] = TEST_ISSUER + "/api/lti/courses/999/line_items"
+		}, platformKey, false},
+	}
+
+	for i, testCase := range testCases {
+		state, nonce := mustLogin(test)
+		if testCase.badState {
+			state = "unknown-state"
+		}
+
+		claims := getTestClaims(nonce, email, []string{lti.ROLE_INSTRUCTOR})
+		if testCase.modify != nil {
+			testCase.modify(claims)
+		}
+
+		_, err := Launch(mustSignLaunch(test, claims, testCase.key), state, state)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+	}
+
+	// No failed launch should have changed the user.
+	user := db.MustGetServerUser(email)
+	if user.GetCourseRole("course101") != model.CourseRoleStudent {
+		test.Fatalf("User role was changed by a failed launch: '%s'.", user.GetCourseRole("course101").String())
+	}
+
+	for _, email := range []string{"new-user@test.edulinq.org", "server-admin@test.edulinq.org", "server-user@test.edulinq.org"} {
+		user = db.MustGetServerUser(email)
+		if user == nil {
+			continue
+		}
+
+		for _, token := range user.Tokens {
+			if token.Source == model.TokenSourceLTI {
+				test.Fatalf("Failed launch created a token for '%s'.", email)
+			}
+		}
+	}
+}
+
+// Initiate a login and return the (state, nonce).
+func mustLogin(test *testing.T) (string, string) {
+	request := &LoginRequest{
+		Issuer:        TEST_ISSUER,
+		LoginHint:     "hint",
+		TargetLinkURI: TEST_TARGET_URI,
+		LaunchHost:    TEST_LAUNCH_HOST,
+		LaunchPath:    TEST_LAUNCH_PATH,
+	}
+
+	target, _, err := Login(request)
+	if err != nil {
+		test.Fatalf("Failed to login: '%v'.", err)
+	}
+
+	parsed, err := url.Parse(target)
+	if err != nil {
+		test.Fatalf("Failed to parse login target '%s': '%v'.", target, err)
+	}
+
+	return parsed.Query().Get("state"), parsed.Query().Get("nonce")
+}
+
+func mustSignLaunch(test *testing.T, claims map[string]any, key *rsa.PrivateKey) string {
+	idToken, err := lti.SignJWT(claims, key, TEST_KEY_ID)
+	if err != nil {
+		test.Fatalf("Failed to sign launch: '%v'.", err)
+	}
+
+	return idToken
+}
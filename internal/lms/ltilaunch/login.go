@@ -0,0 +1,173 @@
+package ltilaunch
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// How long a platform has to complete a launch after the login is initiated.
+const LOGIN_LIFETIME_MSECS = int64(10 * 60 * 1000)
+
+// The parameters of an OIDC login initiation from a platform.
+type LoginRequest struct {
+	Issuer        string
+	LoginHint     string
+	TargetLinkURI string
+
+	// Optional parameters.
+	MessageHint  string
+	ClientID     string
+	DeploymentID string
+
+	// The host and path of this server's launch endpoint.
+	// The target link URI must point to this endpoint, since the platform sends the launch (and its token) there.
+	LaunchHost string
+	LaunchPath string
+}
+
+// A login that is waiting for its launch, keyed by state.
+type pendingLogin struct {
+	Issuer   string
+	ClientID string
+	Nonce    string
+	Expiry   timestamp.Timestamp
+}
+
+var pendingLogins map[string]*pendingLogin = make(map[string]*pendingLogin)
+var pendingLoginsLock sync.Mutex
+
+// Handle an OIDC login initiation (the first step of an LTI launch).
+// Returns the URL (on the platform) to redirect the user to and the login's state,
+// the platform will then send the launch to this server's launch endpoint.
+// The state should be bound to the user's browser (e.g., in a cookie) and checked on launch.
+func Login(request *LoginRequest) (string, string, error) {
+	if request.Issuer == "" {
+		return "", "", fmt.Errorf("LTI login is missing an issuer (iss).")
+	}
+
+	if request.LoginHint == "" {
+		return "", "", fmt.Errorf("LTI login is missing a login hint (login_hint).")
+	}
+
+	if request.TargetLinkURI == "" {
+		return "", "", fmt.Errorf("LTI login is missing a target link URI (target_link_uri).")
+	}
+
+	redirectURI, err := getRedirectURI(request)
+	if err != nil {
+		return "", "", err
+	}
+
+	courses, err := getPlatformCourses(request.Issuer, request.ClientID, request.DeploymentID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(courses) == 0 {
+		return "", "", fmt.Errorf("No course accepts LTI launches from issuer '%s' (client ID: '%s').", request.Issuer, request.ClientID)
+	}
+
+	// All courses for the same client share a registration, so any of them can be used.
+	platform := getPlatform(courses[0])
+	for _, course := range courses[1:] {
+		if getPlatform(course).ClientID != platform.ClientID {
+			return "", "", fmt.Errorf("Multiple clients are registered for issuer '%s', the platform must send a client ID (client_id).", request.Issuer)
+		}
+	}
+
+	authURL, err := url.Parse(platform.AuthURL)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to parse LTI auth URL '%s': '%w'.", platform.AuthURL, err)
+	}
+
+	state := util.UUID()
+	nonce := util.UUID()
+
+	addPendingLogin(state, &pendingLogin{
+		Issuer:   platform.Issuer,
+		ClientID: platform.ClientID,
+		Nonce:    nonce,
+		Expiry:   timestamp.FromMSecs(timestamp.Now().ToMSecs() + LOGIN_LIFETIME_MSECS),
+	})
+
+	query := authURL.Query()
+	query.Set("scope", "openid")
+	query.Set("response_type", "id_token")
+	query.Set("response_mode", "form_post")
+	query.Set("prompt", "none")
+	query.Set("client_id", platform.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("login_hint", request.LoginHint)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+
+	if request.MessageHint != "" {
+		query.Set("lti_message_hint", request.MessageHint)
+	}
+
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), state, nil
+}
+
+// Get the URI the platform should send the launch to.
+// This is the login's target link URI, which must point to this server's launch endpoint.
+func getRedirectURI(request *LoginRequest) (string, error) {
+	target, err := url.Parse(request.TargetLinkURI)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse LTI target link URI '%s': '%w'.", request.TargetLinkURI, err)
+	}
+
+	if ((target.Scheme != "https") && (target.Scheme != "http")) || (target.User != nil) ||
+		!strings.EqualFold(target.Host, request.LaunchHost) || (target.Path != request.LaunchPath) {
+		return "", fmt.Errorf("LTI target link URI '%s' is not this server's launch endpoint.", request.TargetLinkURI)
+	}
+
+	redirectURI := url.URL{
+		Scheme: target.Scheme,
+		Host:   target.Host,
+		Path:   target.Path,
+	}
+
+	return redirectURI.String(), nil
+}
+
+func addPendingLogin(state string, login *pendingLogin) {
+	pendingLoginsLock.Lock()
+	defer pendingLoginsLock.Unlock()
+
+	// Clear out any logins that were never completed.
+	now := timestamp.Now()
+	for key, value := range pendingLogins {
+		if value.Expiry < now {
+			delete(pendingLogins, key)
+		}
+	}
+
+	pendingLogins[state] = login
+}
+
+// Get (and remove) the pending login for a state.
+// Returns nil if the state is unknown or expired.
+func takePendingLogin(state string) *pendingLogin {
+	pendingLoginsLock.Lock()
+	defer pendingLoginsLock.Unlock()
+
+	login, ok := pendingLogins[state]
+	if !ok {
+		return nil
+	}
+
+	delete(pendingLogins, state)
+
+	if login.Expiry < timestamp.Now() {
+		return nil
+	}
+
+	return login
+}
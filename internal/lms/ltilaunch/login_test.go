@@ -0,0 +1,107 @@
+package ltilaunch
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+)
+
+func TestLogin(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setupTestCourse()
+
+	request := &LoginRequest{
+		Issuer:        TEST_ISSUER,
+		LoginHint:     "hint-1234",
+		TargetLinkURI: TEST_TARGET_URI + "?extra=param",
+		MessageHint:   "message-hint",
+		LaunchHost:    TEST_LAUNCH_HOST,
+		LaunchPath:    TEST_LAUNCH_PATH,
+	}
+
+	target, state, err := Login(request)
+	if err != nil {
+		test.Fatalf("Failed to login: '%v'.", err)
+	}
+
+	parsed, err := url.Parse(target)
+	if err != nil {
+		test.Fatalf("Failed to parse login target '%s': '%v'.", target, err)
+	}
+
+	query := parsed.Query()
+
+	if query.Get("state") != state {
+		test.Fatalf("Unexpected state. Expected: '%s', Actual: '%s'.", state, query.Get("state"))
+	}
+
+	parsed.RawQuery = ""
+	if parsed.String() != TEST_AUTH_URL {
+		test.Fatalf("Unexpected login target. Expected: '%s', Actual: '%s'.", TEST_AUTH_URL, target)
+	}
+
+	expected := map[string]string{
+		"scope":            "openid",
+		"response_type":    "id_token",
+		"response_mode":    "form_post",
+		"prompt":           "none",
+		"client_id":        TEST_CLIENT_ID,
+		"redirect_uri":     TEST_TARGET_URI,
+		"login_hint":       "hint-1234",
+		"lti_message_hint": "message-hint",
+	}
+
+	for key, value := range expected {
+		if query.Get(key) != value {
+			test.Errorf("Unexpected value for '%s'. Expected: '%s', Actual: '%s'.", key, value, query.Get(key))
+		}
+	}
+
+	login := takePendingLogin(query.Get("state"))
+	if login == nil {
+		test.Fatalf("Could not find pending login.")
+	}
+
+	if login.Nonce != query.Get("nonce") {
+		test.Fatalf("Unexpected nonce. Expected: '%s', Actual: '%s'.", query.Get("nonce"), login.Nonce)
+	}
+
+	// A state can only be used once.
+	if takePendingLogin(query.Get("state")) != nil {
+		test.Fatalf("Pending login was not removed.")
+	}
+}
+
+func TestLoginErrors(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setupTestCourse()
+
+	testCases := []*LoginRequest{
+		&LoginRequest{LoginHint: "hint", TargetLinkURI: TEST_TARGET_URI},
+		&LoginRequest{Issuer: TEST_ISSUER, TargetLinkURI: TEST_TARGET_URI},
+		&LoginRequest{Issuer: TEST_ISSUER, LoginHint: "hint"},
+		&LoginRequest{Issuer: "https://other.test.com", LoginHint: "hint", TargetLinkURI: TEST_TARGET_URI},
+		&LoginRequest{Issuer: TEST_ISSUER, LoginHint: "hint", TargetLinkURI: TEST_TARGET_URI, ClientID: "other"},
+
+		// The target link URI is not this server's launch endpoint.
+		&LoginRequest{Issuer: TEST_ISSUER, LoginHint: "hint", TargetLinkURI: "https://evil.test.com" + TEST_LAUNCH_PATH},
+		&LoginRequest{Issuer: TEST_ISSUER, LoginHint: "hint", TargetLinkURI: "https://" + TEST_LAUNCH_HOST + "/api/v03/lti/other"},
+		&LoginRequest{Issuer: TEST_ISSUER, LoginHint: "hint", TargetLinkURI: "https://user@" + TEST_LAUNCH_HOST + TEST_LAUNCH_PATH},
+		&LoginRequest{Issuer: TEST_ISSUER, LoginHint: "hint", TargetLinkURI: "javascript://" + TEST_LAUNCH_HOST + TEST_LAUNCH_PATH},
+	}
+
+	for i, testCase := range testCases {
+		if testCase.LaunchHost == "" {
+			testCase.LaunchHost = TEST_LAUNCH_HOST
+			testCase.LaunchPath = TEST_LAUNCH_PATH
+		}
+
+		_, _, err := Login(testCase)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package ltilaunch
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms/backend/lti"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	TEST_ISSUER         = "https://lti.test.com"
+	TEST_CLIENT_ID      = "10000000000001"
+	TEST_KEY_ID         = "platform-key-01"
+	TEST_AUTH_URL       = TEST_ISSUER + "/api/lti/authorize_redirect"
+	TEST_LINE_ITEMS_URL = TEST_ISSUER + "/api/lti/courses/12345/line_items"
+	TEST_LINE_ITEM_URL  = TEST_LINE_ITEMS_URL + "/98765"
+	TEST_LAUNCH_HOST    = "autograder.test.com"
+	TEST_LAUNCH_PATH    = "/api/v03/lti/launch"
+	TEST_TARGET_URI     = "https://" + TEST_LAUNCH_HOST + TEST_LAUNCH_PATH
+)
+
+var jwksServer *httptest.Server
+
+// The key the (fake) platform signs launches with.
+var platformKey *rsa.PrivateKey
+
+func TestMain(suite *testing.M) {
+	var err error
+
+	// Run inside a func so defers will run before os.Exit().
+	code := func() int {
+		db.PrepForTestingMain()
+		defer db.CleanupTestingMain()
+
+		platformKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+
+		jwks := util.MustToJSON(getTestJWKS(&platformKey.PublicKey, TEST_KEY_ID))
+		jwksServer = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Write([]byte(jwks))
+		}))
+		defer jwksServer.Close()
+
+		return suite.Run()
+	}()
+
+	os.Exit(code)
+}
+
+// Make course101 accept launches from the test platform,
+// and link hw0 to the test line item.
+func setupTestCourse() *model.Course {
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(platformKey)
+	if err != nil {
+		panic(err)
+	}
+
+	course := db.MustGetCourse("course101")
+	course.LMS = &model.LMSAdapter{
+		Type: model.LMS_TYPE_LTI,
+		LTI: &model.LTIPlatformInfo{
+			ClientID:     TEST_CLIENT_ID,
+			TokenURL:     TEST_ISSUER + "/login/oauth2/token",
+			PrivateKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})),
+			LineItemsURL: TEST_LINE_ITEMS_URL,
+			Issuer:       TEST_ISSUER,
+			AuthURL:      TEST_AUTH_URL,
+			JWKSURL:      jwksServer.URL,
+		},
+	}
+
+	course.GetAssignment("hw0").LMSID = TEST_LINE_ITEM_URL
+
+	db.MustSaveCourse(course)
+
+	return course
+}
+
+// Get the claims of a standard launch (with the given nonce).
+func getTestClaims(nonce string, email string, roles []string) map[string]any {
+	nowSecs := timestamp.Now().ToMSecs() / 1000
+
+	return map[string]any{
+		"iss":                   TEST_ISSUER,
+		"sub":                   "lms-" + email,
+		"aud":                   TEST_CLIENT_ID,
+		"iat":                   nowSecs,
+		"exp":                   nowSecs + 300,
+		"nonce":                 nonce,
+		"email":                 email,
+		"name":                  email,
+		lti.CLAIM_MESSAGE_TYPE:  lti.MESSAGE_TYPE_RESOURCE_LINK,
+		lti.CLAIM_VERSION:       lti.LTI_VERSION,
+		lti.CLAIM_DEPLOYMENT_ID: "1:abc",
+		lti.CLAIM_ROLES:         roles,
+		lti.CLAIM_ENDPOINT: map[string]any{
+			"lineitems": TEST_LINE_ITEMS_URL,
+			"lineitem":  TEST_LINE_ITEM_URL,
+		},
+	}
+}
+
+// Get a JWKS (as would be served by a platform) for a key.
+func getTestJWKS(key *rsa.PublicKey, keyID string) map[string]any {
+	return map[string]any{
+		"keys": []map[string]any{
+			map[string]any{
+				"kty": "RSA",
+				"kid": keyID,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			},
+		},
+	}
+}
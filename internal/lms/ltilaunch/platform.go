@@ -0,0 +1,61 @@
+package ltilaunch
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Get all courses (sorted by ID) that accept launches from the given issuer.
+// |clientID| and |deploymentID| are only checked when not empty.
+func getPlatformCourses(issuer string, clientID string, deploymentID string) ([]*model.Course, error) {
+	courses, err := db.GetCourses()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get courses: '%w'.", err)
+	}
+
+	results := make([]*model.Course, 0)
+	for _, course := range courses {
+		platform := getPlatform(course)
+		if platform == nil {
+			continue
+		}
+
+		if platform.Issuer != issuer {
+			continue
+		}
+
+		if (clientID != "") && (platform.ClientID != clientID) {
+			continue
+		}
+
+		if (deploymentID != "") && (platform.DeploymentID != "") && (platform.DeploymentID != deploymentID) {
+			continue
+		}
+
+		results = append(results, course)
+	}
+
+	slices.SortFunc(results, func(a *model.Course, b *model.Course) int {
+		return strings.Compare(a.GetID(), b.GetID())
+	})
+
+	return results, nil
+}
+
+// Get a course's LTI platform if the course accepts launches.
+func getPlatform(course *model.Course) *model.LTIPlatformInfo {
+	adapter := course.GetLMSAdapter()
+	if (adapter == nil) || (adapter.Type != model.LMS_TYPE_LTI) || (adapter.LTI == nil) {
+		return nil
+	}
+
+	if !adapter.LTI.LaunchEnabled() {
+		return nil
+	}
+
+	return adapter.LTI
+}
@@ -27,11 +27,34 @@ type Assignment struct {
 	DueDate   *timestamp.Timestamp `json:"due-date,omitempty"`
 	MaxPoints float64              `json:"max-points,omitempty"`
 
+	// Students cannot see or submit to an assignment before its open date,
+	// and cannot submit (even late) after its close date.
+	// Hidden assignments are only visible to course staff (graders and above).
+	OpenDate  *timestamp.Timestamp `json:"open-date,omitempty"`
+	CloseDate *timestamp.Timestamp `json:"close-date,omitempty"`
+	Hidden    bool                 `json:"hidden,omitempty"`
+
 	LMSID      string             `json:"lms-id,omitempty"`
 	LatePolicy *LateGradingPolicy `json:"late-policy,omitempty"`
 
 	SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
 
+	// Which submission counts (is scored) for each user (defaults to the most recent submission).
+	SubmissionSelection SubmissionSelectionPolicy `json:"submission-selection,omitempty"`
+
+	// What feedback students see on their submissions (defaults to full feedback).
+	FeedbackPolicy *FeedbackPolicy `json:"feedback-policy,omitempty"`
+
+	// Rules for the files in a submission (checked before grading).
+	SubmissionFiles *SubmissionFilesInfo `json:"submission-files,omitempty"`
+
+	// Parts of the assignment that are graded by hand.
+	// Manual scores are added to the autograder score before any late policy is applied.
+	ManualRubric []*RubricItem `json:"manual-rubric,omitempty"`
+
+	// When set, students submit to this assignment as teams.
+	Teams *TeamInfo `json:"teams,omitempty"`
+
 	docker.ImageInfo
 
 	// Ignore these fields in JSON.
@@ -119,6 +142,11 @@ func (this *Assignment) Validate() error {
 		return fmt.Errorf("Max points cannot be negative: %f.", this.MaxPoints)
 	}
 
+	err = this.validateReleaseWindow()
+	if err != nil {
+		return err
+	}
+
 	this.imageLock = &sync.Mutex{}
 
 	// Inherit submission limit from course or leave nil.
@@ -133,6 +161,25 @@ func (this *Assignment) Validate() error {
 		}
 	}
 
+	this.SubmissionSelection, err = ValidateSubmissionSelectionPolicy(this.SubmissionSelection)
+	if err != nil {
+		return err
+	}
+
+	if this.FeedbackPolicy != nil {
+		err = this.FeedbackPolicy.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate feedback policy: '%w'.", err)
+		}
+	}
+
+	if this.SubmissionFiles != nil {
+		err = this.SubmissionFiles.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate submission files: '%w'.", err)
+		}
+	}
+
 	// Inherit late policy from course or default to empty.
 	if this.LatePolicy == nil {
 		if this.Course.LatePolicy != nil {
@@ -147,6 +194,18 @@ func (this *Assignment) Validate() error {
 		return fmt.Errorf("Failed to validate late policy: '%w'.", err)
 	}
 
+	err = validateRubric(this.ManualRubric)
+	if err != nil {
+		return fmt.Errorf("Failed to validate manual rubric: '%w'.", err)
+	}
+
+	if this.Teams != nil {
+		err = this.Teams.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate teams: '%w'.", err)
+		}
+	}
+
 	if this.RelSourceDir == "" {
 		return fmt.Errorf("Relative source dir must not be empty.")
 	}
@@ -173,9 +232,84 @@ func (this *Assignment) Validate() error {
 		this.ImageInfo.MaxRuntimeSecs = systemMaxRuntimeSecs
 	}
 
+	limits := &this.ImageInfo.ResourceLimits
+	limits.MaxMemoryMB = clampResourceLimit(this, "memory-mb", limits.MaxMemoryMB, config.GRADING_MEMORY_MAX_MB.Get())
+	limits.MaxCPUs = clampResourceLimit(this, "cpus", limits.MaxCPUs, config.GRADING_CPUS_MAX.Get())
+	limits.MaxPIDs = clampResourceLimit(this, "pids", limits.MaxPIDs, config.GRADING_PIDS_MAX.Get())
+	limits.MaxTmpfsMB = clampResourceLimit(this, "tmpfs-mb", limits.MaxTmpfsMB, config.GRADING_TMPFS_MAX_MB.Get())
+	limits.MaxOutputMB = clampResourceLimit(this, "output-mb", limits.MaxOutputMB, config.GRADING_OUTPUT_MAX_MB.Get())
+
+	return nil
+}
+
+func (this *Assignment) validateReleaseWindow() error {
+	if (this.OpenDate != nil) && (this.CloseDate != nil) && (*this.CloseDate <= *this.OpenDate) {
+		return fmt.Errorf("Close date (%s) must be after open date (%s).", this.CloseDate.SafeMessage(), this.OpenDate.SafeMessage())
+	}
+
+	if (this.OpenDate != nil) && (this.DueDate != nil) && (*this.DueDate <= *this.OpenDate) {
+		return fmt.Errorf("Due date (%s) must be after open date (%s).", this.DueDate.SafeMessage(), this.OpenDate.SafeMessage())
+	}
+
+	if (this.DueDate != nil) && (this.CloseDate != nil) && (*this.CloseDate < *this.DueDate) {
+		return fmt.Errorf("Close date (%s) cannot be before due date (%s).", this.CloseDate.SafeMessage(), this.DueDate.SafeMessage())
+	}
+
 	return nil
 }
 
+// Check if students can see this assignment at the given time.
+// Course staff (graders and above) can always see every assignment.
+func (this *Assignment) IsVisible(now timestamp.Timestamp) bool {
+	if this.Hidden {
+		return false
+	}
+
+	return (this.OpenDate == nil) || (now >= *this.OpenDate)
+}
+
+// Clamp a resource limit to the server's limit (values over the server limit are lowered).
+// Unset values are left unset (so the assignment's config still shows which limits it sets),
+// see GetEffectiveResourceLimits() for the limits that include the server's defaults.
+// A non-positive server limit means that the server does not limit this resource.
+func clampResourceLimit[T int | float64](assignment *Assignment, name string, value T, serverMax T) T {
+	if serverMax <= 0 {
+		return value
+	}
+
+	if value > serverMax {
+		log.Warn("Specified grading resource limit is greater than the limit allowed by the server, lowering assignment limit.",
+			assignment, log.NewAttr("resource", name),
+			log.NewAttr("assignment-limit", value), log.NewAttr("server-limit", serverMax))
+		return serverMax
+	}
+
+	return value
+}
+
+// Get the resource limits to grade this assignment in a container with:
+// the assignment's limits, with any unset limit replaced by the server's limit.
+// Non-docker graders only get the limits that the assignment actually sets.
+func (this *Assignment) GetEffectiveResourceLimits() *docker.ResourceLimits {
+	limits := this.ImageInfo.ResourceLimits
+
+	limits.MaxMemoryMB = defaultResourceLimit(limits.MaxMemoryMB, config.GRADING_MEMORY_MAX_MB.Get())
+	limits.MaxCPUs = defaultResourceLimit(limits.MaxCPUs, config.GRADING_CPUS_MAX.Get())
+	limits.MaxPIDs = defaultResourceLimit(limits.MaxPIDs, config.GRADING_PIDS_MAX.Get())
+	limits.MaxTmpfsMB = defaultResourceLimit(limits.MaxTmpfsMB, config.GRADING_TMPFS_MAX_MB.Get())
+	limits.MaxOutputMB = defaultResourceLimit(limits.MaxOutputMB, config.GRADING_OUTPUT_MAX_MB.Get())
+
+	return &limits
+}
+
+func defaultResourceLimit[T int | float64](value T, serverMax T) T {
+	if (value == 0) && (serverMax > 0) {
+		return serverMax
+	}
+
+	return value
+}
+
 func (this *Assignment) GetCacheDir() string {
 	dir := filepath.Join(this.Course.GetCacheDir(), "assignment_"+this.ID)
 	util.MkDir(dir)
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Rules for the files in a submission.
+// Submissions that break these rules are rejected before they are graded.
+// Patterns are globs (see path.Match).
+// A pattern that contains a slash is matched against a file's full path (relative to the submission),
+// while a pattern without a slash is matched against a file's base name.
+type SubmissionFilesInfo struct {
+	// Paths (relative to the submission) that every submission must contain.
+	Required []string `json:"required,omitempty"`
+
+	// When set, every file in a submission must match at least one of these patterns.
+	Allowed []string `json:"allowed,omitempty"`
+
+	// No file in a submission may match any of these patterns.
+	Forbidden []string `json:"forbidden,omitempty"`
+
+	// The max total size (in KB) of all the files in a submission (zero means there is no limit).
+	MaxSizeKB int64 `json:"max-size-kb,omitempty"`
+}
+
+func (this *SubmissionFilesInfo) Validate() error {
+	if this.MaxSizeKB < 0 {
+		return fmt.Errorf("Max submission size cannot be negative: %d.", this.MaxSizeKB)
+	}
+
+	for i, required := range this.Required {
+		required = path.Clean(filepath.ToSlash(strings.TrimSpace(required)))
+		if (required == ".") || escapesSubmission(required) {
+			return fmt.Errorf("Required file at index %d must be a relative path inside the submission, found '%s'.", i, this.Required[i])
+		}
+
+		this.Required[i] = required
+	}
+
+	for _, patterns := range [][]string{this.Allowed, this.Forbidden} {
+		for i, pattern := range patterns {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				return fmt.Errorf("File pattern at index %d is empty.", i)
+			}
+
+			_, err := path.Match(pattern, "")
+			if err != nil {
+				return fmt.Errorf("Invalid file pattern '%s': '%w'.", pattern, err)
+			}
+
+			if escapesSubmission(path.Clean(filepath.ToSlash(pattern))) {
+				return fmt.Errorf("File pattern '%s' must be a relative path inside the submission.", pattern)
+			}
+
+			patterns[i] = pattern
+		}
+	}
+
+	return nil
+}
+
+// Check if a cleaned (slash-separated) path points outside of the submission.
+func escapesSubmission(cleaned string) bool {
+	return (cleaned == "..") || strings.HasPrefix(cleaned, "../") || path.IsAbs(cleaned)
+}
+
+// Check the files in a submission directory against these rules.
+// Returns a description of every problem found (or an empty slice if the submission is fine).
+func (this *SubmissionFilesInfo) Check(submissionDir string) ([]string, error) {
+	problems := make([]string, 0)
+
+	files := make(map[string]bool)
+	var totalSize int64 = 0
+
+	err := filepath.WalkDir(submissionDir, func(fullPath string, dirent fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if dirent.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(submissionDir, fullPath)
+		if err != nil {
+			return err
+		}
+
+		relPath = filepath.ToSlash(relPath)
+		files[relPath] = true
+
+		if dirent.Type().IsRegular() {
+			info, err := dirent.Info()
+			if err != nil {
+				return err
+			}
+
+			totalSize += info.Size()
+		}
+
+		if (len(this.Allowed) > 0) && !matchesAnyFilePattern(this.Allowed, relPath) {
+			problems = append(problems, fmt.Sprintf("File '%s' is not allowed (allowed files: %s).", relPath, strings.Join(this.Allowed, ", ")))
+		}
+
+		for _, pattern := range this.Forbidden {
+			if matchesFilePattern(pattern, relPath) {
+				problems = append(problems, fmt.Sprintf("File '%s' is forbidden (matches '%s').", relPath, pattern))
+				break
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to walk submission dir '%s': '%w'.", submissionDir, err)
+	}
+
+	for _, required := range this.Required {
+		if !files[required] {
+			problems = append(problems, fmt.Sprintf("Required file '%s' is missing.", required))
+		}
+	}
+
+	if (this.MaxSizeKB > 0) && (totalSize > (this.MaxSizeKB * 1024)) {
+		problems = append(problems, fmt.Sprintf("Submission is too large (%d KB), the max size is %d KB.", (totalSize+1023)/1024, this.MaxSizeKB))
+	}
+
+	return problems, nil
+}
+
+func matchesAnyFilePattern(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if matchesFilePattern(pattern, relPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesFilePattern(pattern string, relPath string) bool {
+	target := relPath
+	if !strings.Contains(pattern, "/") {
+		target = path.Base(relPath)
+	}
+
+	// Patterns were checked during validation.
+	match, _ := path.Match(pattern, target)
+	return match
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSubmissionFilesInfoValidate(test *testing.T) {
+	testCases := []struct {
+		input    *SubmissionFilesInfo
+		expected *SubmissionFilesInfo
+		hasError bool
+	}{
+		{&SubmissionFilesInfo{}, &SubmissionFilesInfo{}, false},
+		{
+			&SubmissionFilesInfo{Required: []string{" ./a.py ", "src/../b.py"}, Allowed: []string{" *.py "}, Forbidden: []string{"*.zip"}, MaxSizeKB: 10},
+			&SubmissionFilesInfo{Required: []string{"a.py", "b.py"}, Allowed: []string{"*.py"}, Forbidden: []string{"*.zip"}, MaxSizeKB: 10},
+			false,
+		},
+
+		{&SubmissionFilesInfo{MaxSizeKB: -1}, nil, true},
+		{&SubmissionFilesInfo{Required: []string{"."}}, nil, true},
+		{&SubmissionFilesInfo{Required: []string{"../a.py"}}, nil, true},
+		{&SubmissionFilesInfo{Required: []string{".."}}, nil, true},
+		{&SubmissionFilesInfo{Required: []string{"src/../.."}}, nil, true},
+		{&SubmissionFilesInfo{Allowed: []string{".."}}, nil, true},
+		{&SubmissionFilesInfo{Allowed: []string{"../*.py"}}, nil, true},
+		{&SubmissionFilesInfo{Allowed: []string{"/*.py"}}, nil, true},
+		{&SubmissionFilesInfo{Required: []string{"/a.py"}}, nil, true},
+		{&SubmissionFilesInfo{Allowed: []string{" "}}, nil, true},
+		{&SubmissionFilesInfo{Forbidden: []string{"["}}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.input.Validate()
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, testCase.input) {
+			test.Errorf("Case %d: Unexpected result. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(testCase.input))
+		}
+	}
+}
+
+func TestSubmissionFilesInfoCheck(test *testing.T) {
+	tempDir := util.MustMkDirTemp("test-submission-files-")
+	defer util.RemoveDirent(tempDir)
+
+	files := map[string]string{
+		"main.py":         "print('hi')",
+		"src/util.py":     "pass",
+		"src/data.bin":    strings.Repeat("0", 2048),
+		"notes/README.md": "notes",
+	}
+
+	for relPath, contents := range files {
+		path := filepath.Join(tempDir, relPath)
+		err := util.MkDir(filepath.Dir(path))
+		if err != nil {
+			test.Fatalf("Failed to make test dir: '%v'.", err)
+		}
+
+		err = os.WriteFile(path, []byte(contents), 0644)
+		if err != nil {
+			test.Fatalf("Failed to write test file '%s': '%v'.", path, err)
+		}
+	}
+
+	testCases := []struct {
+		info     *SubmissionFilesInfo
+		expected []string
+	}{
+		{&SubmissionFilesInfo{}, []string{}},
+		{&SubmissionFilesInfo{Required: []string{"main.py", "src/util.py"}}, []string{}},
+		{&SubmissionFilesInfo{Allowed: []string{"*.py", "*.bin", "notes/*"}}, []string{}},
+		{&SubmissionFilesInfo{MaxSizeKB: 3}, []string{}},
+
+		{&SubmissionFilesInfo{Required: []string{"main.py", "test.py"}}, []string{
+			"Required file 'test.py' is missing.",
+		}},
+		{&SubmissionFilesInfo{Allowed: []string{"*.py"}}, []string{
+			"File 'notes/README.md' is not allowed (allowed files: *.py).",
+			"File 'src/data.bin' is not allowed (allowed files: *.py).",
+		}},
+		{&SubmissionFilesInfo{Forbidden: []string{"*.bin", "src/*"}}, []string{
+			"File 'src/data.bin' is forbidden (matches '*.bin').",
+			"File 'src/util.py' is forbidden (matches 'src/*').",
+		}},
+		{&SubmissionFilesInfo{MaxSizeKB: 1}, []string{
+			"Submission is too large (3 KB), the max size is 1 KB.",
+		}},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.info.Validate()
+		if err != nil {
+			test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			continue
+		}
+
+		problems, err := testCase.info.Check(tempDir)
+		if err != nil {
+			test.Errorf("Case %d: Failed to check files: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, problems) {
+			test.Errorf("Case %d: Unexpected problems. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(problems))
+		}
+	}
+}
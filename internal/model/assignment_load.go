@@ -46,3 +46,32 @@ func ReadAssignmentConfig(course *Course, path string, relSourceDir string) (*As
 
 	return &assignment, nil
 }
+
+// Load an assignment config from its JSON representation and add it to the given course.
+// This is used by database backends that do not store configs as files,
+// so the assignment is expected to already have a relative source dir.
+func ReadAssignmentConfigFromJSON(course *Course, data []byte) (*Assignment, error) {
+	if course == nil {
+		return nil, fmt.Errorf("Cannot load an assignment without a course.")
+	}
+
+	var assignment Assignment
+	err := util.JSONFromBytes(data, &assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Could not load assignment config from JSON: '%w'.", err)
+	}
+
+	assignment.Course = course
+
+	err = assignment.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to validate assignment config ('%s'): '%w'.", assignment.ID, err)
+	}
+
+	err = course.AddAssignment(&assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add assignment to course ('%s'): '%w'.", assignment.ID, err)
+	}
+
+	return &assignment, nil
+}
@@ -4,11 +4,27 @@ import (
 	"fmt"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
+// A submission is rejected if any of the specified limits trigger.
 type SubmissionLimitInfo struct {
 	Max    *int                   `json:"max-attempts"`
 	Window *SubmittionLimitWindow `json:"window,omitempty"`
+
+	// Additional sliding windows (every window is checked).
+	Windows []*SubmittionLimitWindow `json:"windows,omitempty"`
+
+	// The max number of submissions per calendar day (in the course's timezone).
+	// Zero means there is no daily limit.
+	DailyMax int `json:"daily-max-attempts,omitempty"`
+
+	// The minimum amount of time between submissions.
+	Cooldown *common.DurationSpec `json:"cooldown,omitempty"`
+
+	// Limits that replace these limits after the user's due date.
+	// These limits only count submissions made after the due date.
+	AfterDue *SubmissionLimitInfo `json:"after-due,omitempty"`
 }
 
 type SubmittionLimitWindow struct {
@@ -29,9 +45,63 @@ func (this *SubmissionLimitInfo) Validate() error {
 		}
 	}
 
+	for i, window := range this.Windows {
+		if window == nil {
+			return fmt.Errorf("Submission limit window at index %d is empty.", i)
+		}
+
+		err := window.Validate()
+		if err != nil {
+			return fmt.Errorf("Submission limit window at index %d is invalid: '%w'.", i, err)
+		}
+	}
+
+	if this.DailyMax < 0 {
+		return fmt.Errorf("Submission limit daily max attempts cannot be negative, found %d.", this.DailyMax)
+	}
+
+	if this.Cooldown != nil {
+		err := this.Cooldown.Validate()
+		if err != nil {
+			return fmt.Errorf("Submission limit has invalid cooldown: '%w'.", err)
+		}
+	}
+
+	if this.AfterDue != nil {
+		if this.AfterDue.AfterDue != nil {
+			return fmt.Errorf("Submission limit after the due date cannot have its own after due date limit.")
+		}
+
+		err := this.AfterDue.Validate()
+		if err != nil {
+			return fmt.Errorf("Submission limit after the due date is invalid: '%w'.", err)
+		}
+	}
+
 	return nil
 }
 
+// Get all the sliding windows for this limit.
+func (this *SubmissionLimitInfo) GetWindows() []*SubmittionLimitWindow {
+	windows := make([]*SubmittionLimitWindow, 0, len(this.Windows)+1)
+
+	if this.Window != nil {
+		windows = append(windows, this.Window)
+	}
+
+	return append(windows, this.Windows...)
+}
+
+// Get the limit that is active at the given time for a user with the given due date (which may be nil).
+// Returns the limit and if it is the after due date limit.
+func (this *SubmissionLimitInfo) GetActiveLimit(dueDate *timestamp.Timestamp, now timestamp.Timestamp) (*SubmissionLimitInfo, bool) {
+	if (this.AfterDue == nil) || (dueDate == nil) || (now <= *dueDate) {
+		return this, false
+	}
+
+	return this.AfterDue, true
+}
+
 func (this SubmittionLimitWindow) Validate() error {
 	err := this.Duration.Validate()
 	if err != nil {
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Unset limits get the server's limit, but only in the effective limits (not in the assignment).
+func TestAssignmentGetEffectiveResourceLimits(test *testing.T) {
+	oldMemory := config.GRADING_MEMORY_MAX_MB.Get()
+	oldOutput := config.GRADING_OUTPUT_MAX_MB.Get()
+	defer config.GRADING_MEMORY_MAX_MB.Set(oldMemory)
+	defer config.GRADING_OUTPUT_MAX_MB.Set(oldOutput)
+
+	config.GRADING_MEMORY_MAX_MB.Set(100)
+	config.GRADING_OUTPUT_MAX_MB.Set(0)
+
+	assignment := &Assignment{ID: "test"}
+	assignment.MaxPIDs = 5
+
+	actual := assignment.GetEffectiveResourceLimits()
+	if (actual.MaxMemoryMB != 100) || (actual.MaxOutputMB != 0) || (actual.MaxPIDs != 5) {
+		test.Fatalf("Unexpected effective limits: '%+v'.", actual)
+	}
+
+	if assignment.MaxMemoryMB != 0 {
+		test.Fatalf("Assignment limits were modified: '%+v'.", assignment.ResourceLimits)
+	}
+}
+
+func TestClampResourceLimit(test *testing.T) {
+	assignment := &Assignment{ID: "test"}
+
+	testCases := []struct {
+		value     int
+		serverMax int
+		expected  int
+	}{
+		// No server limit.
+		{0, 0, 0},
+		{10, 0, 10},
+		{10, -1, 10},
+
+		// Under the server limit.
+		{5, 10, 5},
+		{10, 10, 10},
+
+		// Over the server limit.
+		{11, 10, 10},
+
+		// Unset values stay unset.
+		{0, 10, 0},
+	}
+
+	for i, testCase := range testCases {
+		actual := clampResourceLimit(assignment, "test", testCase.value, testCase.serverMax)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected limit. Expected: %d, Actual: %d.", i, testCase.expected, actual)
+		}
+	}
+
+	// Float limits work the same way.
+	actual := clampResourceLimit(assignment, "test", 1.5, 1.0)
+	if actual != 1.0 {
+		test.Errorf("Unexpected float limit. Expected: %f, Actual: %f.", 1.0, actual)
+	}
+}
+
+func TestAssignmentReleaseWindow(test *testing.T) {
+	early := timestamp.FromMSecs(1000)
+	middle := timestamp.FromMSecs(2000)
+	late := timestamp.FromMSecs(3000)
+
+	testCases := []struct {
+		openDate  *timestamp.Timestamp
+		dueDate   *timestamp.Timestamp
+		closeDate *timestamp.Timestamp
+		hidden    bool
+		valid     bool
+		visible   bool
+	}{
+		{nil, nil, nil, false, true, true},
+		{nil, nil, nil, true, true, false},
+
+		{&early, &middle, &late, false, true, true},
+		{&middle, nil, nil, false, true, true},
+		{&late, nil, nil, false, true, false},
+		{&early, &middle, &middle, false, true, true},
+		{&early, nil, &late, true, true, false},
+
+		{&middle, nil, &early, false, false, false},
+		{&middle, nil, &middle, false, false, false},
+		{&middle, &early, nil, false, false, false},
+		{nil, &late, &middle, false, false, false},
+	}
+
+	for i, testCase := range testCases {
+		assignment := &Assignment{
+			ID:        "test",
+			OpenDate:  testCase.openDate,
+			DueDate:   testCase.dueDate,
+			CloseDate: testCase.closeDate,
+			Hidden:    testCase.hidden,
+		}
+
+		err := assignment.validateReleaseWindow()
+		if testCase.valid != (err == nil) {
+			test.Errorf("Case %d: Unexpected validation result. Expected valid: %v, Error: '%v'.", i, testCase.valid, err)
+			continue
+		}
+
+		if !testCase.valid {
+			continue
+		}
+
+		actual := assignment.IsVisible(middle)
+		if testCase.visible != actual {
+			test.Errorf("Case %d: Unexpected visibility. Expected: %v, Actual: %v.", i, testCase.visible, actual)
+		}
+	}
+}
+
+func TestAssignmentEffectiveCloseDate(test *testing.T) {
+	closeDate := timestamp.FromMSecs(2000)
+	early := timestamp.FromMSecs(1000)
+	late := timestamp.FromMSecs(3000)
+
+	assignment := &Assignment{ID: "test", CloseDate: &closeDate}
+
+	testCases := []struct {
+		extension *Extension
+		expected  timestamp.Timestamp
+	}{
+		{nil, closeDate},
+		{&Extension{}, closeDate},
+		{&Extension{DueDate: &early}, closeDate},
+		{&Extension{DueDate: &late}, late},
+	}
+
+	for i, testCase := range testCases {
+		actual := assignment.GetEffectiveCloseDate(testCase.extension)
+		if (actual == nil) || (testCase.expected != *actual) {
+			test.Errorf("Case %d: Unexpected close date. Expected: %d, Actual: %v.", i, testCase.expected, actual)
+		}
+	}
+}
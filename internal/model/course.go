@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"path/filepath"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/config"
@@ -30,6 +32,11 @@ type Course struct {
 	// A common submission limit that assignments can inherit.
 	SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
 
+	// The IANA name of the timezone the course runs in (e.g., "America/Los_Angeles").
+	// Used for things like daily submission limits.
+	// Defaults to the server's local timezone.
+	Timezone string `json:"timezone,omitempty"`
+
 	// Deprecated
 	Backup        []*dtasks.BackupTask        `json:"backup,omitempty"`
 	CourseUpdate  []*dtasks.CourseUpdateTask  `json:"course-update,omitempty"`
@@ -67,6 +74,21 @@ func (this *Course) GetSource() *common.FileSpec {
 	return this.Source
 }
 
+// Get the course's timezone (or the server's local timezone if one was not set).
+func (this *Course) GetLocation() *time.Location {
+	if this.Timezone == "" {
+		return time.Local
+	}
+
+	location, err := time.LoadLocation(this.Timezone)
+	if err != nil {
+		log.Error("Failed to load course timezone.", err, this, log.NewAttr("timezone", this.Timezone))
+		return time.Local
+	}
+
+	return location
+}
+
 func (this *Course) GetLMSAdapter() *LMSAdapter {
 	return this.LMS
 }
@@ -120,6 +142,14 @@ func (this *Course) Validate() error {
 		}
 	}
 
+	this.Timezone = strings.TrimSpace(this.Timezone)
+	if this.Timezone != "" {
+		_, err = time.LoadLocation(this.Timezone)
+		if err != nil {
+			return fmt.Errorf("Unknown course timezone '%s': '%w'.", this.Timezone, err)
+		}
+	}
+
 	if this.Tasks == nil {
 		this.Tasks = make([]*UserTaskInfo, 0)
 	}
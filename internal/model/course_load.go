@@ -74,3 +74,23 @@ func ReadCourseConfig(path string) (*Course, error) {
 
 	return &course, nil
 }
+
+// Load just the course config from its JSON representation (and validate).
+// This is used by database backends that do not store configs as files.
+// Do not load any assignments or other resources.
+func ReadCourseConfigFromJSON(data []byte) (*Course, error) {
+	var course Course
+	err := util.JSONFromBytes(data, &course)
+	if err != nil {
+		return nil, fmt.Errorf("Could not load course config from JSON: '%w'.", err)
+	}
+
+	course.Assignments = make(map[string]*Assignment)
+
+	err = course.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Could not validate course config ('%s'): '%w'.", course.ID, err)
+	}
+
+	return &course, nil
+}
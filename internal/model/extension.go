@@ -0,0 +1,140 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Team extensions are stored (and keyed) alongside user extensions using this prefix on the team's id.
+// Team ids cannot contain an '@', so these keys never collide with user emails.
+const TEAM_EXTENSION_KEY_PREFIX = "team::"
+
+// An accommodation for a single user (or every member of a team) on an assignment.
+// Exactly one of User or Team is set.
+// Any field that is set replaces the assignment's value for the user (or team members).
+// A user's own extension takes precedence over their team's extension.
+type Extension struct {
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id"`
+	User         string `json:"user,omitempty"`
+	Team         string `json:"team,omitempty"`
+
+	DueDate         *timestamp.Timestamp `json:"due-date,omitempty"`
+	SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
+
+	Author string              `json:"author"`
+	Reason string              `json:"reason,omitempty"`
+	Time   timestamp.Timestamp `json:"time"`
+}
+
+func (this *Extension) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Extension is nil.")
+	}
+
+	if (this.CourseID == "") || (this.AssignmentID == "") || ((this.User == "") && (this.Team == "")) {
+		return fmt.Errorf("Extension is missing a course, assignment, or user/team.")
+	}
+
+	if (this.User != "") && (this.Team != "") {
+		return fmt.Errorf("Extension cannot be for both a user ('%s') and a team ('%s').", this.User, this.Team)
+	}
+
+	if this.Team != "" {
+		var err error
+		this.Team, err = common.ValidateID(this.Team)
+		if err != nil {
+			return fmt.Errorf("Extension has an invalid team: '%w'.", err)
+		}
+	}
+
+	if (this.DueDate == nil) && (this.SubmissionLimit == nil) {
+		return fmt.Errorf("Extension for '%s' does not set a due date or submission limit.", this.GetKey())
+	}
+
+	if this.SubmissionLimit != nil {
+		err := this.SubmissionLimit.Validate()
+		if err != nil {
+			return fmt.Errorf("Extension for '%s' has an invalid submission limit: '%w'.", this.GetKey(), err)
+		}
+	}
+
+	if this.Author == "" {
+		return fmt.Errorf("Extension for '%s' is missing an author.", this.GetKey())
+	}
+
+	this.Reason = strings.TrimSpace(this.Reason)
+
+	if this.Time.IsZero() {
+		this.Time = timestamp.Now()
+	}
+
+	return nil
+}
+
+// Get the key that this extension is stored under: the user's email or the team's key (see GetTeamExtensionKey()).
+func (this *Extension) GetKey() string {
+	if this.Team != "" {
+		return GetTeamExtensionKey(this.Team)
+	}
+
+	return this.User
+}
+
+func GetTeamExtensionKey(teamID string) string {
+	return TEAM_EXTENSION_KEY_PREFIX + teamID
+}
+
+// Get the extension that applies to a user from all the extensions for an assignment (keyed by GetKey()).
+// A user's own extension takes precedence over the extension for their team (which may be nil).
+// Returns nil if no extension applies to the user.
+func ResolveExtension(extensions map[string]*Extension, email string, team *Team) *Extension {
+	extension := extensions[email]
+	if extension != nil {
+		return extension
+	}
+
+	if team == nil {
+		return nil
+	}
+
+	return extensions[GetTeamExtensionKey(team.ID)]
+}
+
+// Get the due date for a user with the given extension (which may be nil).
+// Returns nil if there is no due date.
+func (this *Assignment) GetEffectiveDueDate(extension *Extension) *timestamp.Timestamp {
+	if (extension != nil) && (extension.DueDate != nil) {
+		return extension.DueDate
+	}
+
+	return this.DueDate
+}
+
+// Get the submission limit for a user with the given extension (which may be nil).
+// Returns nil if there is no limit.
+func (this *Assignment) GetEffectiveSubmissionLimit(extension *Extension) *SubmissionLimitInfo {
+	if (extension != nil) && (extension.SubmissionLimit != nil) {
+		return extension.SubmissionLimit
+	}
+
+	return this.GetSubmissionLimit()
+}
+
+// Get the close date for a user with the given extension (which may be nil).
+// An extension's due date is never cut off by the assignment's close date.
+// Returns nil if there is no close date.
+func (this *Assignment) GetEffectiveCloseDate(extension *Extension) *timestamp.Timestamp {
+	if (this.CloseDate == nil) || (extension == nil) || (extension.DueDate == nil) {
+		return this.CloseDate
+	}
+
+	if *extension.DueDate > *this.CloseDate {
+		return extension.DueDate
+	}
+
+	return this.CloseDate
+}
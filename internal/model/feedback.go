@@ -0,0 +1,159 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type FeedbackPolicyType string
+
+const (
+	// Show all feedback immediately.
+	FeedbackFull FeedbackPolicyType = "full"
+	// Remove questions that the grader marked as hidden (and their points from the total).
+	FeedbackHideHiddenQuestions FeedbackPolicyType = "hide-hidden-questions"
+	// Show question scores, but not question messages.
+	FeedbackHideMessages FeedbackPolicyType = "hide-messages"
+	// Show only the total score.
+	FeedbackTotalOnly FeedbackPolicyType = "total-only"
+	// Show nothing (not even the score).
+	FeedbackNone FeedbackPolicyType = "none"
+)
+
+// Controls what feedback students see on their submissions before feedback is released.
+// Full results are always stored, and course staff (graders and above) always see full feedback.
+type FeedbackPolicy struct {
+	Type FeedbackPolicyType `json:"type"`
+
+	// When full feedback is released.
+	// Defaults to the student's due date (including any extension).
+	// If there is no release date and no due date, feedback stays restricted.
+	ReleaseDate *timestamp.Timestamp `json:"release-date,omitempty"`
+}
+
+func (this *FeedbackPolicy) Validate() error {
+	this.Type = FeedbackPolicyType(strings.ToLower(strings.TrimSpace(string(this.Type))))
+	if this.Type == "" {
+		this.Type = FeedbackFull
+	}
+
+	switch this.Type {
+	case FeedbackFull, FeedbackHideHiddenQuestions, FeedbackHideMessages, FeedbackTotalOnly, FeedbackNone:
+		return nil
+	default:
+		return fmt.Errorf("Unknown feedback policy type: '%s'.", this.Type)
+	}
+}
+
+// Get a copy of a grading result with only the feedback allowed by this policy.
+// The passed in info is not modified.
+func (this *FeedbackPolicy) RestrictGradingInfo(info *GradingInfo) *GradingInfo {
+	if (info == nil) || (this.Type == FeedbackFull) {
+		return info
+	}
+
+	restricted := *info
+	restricted.FeedbackRestricted = true
+	restricted.ScoreOverrides = nil
+	restricted.AdditionalInfo = nil
+
+	questions := make([]*GradedQuestion, 0, len(info.Questions))
+	for _, question := range info.Questions {
+		question = this.RestrictGradedQuestion(question)
+		if question != nil {
+			questions = append(questions, question)
+		}
+	}
+
+	switch this.Type {
+	case FeedbackHideHiddenQuestions:
+		restricted.Score = 0.0
+		restricted.MaxPoints = 0.0
+
+		for _, question := range questions {
+			restricted.Score += question.Score
+			restricted.MaxPoints += question.MaxPoints
+		}
+	case FeedbackTotalOnly:
+		restricted.Prologue = ""
+		restricted.Epilogue = ""
+	case FeedbackNone:
+		restricted.Prologue = ""
+		restricted.Epilogue = ""
+		restricted.Score = 0.0
+		restricted.MaxPoints = 0.0
+	}
+
+	restricted.Questions = questions
+
+	return &restricted
+}
+
+// Get a graded question with only the feedback allowed by this policy.
+// Returns nil if the question should not be shown at all.
+// The passed in question is not modified.
+func (this *FeedbackPolicy) RestrictGradedQuestion(question *GradedQuestion) *GradedQuestion {
+	if (question == nil) || (this.Type == FeedbackFull) {
+		return question
+	}
+
+	switch this.Type {
+	case FeedbackHideHiddenQuestions:
+		if question.Hidden {
+			return nil
+		}
+
+		return question
+	case FeedbackHideMessages:
+		newQuestion := *question
+		newQuestion.Message = ""
+		return &newQuestion
+	default:
+		return nil
+	}
+}
+
+// Get a copy of a full grading result with only the feedback allowed by this policy.
+// Grader output (stdout, stderr, and output files) is removed, since it can contain any feedback.
+// The passed in result is not modified.
+func (this *FeedbackPolicy) RestrictGradingResult(result *GradingResult) *GradingResult {
+	if (result == nil) || (this.Type == FeedbackFull) {
+		return result
+	}
+
+	restricted := *result
+	restricted.Info = this.RestrictGradingInfo(result.Info)
+	restricted.OutputFilesGZip = nil
+	restricted.Stdout = ""
+	restricted.Stderr = ""
+	restricted.ExitStatus = nil
+
+	return &restricted
+}
+
+// Get when full feedback is released for a user (the extension may be nil).
+// Returns nil if feedback has no release date.
+func (this *Assignment) GetFeedbackReleaseDate(extension *Extension) *timestamp.Timestamp {
+	if this.FeedbackPolicy == nil {
+		return nil
+	}
+
+	if this.FeedbackPolicy.ReleaseDate != nil {
+		return this.FeedbackPolicy.ReleaseDate
+	}
+
+	return this.GetEffectiveDueDate(extension)
+}
+
+// Check if a user's feedback is restricted at the given time (the extension may be nil).
+func (this *Assignment) IsFeedbackRestricted(extension *Extension, now timestamp.Timestamp) bool {
+	if (this.FeedbackPolicy == nil) || (this.FeedbackPolicy.Type == FeedbackFull) {
+		return false
+	}
+
+	releaseDate := this.GetFeedbackReleaseDate(extension)
+
+	return (releaseDate == nil) || (now < *releaseDate)
+}
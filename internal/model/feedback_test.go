@@ -0,0 +1,138 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestFeedbackPolicyValidate(test *testing.T) {
+	testCases := []struct {
+		input    FeedbackPolicyType
+		expected FeedbackPolicyType
+		hasError bool
+	}{
+		{"", FeedbackFull, false},
+		{"full", FeedbackFull, false},
+		{" Total-Only ", FeedbackTotalOnly, false},
+		{"hide-hidden-questions", FeedbackHideHiddenQuestions, false},
+		{"ZZZ", "", true},
+	}
+
+	for i, testCase := range testCases {
+		policy := &FeedbackPolicy{Type: testCase.input}
+
+		err := policy.Validate()
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if testCase.expected != policy.Type {
+			test.Errorf("Case %d: Unexpected type. Expected: '%s', Actual: '%s'.", i, testCase.expected, policy.Type)
+		}
+	}
+}
+
+func TestFeedbackPolicyRestrictGradingInfo(test *testing.T) {
+	makeInfo := func() *GradingInfo {
+		return &GradingInfo{
+			ID:        "info",
+			MaxPoints: 3.0,
+			Score:     2.0,
+			Prologue:  "Prologue",
+			Questions: []*GradedQuestion{
+				&GradedQuestion{Name: "Q1", MaxPoints: 1.0, Score: 1.0, Message: "M1"},
+				&GradedQuestion{Name: "Q2", MaxPoints: 2.0, Score: 1.0, Message: "M2", Hidden: true},
+			},
+			AdditionalInfo: map[string]any{"key": "value"},
+		}
+	}
+
+	testCases := []struct {
+		policyType FeedbackPolicyType
+		expected   *GradingInfo
+	}{
+		{FeedbackFull, makeInfo()},
+		{
+			FeedbackHideHiddenQuestions,
+			&GradingInfo{ID: "info", MaxPoints: 1.0, Score: 1.0, Prologue: "Prologue", FeedbackRestricted: true, Questions: []*GradedQuestion{
+				&GradedQuestion{Name: "Q1", MaxPoints: 1.0, Score: 1.0, Message: "M1"},
+			}},
+		},
+		{
+			FeedbackHideMessages,
+			&GradingInfo{ID: "info", MaxPoints: 3.0, Score: 2.0, Prologue: "Prologue", FeedbackRestricted: true, Questions: []*GradedQuestion{
+				&GradedQuestion{Name: "Q1", MaxPoints: 1.0, Score: 1.0},
+				&GradedQuestion{Name: "Q2", MaxPoints: 2.0, Score: 1.0, Hidden: true},
+			}},
+		},
+		{FeedbackTotalOnly, &GradingInfo{ID: "info", MaxPoints: 3.0, Score: 2.0, FeedbackRestricted: true, Questions: []*GradedQuestion{}}},
+		{FeedbackNone, &GradingInfo{ID: "info", FeedbackRestricted: true, Questions: []*GradedQuestion{}}},
+	}
+
+	for i, testCase := range testCases {
+		info := makeInfo()
+		policy := &FeedbackPolicy{Type: testCase.policyType}
+
+		actual := policy.RestrictGradingInfo(info)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected result. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+			continue
+		}
+
+		// The original should never be modified.
+		if !reflect.DeepEqual(makeInfo(), info) {
+			test.Errorf("Case %d: Original info was modified: '%s'.", i, util.MustToJSONIndent(info))
+			continue
+		}
+	}
+}
+
+func TestAssignmentIsFeedbackRestricted(test *testing.T) {
+	now := timestamp.FromMSecs(1000)
+	before := timestamp.FromMSecs(500)
+	after := timestamp.FromMSecs(1500)
+
+	testCases := []struct {
+		policy     *FeedbackPolicy
+		dueDate    *timestamp.Timestamp
+		extension  *Extension
+		restricted bool
+	}{
+		{nil, &after, nil, false},
+		{&FeedbackPolicy{Type: FeedbackFull}, &after, nil, false},
+
+		// Explicit release date.
+		{&FeedbackPolicy{Type: FeedbackNone, ReleaseDate: &after}, nil, nil, true},
+		{&FeedbackPolicy{Type: FeedbackNone, ReleaseDate: &before}, &after, nil, false},
+
+		// Released at the due date.
+		{&FeedbackPolicy{Type: FeedbackHideMessages}, &after, nil, true},
+		{&FeedbackPolicy{Type: FeedbackHideMessages}, &before, nil, false},
+		{&FeedbackPolicy{Type: FeedbackHideMessages}, &before, &Extension{DueDate: &after}, true},
+
+		// Never released.
+		{&FeedbackPolicy{Type: FeedbackTotalOnly}, nil, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		assignment := &Assignment{DueDate: testCase.dueDate, FeedbackPolicy: testCase.policy}
+
+		restricted := assignment.IsFeedbackRestricted(testCase.extension, now)
+		if testCase.restricted != restricted {
+			test.Errorf("Case %d: Unexpected result. Expected: %v, Actual: %v.", i, testCase.restricted, restricted)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/edulinq/autograder/internal/docker"
 	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -14,6 +15,10 @@ type GradingResult struct {
 	OutputFilesGZip map[string][]byte `json:"output-files-gzip"`
 	Stdout          string            `json:"stdout"`
 	Stderr          string            `json:"stderr"`
+
+	// How the grader exited (nil if it did not run to completion).
+	// This is reported for diagnostics and is not stored with the submission.
+	ExitStatus *docker.ExitStatus `json:"exit-status,omitempty"`
 }
 
 type GradingInfo struct {
@@ -27,6 +32,18 @@ type GradingInfo struct {
 	MaxPoints    float64 `json:"max_points"`
 	Score        float64 `json:"score"`
 
+	// Set when this submission is a regrade of an earlier submission (see procedures/regrade).
+	// A regrade keeps the grading times of the original submission (so it is scored the same way),
+	// and RegradeTime holds when the regrade actually happened.
+	RegradeOf   string              `json:"regrade-of,omitempty"`
+	RegradeTime timestamp.Timestamp `json:"regrade-time,omitempty"`
+
+	// Set for team submissions (see TeamInfo).
+	// Every member gets their own copy of a team submission, so User is the member this copy belongs to
+	// and Submitter is the member that actually made the submission.
+	Team      string `json:"team,omitempty"`
+	Submitter string `json:"submitter,omitempty"`
+
 	// Information generally filled out by the grader.
 	Name             string              `json:"name"`
 	Questions        []*GradedQuestion   `json:"questions"`
@@ -37,6 +54,14 @@ type GradingInfo struct {
 
 	// Additional pass-through information that the grader can use.
 	AdditionalInfo map[string]any `json:"additional-info"`
+
+	// Every change that course staff have made to question scores (oldest first).
+	// See ApplyScoreOverride().
+	ScoreOverrides []*ScoreOverride `json:"score-overrides,omitempty"`
+
+	// Set when some feedback was removed because of the assignment's feedback policy.
+	// Never set on stored results.
+	FeedbackRestricted bool `json:"feedback-restricted,omitempty"`
 }
 
 type GradedQuestion struct {
@@ -46,12 +71,32 @@ type GradedQuestion struct {
 	Message          string              `json:"message"`
 	GradingStartTime timestamp.Timestamp `json:"grading_start_time"`
 	GradingEndTime   timestamp.Timestamp `json:"grading_end_time"`
+
+	// Hidden questions can be withheld from students (see FeedbackPolicy).
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Set when the score has been overridden by course staff.
+	// Holds the score that the grader originally gave.
+	GraderScore *float64 `json:"grader-score,omitempty"`
+}
+
+func (this GradingInfo) IsRegrade() bool {
+	return (this.RegradeOf != "")
 }
 
 func (this *GradingResult) HasTextOutput() bool {
 	return ((this.Stdout != "") || (this.Stderr != ""))
 }
 
+// Get the grader's exit status (safe to call on a nil result).
+func (this *GradingResult) GetExitStatus() *docker.ExitStatus {
+	if this == nil {
+		return nil
+	}
+
+	return this.ExitStatus
+}
+
 func (this *GradingResult) GetCombinedOutput() string {
 	return fmt.Sprintf("--- stdout ---\n%s\n--------------\n--- stderr ---\n%s\n--------------", this.Stdout, this.Stderr)
 }
@@ -61,6 +106,7 @@ func (this GradingInfo) ToScoringInfo() *ScoringInfo {
 		ID:                      this.ID,
 		SubmissionTime:          this.GradingStartTime,
 		RawScore:                this.Score,
+		ScoreOverridden:         this.HasScoreOverrides(),
 		AutograderStructVersion: SCORING_INFO_STRUCT_VERSION,
 	}
 }
@@ -118,7 +164,11 @@ func (this GradingInfo) Report() string {
 }
 
 // Fill in the MaxPoints, Score, and (if empty) time fields.
+// MaxPoints and Score are always computed from the questions, so they include any score overrides.
 func (this *GradingInfo) ComputePoints() {
+	this.Score = 0.0
+	this.MaxPoints = 0.0
+
 	for _, question := range this.Questions {
 		this.Score += question.Score
 		this.MaxPoints += question.MaxPoints
@@ -138,6 +188,10 @@ func (this GradedQuestion) Report() string {
 
 	builder.WriteString(fmt.Sprintf("%s: %s / %s\n", this.Name, util.FloatToStr(this.Score), util.FloatToStr(this.MaxPoints)))
 
+	if this.GraderScore != nil {
+		builder.WriteString(fmt.Sprintf("    Score overridden by course staff (autograder score: %s).\n", util.FloatToStr(*this.GraderScore)))
+	}
+
 	if this.Message != "" {
 		for _, line := range strings.Split(this.Message, "\n") {
 			builder.WriteString(fmt.Sprintf("    %s\n", strings.TrimSpace(line)))
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The different things that can happen while a submission is being graded.
+type GradingEventType string
+
+const (
+	GradingEventTypeUnknown GradingEventType = ""
+	// The submission is waiting for a free grading slot.
+	GradingEventTypeQueued = "queued"
+	// The assignment's image is being (re)built.
+	GradingEventTypeBuildingImage = "building-image"
+	// The grader has started running.
+	GradingEventTypeStarted = "started"
+	// The grader wrote some output (to stdout or stderr).
+	GradingEventTypeOutput = "output"
+	// The grader produced the result for a question.
+	GradingEventTypeQuestion = "question"
+	// Grading has finished (successfully or not).
+	GradingEventTypeFinished = "finished"
+)
+
+var gradingEventTypeToString = map[GradingEventType]string{
+	GradingEventTypeUnknown:       string(GradingEventTypeUnknown),
+	GradingEventTypeQueued:        string(GradingEventTypeQueued),
+	GradingEventTypeBuildingImage: string(GradingEventTypeBuildingImage),
+	GradingEventTypeStarted:       string(GradingEventTypeStarted),
+	GradingEventTypeOutput:        string(GradingEventTypeOutput),
+	GradingEventTypeQuestion:      string(GradingEventTypeQuestion),
+	GradingEventTypeFinished:      string(GradingEventTypeFinished),
+}
+
+var stringToGradingEventType = map[string]GradingEventType{
+	string(GradingEventTypeUnknown):       GradingEventTypeUnknown,
+	string(GradingEventTypeQueued):        GradingEventTypeQueued,
+	string(GradingEventTypeBuildingImage): GradingEventTypeBuildingImage,
+	string(GradingEventTypeStarted):       GradingEventTypeStarted,
+	string(GradingEventTypeOutput):        GradingEventTypeOutput,
+	string(GradingEventTypeQuestion):      GradingEventTypeQuestion,
+	string(GradingEventTypeFinished):      GradingEventTypeFinished,
+}
+
+// Something that happened while a submission was being graded.
+type GradingEvent struct {
+	Type GradingEventType    `json:"type"`
+	Time timestamp.Timestamp `json:"time"`
+
+	// Output events: the stream the output was written to ("stdout" or "stderr") and the output itself.
+	Stream string `json:"stream,omitempty"`
+	Text   string `json:"text,omitempty"`
+
+	// Question events: the result of the question (in the same form as the final grading info).
+	Question *GradedQuestion `json:"question,omitempty"`
+}
+
+func NewGradingEvent(eventType GradingEventType) *GradingEvent {
+	return &GradingEvent{
+		Type: eventType,
+		Time: timestamp.Now(),
+	}
+}
+
+func (this GradingEventType) MarshalJSON() ([]byte, error) {
+	return util.MarshalEnum(this, gradingEventTypeToString)
+}
+
+func (this *GradingEventType) UnmarshalJSON(data []byte) error {
+	value, err := util.UnmarshalEnum(data, stringToGradingEventType, true)
+	if err == nil {
+		*this = *value
+	}
+
+	return err
+}
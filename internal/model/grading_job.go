@@ -0,0 +1,92 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type GradingJobStatus string
+
+const (
+	GradingJobStatusUnknown  GradingJobStatus = ""
+	GradingJobStatusPending                   = "pending"
+	GradingJobStatusRunning                   = "running"
+	GradingJobStatusComplete                  = "complete"
+)
+
+var gradingJobStatusToString = map[GradingJobStatus]string{
+	GradingJobStatusUnknown:  string(GradingJobStatusUnknown),
+	GradingJobStatusPending:  string(GradingJobStatusPending),
+	GradingJobStatusRunning:  string(GradingJobStatusRunning),
+	GradingJobStatusComplete: string(GradingJobStatusComplete),
+}
+
+var stringToGradingJobStatus = map[string]GradingJobStatus{
+	string(GradingJobStatusUnknown):  GradingJobStatusUnknown,
+	string(GradingJobStatusPending):  GradingJobStatusPending,
+	string(GradingJobStatusRunning):  GradingJobStatusRunning,
+	string(GradingJobStatusComplete): GradingJobStatusComplete,
+}
+
+// A submission that is waiting to be (or has been) graded asynchronously.
+// Jobs are stored in the database, so they survive server restarts.
+type GradingJob struct {
+	ID           string `json:"id"`
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id"`
+	User         string `json:"user"`
+	Message      string `json:"message"`
+	AllowLate    bool   `json:"allow-late"`
+
+	Status GradingJobStatus `json:"status"`
+
+	CreateTime timestamp.Timestamp `json:"create-time"`
+	StartTime  timestamp.Timestamp `json:"start-time,omitempty"`
+	EndTime    timestamp.Timestamp `json:"end-time,omitempty"`
+
+	// The submitted files (in the same format as GradingResult.InputFilesGZip).
+	// Only kept until the job is complete.
+	InputFilesGZip map[string][]byte `json:"input-files-gzip,omitempty"`
+
+	// The outcome of the job (only set once the job is complete).
+	// Like a synchronous submission, a job is successful if it was not rejected and there is no result message.
+	Rejected       bool   `json:"rejected,omitempty"`
+	ResultMessage  string `json:"result-message,omitempty"`
+	GradingSuccess bool   `json:"grading-success,omitempty"`
+	SubmissionID   string `json:"submission-id,omitempty"`
+}
+
+func (this *GradingJob) Validate() error {
+	if this.ID == "" {
+		return fmt.Errorf("Grading job has an empty ID.")
+	}
+
+	if (this.CourseID == "") || (this.AssignmentID == "") || (this.User == "") {
+		return fmt.Errorf("Grading job '%s' is missing its course, assignment, or user.", this.ID)
+	}
+
+	if this.Status == GradingJobStatusUnknown {
+		return fmt.Errorf("Grading job '%s' has an unknown status.", this.ID)
+	}
+
+	return nil
+}
+
+func (this *GradingJob) IsDone() bool {
+	return this.Status == GradingJobStatusComplete
+}
+
+func (this GradingJobStatus) MarshalJSON() ([]byte, error) {
+	return util.MarshalEnum(this, gradingJobStatusToString)
+}
+
+func (this *GradingJobStatus) UnmarshalJSON(data []byte) error {
+	value, err := util.UnmarshalEnum(data, stringToGradingJobStatus, true)
+	if err == nil {
+		*this = *value
+	}
+
+	return err
+}
@@ -7,6 +7,9 @@ import (
 
 const (
 	LMS_TYPE_CANVAS = "canvas"
+	LMS_TYPE_MOODLE = "moodle"
+	LMS_TYPE_LTI    = "lti"
+	LMS_TYPE_FILE   = "file"
 	LMS_TYPE_TEST   = "test"
 )
 
@@ -18,6 +21,12 @@ type LMSAdapter struct {
 	APIToken    string `json:"api-token,omitempty"`
 	BaseURL     string `json:"base-url,omitempty"`
 
+	// Connection options for LTI 1.3 platforms (only used by the LTI type).
+	LTI *LTIPlatformInfo `json:"lti,omitempty"`
+
+	// Options for file (CSV) gradebooks (only used by the file type).
+	File *FileGradebookInfo `json:"file,omitempty"`
+
 	// Behavior options.
 
 	SyncUserAttributes bool `json:"sync-user-attributes,omitempty"`
@@ -30,6 +39,9 @@ type LMSAdapter struct {
 type LMSSyncResult struct {
 	UserSync       []*UserOpResult       `json:"user-sync"`
 	AssignmentSync *AssignmentSyncResult `json:"assignment-sync"`
+
+	// Keyed by assignment ID, only present for assignments that sync teams from the LMS.
+	TeamSync map[string]*TeamSyncResult `json:"team-sync,omitempty"`
 }
 
 func (this *LMSAdapter) Validate() error {
@@ -38,6 +50,28 @@ func (this *LMSAdapter) Validate() error {
 	}
 	this.Type = strings.ToLower(this.Type)
 
+	if this.LTI != nil {
+		err := this.LTI.Validate()
+		if err != nil {
+			return fmt.Errorf("Invalid LTI information: '%w'.", err)
+		}
+	}
+
+	if (this.Type == LMS_TYPE_LTI) && (this.LTI == nil) {
+		return fmt.Errorf("LTI LMS adapters require LTI information (lti).")
+	}
+
+	if this.File != nil {
+		err := this.File.Validate()
+		if err != nil {
+			return fmt.Errorf("Invalid file gradebook information: '%w'.", err)
+		}
+	}
+
+	if (this.Type == LMS_TYPE_FILE) && (this.File == nil) {
+		return fmt.Errorf("File LMS adapters require file gradebook information (file).")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	GRADEBOOK_FORMAT_GENERIC    = "generic"
+	GRADEBOOK_FORMAT_CANVAS     = "canvas"
+	GRADEBOOK_FORMAT_GRADESCOPE = "gradescope"
+
+	DEFAULT_ROSTER_FILENAME = "roster.csv"
+)
+
+var GRADEBOOK_FORMATS []string = []string{GRADEBOOK_FORMAT_GENERIC, GRADEBOOK_FORMAT_CANVAS, GRADEBOOK_FORMAT_GRADESCOPE}
+
+// How the "file" LMS keeps a course's roster and gradebook.
+// The roster is read from a CSV file, and scores (and comments) are written to CSV files.
+type FileGradebookInfo struct {
+	// The directory the roster and gradebook files are kept in.
+	// Must be an absolute path.
+	Dir string `json:"dir"`
+
+	// The path to the roster CSV file.
+	// Relative paths are relative to the directory.
+	// Defaults to DEFAULT_ROSTER_FILENAME.
+	Roster string `json:"roster,omitempty"`
+
+	// The format of the full gradebook that is written whenever scores are updated.
+	// Defaults to GRADEBOOK_FORMAT_GENERIC.
+	Format string `json:"format,omitempty"`
+}
+
+func (this *FileGradebookInfo) Validate() error {
+	this.Dir = strings.TrimSpace(this.Dir)
+	if this.Dir == "" {
+		return fmt.Errorf("File gradebook dir (dir) cannot be empty.")
+	}
+
+	if !filepath.IsAbs(this.Dir) {
+		return fmt.Errorf("File gradebook dir (dir) must be an absolute path, found '%s'.", this.Dir)
+	}
+
+	this.Roster = strings.TrimSpace(this.Roster)
+	if this.Roster == "" {
+		this.Roster = DEFAULT_ROSTER_FILENAME
+	}
+
+	this.Format = strings.ToLower(strings.TrimSpace(this.Format))
+	if this.Format == "" {
+		this.Format = GRADEBOOK_FORMAT_GENERIC
+	}
+
+	return ValidateGradebookFormat(this.Format)
+}
+
+func (this *FileGradebookInfo) GetRosterPath() string {
+	if filepath.IsAbs(this.Roster) {
+		return this.Roster
+	}
+
+	return filepath.Join(this.Dir, this.Roster)
+}
+
+func ValidateGradebookFormat(format string) error {
+	for _, validFormat := range GRADEBOOK_FORMATS {
+		if format == validFormat {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Unknown gradebook format '%s'. Valid formats: ['%s'].", format, strings.Join(GRADEBOOK_FORMATS, "', '"))
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// How the autograder (as an LTI 1.3 tool) connects to an LTI platform (the LMS).
+// These values come from registering the autograder with the platform.
+type LTIPlatformInfo struct {
+	// The client ID the platform assigned to the autograder.
+	ClientID string `json:"client-id"`
+
+	// The OAuth2 endpoint used to get access tokens for LTI services.
+	TokenURL string `json:"token-url"`
+
+	// The private key (PEM) used to sign access token requests.
+	// The matching public key must be registered with the platform.
+	PrivateKey string `json:"private-key"`
+
+	// The identifier of the key (used as the "kid" JWT header).
+	KeyID string `json:"key-id,omitempty"`
+
+	// The audience for access token requests.
+	// Defaults to the token URL.
+	TokenAudience string `json:"token-audience,omitempty"`
+
+	// The Assignment and Grade Services (AGS) line items endpoint for the course.
+	LineItemsURL string `json:"lineitems-url"`
+
+	// The Names and Role Provisioning Services (NRPS) memberships endpoint for the course.
+	MembershipsURL string `json:"memberships-url,omitempty"`
+
+	// Launch options.
+	// These are only required if users will launch the autograder from the platform.
+
+	// The platform's issuer identifier (the "iss" claim of launches).
+	Issuer string `json:"issuer,omitempty"`
+
+	// The platform's OIDC authorization endpoint (where login requests are sent).
+	AuthURL string `json:"auth-url,omitempty"`
+
+	// The platform's public keyset (used to verify launches).
+	JWKSURL string `json:"jwks-url,omitempty"`
+
+	// If set, only launches from this deployment will be accepted.
+	DeploymentID string `json:"deployment-id,omitempty"`
+
+	// The platform's ID for the course (the "id" of the context claim).
+	// If not set, launches are matched to the course using the line items URL.
+	ContextID string `json:"context-id,omitempty"`
+}
+
+func (this *LTIPlatformInfo) Validate() error {
+	this.ClientID = strings.TrimSpace(this.ClientID)
+	if this.ClientID == "" {
+		return fmt.Errorf("LTI client ID (client-id) cannot be empty.")
+	}
+
+	this.TokenURL = strings.TrimSpace(this.TokenURL)
+	if this.TokenURL == "" {
+		return fmt.Errorf("LTI token URL (token-url) cannot be empty.")
+	}
+
+	if strings.TrimSpace(this.PrivateKey) == "" {
+		return fmt.Errorf("LTI private key (private-key) cannot be empty.")
+	}
+
+	this.TokenAudience = strings.TrimSpace(this.TokenAudience)
+	if this.TokenAudience == "" {
+		this.TokenAudience = this.TokenURL
+	}
+
+	this.LineItemsURL = strings.TrimSpace(this.LineItemsURL)
+	if this.LineItemsURL == "" {
+		return fmt.Errorf("LTI line items URL (lineitems-url) cannot be empty.")
+	}
+
+	this.MembershipsURL = strings.TrimSpace(this.MembershipsURL)
+
+	this.Issuer = strings.TrimSpace(this.Issuer)
+	this.AuthURL = strings.TrimSpace(this.AuthURL)
+	this.JWKSURL = strings.TrimSpace(this.JWKSURL)
+	this.DeploymentID = strings.TrimSpace(this.DeploymentID)
+	this.ContextID = strings.TrimSpace(this.ContextID)
+
+	if (this.Issuer != "") || (this.AuthURL != "") || (this.JWKSURL != "") {
+		if this.Issuer == "" {
+			return fmt.Errorf("LTI launches require an issuer (issuer).")
+		}
+
+		if this.AuthURL == "" {
+			return fmt.Errorf("LTI launches require an auth URL (auth-url).")
+		}
+
+		if this.JWKSURL == "" {
+			return fmt.Errorf("LTI launches require a JWKS URL (jwks-url).")
+		}
+	}
+
+	return nil
+}
+
+// Returns true if users can launch the autograder from this platform.
+func (this *LTIPlatformInfo) LaunchEnabled() bool {
+	return this.Issuer != ""
+}
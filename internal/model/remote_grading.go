@@ -0,0 +1,112 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// A single grader run that is sent to a remote grading worker.
+type RemoteGradingTask struct {
+	ID               string `json:"id"`
+	FullSubmissionID string `json:"full-submission-id"`
+
+	ImageName string `json:"image-name"`
+	// A hash of the image's build context.
+	// Workers only need to (re)build an image when they have not built an image with this hash.
+	ImageHash string `json:"image-hash"`
+	// The image's build context (see docker.GetBuildContextGZip()).
+	// Only sent when the worker does not already have the image.
+	ImageContextGZip map[string][]byte `json:"image-context-gzip,omitempty"`
+
+	// The submitted files (in the same format as GradingResult.InputFilesGZip).
+	InputFilesGZip map[string][]byte `json:"input-files-gzip"`
+
+	MaxRuntimeSecs int                   `json:"max-runtime-secs"`
+	ResourceLimits docker.ResourceLimits `json:"resource-limits"`
+}
+
+// The outcome of a remote grading worker running a task.
+// Interpreting the output (e.g., finding the grader's result) is left to the server.
+type RemoteGradingResult struct {
+	TaskID string `json:"task-id"`
+
+	// The grader's output directory (in the same format as GradingResult.OutputFilesGZip).
+	OutputFilesGZip map[string][]byte  `json:"output-files-gzip"`
+	Stdout          string             `json:"stdout"`
+	Stderr          string             `json:"stderr"`
+	ExitStatus      *docker.ExitStatus `json:"exit-status,omitempty"`
+	Timeout         bool               `json:"timeout"`
+
+	// Set when the worker could not run the grader (e.g., the image failed to build).
+	// The task may be given to another worker.
+	Error string `json:"error,omitempty"`
+}
+
+// The server's view of a remote grading worker.
+type RemoteWorkerInfo struct {
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	RegisterTime  timestamp.Timestamp `json:"register-time"`
+	LastHeartbeat timestamp.Timestamp `json:"last-heartbeat"`
+
+	// The IDs of the tasks currently assigned to this worker.
+	Tasks []string `json:"tasks"`
+}
+
+func (this *RemoteGradingTask) Validate() error {
+	if this.ID == "" {
+		return fmt.Errorf("Remote grading task has an empty ID.")
+	}
+
+	if this.ImageName == "" {
+		return fmt.Errorf("Remote grading task '%s' has no image.", this.ID)
+	}
+
+	err := validateRelPaths(this.ImageContextGZip)
+	if err != nil {
+		return fmt.Errorf("Remote grading task '%s' has a bad image context: '%w'.", this.ID, err)
+	}
+
+	err = validateRelPaths(this.InputFilesGZip)
+	if err != nil {
+		return fmt.Errorf("Remote grading task '%s' has bad input files: '%w'.", this.ID, err)
+	}
+
+	return this.ResourceLimits.Validate()
+}
+
+func (this *RemoteGradingResult) Validate() error {
+	if this.TaskID == "" {
+		return fmt.Errorf("Remote grading result has an empty task ID.")
+	}
+
+	err := validateRelPaths(this.OutputFilesGZip)
+	if err != nil {
+		return fmt.Errorf("Remote grading result for task '%s' has bad output files: '%w'.", this.TaskID, err)
+	}
+
+	return nil
+}
+
+// Files sent between the server and workers must stay inside of the directory they are written to.
+func validateRelPaths(fileContents map[string][]byte) error {
+	for relPath, _ := range fileContents {
+		if !filepath.IsLocal(relPath) {
+			return fmt.Errorf("Path is not a local relative path: '%s'.", relPath)
+		}
+	}
+
+	return nil
+}
+
+func (this *RemoteGradingTask) LogValue() []*log.Attr {
+	return []*log.Attr{
+		log.NewAttr("task-id", this.ID),
+		log.NewAttr("submission-id", this.FullSubmissionID),
+		log.NewAttr("image", this.ImageName),
+	}
+}
@@ -0,0 +1,168 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// A part of an assignment that is graded by hand (see Assignment.ManualRubric).
+type RubricItem struct {
+	ID          string  `json:"id"`
+	Description string  `json:"description,omitempty"`
+	MaxPoints   float64 `json:"max-points"`
+}
+
+// A user's hand-graded scores for an assignment.
+// Manual grades belong to the user (not a specific submission), so they are kept across submissions.
+type ManualGrade struct {
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id"`
+	User         string `json:"user"`
+
+	// Keyed by rubric item ID.
+	// Items that have not been graded yet are not present.
+	Items map[string]*ManualGradeItem `json:"items"`
+}
+
+type ManualGradeItem struct {
+	Score   float64             `json:"score"`
+	Comment string              `json:"comment,omitempty"`
+	Grader  string              `json:"grader"`
+	Time    timestamp.Timestamp `json:"time"`
+}
+
+func (this *RubricItem) Validate() error {
+	var err error
+	this.ID, err = common.ValidateID(this.ID)
+	if err != nil {
+		return err
+	}
+
+	if (this.MaxPoints < 0.0) || math.IsNaN(this.MaxPoints) || math.IsInf(this.MaxPoints, 0) {
+		return fmt.Errorf("Rubric item '%s' has invalid max points: %f.", this.ID, this.MaxPoints)
+	}
+
+	return nil
+}
+
+func validateRubric(rubric []*RubricItem) error {
+	seen := make(map[string]bool, len(rubric))
+
+	for i, item := range rubric {
+		if item == nil {
+			return fmt.Errorf("Rubric item at index %d is nil.", i)
+		}
+
+		err := item.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate rubric item at index %d: '%w'.", i, err)
+		}
+
+		if seen[item.ID] {
+			return fmt.Errorf("Duplicate rubric item: '%s'.", item.ID)
+		}
+
+		seen[item.ID] = true
+	}
+
+	return nil
+}
+
+func (this *ManualGrade) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Manual grade is nil.")
+	}
+
+	if (this.CourseID == "") || (this.AssignmentID == "") || (this.User == "") {
+		return fmt.Errorf("Manual grade is missing a course, assignment, or user.")
+	}
+
+	if this.Items == nil {
+		this.Items = make(map[string]*ManualGradeItem)
+	}
+
+	for id, item := range this.Items {
+		if item == nil {
+			return fmt.Errorf("Manual grade item '%s' is nil.", id)
+		}
+
+		if math.IsNaN(item.Score) || math.IsInf(item.Score, 0) {
+			return fmt.Errorf("Manual grade item '%s' has an invalid score: %f.", id, item.Score)
+		}
+
+		item.Comment = strings.TrimSpace(item.Comment)
+	}
+
+	return nil
+}
+
+func (this *Assignment) HasManualRubric() bool {
+	return len(this.ManualRubric) > 0
+}
+
+// Get a rubric item by ID.
+// Returns nil if there is no matching item.
+func (this *Assignment) GetRubricItem(id string) *RubricItem {
+	for _, item := range this.ManualRubric {
+		if item.ID == id {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// The total number of points available from manual grading.
+func (this *Assignment) GetManualMaxPoints() float64 {
+	total := 0.0
+	for _, item := range this.ManualRubric {
+		total += item.MaxPoints
+	}
+
+	return total
+}
+
+// Get the total manual score for a grade.
+// Only items that are in this assignment's rubric are counted.
+func (this *Assignment) ComputeManualScore(grade *ManualGrade) float64 {
+	if grade == nil {
+		return 0.0
+	}
+
+	total := 0.0
+	for _, item := range this.ManualRubric {
+		gradeItem := grade.Items[item.ID]
+		if gradeItem != nil {
+			total += gradeItem.Score
+		}
+	}
+
+	return total
+}
+
+// Check that a grade for a single rubric item is valid for this assignment.
+func (this *Assignment) CheckManualGradeItem(id string, item *ManualGradeItem) error {
+	rubricItem := this.GetRubricItem(id)
+	if rubricItem == nil {
+		return fmt.Errorf("Assignment '%s' does not have a rubric item named '%s'.", this.FullID(), id)
+	}
+
+	if item == nil {
+		return fmt.Errorf("Manual grade item '%s' is nil.", id)
+	}
+
+	if math.IsNaN(item.Score) || (item.Score < 0.0) || (item.Score > rubricItem.MaxPoints) {
+		return fmt.Errorf("Score for rubric item '%s' must be between 0 and %s, found %f.", id, util.FloatToStr(rubricItem.MaxPoints), item.Score)
+	}
+
+	if item.Grader == "" {
+		return fmt.Errorf("Manual grade item '%s' is missing a grader.", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidateRubric(test *testing.T) {
+	testCases := []struct {
+		rubric   []*RubricItem
+		hasError bool
+	}{
+		{nil, false},
+		{[]*RubricItem{&RubricItem{ID: "style", MaxPoints: 2.0}}, false},
+		{[]*RubricItem{&RubricItem{ID: "Style", MaxPoints: 0.0}, &RubricItem{ID: "report", MaxPoints: 1.5}}, false},
+
+		{[]*RubricItem{nil}, true},
+		{[]*RubricItem{&RubricItem{ID: "", MaxPoints: 2.0}}, true},
+		{[]*RubricItem{&RubricItem{ID: "a b", MaxPoints: 2.0}}, true},
+		{[]*RubricItem{&RubricItem{ID: "style", MaxPoints: -1.0}}, true},
+		{[]*RubricItem{&RubricItem{ID: "style", MaxPoints: math.NaN()}}, true},
+		{[]*RubricItem{&RubricItem{ID: "style", MaxPoints: 1.0}, &RubricItem{ID: "STYLE", MaxPoints: 1.0}}, true},
+	}
+
+	for i, testCase := range testCases {
+		err := validateRubric(testCase.rubric)
+		if testCase.hasError != (err != nil) {
+			test.Errorf("Case %d: Unexpected error state. Expected error: %v, Actual: '%v'.", i, testCase.hasError, err)
+		}
+	}
+}
+
+func TestComputeManualScore(test *testing.T) {
+	assignment := &Assignment{
+		ManualRubric: []*RubricItem{
+			&RubricItem{ID: "style", MaxPoints: 2.0},
+			&RubricItem{ID: "report", MaxPoints: 3.0},
+		},
+	}
+
+	grade := &ManualGrade{
+		Items: map[string]*ManualGradeItem{
+			"style": &ManualGradeItem{Score: 1.5},
+			// Not in the rubric.
+			"old": &ManualGradeItem{Score: 10.0},
+		},
+	}
+
+	if assignment.GetManualMaxPoints() != 5.0 {
+		test.Fatalf("Unexpected manual max points. Expected: 5.0, Actual: %f.", assignment.GetManualMaxPoints())
+	}
+
+	score := assignment.ComputeManualScore(grade)
+	if score != 1.5 {
+		test.Fatalf("Unexpected manual score. Expected: 1.5, Actual: %f.", score)
+	}
+
+	if assignment.ComputeManualScore(nil) != 0.0 {
+		test.Fatalf("Nil grade has a non-zero score.")
+	}
+}
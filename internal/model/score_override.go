@@ -0,0 +1,163 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// A change (made by course staff) to the score of a single question in a submission.
+// Every change is kept (in order) in GradingInfo.ScoreOverrides as an audit history.
+type ScoreOverride struct {
+	Question string `json:"question"`
+
+	// The new score for the question.
+	// Ignored when this change removes an override.
+	Score float64 `json:"score"`
+	// This change removes the question's current override (restoring the grader's score).
+	Remove bool `json:"remove,omitempty"`
+
+	// The question's score right before this change.
+	// Set when the override is applied.
+	PreviousScore float64 `json:"previous-score"`
+
+	Author string              `json:"author"`
+	Reason string              `json:"reason"`
+	Time   timestamp.Timestamp `json:"time"`
+}
+
+func (this *ScoreOverride) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Score override is nil.")
+	}
+
+	this.Question = strings.TrimSpace(this.Question)
+	if this.Question == "" {
+		return fmt.Errorf("Score override is missing a question.")
+	}
+
+	if math.IsNaN(this.Score) || math.IsInf(this.Score, 0) {
+		return fmt.Errorf("Score override for question '%s' has an invalid score: %f.", this.Question, this.Score)
+	}
+
+	if this.Remove {
+		this.Score = 0.0
+	}
+
+	if this.Author == "" {
+		return fmt.Errorf("Score override for question '%s' is missing an author.", this.Question)
+	}
+
+	this.Reason = strings.TrimSpace(this.Reason)
+	if this.Reason == "" {
+		return fmt.Errorf("Score override for question '%s' is missing a reason.", this.Question)
+	}
+
+	if this.Time.IsZero() {
+		this.Time = timestamp.Now()
+	}
+
+	return nil
+}
+
+// Check that a score override is valid and can be applied to this submission.
+func (this *GradingInfo) CheckScoreOverride(override *ScoreOverride) error {
+	err := override.Validate()
+	if err != nil {
+		return err
+	}
+
+	question := this.GetQuestion(override.Question)
+	if question == nil {
+		return fmt.Errorf("Submission '%s' does not have a question named '%s'.", this.ID, override.Question)
+	}
+
+	if override.Remove && (question.GraderScore == nil) {
+		return fmt.Errorf("Question '%s' in submission '%s' does not have a score override to remove.", override.Question, this.ID)
+	}
+
+	return nil
+}
+
+// Apply a score override to this submission and add it to the override history.
+// Score and MaxPoints are recomputed (see ComputePoints()).
+func (this *GradingInfo) ApplyScoreOverride(override *ScoreOverride) error {
+	err := this.CheckScoreOverride(override)
+	if err != nil {
+		return err
+	}
+
+	question := this.GetQuestion(override.Question)
+	override.PreviousScore = question.Score
+
+	if override.Remove {
+		question.Score = *question.GraderScore
+		question.GraderScore = nil
+	} else {
+		if question.GraderScore == nil {
+			graderScore := question.Score
+			question.GraderScore = &graderScore
+		}
+
+		question.Score = override.Score
+	}
+
+	this.ScoreOverrides = append(this.ScoreOverrides, override)
+	this.ComputePoints()
+
+	return nil
+}
+
+// Get the override currently in effect for each overridden question (in question order).
+func (this *GradingInfo) GetActiveScoreOverrides() []*ScoreOverride {
+	latest := make(map[string]*ScoreOverride)
+	for _, override := range this.ScoreOverrides {
+		latest[override.Question] = override
+	}
+
+	overrides := make([]*ScoreOverride, 0)
+	for _, question := range this.Questions {
+		override := latest[question.Name]
+		if (override == nil) || override.Remove || (question.GraderScore == nil) {
+			continue
+		}
+
+		overrides = append(overrides, override)
+	}
+
+	return overrides
+}
+
+func (this *GradingInfo) HasScoreOverrides() bool {
+	for _, question := range this.Questions {
+		if question.GraderScore != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Remove all score overrides (and their history) without changing any scores.
+// Used to make sure that graders cannot set their own overrides.
+func (this *GradingInfo) ClearScoreOverrides() {
+	this.ScoreOverrides = nil
+
+	for _, question := range this.Questions {
+		question.GraderScore = nil
+	}
+}
+
+// Get a question by name.
+// Returns nil if there is no matching question.
+func (this *GradingInfo) GetQuestion(name string) *GradedQuestion {
+	for _, question := range this.Questions {
+		if question.Name == name {
+			return question
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestApplyScoreOverrideValidation(test *testing.T) {
+	testCases := []struct {
+		override *ScoreOverride
+		hasError bool
+	}{
+		{&ScoreOverride{Question: "Q1", Score: 0.5, Author: "a@test.edulinq.org", Reason: "Style."}, false},
+		{&ScoreOverride{Question: " Q1 ", Score: -1.0, Author: "a@test.edulinq.org", Reason: "Penalty."}, false},
+		{&ScoreOverride{Question: "Q1", Score: 3.0, Author: "a@test.edulinq.org", Reason: "Extra credit."}, false},
+
+		{nil, true},
+		{&ScoreOverride{Question: "", Score: 0.5, Author: "a@test.edulinq.org", Reason: "Style."}, true},
+		{&ScoreOverride{Question: "ZZZ", Score: 0.5, Author: "a@test.edulinq.org", Reason: "Style."}, true},
+		{&ScoreOverride{Question: "Q1", Score: math.NaN(), Author: "a@test.edulinq.org", Reason: "Style."}, true},
+		{&ScoreOverride{Question: "Q1", Score: math.Inf(1), Author: "a@test.edulinq.org", Reason: "Style."}, true},
+		{&ScoreOverride{Question: "Q1", Score: 0.5, Author: "", Reason: "Style."}, true},
+		{&ScoreOverride{Question: "Q1", Score: 0.5, Author: "a@test.edulinq.org", Reason: " "}, true},
+
+		// Nothing to remove.
+		{&ScoreOverride{Question: "Q1", Remove: true, Author: "a@test.edulinq.org", Reason: "Style."}, true},
+	}
+
+	for i, testCase := range testCases {
+		info := makeTestOverrideInfo()
+
+		err := info.ApplyScoreOverride(testCase.override)
+		if testCase.hasError != (err != nil) {
+			test.Errorf("Case %d: Unexpected error state. Expected error: %v, Actual: '%v'.", i, testCase.hasError, err)
+			continue
+		}
+
+		if err != nil {
+			if len(info.ScoreOverrides) != 0 {
+				test.Errorf("Case %d: Failed override was added to the history.", i)
+			}
+
+			continue
+		}
+
+		expectedScore := testCase.override.Score + 1.0
+		if info.Score != expectedScore {
+			test.Errorf("Case %d: Unexpected score. Expected: %f, Actual: %f.", i, expectedScore, info.Score)
+			continue
+		}
+
+		if info.MaxPoints != 2.0 {
+			test.Errorf("Case %d: Unexpected max points. Expected: 2.0, Actual: %f.", i, info.MaxPoints)
+			continue
+		}
+
+		if testCase.override.Time.IsZero() {
+			test.Errorf("Case %d: Override time was not set.", i)
+			continue
+		}
+	}
+}
+
+func TestApplyScoreOverrideHistory(test *testing.T) {
+	info := makeTestOverrideInfo()
+
+	overrides := []*ScoreOverride{
+		&ScoreOverride{Question: "Q1", Score: 0.5, Author: "a@test.edulinq.org", Reason: "First."},
+		&ScoreOverride{Question: "Q1", Score: 0.25, Author: "b@test.edulinq.org", Reason: "Second."},
+		&ScoreOverride{Question: "Q2", Score: 0.0, Author: "a@test.edulinq.org", Reason: "Third."},
+		&ScoreOverride{Question: "Q2", Remove: true, Author: "a@test.edulinq.org", Reason: "Undo."},
+	}
+
+	expectedPrevious := []float64{1.0, 0.5, 1.0, 0.0}
+
+	for i, override := range overrides {
+		err := info.ApplyScoreOverride(override)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to apply override: '%v'.", i, err)
+		}
+
+		if override.PreviousScore != expectedPrevious[i] {
+			test.Fatalf("Case %d: Unexpected previous score. Expected: %f, Actual: %f.", i, expectedPrevious[i], override.PreviousScore)
+		}
+	}
+
+	if info.Score != 1.25 {
+		test.Fatalf("Unexpected score. Expected: 1.25, Actual: %f.", info.Score)
+	}
+
+	if len(info.ScoreOverrides) != len(overrides) {
+		test.Fatalf("Unexpected history length. Expected: %d, Actual: %d.", len(overrides), len(info.ScoreOverrides))
+	}
+
+	active := info.GetActiveScoreOverrides()
+	if (len(active) != 1) || (active[0] != overrides[1]) {
+		test.Fatalf("Unexpected active overrides: '%v'.", active)
+	}
+
+	question := info.GetQuestion("Q1")
+	if (question.GraderScore == nil) || (*question.GraderScore != 1.0) {
+		test.Fatalf("Grader score was not kept.")
+	}
+
+	if !strings.Contains(question.Report(), "autograder score: 1") {
+		test.Fatalf("Question report does not mention the override: '%s'.", question.Report())
+	}
+
+	if !info.ToScoringInfo().ScoreOverridden {
+		test.Fatalf("Scoring info is not marked as overridden.")
+	}
+
+	// Clearing overrides does not change the scores.
+	info.ClearScoreOverrides()
+
+	if info.HasScoreOverrides() || (len(info.ScoreOverrides) != 0) {
+		test.Fatalf("Overrides were not cleared.")
+	}
+
+	if info.Score != 1.25 {
+		test.Fatalf("Clearing overrides changed the score. Expected: 1.25, Actual: %f.", info.Score)
+	}
+}
+
+func makeTestOverrideInfo() *GradingInfo {
+	info := &GradingInfo{
+		ID: "course101::hw0::course-student@test.edulinq.org::1697406272",
+		Questions: []*GradedQuestion{
+			&GradedQuestion{Name: "Q1", MaxPoints: 1.0, Score: 1.0},
+			&GradedQuestion{Name: "Q2", MaxPoints: 1.0, Score: 1.0},
+		},
+	}
+
+	info.ComputePoints()
+
+	return info
+}
@@ -17,6 +17,13 @@ type ScoringInfo struct {
 	NumDaysLate    int                 `json:"num-days-late"`
 	Reject         bool                `json:"reject"`
 
+	// For assignments with a manual rubric, the two parts of RawScore.
+	AutograderScore float64 `json:"autograder-score,omitempty"`
+	ManualScore     float64 `json:"manual-score,omitempty"`
+
+	// The score of at least one question was overridden by course staff.
+	ScoreOverridden bool `json:"score-overridden,omitempty"`
+
 	// A distinct key so we can recognize this as an autograder object.
 	AutograderStructVersion string `json:"__autograder__version__"`
 
@@ -49,6 +56,9 @@ func (this *ScoringInfo) Equal(other *ScoringInfo) bool {
 		this.LateDayUsage == other.LateDayUsage &&
 		this.NumDaysLate == other.NumDaysLate &&
 		this.Reject == other.Reject &&
+		this.AutograderScore == other.AutograderScore &&
+		this.ManualScore == other.ManualScore &&
+		this.ScoreOverridden == other.ScoreOverridden &&
 		this.AutograderStructVersion == other.AutograderStructVersion)
 }
 
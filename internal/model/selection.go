@@ -0,0 +1,162 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// How to pick the submission that counts (is scored) for each user.
+type SubmissionSelectionPolicy string
+
+const (
+	// The most recent submission (the default).
+	SelectLatest SubmissionSelectionPolicy = "latest"
+	// The submission with the highest score.
+	SelectHighestScore SubmissionSelectionPolicy = "highest-score"
+	// The submission with the highest score that was made before the user's due date.
+	// Falls back to the most recent submission if there are no on-time submissions.
+	SelectHighestBeforeDue SubmissionSelectionPolicy = "highest-before-due"
+	// The submission the user marked as final.
+	// Falls back to the most recent submission if the user has not marked one.
+	SelectStudentFinal SubmissionSelectionPolicy = "student-final"
+)
+
+// A submission that a user marked as the one that should count (see SelectStudentFinal).
+type FinalSubmission struct {
+	User         string              `json:"user"`
+	SubmissionID string              `json:"submission-id"`
+	Time         timestamp.Timestamp `json:"time"`
+}
+
+// An empty policy is the same as SelectLatest.
+func ValidateSubmissionSelectionPolicy(policy SubmissionSelectionPolicy) (SubmissionSelectionPolicy, error) {
+	policy = SubmissionSelectionPolicy(strings.ToLower(strings.TrimSpace(string(policy))))
+
+	switch policy {
+	case "", SelectLatest, SelectHighestScore, SelectHighestBeforeDue, SelectStudentFinal:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("Unknown submission selection policy: '%s'.", policy)
+	}
+}
+
+func (this *FinalSubmission) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Final submission is nil.")
+	}
+
+	this.User = strings.ToLower(strings.TrimSpace(this.User))
+	if this.User == "" {
+		return fmt.Errorf("Final submission has an empty user.")
+	}
+
+	this.SubmissionID = common.GetShortSubmissionID(strings.TrimSpace(this.SubmissionID))
+	if this.SubmissionID == "" {
+		return fmt.Errorf("Final submission for '%s' has an empty submission ID.", this.User)
+	}
+
+	if this.Time.IsZero() {
+		this.Time = timestamp.Now()
+	}
+
+	return nil
+}
+
+// Select the submission that counts from a user's submission history (ordered oldest first).
+// Regraded submissions are replaced by their most recent regrade.
+// The extension and final submission may be nil.
+// Returns nil if the history is empty.
+func (this *Assignment) SelectSubmission(history []*SubmissionHistoryItem, extension *Extension, final *FinalSubmission) *SubmissionHistoryItem {
+	if len(history) == 0 {
+		return nil
+	}
+
+	latest := history[len(history)-1]
+
+	if (this.SubmissionSelection == "") || (this.SubmissionSelection == SelectLatest) {
+		return latest
+	}
+
+	candidates := getSelectionCandidates(history)
+
+	switch this.SubmissionSelection {
+	case SelectHighestScore:
+		return selectHighestScore(candidates, nil)
+	case SelectHighestBeforeDue:
+		dueDate := this.GetEffectiveDueDate(extension)
+		if dueDate == nil {
+			return selectHighestScore(candidates, nil)
+		}
+
+		selected := selectHighestScore(candidates, func(item *SubmissionHistoryItem) bool {
+			return (item.GradingStartTime <= *dueDate)
+		})
+
+		if selected != nil {
+			return selected
+		}
+	case SelectStudentFinal:
+		if final == nil {
+			break
+		}
+
+		for _, item := range candidates {
+			if getOriginalShortID(item) == final.SubmissionID {
+				return item
+			}
+		}
+	}
+
+	return latest
+}
+
+// Get the submissions that can be selected (keeping order):
+// every original submission, replaced by its most recent regrade (if any).
+func getSelectionCandidates(history []*SubmissionHistoryItem) []*SubmissionHistoryItem {
+	positions := make(map[string]int)
+	candidates := make([]*SubmissionHistoryItem, 0, len(history))
+
+	for _, item := range history {
+		id := getOriginalShortID(item)
+
+		position, ok := positions[id]
+		if ok {
+			candidates[position] = item
+			continue
+		}
+
+		positions[id] = len(candidates)
+		candidates = append(candidates, item)
+	}
+
+	return candidates
+}
+
+// Get the highest scoring item (ties go to the most recent) that passes the filter (if any).
+// Returns nil if no items pass the filter.
+func selectHighestScore(items []*SubmissionHistoryItem, filter func(*SubmissionHistoryItem) bool) *SubmissionHistoryItem {
+	var best *SubmissionHistoryItem = nil
+
+	for _, item := range items {
+		if (filter != nil) && !filter(item) {
+			continue
+		}
+
+		if (best == nil) || (item.Score >= best.Score) {
+			best = item
+		}
+	}
+
+	return best
+}
+
+func getOriginalShortID(item *SubmissionHistoryItem) string {
+	if item.IsRegrade() {
+		return common.GetShortSubmissionID(item.RegradeOf)
+	}
+
+	return item.ShortID
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestAssignmentSelectSubmission(test *testing.T) {
+	// Submission "3" was regraded twice ("4" and then "5").
+	history := []*SubmissionHistoryItem{
+		&SubmissionHistoryItem{ShortID: "1", Score: 5, GradingStartTime: timestamp.FromMSecs(100)},
+		&SubmissionHistoryItem{ShortID: "2", Score: 3, GradingStartTime: timestamp.FromMSecs(200)},
+		&SubmissionHistoryItem{ShortID: "3", Score: 10, GradingStartTime: timestamp.FromMSecs(300)},
+		&SubmissionHistoryItem{ShortID: "4", Score: 9, GradingStartTime: timestamp.FromMSecs(300), RegradeOf: "course::assignment::user::3"},
+		&SubmissionHistoryItem{ShortID: "5", Score: 4, GradingStartTime: timestamp.FromMSecs(300), RegradeOf: "course::assignment::user::3"},
+	}
+
+	early := timestamp.FromMSecs(50)
+	onTime := timestamp.FromMSecs(250)
+	late := timestamp.FromMSecs(1000)
+
+	testCases := []struct {
+		selection SubmissionSelectionPolicy
+		dueDate   *timestamp.Timestamp
+		extension *Extension
+		final     string
+		expected  string
+	}{
+		{"", nil, nil, "", "5"},
+		{SelectLatest, nil, nil, "1", "5"},
+
+		// Regrades replace their original.
+		{SelectHighestScore, nil, nil, "", "1"},
+
+		{SelectHighestBeforeDue, &onTime, nil, "", "1"},
+		{SelectHighestBeforeDue, &late, nil, "", "1"},
+		{SelectHighestBeforeDue, nil, nil, "", "1"},
+		{SelectHighestBeforeDue, &early, nil, "", "5"},
+		{SelectHighestBeforeDue, &early, &Extension{DueDate: &onTime}, "", "1"},
+
+		{SelectStudentFinal, nil, nil, "", "5"},
+		{SelectStudentFinal, nil, nil, "2", "2"},
+		{SelectStudentFinal, nil, nil, "3", "5"},
+		{SelectStudentFinal, nil, nil, "ZZZ", "5"},
+	}
+
+	for i, testCase := range testCases {
+		assignment := &Assignment{SubmissionSelection: testCase.selection, DueDate: testCase.dueDate}
+
+		var final *FinalSubmission
+		if testCase.final != "" {
+			final = &FinalSubmission{User: "user", SubmissionID: testCase.final}
+		}
+
+		selected := assignment.SelectSubmission(history, testCase.extension, final)
+		if (selected == nil) || (selected.ShortID != testCase.expected) {
+			test.Errorf("Case %d: Unexpected selection. Expected: '%s', Actual: '%+v'.", i, testCase.expected, selected)
+		}
+	}
+
+	assignment := &Assignment{SubmissionSelection: SelectHighestScore}
+	if assignment.SelectSubmission(nil, nil, nil) != nil {
+		test.Errorf("Got a selection from an empty history.")
+	}
+}
+
+func TestValidateSubmissionSelectionPolicy(test *testing.T) {
+	testCases := []struct {
+		input    SubmissionSelectionPolicy
+		expected SubmissionSelectionPolicy
+		hasError bool
+	}{
+		{"", "", false},
+		{" Highest-Score ", SelectHighestScore, false},
+		{"student-final", SelectStudentFinal, false},
+		{"ZZZ", "", true},
+	}
+
+	for i, testCase := range testCases {
+		actual, err := ValidateSubmissionSelectionPolicy(testCase.input)
+		if (err != nil) != testCase.hasError {
+			test.Errorf("Case %d: Unexpected error state. Expected error: %v, Actual: '%v'.", i, testCase.hasError, err)
+			continue
+		}
+
+		if actual != testCase.expected {
+			test.Errorf("Case %d: Unexpected result. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual)
+		}
+	}
+}
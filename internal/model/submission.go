@@ -10,27 +10,39 @@ type TestSubmission struct {
 }
 
 type SubmissionHistoryItem struct {
-	ID               string              `json:"id"`
-	ShortID          string              `json:"short-id"`
-	CourseID         string              `json:"course-id"`
-	AssignmentID     string              `json:"assignment-id"`
-	User             string              `json:"user"`
-	Message          string              `json:"message"`
-	MaxPoints        float64             `json:"max_points"`
-	Score            float64             `json:"score"`
-	GradingStartTime timestamp.Timestamp `json:"grading_start_time"`
+	ID                 string              `json:"id"`
+	ShortID            string              `json:"short-id"`
+	CourseID           string              `json:"course-id"`
+	AssignmentID       string              `json:"assignment-id"`
+	User               string              `json:"user"`
+	Message            string              `json:"message"`
+	MaxPoints          float64             `json:"max_points"`
+	Score              float64             `json:"score"`
+	GradingStartTime   timestamp.Timestamp `json:"grading_start_time"`
+	RegradeOf          string              `json:"regrade-of,omitempty"`
+	Team               string              `json:"team,omitempty"`
+	Submitter          string              `json:"submitter,omitempty"`
+	FeedbackRestricted bool                `json:"feedback-restricted,omitempty"`
 }
 
 func (this GradingInfo) ToHistoryItem() *SubmissionHistoryItem {
 	return &SubmissionHistoryItem{
-		ID:               this.ID,
-		ShortID:          this.ShortID,
-		CourseID:         this.CourseID,
-		AssignmentID:     this.AssignmentID,
-		User:             this.User,
-		Message:          this.Message,
-		MaxPoints:        this.MaxPoints,
-		Score:            this.Score,
-		GradingStartTime: this.GradingStartTime,
+		ID:                 this.ID,
+		ShortID:            this.ShortID,
+		CourseID:           this.CourseID,
+		AssignmentID:       this.AssignmentID,
+		User:               this.User,
+		Message:            this.Message,
+		MaxPoints:          this.MaxPoints,
+		Score:              this.Score,
+		GradingStartTime:   this.GradingStartTime,
+		RegradeOf:          this.RegradeOf,
+		Team:               this.Team,
+		Submitter:          this.Submitter,
+		FeedbackRestricted: this.FeedbackRestricted,
 	}
 }
+
+func (this SubmissionHistoryItem) IsRegrade() bool {
+	return (this.RegradeOf != "")
+}
@@ -60,6 +60,33 @@ func TestTaskValidationBase(test *testing.T) {
                         "course-admin@test.edulinq.org"
                     ]
                 }
+            }`,
+			"",
+		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseLMSReconcile,
+				When: &common.ScheduledTime{
+					Daily: "3:00",
+				},
+				Options: map[string]any{
+					"to": []string{
+						"course-admin@test.edulinq.org",
+					},
+				},
+			},
+			`{
+                "type": "lms-reconcile",
+                "when": {
+                    "daily": "3:00",
+                    "every": {}
+                },
+                "options": {
+                    "send-empty": false,
+                    "to": [
+                        "course-admin@test.edulinq.org"
+                    ]
+                }
             }`,
 			"",
 		},
@@ -203,6 +230,16 @@ func TestTaskValidationBase(test *testing.T) {
 			``,
 			"'to' value is not properly formatted",
 		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseLMSReconcile,
+				When: &common.ScheduledTime{
+					Daily: "3:00",
+				},
+			},
+			``,
+			"no email recipients are declared",
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -15,6 +15,7 @@ const (
 
 	TaskTypeCourseBackup        TaskType = "backup"
 	TaskTypeCourseEmailLogs     TaskType = "email-logs"
+	TaskTypeCourseLMSReconcile  TaskType = "lms-reconcile"
 	TaskTypeCourseReport        TaskType = "report"
 	TaskTypeCourseScoringUpload TaskType = "scoring-upload"
 	TaskTypeCourseUpdate        TaskType = "update"
@@ -27,6 +28,7 @@ var taskTypeToString = map[TaskType]string{
 
 	TaskTypeCourseBackup:        string(TaskTypeCourseBackup),
 	TaskTypeCourseEmailLogs:     string(TaskTypeCourseEmailLogs),
+	TaskTypeCourseLMSReconcile:  string(TaskTypeCourseLMSReconcile),
 	TaskTypeCourseReport:        string(TaskTypeCourseReport),
 	TaskTypeCourseScoringUpload: string(TaskTypeCourseScoringUpload),
 	TaskTypeCourseUpdate:        string(TaskTypeCourseUpdate),
@@ -39,6 +41,7 @@ var stringToTaskType = map[string]TaskType{
 
 	string(TaskTypeCourseBackup):        TaskTypeCourseBackup,
 	string(TaskTypeCourseEmailLogs):     TaskTypeCourseEmailLogs,
+	string(TaskTypeCourseLMSReconcile):  TaskTypeCourseLMSReconcile,
 	string(TaskTypeCourseReport):        TaskTypeCourseReport,
 	string(TaskTypeCourseScoringUpload): TaskTypeCourseScoringUpload,
 	string(TaskTypeCourseUpdate):        TaskTypeCourseUpdate,
@@ -71,6 +74,8 @@ func validateTaskTypes(task *UserTaskInfo) error {
 		return nil
 	case TaskTypeCourseEmailLogs:
 		return validateTaskTypeCourseEmailLogs(task)
+	case TaskTypeCourseLMSReconcile:
+		return validateTaskTypeCourseLMSReconcile(task)
 	case TaskTypeTest:
 		return nil
 	default:
@@ -101,6 +106,17 @@ func validateTaskTypeCourseEmailLogs(task *UserTaskInfo) error {
 	return nil
 }
 
+func validateTaskTypeCourseLMSReconcile(task *UserTaskInfo) error {
+	err := validateEmailList(task)
+	if err != nil {
+		return err
+	}
+
+	task.Options["send-empty"] = (task.Options["send-empty"] == true)
+
+	return nil
+}
+
 func validateTaskTypeCourseReport(task *UserTaskInfo) error {
 	return validateEmailList(task)
 }
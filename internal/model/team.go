@@ -0,0 +1,165 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+)
+
+const (
+	TEAM_SOURCE_CONFIG = "config"
+	TEAM_SOURCE_API    = "api"
+	TEAM_SOURCE_LMS    = "lms"
+)
+
+// Team settings for an assignment.
+// An assignment only has teams when this is set.
+type TeamInfo struct {
+	// The most members a team may have (zero means there is no limit).
+	MaxSize int `json:"max-size,omitempty"`
+
+	// The group set (group category) in the course's LMS that teams can be synced from.
+	LMSGroupSetID string `json:"lms-group-set-id,omitempty"`
+
+	// Teams defined in the assignment config.
+	Teams []*Team `json:"teams,omitempty"`
+}
+
+// A group of users that submit to an assignment together.
+// A submission from any member is recorded for every member.
+type Team struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	Members []string `json:"members"`
+
+	// Where this team came from (one of the TEAM_SOURCE_* constants).
+	Source string `json:"source,omitempty"`
+}
+
+func (this *TeamInfo) Validate() error {
+	if this.MaxSize < 0 {
+		return fmt.Errorf("Max team size cannot be negative: %d.", this.MaxSize)
+	}
+
+	this.LMSGroupSetID = strings.TrimSpace(this.LMSGroupSetID)
+
+	seenTeams := make(map[string]bool, len(this.Teams))
+	seenMembers := make(map[string]string)
+
+	for i, team := range this.Teams {
+		if team == nil {
+			return fmt.Errorf("Team at index %d is nil.", i)
+		}
+
+		team.Source = TEAM_SOURCE_CONFIG
+
+		err := this.ValidateTeam(team)
+		if err != nil {
+			return fmt.Errorf("Failed to validate team at index %d: '%w'.", i, err)
+		}
+
+		if seenTeams[team.ID] {
+			return fmt.Errorf("Duplicate team: '%s'.", team.ID)
+		}
+
+		seenTeams[team.ID] = true
+
+		for _, member := range team.Members {
+			otherTeam, ok := seenMembers[member]
+			if ok {
+				return fmt.Errorf("User '%s' is on more than one team ('%s' and '%s').", member, otherTeam, team.ID)
+			}
+
+			seenMembers[member] = team.ID
+		}
+	}
+
+	return nil
+}
+
+// Validate a team against these settings.
+func (this *TeamInfo) ValidateTeam(team *Team) error {
+	err := team.Validate()
+	if err != nil {
+		return err
+	}
+
+	if (this.MaxSize > 0) && (len(team.Members) > this.MaxSize) {
+		return fmt.Errorf("Team '%s' has %d members, but the max team size is %d.", team.ID, len(team.Members), this.MaxSize)
+	}
+
+	return nil
+}
+
+func (this *Team) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Team is nil.")
+	}
+
+	var err error
+	this.ID, err = common.ValidateID(this.ID)
+	if err != nil {
+		return err
+	}
+
+	this.Name = strings.TrimSpace(this.Name)
+
+	if len(this.Members) == 0 {
+		return fmt.Errorf("Team '%s' has no members.", this.ID)
+	}
+
+	for i, member := range this.Members {
+		this.Members[i] = strings.ToLower(strings.TrimSpace(member))
+		if this.Members[i] == "" {
+			return fmt.Errorf("Team '%s' has an empty member at index %d.", this.ID, i)
+		}
+	}
+
+	slices.Sort(this.Members)
+	this.Members = slices.Compact(this.Members)
+
+	if this.Source == "" {
+		this.Source = TEAM_SOURCE_API
+	}
+
+	return nil
+}
+
+// Get the team's name, falling back to id if there is no name.
+func (this *Team) GetName() string {
+	if this.Name == "" {
+		return this.ID
+	}
+
+	return this.Name
+}
+
+func (this *Team) HasMember(email string) bool {
+	return slices.Contains(this.Members, email)
+}
+
+// The result of syncing an assignment's teams with the groups in its LMS group set.
+type TeamSyncResult struct {
+	// Teams that were created or updated from LMS groups.
+	SyncedTeams []*Team `json:"synced-teams"`
+
+	// LMS teams that were removed because their LMS group no longer exists.
+	RemovedTeams []string `json:"removed-teams"`
+
+	// LMS groups that could not be made into teams (keyed by LMS group ID), along with the reason.
+	SkippedGroups map[string]string `json:"skipped-groups"`
+
+	// Members of LMS groups that are not students in the course (they are left off of their team).
+	UnknownUsers []string `json:"unknown-users"`
+}
+
+func NewTeamSyncResult() *TeamSyncResult {
+	return &TeamSyncResult{
+		SyncedTeams:   make([]*Team, 0),
+		RemovedTeams:  make([]string, 0),
+		SkippedGroups: make(map[string]string),
+		UnknownUsers:  make([]string, 0),
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTeamInfoValidate(test *testing.T) {
+	testCases := []struct {
+		info  *TeamInfo
+		valid bool
+	}{
+		{&TeamInfo{}, true},
+		{&TeamInfo{MaxSize: 2, Teams: []*Team{&Team{ID: "a", Members: []string{"x@test.edulinq.org", "y@test.edulinq.org"}}}}, true},
+		{&TeamInfo{Teams: []*Team{&Team{ID: "a", Members: []string{"x@test.edulinq.org"}}, &Team{ID: "b", Members: []string{"y@test.edulinq.org"}}}}, true},
+
+		{&TeamInfo{MaxSize: -1}, false},
+		{&TeamInfo{MaxSize: 1, Teams: []*Team{&Team{ID: "a", Members: []string{"x@test.edulinq.org", "y@test.edulinq.org"}}}}, false},
+		{&TeamInfo{Teams: []*Team{nil}}, false},
+		{&TeamInfo{Teams: []*Team{&Team{ID: "a"}}}, false},
+		{&TeamInfo{Teams: []*Team{&Team{ID: "a b", Members: []string{"x@test.edulinq.org"}}}}, false},
+		{&TeamInfo{Teams: []*Team{&Team{ID: "a", Members: []string{" "}}}}, false},
+		{&TeamInfo{Teams: []*Team{&Team{ID: "a", Members: []string{"x@test.edulinq.org"}}, &Team{ID: "a", Members: []string{"y@test.edulinq.org"}}}}, false},
+		{&TeamInfo{Teams: []*Team{&Team{ID: "a", Members: []string{"x@test.edulinq.org"}}, &Team{ID: "b", Members: []string{"x@test.edulinq.org"}}}}, false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.info.Validate()
+		if testCase.valid != (err == nil) {
+			test.Errorf("Case %d: Unexpected validation result. Expected valid: %v, Error: '%v'.", i, testCase.valid, err)
+		}
+	}
+}
+
+func TestTeamValidateNormalizesMembers(test *testing.T) {
+	team := &Team{ID: " A ", Members: []string{"Y@test.edulinq.org", " x@test.edulinq.org", "y@test.edulinq.org"}}
+
+	err := team.Validate()
+	if err != nil {
+		test.Fatalf("Failed to validate team: '%v'.", err)
+	}
+
+	expected := &Team{ID: "a", Members: []string{"x@test.edulinq.org", "y@test.edulinq.org"}, Source: TEAM_SOURCE_API}
+	if !reflect.DeepEqual(expected, team) {
+		test.Fatalf("Unexpected team. Expected: '%+v', Actual: '%+v'.", expected, team)
+	}
+}
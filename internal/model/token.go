@@ -26,6 +26,7 @@ const (
 	TokenSourceUser                 = "user"
 	TokenSourceAdmin                = "admin"
 	TokenSourcePassword             = "password"
+	TokenSourceLTI                  = "lti"
 )
 
 // Tokens refer to any hex string that is used for authentication.
@@ -37,6 +38,10 @@ type Token struct {
 	Name         string              `json:"name"`
 	CreationTime timestamp.Timestamp `json:"creation-time"`
 	AccessTime   timestamp.Timestamp `json:"access-time"`
+
+	// The only course this token can be used with (empty means the token is not limited).
+	// Limited tokens can only authenticate requests for their course.
+	Course string `json:"course,omitempty"`
 }
 
 const (
@@ -199,6 +204,7 @@ func (this *Token) Clone() *Token {
 		Name:         this.Name,
 		CreationTime: this.CreationTime,
 		AccessTime:   this.AccessTime,
+		Course:       this.Course,
 	}
 }
 
@@ -236,6 +242,11 @@ func TokenPointerCompare(a *Token, b *Token) int {
 		return result
 	}
 
+	result = strings.Compare(a.Course, b.Course)
+	if result != 0 {
+		return result
+	}
+
 	return 0
 }
 
@@ -341,11 +341,22 @@ func (this *ServerUser) CreateRandomToken(name string, source TokenSource) (*Tok
 // Attempt to authenticate this user with the provided text.
 // True will be returned if any of the tokens match.
 func (this *ServerUser) Auth(input string) (bool, error) {
-	var match bool = false
+	token, err := this.AuthToken(input)
+	if err != nil {
+		return false, err
+	}
+
+	return (token != nil), nil
+}
+
+// Attempt to authenticate this user with the provided text,
+// and return the token (or password) that matched (nil if nothing matched).
+func (this *ServerUser) AuthToken(input string) (*Token, error) {
+	var match *Token = nil
 	var errs error = nil
 
 	if this.Salt == nil {
-		return false, fmt.Errorf("User '%s' has no salt. Cannot auth.", this.Email)
+		return nil, fmt.Errorf("User '%s' has no salt. Cannot auth.", this.Email)
 	}
 
 	// Make sure that the password and all tokens are checked so we are not vulnerable to timing attacks.
@@ -353,17 +364,21 @@ func (this *ServerUser) Auth(input string) (bool, error) {
 	if this.Password != nil {
 		tokenMatch, err := this.Password.Check(input, *this.Salt)
 		errs = errors.Join(errs, err)
-		match = match || tokenMatch
+		if tokenMatch && (match == nil) {
+			match = this.Password
+		}
 	}
 
 	for _, token := range this.Tokens {
 		tokenMatch, err := token.Check(input, *this.Salt)
 		errs = errors.Join(errs, err)
-		match = match || tokenMatch
+		if tokenMatch && (match == nil) {
+			match = token
+		}
 	}
 
 	if errs != nil {
-		return false, errs
+		return nil, errs
 	}
 
 	return match, nil
@@ -0,0 +1,426 @@
+// Copy all the data from one database backend into another,
+// e.g., when moving a deployment from the disk database to Postgres.
+// The source is only read from, and the target is verified against the source after the copy.
+// Migrations should be done while the server is stopped,
+// since any writes to the source during a migration will cause verification to fail.
+package migrate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// The earliest possible time, used to select all time-based records.
+const MIN_TIME = timestamp.Timestamp(math.MinInt64)
+
+type MigrateOptions struct {
+	// Clear the target database before copying.
+	// If false, the target database must be empty.
+	ClearTarget bool
+}
+
+// A stats query that matches all records.
+type allStatsQuery struct{}
+
+func (this allStatsQuery) Match(record stats.Metric) bool {
+	return true
+}
+
+// Copy everything from the source backend into the target backend, and then verify the copy.
+// A report will be returned if the copy completed (even if verification failed),
+// the returned error will be non-nil if the copy failed or the verification did not match.
+func Migrate(source db.Backend, target db.Backend, options MigrateOptions) (*VerifyReport, error) {
+	if options.ClearTarget {
+		err := target.Clear()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to clear target database: '%w'.", err)
+		}
+	} else {
+		err := checkEmpty(target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	steps := []struct {
+		name      string
+		operation func(source db.Backend, target db.Backend) (int, error)
+	}{
+		{CATEGORY_COURSES, copyCourses},
+		{CATEGORY_USERS, copyUsers},
+		{CATEGORY_SUBMISSIONS, copySubmissions},
+		{CATEGORY_TASKS, copyTasks},
+		{CATEGORY_LOGS, copyLogs},
+		{CATEGORY_SYSTEM_STATS, copySystemStats},
+		{CATEGORY_COURSE_STATS, copyCourseStats},
+		{CATEGORY_GRADING_JOBS, copyGradingJobs},
+		{CATEGORY_MANUAL_GRADES, copyManualGrades},
+		{CATEGORY_EXTENSIONS, copyExtensions},
+		{CATEGORY_TEAMS, copyTeams},
+		{CATEGORY_FINAL_SUBMISSIONS, copyFinalSubmissions},
+	}
+
+	for _, step := range steps {
+		count, err := step.operation(source, target)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to migrate %s: '%w'.", step.name, err)
+		}
+
+		log.Info("Migrated records.", log.NewAttr("category", step.name), log.NewAttr("count", count))
+	}
+
+	report, err := Verify(source, target)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to verify migration: '%w'.", err)
+	}
+
+	if !report.Matches() {
+		return report, fmt.Errorf("Migrated database does not match the source database.")
+	}
+
+	return report, nil
+}
+
+func checkEmpty(target db.Backend) error {
+	courses, err := target.GetCourses()
+	if err != nil {
+		return fmt.Errorf("Failed to get courses from target database: '%w'.", err)
+	}
+
+	users, err := target.GetServerUsers()
+	if err != nil {
+		return fmt.Errorf("Failed to get users from target database: '%w'.", err)
+	}
+
+	if (len(courses) > 0) || (len(users) > 0) {
+		return fmt.Errorf("Target database is not empty (found %d courses and %d users).", len(courses), len(users))
+	}
+
+	return nil
+}
+
+func copyCourses(source db.Backend, target db.Backend) (int, error) {
+	courses, err := source.GetCourses()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, course := range courses {
+		// Saving a course also saves all of its assignments.
+		err = target.SaveCourse(course)
+		if err != nil {
+			return 0, fmt.Errorf("Failed to save course '%s': '%w'.", course.GetID(), err)
+		}
+	}
+
+	return len(courses), nil
+}
+
+func copyUsers(source db.Backend, target db.Backend) (int, error) {
+	users, err := source.GetServerUsers()
+	if err != nil {
+		return 0, err
+	}
+
+	err = target.UpsertUsers(users)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(users), nil
+}
+
+// Submissions are copied one user at a time to limit how many submission files are in memory at once.
+func copySubmissions(source db.Backend, target db.Backend) (int, error) {
+	count := 0
+
+	err := forEachUserSubmissions(source, func(course *model.Course, submissions []*model.GradingResult) error {
+		err := target.SaveSubmissions(course, submissions)
+		if err != nil {
+			return err
+		}
+
+		count += len(submissions)
+		return nil
+	})
+
+	return count, err
+}
+
+func copyTasks(source db.Backend, target db.Backend) (int, error) {
+	tasks, err := source.GetActiveTasks()
+	if err != nil {
+		return 0, err
+	}
+
+	err = target.UpsertActiveTasks(tasks)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(tasks), nil
+}
+
+func copyLogs(source db.Backend, target db.Backend) (int, error) {
+	records, err := getAllLogs(source)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		err = target.LogDirect(record)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(records), nil
+}
+
+func copySystemStats(source db.Backend, target db.Backend) (int, error) {
+	records, err := source.GetSystemStats(allStatsQuery{})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		err = target.StoreSystemStats(record)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(records), nil
+}
+
+func copyCourseStats(source db.Backend, target db.Backend) (int, error) {
+	count := 0
+
+	err := forEachCourseStats(source, func(records []*stats.CourseMetric) error {
+		for _, record := range records {
+			err := target.StoreCourseMetric(record)
+			if err != nil {
+				return err
+			}
+		}
+
+		count += len(records)
+		return nil
+	})
+
+	return count, err
+}
+
+func copyGradingJobs(source db.Backend, target db.Backend) (int, error) {
+	jobs, err := getAllGradingJobs(source)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		err = target.SaveGradingJob(job)
+		if err != nil {
+			return 0, fmt.Errorf("Failed to save grading job '%s': '%w'.", job.ID, err)
+		}
+	}
+
+	return len(jobs), nil
+}
+
+func copyManualGrades(source db.Backend, target db.Backend) (int, error) {
+	count := 0
+
+	err := forEachAssignment(source, func(assignment *model.Assignment) error {
+		grades, err := source.GetManualGrades(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get manual grades for assignment '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, grade := range grades {
+			err = target.SaveManualGrade(assignment, grade)
+			if err != nil {
+				return fmt.Errorf("Failed to save manual grade for '%s' on '%s': '%w'.", grade.User, assignment.FullID(), err)
+			}
+		}
+
+		count += len(grades)
+		return nil
+	})
+
+	return count, err
+}
+
+func copyExtensions(source db.Backend, target db.Backend) (int, error) {
+	count := 0
+
+	err := forEachAssignment(source, func(assignment *model.Assignment) error {
+		extensions, err := source.GetExtensions(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get extensions for assignment '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, extension := range extensions {
+			err = target.SaveExtension(assignment, extension)
+			if err != nil {
+				return fmt.Errorf("Failed to save extension for '%s' on '%s': '%w'.", extension.User, assignment.FullID(), err)
+			}
+		}
+
+		count += len(extensions)
+		return nil
+	})
+
+	return count, err
+}
+
+func copyTeams(source db.Backend, target db.Backend) (int, error) {
+	count := 0
+
+	err := forEachAssignment(source, func(assignment *model.Assignment) error {
+		teams, err := source.GetTeams(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get teams for assignment '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, team := range teams {
+			err = target.SaveTeam(assignment, team)
+			if err != nil {
+				return fmt.Errorf("Failed to save team '%s' on '%s': '%w'.", team.ID, assignment.FullID(), err)
+			}
+		}
+
+		count += len(teams)
+		return nil
+	})
+
+	return count, err
+}
+
+func copyFinalSubmissions(source db.Backend, target db.Backend) (int, error) {
+	count := 0
+
+	err := forEachAssignment(source, func(assignment *model.Assignment) error {
+		finals, err := source.GetFinalSubmissions(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get final submissions for assignment '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, final := range finals {
+			err = target.SaveFinalSubmission(assignment, final)
+			if err != nil {
+				return fmt.Errorf("Failed to save final submission for '%s' on '%s': '%w'.", final.User, assignment.FullID(), err)
+			}
+		}
+
+		count += len(finals)
+		return nil
+	})
+
+	return count, err
+}
+
+// Call the given function with all the submissions (attempts) for each user/assignment pair.
+func forEachUserSubmissions(backend db.Backend, operation func(*model.Course, []*model.GradingResult) error) error {
+	courses, err := backend.GetCourses()
+	if err != nil {
+		return err
+	}
+
+	for _, course := range courses {
+		for _, assignment := range course.Assignments {
+			emails, err := backend.GetSubmissionUsers(assignment)
+			if err != nil {
+				return fmt.Errorf("Failed to get submitting users for assignment '%s': '%w'.", assignment.FullID(), err)
+			}
+
+			for _, email := range emails {
+				submissions, err := backend.GetSubmissionAttempts(assignment, email)
+				if err != nil {
+					return fmt.Errorf("Failed to get submissions for assignment '%s' and user '%s': '%w'.", assignment.FullID(), email, err)
+				}
+
+				err = operation(course, submissions)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Call the given function with all the stats for each course.
+func forEachCourseStats(backend db.Backend, operation func([]*stats.CourseMetric) error) error {
+	courses, err := backend.GetCourses()
+	if err != nil {
+		return err
+	}
+
+	for _, course := range courses {
+		query := stats.CourseMetricQuery{
+			BaseQuery: stats.BaseQuery{After: MIN_TIME},
+			CourseID:  course.GetID(),
+		}
+
+		records, err := backend.GetCourseMetrics(query)
+		if err != nil {
+			return fmt.Errorf("Failed to get stats for course '%s': '%w'.", course.GetID(), err)
+		}
+
+		err = operation(records)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Call the given function with every assignment in every course.
+func forEachAssignment(backend db.Backend, operation func(*model.Assignment) error) error {
+	courses, err := backend.GetCourses()
+	if err != nil {
+		return err
+	}
+
+	for _, course := range courses {
+		for _, assignment := range course.Assignments {
+			err = operation(assignment)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func getAllLogs(backend db.Backend) ([]*log.Record, error) {
+	return backend.GetLogRecords(log.ParsedLogQuery{
+		Level: log.LevelTrace,
+		After: MIN_TIME,
+	})
+}
+
+func getAllGradingJobs(backend db.Backend) ([]*model.GradingJob, error) {
+	allJobs := make([]*model.GradingJob, 0)
+
+	statuses := []model.GradingJobStatus{model.GradingJobStatusPending, model.GradingJobStatusRunning, model.GradingJobStatusComplete}
+	for _, status := range statuses {
+		jobs, err := backend.GetGradingJobs(status)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get %s grading jobs: '%w'.", status, err)
+		}
+
+		allJobs = append(allJobs, jobs...)
+	}
+
+	return allJobs, nil
+}
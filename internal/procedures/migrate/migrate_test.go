@@ -0,0 +1,273 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestMigrateRoundTrip(test *testing.T) {
+	source, target := openTestBackends(test)
+	defer db.ResetForTesting()
+	defer source.Close()
+	defer target.Close()
+
+	addTestRecords(test, source)
+
+	report, err := Migrate(source, target, MigrateOptions{ClearTarget: true})
+	if err != nil {
+		test.Fatalf("Failed to migrate to SQLite: '%v'. Report: '%s'.", err, util.MustToJSONIndent(report))
+	}
+
+	for _, category := range report.Categories {
+		if category.SourceCount == 0 {
+			test.Errorf("Category '%s' has no records, the test data should cover every category.", category.Category)
+		}
+	}
+
+	// Migrate back into the (cleared) disk database.
+	backReport, err := Migrate(target, source, MigrateOptions{ClearTarget: true})
+	if err != nil {
+		test.Fatalf("Failed to migrate back to disk: '%v'. Report: '%s'.", err, util.MustToJSONIndent(backReport))
+	}
+
+	for i, category := range backReport.Categories {
+		if category.TargetChecksum != report.Categories[i].SourceChecksum {
+			test.Errorf("Category '%s' changed after a round trip. Expected: '%s', Actual: '%s'.",
+				category.Category, report.Categories[i].SourceChecksum, category.TargetChecksum)
+		}
+	}
+}
+
+func TestMigrateNonEmptyTarget(test *testing.T) {
+	source, target := openTestBackends(test)
+	defer db.ResetForTesting()
+	defer source.Close()
+	defer target.Close()
+
+	_, err := Migrate(source, target, MigrateOptions{ClearTarget: true})
+	if err != nil {
+		test.Fatalf("Failed to do initial migration: '%v'.", err)
+	}
+
+	_, err = Migrate(source, target, MigrateOptions{})
+	if err == nil {
+		test.Fatalf("Did not get an error when migrating into a non-empty database.")
+	}
+
+	if !strings.Contains(err.Error(), "not empty") {
+		test.Fatalf("Unexpected error when migrating into a non-empty database: '%v'.", err)
+	}
+}
+
+func TestVerifyMismatch(test *testing.T) {
+	source, target := openTestBackends(test)
+	defer db.ResetForTesting()
+	defer source.Close()
+	defer target.Close()
+
+	_, err := Migrate(source, target, MigrateOptions{ClearTarget: true})
+	if err != nil {
+		test.Fatalf("Failed to do initial migration: '%v'.", err)
+	}
+
+	course, err := target.GetCourse(db.TEST_COURSE_ID)
+	if err != nil {
+		test.Fatalf("Failed to get test course: '%v'.", err)
+	}
+
+	removed, err := target.RemoveSubmission(course.Assignments[db.TEST_ASSIGNMENT_ID], "course-student@test.edulinq.org", "")
+	if err != nil {
+		test.Fatalf("Failed to remove submission: '%v'.", err)
+	}
+
+	if !removed {
+		test.Fatalf("Did not remove a submission.")
+	}
+
+	report, err := Verify(source, target)
+	if err != nil {
+		test.Fatalf("Failed to verify: '%v'.", err)
+	}
+
+	if report.Matches() {
+		test.Fatalf("Report matches after a submission was removed.")
+	}
+
+	for _, category := range report.Categories {
+		expected := (category.Category != CATEGORY_SUBMISSIONS)
+		if expected != category.Matches() {
+			test.Errorf("Category '%s' has an unexpected match result. Expected: '%v', Actual: '%v'.",
+				category.Category, expected, category.Matches())
+		}
+	}
+}
+
+// Open the test (disk) database as the source and a SQLite database as the target.
+func openTestBackends(test *testing.T) (db.Backend, db.Backend) {
+	db.ResetForTesting()
+
+	// Keep the logging from writing into the source database during a migration.
+	log.SetStorageBackend(nil)
+
+	source, err := db.OpenBackend(db.DB_TYPE_DISK)
+	if err != nil {
+		test.Fatalf("Failed to open source database: '%v'.", err)
+	}
+
+	target, err := db.OpenBackend(db.DB_TYPE_SQLITE)
+	if err != nil {
+		test.Fatalf("Failed to open target database: '%v'.", err)
+	}
+
+	return source, target
+}
+
+// Add records in the categories that the test data does not already have.
+func addTestRecords(test *testing.T, backend db.Backend) {
+	tasks := map[string]*model.FullScheduledTask{
+		"A": &model.FullScheduledTask{
+			SystemTaskInfo: model.SystemTaskInfo{
+				Source:      model.TaskSourceCourse,
+				NextRunTime: timestamp.FromMSecs(100),
+				CourseID:    db.TEST_COURSE_ID,
+				Hash:        "A",
+			},
+		},
+	}
+
+	err := backend.UpsertActiveTasks(tasks)
+	if err != nil {
+		test.Fatalf("Failed to add test tasks: '%v'.", err)
+	}
+
+	err = backend.LogDirect(&log.Record{
+		Level:      log.LevelInfo,
+		Message:    "Test message.",
+		Timestamp:  timestamp.FromMSecs(200),
+		Course:     db.TEST_COURSE_ID,
+		Attributes: map[string]any{"key": "value"},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add test log record: '%v'.", err)
+	}
+
+	err = backend.StoreSystemStats(&stats.SystemMetrics{
+		BaseMetric: stats.BaseMetric{Timestamp: timestamp.FromMSecs(300)},
+		CPUPercent: 1,
+		MemPercent: 2,
+	})
+	if err != nil {
+		test.Fatalf("Failed to add test system stats: '%v'.", err)
+	}
+
+	err = backend.StoreCourseMetric(&stats.CourseMetric{
+		BaseMetric:   stats.BaseMetric{Timestamp: timestamp.FromMSecs(400)},
+		Type:         stats.CourseMetricTypeGradingTime,
+		CourseID:     db.TEST_COURSE_ID,
+		AssignmentID: db.TEST_ASSIGNMENT_ID,
+		UserEmail:    "course-student@test.edulinq.org",
+		Value:        100,
+	})
+	if err != nil {
+		test.Fatalf("Failed to add test course metric: '%v'.", err)
+	}
+
+	jobs := []*model.GradingJob{
+		&model.GradingJob{
+			ID:             "pending-job",
+			CourseID:       db.TEST_COURSE_ID,
+			AssignmentID:   db.TEST_ASSIGNMENT_ID,
+			User:           "course-student@test.edulinq.org",
+			Status:         model.GradingJobStatusPending,
+			CreateTime:     timestamp.FromMSecs(500),
+			InputFilesGZip: map[string][]byte{"submission.py": []byte{1, 2, 3}},
+		},
+		&model.GradingJob{
+			ID:             "complete-job",
+			CourseID:       db.TEST_COURSE_ID,
+			AssignmentID:   db.TEST_ASSIGNMENT_ID,
+			User:           "course-student@test.edulinq.org",
+			Status:         model.GradingJobStatusComplete,
+			CreateTime:     timestamp.FromMSecs(600),
+			StartTime:      timestamp.FromMSecs(700),
+			EndTime:        timestamp.FromMSecs(800),
+			GradingSuccess: true,
+			SubmissionID:   "course101::hw0::course-student@test.edulinq.org::1697406272",
+		},
+	}
+
+	for _, job := range jobs {
+		err = backend.SaveGradingJob(job)
+		if err != nil {
+			test.Fatalf("Failed to add test grading job: '%v'.", err)
+		}
+	}
+
+	course, err := backend.GetCourse(db.TEST_COURSE_ID)
+	if err != nil {
+		test.Fatalf("Failed to get test course: '%v'.", err)
+	}
+
+	grade := &model.ManualGrade{
+		CourseID:     db.TEST_COURSE_ID,
+		AssignmentID: db.TEST_ASSIGNMENT_ID,
+		User:         "course-student@test.edulinq.org",
+		Items: map[string]*model.ManualGradeItem{
+			"style": &model.ManualGradeItem{
+				Score:  1,
+				Grader: "course-grader@test.edulinq.org",
+				Time:   timestamp.FromMSecs(900),
+			},
+		},
+	}
+
+	err = backend.SaveManualGrade(course.Assignments[db.TEST_ASSIGNMENT_ID], grade)
+	if err != nil {
+		test.Fatalf("Failed to add test manual grade: '%v'.", err)
+	}
+
+	dueDate := timestamp.FromMSecs(1000)
+	extension := &model.Extension{
+		CourseID:     db.TEST_COURSE_ID,
+		AssignmentID: db.TEST_ASSIGNMENT_ID,
+		User:         "course-student@test.edulinq.org",
+		DueDate:      &dueDate,
+		Author:       "course-admin@test.edulinq.org",
+		Reason:       "Accommodation.",
+		Time:         timestamp.FromMSecs(1100),
+	}
+
+	err = backend.SaveExtension(course.Assignments[db.TEST_ASSIGNMENT_ID], extension)
+	if err != nil {
+		test.Fatalf("Failed to add test extension: '%v'.", err)
+	}
+
+	team := &model.Team{
+		ID:      "team-a",
+		Members: []string{"course-other@test.edulinq.org", "course-student@test.edulinq.org"},
+		Source:  model.TEAM_SOURCE_API,
+	}
+
+	err = backend.SaveTeam(course.Assignments[db.TEST_ASSIGNMENT_ID], team)
+	if err != nil {
+		test.Fatalf("Failed to add test team: '%v'.", err)
+	}
+
+	final := &model.FinalSubmission{
+		User:         "course-student@test.edulinq.org",
+		SubmissionID: "1697406265",
+		Time:         timestamp.FromMSecs(1000),
+	}
+
+	err = backend.SaveFinalSubmission(course.Assignments[db.TEST_ASSIGNMENT_ID], final)
+	if err != nil {
+		test.Fatalf("Failed to add test final submission: '%v'.", err)
+	}
+}
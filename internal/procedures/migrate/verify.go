@@ -0,0 +1,362 @@
+package migrate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	CATEGORY_COURSES           = "courses"
+	CATEGORY_USERS             = "users"
+	CATEGORY_SUBMISSIONS       = "submissions"
+	CATEGORY_TASKS             = "tasks"
+	CATEGORY_LOGS              = "logs"
+	CATEGORY_SYSTEM_STATS      = "system-stats"
+	CATEGORY_COURSE_STATS      = "course-stats"
+	CATEGORY_GRADING_JOBS      = "grading-jobs"
+	CATEGORY_MANUAL_GRADES     = "manual-grades"
+	CATEGORY_EXTENSIONS        = "extensions"
+	CATEGORY_TEAMS             = "teams"
+	CATEGORY_FINAL_SUBMISSIONS = "final-submissions"
+)
+
+// A comparison of one category of data between two databases.
+// The checksum of a category is computed over the checksums of each record (which is the hash of the record's JSON),
+// and does not depend on the order the records are returned in.
+type CategoryReport struct {
+	Category       string `json:"category"`
+	SourceCount    int    `json:"source-count"`
+	TargetCount    int    `json:"target-count"`
+	SourceChecksum string `json:"source-checksum"`
+	TargetChecksum string `json:"target-checksum"`
+}
+
+type VerifyReport struct {
+	Categories []*CategoryReport `json:"categories"`
+}
+
+func (this *CategoryReport) Matches() bool {
+	return (this.SourceCount == this.TargetCount) && (this.SourceChecksum == this.TargetChecksum)
+}
+
+func (this *VerifyReport) Matches() bool {
+	for _, category := range this.Categories {
+		if !category.Matches() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compare the contents of two databases.
+// The returned error is only for failures when reading the databases,
+// check VerifyReport.Matches() to see if the databases match.
+func Verify(source db.Backend, target db.Backend) (*VerifyReport, error) {
+	checksummers := []struct {
+		category string
+		checksum func(backend db.Backend) (int, string, error)
+	}{
+		{CATEGORY_COURSES, checksumCourses},
+		{CATEGORY_USERS, checksumUsers},
+		{CATEGORY_SUBMISSIONS, checksumSubmissions},
+		{CATEGORY_TASKS, checksumTasks},
+		{CATEGORY_LOGS, checksumLogs},
+		{CATEGORY_SYSTEM_STATS, checksumSystemStats},
+		{CATEGORY_COURSE_STATS, checksumCourseStats},
+		{CATEGORY_GRADING_JOBS, checksumGradingJobs},
+		{CATEGORY_MANUAL_GRADES, checksumManualGrades},
+		{CATEGORY_EXTENSIONS, checksumExtensions},
+		{CATEGORY_TEAMS, checksumTeams},
+		{CATEGORY_FINAL_SUBMISSIONS, checksumFinalSubmissions},
+	}
+
+	report := &VerifyReport{
+		Categories: make([]*CategoryReport, 0, len(checksummers)),
+	}
+
+	for _, checksummer := range checksummers {
+		sourceCount, sourceChecksum, err := checksummer.checksum(source)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to checksum %s in the source database: '%w'.", checksummer.category, err)
+		}
+
+		targetCount, targetChecksum, err := checksummer.checksum(target)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to checksum %s in the target database: '%w'.", checksummer.category, err)
+		}
+
+		report.Categories = append(report.Categories, &CategoryReport{
+			Category:       checksummer.category,
+			SourceCount:    sourceCount,
+			TargetCount:    targetCount,
+			SourceChecksum: sourceChecksum,
+			TargetChecksum: targetChecksum,
+		})
+	}
+
+	return report, nil
+}
+
+// Accumulates the hashes of individual records.
+type checksummer struct {
+	hashes []string
+}
+
+func (this *checksummer) add(record any) error {
+	hash, err := util.Sha256HashFromJSONObject(record)
+	if err != nil {
+		return fmt.Errorf("Failed to hash record: '%w'.", err)
+	}
+
+	this.hashes = append(this.hashes, hash)
+	return nil
+}
+
+// Returns (count, checksum, error).
+// Passing the error through makes for terser checksum functions.
+func (this *checksummer) result(err error) (int, string, error) {
+	if err != nil {
+		return 0, "", err
+	}
+
+	slices.Sort(this.hashes)
+	return len(this.hashes), util.Sha256HexFromString(strings.Join(this.hashes, "\n")), nil
+}
+
+func checksumCourses(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	courses, err := backend.GetCourses()
+	if err != nil {
+		return sum.result(err)
+	}
+
+	for _, course := range courses {
+		// Assignments are not included in a course's JSON.
+		err = sum.add([]any{course, course.GetSortedAssignments()})
+		if err != nil {
+			return sum.result(err)
+		}
+	}
+
+	return sum.result(nil)
+}
+
+func checksumUsers(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	users, err := backend.GetServerUsers()
+	if err != nil {
+		return sum.result(err)
+	}
+
+	for _, user := range users {
+		err = sum.add(user)
+		if err != nil {
+			return sum.result(err)
+		}
+	}
+
+	return sum.result(nil)
+}
+
+func checksumSubmissions(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	err := forEachUserSubmissions(backend, func(course *model.Course, submissions []*model.GradingResult) error {
+		for _, submission := range submissions {
+			err := sum.add(submission)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return sum.result(err)
+}
+
+func checksumTasks(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	tasks, err := backend.GetActiveTasks()
+	if err != nil {
+		return sum.result(err)
+	}
+
+	for hash, task := range tasks {
+		err = sum.add([]any{hash, task})
+		if err != nil {
+			return sum.result(err)
+		}
+	}
+
+	return sum.result(nil)
+}
+
+func checksumLogs(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	records, err := getAllLogs(backend)
+	if err != nil {
+		return sum.result(err)
+	}
+
+	for _, record := range records {
+		err = sum.add(record)
+		if err != nil {
+			return sum.result(err)
+		}
+	}
+
+	return sum.result(nil)
+}
+
+func checksumSystemStats(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	records, err := backend.GetSystemStats(allStatsQuery{})
+	if err != nil {
+		return sum.result(err)
+	}
+
+	for _, record := range records {
+		err = sum.add(record)
+		if err != nil {
+			return sum.result(err)
+		}
+	}
+
+	return sum.result(nil)
+}
+
+func checksumCourseStats(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	err := forEachCourseStats(backend, func(records []*stats.CourseMetric) error {
+		for _, record := range records {
+			err := sum.add(record)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return sum.result(err)
+}
+
+func checksumGradingJobs(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	jobs, err := getAllGradingJobs(backend)
+	if err != nil {
+		return sum.result(err)
+	}
+
+	for _, job := range jobs {
+		err = sum.add(job)
+		if err != nil {
+			return sum.result(err)
+		}
+	}
+
+	return sum.result(nil)
+}
+
+func checksumManualGrades(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	err := forEachAssignment(backend, func(assignment *model.Assignment) error {
+		grades, err := backend.GetManualGrades(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get manual grades for assignment '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, grade := range grades {
+			err = sum.add(grade)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return sum.result(err)
+}
+
+func checksumExtensions(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	err := forEachAssignment(backend, func(assignment *model.Assignment) error {
+		extensions, err := backend.GetExtensions(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get extensions for assignment '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, extension := range extensions {
+			err = sum.add(extension)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return sum.result(err)
+}
+
+func checksumTeams(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	err := forEachAssignment(backend, func(assignment *model.Assignment) error {
+		teams, err := backend.GetTeams(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get teams for assignment '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, team := range teams {
+			err = sum.add(team)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return sum.result(err)
+}
+
+func checksumFinalSubmissions(backend db.Backend) (int, string, error) {
+	var sum checksummer
+
+	err := forEachAssignment(backend, func(assignment *model.Assignment) error {
+		finals, err := backend.GetFinalSubmissions(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get final submissions for assignment '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, final := range finals {
+			err = sum.add(final)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return sum.result(err)
+}
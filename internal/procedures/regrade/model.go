@@ -0,0 +1,65 @@
+package regrade
+
+import (
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type RegradeOptions struct {
+	AllAttempts bool     `json:"all-attempts" help:"Regrade every submission (instead of just the most recent submission for each user)." default:"false"`
+	DryRun      bool     `json:"dry-run" help:"Do not save any regrades, just report how scores would change." default:"false"`
+	Users       []string `json:"users" help:"Only regrade submissions from these users (all users by default)."`
+}
+
+// The outcome of regrading a single submission.
+type RegradeResult struct {
+	User            string `json:"user"`
+	OldSubmissionID string `json:"old-submission-id"`
+	// Not set on a dry run or when the regrade failed.
+	NewSubmissionID string `json:"new-submission-id,omitempty"`
+
+	Success bool `json:"success"`
+	// Why the regrade failed (if it did).
+	Message string `json:"message,omitempty"`
+
+	OldScore     float64 `json:"old-score"`
+	OldMaxPoints float64 `json:"old-max-points"`
+	NewScore     float64 `json:"new-score"`
+	NewMaxPoints float64 `json:"new-max-points"`
+	// True if any question (not just the total score) changed.
+	Changed bool `json:"changed"`
+}
+
+// The progress (and eventually the final report) of a regrade.
+type RegradeStatus struct {
+	ID           string         `json:"id"`
+	CourseID     string         `json:"course-id"`
+	AssignmentID string         `json:"assignment-id"`
+	Options      RegradeOptions `json:"options"`
+
+	StartTime timestamp.Timestamp `json:"start-time"`
+	EndTime   timestamp.Timestamp `json:"end-time,omitempty"`
+	Done      bool                `json:"done"`
+	// Set if the regrade could not be run (individual submission failures are in the results).
+	Error string `json:"error,omitempty"`
+
+	// The number of submissions to regrade.
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Changed   int `json:"changed"`
+
+	// Sorted by user and then old submission ID once the regrade is done.
+	Results []*RegradeResult `json:"results"`
+}
+
+func (this *RegradeResult) ScoreDiff() float64 {
+	return this.NewScore - this.OldScore
+}
+
+func (this *RegradeStatus) copy() *RegradeStatus {
+	statusCopy := *this
+	statusCopy.Options.Users = append([]string(nil), this.Options.Users...)
+	statusCopy.Results = append([]*RegradeResult(nil), this.Results...)
+
+	return &statusCopy
+}
@@ -0,0 +1,338 @@
+package regrade
+
+// Regrade existing submissions for an assignment (e.g., after a bug in the grader was fixed).
+// Each regrade runs the current grader on the stored files of a submission, and is saved as a new submission.
+// Regrades are tagged with the submission they regrade (see model.GradingInfo.RegradeOf),
+// keep the original submission's times (so late policies apply the same way),
+// and do not count against submission limits.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// A function that gets called with the current status after each submission is regraded.
+type ProgressFunc func(status *RegradeStatus)
+
+var (
+	statusLock sync.Mutex
+	// All the regrades started with Start() (that have not expired): {id: status}.
+	statuses map[string]*RegradeStatus = make(map[string]*RegradeStatus)
+
+	// How long the status of a finished regrade is kept.
+	// This is a variable (instead of a constant) so it can be changed for testing.
+	statusTTL time.Duration = 24 * time.Hour
+)
+
+// Start a regrade in the background and return its initial status.
+// The status can be checked with GetStatus().
+func Start(assignment *model.Assignment, options RegradeOptions) *RegradeStatus {
+	status := newStatus(assignment, options)
+
+	statusLock.Lock()
+	pruneStatusesLocked()
+	statuses[status.ID] = status
+	initialStatus := status.copy()
+	statusLock.Unlock()
+
+	go func() {
+		err := regrade(context.Background(), assignment, status, nil)
+		if err != nil {
+			log.Error("Failed to regrade assignment.", err, assignment, log.NewAttr("regrade-id", status.ID))
+		}
+	}()
+
+	return initialStatus
+}
+
+// Get the status of a regrade started with Start().
+// Returns nil if the regrade is not known (or it finished more than statusTTL ago).
+func GetStatus(id string) *RegradeStatus {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+
+	pruneStatusesLocked()
+
+	status, ok := statuses[id]
+	if !ok {
+		return nil
+	}
+
+	return status.copy()
+}
+
+// Remove the statuses of regrades that finished more than statusTTL ago.
+// The caller must hold the status lock.
+func pruneStatusesLocked() {
+	expiration := timestamp.Now().ToMSecs() - statusTTL.Milliseconds()
+
+	for id, status := range statuses {
+		if status.Done && (status.EndTime.ToMSecs() < expiration) {
+			delete(statuses, id)
+		}
+	}
+}
+
+// Regrade an assignment and wait for it to complete.
+// The progress function (which may be nil) is called after each submission is regraded.
+// An error is only returned if the regrade could not be run (the error is also recorded in the status).
+func Regrade(ctx context.Context, assignment *model.Assignment, options RegradeOptions, progress ProgressFunc) (*RegradeStatus, error) {
+	status := newStatus(assignment, options)
+	err := regrade(ctx, assignment, status, progress)
+
+	statusLock.Lock()
+	defer statusLock.Unlock()
+
+	return status.copy(), err
+}
+
+func newStatus(assignment *model.Assignment, options RegradeOptions) *RegradeStatus {
+	return &RegradeStatus{
+		ID:           util.UUID(),
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		Options:      options,
+		StartTime:    timestamp.Now(),
+		Results:      make([]*RegradeResult, 0),
+	}
+}
+
+func regrade(ctx context.Context, assignment *model.Assignment, status *RegradeStatus, progress ProgressFunc) error {
+	err := runRegrade(ctx, assignment, status, progress)
+
+	statusLock.Lock()
+	defer statusLock.Unlock()
+
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	slices.SortFunc(status.Results, compareResults)
+
+	status.EndTime = timestamp.Now()
+	status.Done = true
+
+	log.Info("Regrade complete.", assignment,
+		log.NewAttr("regrade-id", status.ID), log.NewAttr("dry-run", status.Options.DryRun),
+		log.NewAttr("total", status.Total), log.NewAttr("failed", status.Failed), log.NewAttr("changed", status.Changed))
+
+	return err
+}
+
+func runRegrade(ctx context.Context, assignment *model.Assignment, status *RegradeStatus, progress ProgressFunc) error {
+	targets, err := getTargets(assignment, status.Options)
+	if err != nil {
+		return fmt.Errorf("Failed to get submissions to regrade: '%w'.", err)
+	}
+
+	users := make([]string, 0, len(targets))
+	for user, submissionIDs := range targets {
+		users = append(users, user)
+
+		statusLock.Lock()
+		status.Total += len(submissionIDs)
+		statusLock.Unlock()
+	}
+
+	slices.Sort(users)
+
+	log.Info("Starting regrade.", assignment,
+		log.NewAttr("regrade-id", status.ID), log.NewAttr("dry-run", status.Options.DryRun), log.NewAttr("total", status.Total))
+
+	gradeOptions := grader.GetDefaultGradeOptions()
+	gradeOptions.DryRun = status.Options.DryRun
+
+	// Each user's submissions are regraded in order (so the regrade of the most recent submission is saved last),
+	// but different users can be regraded at the same time.
+	userChan := make(chan string, len(users))
+	for _, user := range users {
+		userChan <- user
+	}
+	close(userChan)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < max(1, config.GRADING_REGRADE_WORKERS.Get()); i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			for user := range userChan {
+				for _, submissionID := range targets[user] {
+					result := regradeSubmission(ctx, assignment, user, submissionID, gradeOptions)
+					recordResult(status, result, progress)
+				}
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	return nil
+}
+
+// Get the submissions to regrade: {user: [short submission id, ...]}.
+func getTargets(assignment *model.Assignment, options RegradeOptions) (map[string][]string, error) {
+	targets := make(map[string][]string)
+
+	if !options.AllAttempts {
+		recentSubmissions, err := db.GetRecentSubmissions(assignment, model.CourseRoleUnknown)
+		if err != nil {
+			return nil, err
+		}
+
+		for user, gradingInfo := range recentSubmissions {
+			if (gradingInfo == nil) || !includeUser(options, user) {
+				continue
+			}
+
+			targets[user] = []string{gradingInfo.ShortID}
+		}
+
+		return targets, nil
+	}
+
+	users, err := db.GetSubmissionUsers(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if !includeUser(options, user) {
+			continue
+		}
+
+		history, err := db.GetSubmissionHistory(assignment, user)
+		if err != nil {
+			return nil, err
+		}
+
+		submissionIDs := make([]string, 0, len(history))
+		for _, item := range history {
+			// Earlier regrades are skipped (their original submissions will be regraded instead).
+			if item.IsRegrade() {
+				continue
+			}
+
+			submissionIDs = append(submissionIDs, item.ShortID)
+		}
+
+		if len(submissionIDs) > 0 {
+			targets[user] = submissionIDs
+		}
+	}
+
+	return targets, nil
+}
+
+func includeUser(options RegradeOptions, user string) bool {
+	return (len(options.Users) == 0) || slices.Contains(options.Users, user)
+}
+
+func regradeSubmission(ctx context.Context, assignment *model.Assignment, user string, submissionID string, gradeOptions grader.GradeOptions) *RegradeResult {
+	result := &RegradeResult{
+		User:            user,
+		OldSubmissionID: submissionID,
+	}
+
+	submission, err := db.GetSubmissionContents(assignment, user, submissionID)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to get submission: '%v'.", err)
+		return result
+	}
+
+	if (submission == nil) || (submission.Info == nil) {
+		result.Message = "Could not find submission."
+		return result
+	}
+
+	result.OldSubmissionID = submission.Info.ID
+	result.OldScore = submission.Info.Score
+	result.OldMaxPoints = submission.Info.MaxPoints
+
+	submissionDir, err := util.MkDirTemp("regrade-")
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to make temp dir: '%v'.", err)
+		return result
+	}
+	defer os.RemoveAll(submissionDir)
+
+	err = util.GzipBytesToDirectory(submissionDir, submission.InputFilesGZip)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to write submission files: '%v'.", err)
+		return result
+	}
+
+	gradeOptions.RegradeOf = submission.Info
+
+	gradingResult, _, softError, err := grader.Grade(ctx, assignment, submissionDir, user, submission.Info.Message, false, gradeOptions)
+	if err != nil {
+		log.Warn("Failed to regrade submission.", err, assignment, log.NewUserAttr(user), log.NewAttr("submission", submission.Info.ID))
+		result.Message = fmt.Sprintf("Failed to grade submission: '%v'.", err)
+		return result
+	}
+
+	if softError != "" {
+		result.Message = softError
+		return result
+	}
+
+	result.Success = true
+	result.NewScore = gradingResult.Info.Score
+	result.NewMaxPoints = gradingResult.Info.MaxPoints
+	result.Changed = (result.OldScore != result.NewScore) || (result.OldMaxPoints != result.NewMaxPoints) ||
+		!gradingResult.Info.Equals(*submission.Info, false)
+
+	if !gradeOptions.DryRun {
+		result.NewSubmissionID = gradingResult.Info.ID
+	}
+
+	return result
+}
+
+func recordResult(status *RegradeStatus, result *RegradeResult, progress ProgressFunc) {
+	statusLock.Lock()
+
+	status.Results = append(status.Results, result)
+	status.Completed++
+
+	if !result.Success {
+		status.Failed++
+	}
+
+	if result.Changed {
+		status.Changed++
+	}
+
+	var statusCopy *RegradeStatus
+	if progress != nil {
+		statusCopy = status.copy()
+	}
+
+	statusLock.Unlock()
+
+	if progress != nil {
+		progress(statusCopy)
+	}
+}
+
+func compareResults(a *RegradeResult, b *RegradeResult) int {
+	value := strings.Compare(a.User, b.User)
+	if value != 0 {
+		return value
+	}
+
+	return strings.Compare(a.OldSubmissionID, b.OldSubmissionID)
+}
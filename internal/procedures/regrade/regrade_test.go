@@ -0,0 +1,402 @@
+package regrade
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	STUDENT_EMAIL = "course-student@test.edulinq.org"
+	OTHER_EMAIL   = "course-other@test.edulinq.org"
+)
+
+func TestRegradeDryRun(test *testing.T) {
+	assignment, oldIDs := setupForTesting(test)
+	defer db.ResetForTesting()
+	defer setNoDockerForTesting()()
+
+	options := RegradeOptions{
+		DryRun: true,
+		Users:  []string{STUDENT_EMAIL, OTHER_EMAIL},
+	}
+
+	status, err := Regrade(context.Background(), assignment, options, nil)
+	if err != nil {
+		test.Fatalf("Failed to regrade: '%v'.", err)
+	}
+
+	checkStatus(test, status, 2, 0, 1)
+
+	// The student's (broken) most recent submission should be fixed, the other user did not change.
+	checkResult(test, status.Results[0], OTHER_EMAIL, oldIDs[OTHER_EMAIL][0], false, 0, 0)
+	checkResult(test, status.Results[1], STUDENT_EMAIL, oldIDs[STUDENT_EMAIL][1], true, 0, 10)
+
+	for i, result := range status.Results {
+		if result.NewSubmissionID != "" {
+			test.Errorf("Case %d: Dry run has a new submission: '%s'.", i, result.NewSubmissionID)
+		}
+	}
+
+	checkHistoryLength(test, assignment, STUDENT_EMAIL, 2)
+	checkHistoryLength(test, assignment, OTHER_EMAIL, 1)
+}
+
+func TestRegradeRecent(test *testing.T) {
+	assignment, oldIDs := setupForTesting(test)
+	defer db.ResetForTesting()
+	defer setNoDockerForTesting()()
+
+	options := RegradeOptions{
+		Users: []string{STUDENT_EMAIL, OTHER_EMAIL},
+	}
+
+	status, err := Regrade(context.Background(), assignment, options, nil)
+	if err != nil {
+		test.Fatalf("Failed to regrade: '%v'.", err)
+	}
+
+	checkStatus(test, status, 2, 0, 1)
+	checkResult(test, status.Results[1], STUDENT_EMAIL, oldIDs[STUDENT_EMAIL][1], true, 0, 10)
+
+	checkHistoryLength(test, assignment, STUDENT_EMAIL, 3)
+	checkHistoryLength(test, assignment, OTHER_EMAIL, 2)
+
+	original, err := db.GetSubmissionResult(assignment, STUDENT_EMAIL, getShortID(test, assignment, STUDENT_EMAIL, oldIDs[STUDENT_EMAIL][1]))
+	if err != nil {
+		test.Fatalf("Failed to get original submission: '%v'.", err)
+	}
+
+	recent, err := db.GetSubmissionResult(assignment, STUDENT_EMAIL, "")
+	if err != nil {
+		test.Fatalf("Failed to get recent submission: '%v'.", err)
+	}
+
+	if recent.ID != status.Results[1].NewSubmissionID {
+		test.Fatalf("Regrade is not the most recent submission. Expected: '%s', Actual: '%s'.", status.Results[1].NewSubmissionID, recent.ID)
+	}
+
+	if recent.RegradeOf != original.ID {
+		test.Fatalf("Regrade is not tagged with the original submission. Expected: '%s', Actual: '%s'.", original.ID, recent.RegradeOf)
+	}
+
+	if recent.RegradeTime.IsZero() {
+		test.Fatalf("Regrade does not have a regrade time.")
+	}
+
+	if (recent.GradingStartTime != original.GradingStartTime) || (recent.Message != original.Message) {
+		test.Fatalf("Regrade did not keep the original submission's time and message. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(original), util.MustToJSONIndent(recent))
+	}
+
+	// Regrading again should point at the original submission, and not change anything.
+	status, err = Regrade(context.Background(), assignment, options, nil)
+	if err != nil {
+		test.Fatalf("Failed to regrade again: '%v'.", err)
+	}
+
+	checkStatus(test, status, 2, 0, 0)
+
+	recent, err = db.GetSubmissionResult(assignment, STUDENT_EMAIL, "")
+	if err != nil {
+		test.Fatalf("Failed to get recent submission: '%v'.", err)
+	}
+
+	if recent.RegradeOf != original.ID {
+		test.Fatalf("Regrade of a regrade is not tagged with the original submission. Expected: '%s', Actual: '%s'.", original.ID, recent.RegradeOf)
+	}
+}
+
+func TestRegradeAllAttempts(test *testing.T) {
+	assignment, oldIDs := setupForTesting(test)
+	defer db.ResetForTesting()
+	defer setNoDockerForTesting()()
+
+	options := RegradeOptions{
+		Users: []string{STUDENT_EMAIL, OTHER_EMAIL},
+	}
+
+	// Regrade the most recent submissions first, these regrades should be skipped.
+	_, err := Regrade(context.Background(), assignment, options, nil)
+	if err != nil {
+		test.Fatalf("Failed to regrade: '%v'.", err)
+	}
+
+	options.AllAttempts = true
+
+	progressCount := 0
+	progress := func(status *RegradeStatus) {
+		progressCount++
+
+		if status.Completed != progressCount {
+			test.Errorf("Unexpected progress. Expected: %d, Actual: %d.", progressCount, status.Completed)
+		}
+	}
+
+	status, err := Regrade(context.Background(), assignment, options, progress)
+	if err != nil {
+		test.Fatalf("Failed to regrade: '%v'.", err)
+	}
+
+	if progressCount != 3 {
+		test.Fatalf("Unexpected number of progress updates. Expected: %d, Actual: %d.", 3, progressCount)
+	}
+
+	checkStatus(test, status, 3, 0, 1)
+
+	checkResult(test, status.Results[0], OTHER_EMAIL, oldIDs[OTHER_EMAIL][0], false, 0, 0)
+	checkResult(test, status.Results[1], STUDENT_EMAIL, oldIDs[STUDENT_EMAIL][0], false, 10, 10)
+	checkResult(test, status.Results[2], STUDENT_EMAIL, oldIDs[STUDENT_EMAIL][1], true, 0, 10)
+
+	// The regrade of the most recent submission is saved last.
+	recent, err := db.GetSubmissionResult(assignment, STUDENT_EMAIL, "")
+	if err != nil {
+		test.Fatalf("Failed to get recent submission: '%v'.", err)
+	}
+
+	if recent.RegradeOf != oldIDs[STUDENT_EMAIL][1] {
+		test.Fatalf("Most recent submission is not a regrade of the most recent original submission. Expected: '%s', Actual: '%s'.",
+			oldIDs[STUDENT_EMAIL][1], recent.RegradeOf)
+	}
+}
+
+func TestRegradeScoreOverrides(test *testing.T) {
+	assignment, oldIDs := setupForTesting(test)
+	defer db.ResetForTesting()
+	defer setNoDockerForTesting()()
+
+	override := &model.ScoreOverride{
+		Question: "Task 1: add()",
+		Score:    5,
+		Author:   "course-grader@test.edulinq.org",
+		Reason:   "Partial credit.",
+	}
+
+	_, err := db.ApplyScoreOverride(assignment, OTHER_EMAIL, "", override)
+	if err != nil {
+		test.Fatalf("Failed to apply override: '%v'.", err)
+	}
+
+	options := RegradeOptions{
+		Users: []string{OTHER_EMAIL},
+	}
+
+	status, err := Regrade(context.Background(), assignment, options, nil)
+	if err != nil {
+		test.Fatalf("Failed to regrade: '%v'.", err)
+	}
+
+	// The override is kept, so nothing changed.
+	checkStatus(test, status, 1, 0, 0)
+	checkResult(test, status.Results[0], OTHER_EMAIL, oldIDs[OTHER_EMAIL][0], false, 5, 5)
+
+	recent, err := db.GetSubmissionResult(assignment, OTHER_EMAIL, "")
+	if err != nil {
+		test.Fatalf("Failed to get recent submission: '%v'.", err)
+	}
+
+	question := recent.GetQuestion(override.Question)
+	if (question.GraderScore == nil) || (*question.GraderScore != 0) || (len(recent.GetActiveScoreOverrides()) != 1) {
+		test.Fatalf("Override was not carried into the regrade: '%s'.", util.MustToJSONIndent(recent))
+	}
+}
+
+func TestRegradeStart(test *testing.T) {
+	assignment, _ := setupForTesting(test)
+	defer db.ResetForTesting()
+	defer setNoDockerForTesting()()
+
+	options := RegradeOptions{
+		DryRun: true,
+		Users:  []string{STUDENT_EMAIL},
+	}
+
+	status := Start(assignment, options)
+	if status.Done {
+		test.Fatalf("Regrade is done before it started.")
+	}
+
+	for i := 0; i < 100; i++ {
+		status = GetStatus(status.ID)
+		if status == nil {
+			test.Fatalf("Could not find regrade.")
+		}
+
+		if status.Done {
+			break
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if !status.Done {
+		test.Fatalf("Regrade did not finish.")
+	}
+
+	checkStatus(test, status, 1, 0, 1)
+
+	if GetStatus("ZZZ") != nil {
+		test.Fatalf("Found an unknown regrade.")
+	}
+}
+
+// Statuses of finished regrades expire.
+func TestRegradeStatusExpiration(test *testing.T) {
+	oldTTL := statusTTL
+	statusTTL = time.Hour
+	defer func() {
+		statusTTL = oldTTL
+	}()
+
+	now := timestamp.Now()
+
+	testCases := []struct {
+		done     bool
+		endTime  timestamp.Timestamp
+		expected bool
+	}{
+		{false, timestamp.Zero(), true},
+		{true, now, true},
+		{true, now - timestamp.FromMSecs(30*60*1000), true},
+		{true, now - timestamp.FromMSecs(2*60*60*1000), false},
+	}
+
+	for i, testCase := range testCases {
+		status := &RegradeStatus{
+			ID:      util.UUID(),
+			Done:    testCase.done,
+			EndTime: testCase.endTime,
+		}
+
+		statusLock.Lock()
+		statuses[status.ID] = status
+		statusLock.Unlock()
+
+		found := (GetStatus(status.ID) != nil)
+		if testCase.expected != found {
+			test.Errorf("Case %d: Unexpected status presence. Expected: %v, Actual: %v.", i, testCase.expected, found)
+		}
+
+		statusLock.Lock()
+		delete(statuses, status.ID)
+		statusLock.Unlock()
+	}
+}
+
+// Make two submissions for the student (the most recent has a "broken" score of zero), and one (not implemented) for the other user.
+// Returns the assignment and the full IDs of the original submissions (in order).
+func setupForTesting(test *testing.T) (*model.Assignment, map[string][]string) {
+	db.ResetForTesting()
+
+	restore := setNoDockerForTesting()
+	defer restore()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	submissions := []struct {
+		user       string
+		submission string
+	}{
+		{STUDENT_EMAIL, "solution"},
+		{STUDENT_EMAIL, "solution"},
+		{OTHER_EMAIL, "not-implemented"},
+	}
+
+	ids := make(map[string][]string)
+
+	for i, submission := range submissions {
+		submissionPath := filepath.Join(assignment.GetSourceDir(), "test-submissions", submission.submission)
+
+		result, _, softError, err := grader.Grade(context.Background(), assignment, submissionPath, submission.user, "", false, grader.GetDefaultGradeOptions())
+		if err != nil {
+			test.Fatalf("Case %d: Failed to grade: '%v'.", i, err)
+		}
+
+		if softError != "" {
+			test.Fatalf("Case %d: Got a soft error: '%s'.", i, softError)
+		}
+
+		ids[submission.user] = append(ids[submission.user], result.Info.ID)
+	}
+
+	// Break the score of the student's most recent submission.
+	recent, err := db.GetSubmissionContents(assignment, STUDENT_EMAIL, "")
+	if err != nil {
+		test.Fatalf("Failed to get recent submission: '%v'.", err)
+	}
+
+	recent.Info.Score = 0
+	for _, question := range recent.Info.Questions {
+		question.Score = 0
+	}
+
+	err = db.SaveSubmission(assignment, recent)
+	if err != nil {
+		test.Fatalf("Failed to save broken submission: '%v'.", err)
+	}
+
+	return assignment, ids
+}
+
+func checkStatus(test *testing.T, status *RegradeStatus, total int, failed int, changed int) {
+	if !status.Done || (status.Error != "") {
+		test.Fatalf("Regrade did not complete successfully: '%s'.", util.MustToJSONIndent(status))
+	}
+
+	if (status.Total != total) || (status.Completed != total) || (status.Failed != failed) || (status.Changed != changed) || (len(status.Results) != total) {
+		test.Fatalf("Unexpected counts. Expected (total: %d, failed: %d, changed: %d), Actual: '%s'.",
+			total, failed, changed, util.MustToJSONIndent(status))
+	}
+}
+
+func checkResult(test *testing.T, result *RegradeResult, user string, oldID string, changed bool, oldScore float64, newScore float64) {
+	if !result.Success || (result.User != user) || (result.OldSubmissionID != oldID) || (result.Changed != changed) ||
+		(result.OldScore != oldScore) || (result.NewScore != newScore) {
+		test.Fatalf("Unexpected result. Expected (user: '%s', old id: '%s', changed: %v, old score: %f, new score: %f), Actual: '%s'.",
+			user, oldID, changed, oldScore, newScore, util.MustToJSONIndent(result))
+	}
+}
+
+func checkHistoryLength(test *testing.T, assignment *model.Assignment, user string, expected int) {
+	history, err := db.GetSubmissionHistory(assignment, user)
+	if err != nil {
+		test.Fatalf("Failed to get history: '%v'.", err)
+	}
+
+	if len(history) != expected {
+		test.Fatalf("Unexpected history length for '%s'. Expected: %d, Actual: %d.", user, expected, len(history))
+	}
+}
+
+func getShortID(test *testing.T, assignment *model.Assignment, user string, fullID string) string {
+	history, err := db.GetSubmissionHistory(assignment, user)
+	if err != nil {
+		test.Fatalf("Failed to get history: '%v'.", err)
+	}
+
+	for _, item := range history {
+		if item.ID == fullID {
+			return item.ShortID
+		}
+	}
+
+	test.Fatalf("Could not find submission '%s'.", fullID)
+	return ""
+}
+
+func setNoDockerForTesting() func() {
+	oldDockerVal := config.DOCKER_DISABLE.Get()
+	config.DOCKER_DISABLE.Set(true)
+
+	return func() {
+		config.DOCKER_DISABLE.Set(oldDockerVal)
+	}
+}
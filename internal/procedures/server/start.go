@@ -8,6 +8,7 @@ import (
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/stats"
 	"github.com/edulinq/autograder/internal/tasks"
@@ -47,6 +48,12 @@ func setup(initiator common.ServerInitiator) error {
 	if initiator == common.PRIMARY_SERVER {
 		// Initialize the task engine.
 		tasks.Start()
+
+		// Grade any queued submissions (including ones left over from a previous run).
+		err = grader.StartGradingQueue()
+		if err != nil {
+			return fmt.Errorf("Failed to start the grading queue: '%w'.", err)
+		}
 	}
 
 	return nil
@@ -61,6 +68,7 @@ func CleanupAndStop() (err error) {
 	}
 
 	tasks.Stop()
+	grader.StopGradingQueue()
 
 	stats.StopCollection()
 
@@ -12,7 +12,9 @@ import (
 )
 
 const (
-	OVERALL_NAME = "<Overall>"
+	OVERALL_NAME    = "<Overall>"
+	AUTOGRADER_NAME = "<Autograder>"
+	MANUAL_NAME     = "<Manual>"
 )
 
 type AssignmentScoringReport struct {
@@ -20,6 +22,14 @@ type AssignmentScoringReport struct {
 	NumberOfSubmissions int                           `json:"number-of-submissions"`
 	LatestSubmission    timestamp.Timestamp           `json:"latest-submission"`
 	Questions           []*ScoringReportQuestionStats `json:"questions"`
+
+	// The number of students with an extension (a due date or submission limit just for them).
+	NumberOfExtensions int `json:"number-of-extensions"`
+
+	// Only present for assignments with a manual rubric.
+	// When present, Questions also includes the autograder total (AUTOGRADER_NAME)
+	// and the overall total includes manual points.
+	ManualItems []*ScoringReportQuestionStats `json:"manual-items,omitempty"`
 }
 
 type ScoringReportQuestionStats struct {
@@ -31,6 +41,9 @@ type ScoringReportQuestionStats struct {
 	Mean   float64 `json:"mean"`
 	StdDev float64 `json:"standard-deviation"`
 
+	// The number of submissions where course staff overrode this question's score.
+	NumberOfOverrides int `json:"number-of-overrides"`
+
 	MinString    string `json:"-"`
 	MaxString    string `json:"-"`
 	MedianString string `json:"-"`
@@ -38,30 +51,66 @@ type ScoringReportQuestionStats struct {
 	StdDevString string `json:"-"`
 }
 
+// Normalized scores (keyed by question/item name) for all the students that have a submission.
+type assignmentScores struct {
+	questionNames      []string
+	scores             map[string][]float64
+	overrides          map[string]int
+	manualNames        []string
+	manualScores       map[string][]float64
+	lastSubmissionTime timestamp.Timestamp
+}
+
 const DEFAULT_VALUE float64 = -1.0
 
 func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScoringReport, error) {
-	questionNames, scores, lastSubmissionTime, err := fetchScores(assignment)
+	scores, err := fetchScores(assignment)
 	if err != nil {
 		return nil, err
 	}
 
 	numSubmissions := 0
-	questions := make([]*ScoringReportQuestionStats, 0, len(questionNames))
+	if len(scores.questionNames) > 0 {
+		numSubmissions = len(scores.scores[OVERALL_NAME])
+	}
+
+	report := AssignmentScoringReport{
+		AssignmentName:      assignment.GetName(),
+		NumberOfSubmissions: numSubmissions,
+		LatestSubmission:    scores.lastSubmissionTime,
+		Questions:           computeStats(scores.questionNames, scores.scores, scores.overrides),
+	}
+
+	if assignment.HasManualRubric() {
+		report.ManualItems = computeStats(scores.manualNames, scores.manualScores, nil)
+	}
+
+	report.NumberOfExtensions, err = countStudentExtensions(assignment)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, questionName := range questionNames {
-		min, max := util.MinMax(scores[questionName])
-		mean, stdDev := stat.MeanStdDev(scores[questionName], nil)
-		median := util.Median(scores[questionName])
+	return &report, nil
+}
+
+func computeStats(names []string, scores map[string][]float64, overrides map[string]int) []*ScoringReportQuestionStats {
+	questions := make([]*ScoringReportQuestionStats, 0, len(names))
+
+	for _, name := range names {
+		min, max := util.MinMax(scores[name])
+		mean, stdDev := stat.MeanStdDev(scores[name], nil)
+		median := util.Median(scores[name])
 
 		stats := &ScoringReportQuestionStats{
-			QuestionName: questionName,
+			QuestionName: name,
 			Min:          util.DefaultNaN(min, DEFAULT_VALUE),
 			Max:          util.DefaultNaN(max, DEFAULT_VALUE),
 			Median:       util.DefaultNaN(median, DEFAULT_VALUE),
 			Mean:         util.DefaultNaN(mean, DEFAULT_VALUE),
 			StdDev:       util.DefaultNaN(stdDev, DEFAULT_VALUE),
 
+			NumberOfOverrides: overrides[name],
+
 			MinString:    fmt.Sprintf("%0.2f", min),
 			MaxString:    fmt.Sprintf("%0.2f", max),
 			MedianString: fmt.Sprintf("%0.2f", median),
@@ -70,69 +119,145 @@ func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScorin
 		}
 
 		questions = append(questions, stats)
-		numSubmissions = len(scores[questionName])
 	}
 
-	report := AssignmentScoringReport{
-		AssignmentName:      assignment.GetName(),
-		NumberOfSubmissions: numSubmissions,
-		LatestSubmission:    lastSubmissionTime,
-		Questions:           questions,
-	}
-
-	return &report, nil
+	return questions
 }
 
-func fetchScores(assignment *model.Assignment) ([]string, map[string][]float64, timestamp.Timestamp, error) {
-	results, err := db.GetRecentSubmissions(assignment, model.CourseRoleStudent)
+func fetchScores(assignment *model.Assignment) (*assignmentScores, error) {
+	results, err := db.GetSelectedSubmissions(assignment, model.CourseRoleStudent)
 	if err != nil {
-		return nil, nil, timestamp.Zero(), fmt.Errorf("Failed to get recent submission results: '%w'.", err)
+		return nil, fmt.Errorf("Failed to get selected submission results: '%w'.", err)
+	}
+
+	manualGrades := make(map[string]*model.ManualGrade)
+	if assignment.HasManualRubric() {
+		manualGrades, err = db.GetManualGrades(assignment)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get manual grades: '%w'.", err)
+		}
+	}
+
+	scores := &assignmentScores{
+		questionNames:      make([]string, 0),
+		scores:             make(map[string][]float64),
+		overrides:          make(map[string]int),
+		manualNames:        make([]string, 0),
+		manualScores:       make(map[string][]float64),
+		lastSubmissionTime: timestamp.Zero(),
+	}
+
+	for _, item := range assignment.ManualRubric {
+		scores.manualNames = append(scores.manualNames, item.ID)
 	}
 
-	questionNames := make([]string, 0)
-	scores := make(map[string][]float64)
-	lastSubmissionTime := timestamp.Zero()
+	if assignment.HasManualRubric() {
+		scores.manualNames = append(scores.manualNames, MANUAL_NAME)
+	}
 
-	for _, result := range results {
+	for email, result := range results {
 		if result == nil {
 			continue
 		}
 
-		if result.GradingStartTime > lastSubmissionTime {
-			lastSubmissionTime = result.GradingStartTime
+		if result.GradingStartTime > scores.lastSubmissionTime {
+			scores.lastSubmissionTime = result.GradingStartTime
 		}
 
-		if len(questionNames) == 0 {
+		if len(scores.questionNames) == 0 {
 			for _, question := range result.Questions {
-				questionNames = append(questionNames, question.Name)
-				scores[question.Name] = make([]float64, 0)
+				scores.questionNames = append(scores.questionNames, question.Name)
 			}
 
-			questionNames = append(questionNames, OVERALL_NAME)
+			if assignment.HasManualRubric() {
+				scores.questionNames = append(scores.questionNames, AUTOGRADER_NAME)
+			}
+
+			scores.questionNames = append(scores.questionNames, OVERALL_NAME)
 		}
 
 		total := 0.0
 		max_points := 0.0
+		overridden := false
 
 		for _, question := range result.Questions {
-			var score float64 = 0.0
-			if !util.IsZero(question.MaxPoints) {
-				score = question.Score / question.MaxPoints
-			}
+			scores.scores[question.Name] = append(scores.scores[question.Name], normalizeScore(question.Score, question.MaxPoints))
 
-			scores[question.Name] = append(scores[question.Name], score)
+			if question.GraderScore != nil {
+				scores.overrides[question.Name]++
+				overridden = true
+			}
 
 			total += question.Score
 			max_points += question.MaxPoints
 		}
 
-		total_score := 0.0
-		if !util.IsZero(max_points) {
-			total_score = total / max_points
+		if overridden {
+			scores.overrides[OVERALL_NAME]++
+		}
+
+		if !assignment.HasManualRubric() {
+			scores.scores[OVERALL_NAME] = append(scores.scores[OVERALL_NAME], normalizeScore(total, max_points))
+			continue
 		}
 
-		scores[OVERALL_NAME] = append(scores[OVERALL_NAME], total_score)
+		scores.scores[AUTOGRADER_NAME] = append(scores.scores[AUTOGRADER_NAME], normalizeScore(total, max_points))
+
+		if overridden {
+			scores.overrides[AUTOGRADER_NAME]++
+		}
+
+		// Missing manual grades count as zero.
+		grade := manualGrades[email]
+		for _, item := range assignment.ManualRubric {
+			score := 0.0
+			if (grade != nil) && (grade.Items[item.ID] != nil) {
+				score = grade.Items[item.ID].Score
+			}
+
+			scores.manualScores[item.ID] = append(scores.manualScores[item.ID], normalizeScore(score, item.MaxPoints))
+		}
+
+		manualTotal := assignment.ComputeManualScore(grade)
+		manualMaxPoints := assignment.GetManualMaxPoints()
+
+		scores.manualScores[MANUAL_NAME] = append(scores.manualScores[MANUAL_NAME], normalizeScore(manualTotal, manualMaxPoints))
+		scores.scores[OVERALL_NAME] = append(scores.scores[OVERALL_NAME], normalizeScore((total+manualTotal), (max_points+manualMaxPoints)))
+	}
+
+	return scores, nil
+}
+
+func normalizeScore(score float64, maxPoints float64) float64 {
+	if util.IsZero(maxPoints) {
+		return 0.0
+	}
+
+	return score / maxPoints
+}
+
+func countStudentExtensions(assignment *model.Assignment) (int, error) {
+	extensions, err := db.GetUserExtensions(assignment)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get extensions: '%w'.", err)
+	}
+
+	if len(extensions) == 0 {
+		return 0, nil
+	}
+
+	users, err := db.GetCourseUsers(assignment.GetCourse())
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get course users: '%w'.", err)
+	}
+
+	count := 0
+	for email := range extensions {
+		user := users[email]
+		if (user != nil) && (user.Role == model.CourseRoleStudent) {
+			count++
+		}
 	}
 
-	return questionNames, scores, lastSubmissionTime, nil
+	return count, nil
 }
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestAssignmentReportManualRubric(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	assignment.ManualRubric = []*model.RubricItem{
+		&model.RubricItem{ID: "style", MaxPoints: 2.0},
+		&model.RubricItem{ID: "report", MaxPoints: 4.0},
+	}
+	db.MustSaveAssignment(assignment)
+
+	_, err := db.UpdateManualGrade(assignment, "course-student@test.edulinq.org", map[string]*model.ManualGradeItem{
+		"style": &model.ManualGradeItem{Score: 1.0, Grader: "course-grader@test.edulinq.org"},
+	})
+	if err != nil {
+		test.Fatalf("Failed to set manual grade: '%v'.", err)
+	}
+
+	report, err := GetAssignmentScoringReport(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get assignment report: '%v'.", err)
+	}
+
+	// Autograder: 2/2, Manual: 1/6 (style: 1/2, report: 0/4), Overall: 3/8.
+	expectedQuestions := map[string]float64{
+		"Q1":            1.0,
+		"Q2":            1.0,
+		"Style":         0.0,
+		AUTOGRADER_NAME: 1.0,
+		OVERALL_NAME:    0.375,
+	}
+
+	expectedManual := map[string]float64{
+		"style":     0.5,
+		"report":    0.0,
+		MANUAL_NAME: 1.0 / 6.0,
+	}
+
+	checkReportMeans(test, "question", expectedQuestions, report.Questions)
+	checkReportMeans(test, "manual", expectedManual, report.ManualItems)
+
+	reportHTML, err := report.ToHTML(false)
+	if err != nil {
+		test.Fatalf("Failed to generate HTML for report: '%v'.", err)
+	}
+
+	if !strings.Contains(reportHTML, "Manual Rubric Item") {
+		test.Fatalf("Report HTML does not include manual items: '%s'.", reportHTML)
+	}
+}
+
+func checkReportMeans(test *testing.T, label string, expected map[string]float64, actual []*ScoringReportQuestionStats) {
+	if len(expected) != len(actual) {
+		test.Fatalf("Unexpected number of %s stats. Expected: %d, Actual: %d ('%s').", label, len(expected), len(actual), util.MustToJSONIndent(actual))
+	}
+
+	for _, stats := range actual {
+		expectedMean, ok := expected[stats.QuestionName]
+		if !ok {
+			test.Errorf("Unexpected %s stats: '%s'.", label, stats.QuestionName)
+			continue
+		}
+
+		if !util.IsClose(expectedMean, stats.Mean) {
+			test.Errorf("Unexpected mean for %s '%s'. Expected: %f, Actual: %f.", label, stats.QuestionName, expectedMean, stats.Mean)
+		}
+	}
+}
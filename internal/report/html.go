@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"html/template"
 	"strings"
+
+	"github.com/edulinq/autograder/internal/util"
 )
 
 func (this *CourseScoringReport) ToHTML() (string, error) {
@@ -55,6 +57,34 @@ func (this *AssignmentScoringReport) ToInlineHTML() (template.HTML, error) {
 	return template.HTML(html), nil
 }
 
+func (this *CourseReconciliationReport) ToHTML() (string, error) {
+	title := fmt.Sprintf("LMS Reconciliation Report for %s", this.CourseName)
+	templateHTML := fmt.Sprintf(outterShell, title, style, reconciliationReportTemplate)
+
+	tmpl, err := template.New("course-reconciliation-report").Funcs(reconciliationFuncs).Parse(templateHTML)
+	if err != nil {
+		return "", fmt.Errorf("Could not parse course reconciliation report template: '%w'.", err)
+	}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, this)
+	if err != nil {
+		return "", fmt.Errorf("Failed to execute course reconciliation report template: '%w'.", err)
+	}
+
+	return builder.String(), nil
+}
+
+var reconciliationFuncs template.FuncMap = template.FuncMap{
+	"score": func(score *float64) string {
+		if score == nil {
+			return ""
+		}
+
+		return util.FloatToStr(*score)
+	},
+}
+
 // Replacements: [title, head, body]
 var outterShell string = `
     <html>
@@ -95,6 +125,9 @@ var assignmentReportTemplate string = `
             <h2>Assignment: {{ .AssignmentName }}</h2>
             <p>Number of Submissions: {{ .NumberOfSubmissions }}</p>
             <p>Latest Submission: {{ .LatestSubmission.UnsafePrettyString }}</p>
+            {{ if .NumberOfExtensions }}
+                <p>Number of Extensions: {{ .NumberOfExtensions }}</p>
+            {{ end }}
         </div>
         <div class='ag-body'>
             <table>
@@ -106,6 +139,7 @@ var assignmentReportTemplate string = `
                         <th>Min</th>
                         <th>Max</th>
                         <th>StdDev</th>
+                        <th>Overrides</th>
                     </tr>
                 </thead>
                 <tbody>
@@ -117,10 +151,83 @@ var assignmentReportTemplate string = `
                             <td class='numeric'>{{ .MinString }}</td>
                             <td class='numeric'>{{ .MaxString }}</td>
                             <td class='numeric'>{{ .StdDevString }}</td>
+                            <td class='numeric'>{{ .NumberOfOverrides }}</td>
                         </tr>
                     {{ end }}
                 </tbody>
             </table>
+            {{ if .ManualItems }}
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Manual Rubric Item</th>
+                            <th>Mean</th>
+                            <th>Median</th>
+                            <th>Min</th>
+                            <th>Max</th>
+                            <th>StdDev</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{ range .ManualItems }}
+                            <tr>
+                                <td class='text'>{{ .QuestionName }}</td>
+                                <td class='numeric'>{{ .MeanString }}</td>
+                                <td class='numeric'>{{ .MedianString }}</td>
+                                <td class='numeric'>{{ .MinString }}</td>
+                                <td class='numeric'>{{ .MaxString }}</td>
+                                <td class='numeric'>{{ .StdDevString }}</td>
+                            </tr>
+                        {{ end }}
+                    </tbody>
+                </table>
+            {{ end }}
+        </div>
+    </div>
+`
+
+var reconciliationReportTemplate string = `
+    <div class='autograder autograder-course-reconciliation-report'>
+        <div class='ag-header'>
+            <h1>Course: {{ .CourseName }}</h1>
+        </div>
+        <div class='ag-body'>
+            {{ range .Assignments }}
+                <div class='autograder-assignment-reconciliation-report'>
+                    <h2>Assignment: {{ .AssignmentName }}</h2>
+                    <p>
+                        {{ range .Counts }}
+                            {{ .Status }}: {{ .Count }}<br />
+                        {{ end }}
+                    </p>
+                    {{ with .GetUnsyncedResults }}
+                        <table>
+                            <thead>
+                                <tr>
+                                    <th>Email</th>
+                                    <th>LMS ID</th>
+                                    <th>Status</th>
+                                    <th>Autograder Score</th>
+                                    <th>LMS Score</th>
+                                    <th>Uploaded Score</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{ range . }}
+                                    <tr>
+                                        <td class='text'>{{ .Email }}</td>
+                                        <td class='text'>{{ .LMSID }}</td>
+                                        <td class='text'>{{ .Status }}</td>
+                                        <td class='numeric'>{{ score .AutograderScore }}</td>
+                                        <td class='numeric'>{{ score .LMSScore }}</td>
+                                        <td class='numeric'>{{ score .UploadedScore }}</td>
+                                    </tr>
+                                {{ end }}
+                            </tbody>
+                        </table>
+                    {{ end }}
+                </div>
+            {{ end }}
         </div>
     </div>
 `
@@ -146,6 +253,21 @@ var style string = `
             padding-right: 15px;
         }
 
+        .autograder-assignment-reconciliation-report table th,
+        .autograder-assignment-reconciliation-report table .text {
+            text-align: left;
+        }
+
+        .autograder-assignment-reconciliation-report table .numeric {
+            text-align: right;
+        }
+
+        .autograder-assignment-reconciliation-report table th,
+        .autograder-assignment-reconciliation-report table td {
+            padding: 5px;
+            padding-right: 10px;
+        }
+
         .autograder-assignment-scoring-report table tr:last-child {
             font-style: italic;
         }
@@ -0,0 +1,123 @@
+package report
+
+import (
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/scoring"
+)
+
+// Statuses that mean the LMS and autograder disagree in a way that someone should look at.
+var conflictStatuses []scoring.ReconcileStatus = []scoring.ReconcileStatus{
+	scoring.ReconcileStatusLMSEdited,
+	scoring.ReconcileStatusMissingLMSID,
+}
+
+type CourseReconciliationReport struct {
+	CourseName  string                            `json:"course-name"`
+	Assignments []*AssignmentReconciliationReport `json:"assignments"`
+}
+
+type AssignmentReconciliationReport struct {
+	AssignmentID   string `json:"assignment-id"`
+	AssignmentName string `json:"assignment-name"`
+
+	// The number of students with each status (in the same order as scoring.ReconcileStatuses).
+	Counts []*ReconciliationStatusCount `json:"counts"`
+
+	Results []*scoring.ReconcileResult `json:"results"`
+}
+
+type ReconciliationStatusCount struct {
+	Status scoring.ReconcileStatus `json:"status"`
+	Count  int                     `json:"count"`
+}
+
+// Compare the scores of every assignment (with an LMS ID) against the scores in the course's LMS.
+func GetCourseReconciliationReport(course *model.Course) (*CourseReconciliationReport, error) {
+	results, err := scoring.ReconcileCourseScores(course)
+	if err != nil {
+		return nil, err
+	}
+
+	assignmentReports := make([]*AssignmentReconciliationReport, 0, len(results))
+
+	for _, assignment := range course.GetSortedAssignments() {
+		assignmentResults, ok := results[assignment.GetID()]
+		if !ok {
+			continue
+		}
+
+		assignmentReports = append(assignmentReports, newAssignmentReconciliationReport(assignment, assignmentResults))
+	}
+
+	report := CourseReconciliationReport{
+		CourseName:  course.GetName(),
+		Assignments: assignmentReports,
+	}
+
+	return &report, nil
+}
+
+// Does any student in the report have a conflict (e.g., an LMS score that was edited outside the autograder)?
+func (this *CourseReconciliationReport) HasConflicts() bool {
+	for _, assignment := range this.Assignments {
+		if assignment.HasConflicts() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (this *AssignmentReconciliationReport) HasConflicts() bool {
+	for _, count := range this.Counts {
+		if (count.Count > 0) && isConflictStatus(count.Status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get the results that are not in sync.
+func (this *AssignmentReconciliationReport) GetUnsyncedResults() []*scoring.ReconcileResult {
+	results := make([]*scoring.ReconcileResult, 0)
+	for _, result := range this.Results {
+		if result.Status != scoring.ReconcileStatusInSync {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+func newAssignmentReconciliationReport(assignment *model.Assignment, results []*scoring.ReconcileResult) *AssignmentReconciliationReport {
+	counts := make(map[scoring.ReconcileStatus]int, len(scoring.ReconcileStatuses))
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	statusCounts := make([]*ReconciliationStatusCount, 0, len(scoring.ReconcileStatuses))
+	for _, status := range scoring.ReconcileStatuses {
+		statusCounts = append(statusCounts, &ReconciliationStatusCount{
+			Status: status,
+			Count:  counts[status],
+		})
+	}
+
+	return &AssignmentReconciliationReport{
+		AssignmentID:   assignment.GetID(),
+		AssignmentName: assignment.GetName(),
+		Counts:         statusCounts,
+		Results:        results,
+	}
+}
+
+func isConflictStatus(status scoring.ReconcileStatus) bool {
+	for _, conflictStatus := range conflictStatuses {
+		if status == conflictStatus {
+			return true
+		}
+	}
+
+	return false
+}
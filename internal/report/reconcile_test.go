@@ -0,0 +1,96 @@
+package report
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/scoring"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestCourseReconciliationReport(test *testing.T) {
+	defer lmstest.ClearAssignmentScores()
+
+	testCases := []struct {
+		lmsScore          float64
+		expectedStatus    scoring.ReconcileStatus
+		expectedConflicts bool
+	}{
+		{2, scoring.ReconcileStatusInSync, false},
+		{0, scoring.ReconcileStatusNeedsUpload, false},
+		{1, scoring.ReconcileStatusLMSEdited, true},
+	}
+
+	for i, testCase := range testCases {
+		course := db.MustGetTestCourse()
+		course.Assignments["hw0"].LMSID = "001"
+
+		lmstest.SetAssignmentScores("001", []*lmstypes.SubmissionScore{
+			&lmstypes.SubmissionScore{
+				UserID: "lms-course-student@test.edulinq.org",
+				Score:  testCase.lmsScore,
+			},
+		})
+
+		report, err := GetCourseReconciliationReport(course)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get reconciliation report: '%v'.", i, err)
+			continue
+		}
+
+		if len(report.Assignments) != 1 {
+			test.Errorf("Case %d: Unexpected number of assignments. Expected: 1, Actual: %d.", i, len(report.Assignments))
+			continue
+		}
+
+		expectedCounts := make([]*ReconciliationStatusCount, 0, len(scoring.ReconcileStatuses))
+		for _, status := range scoring.ReconcileStatuses {
+			count := 0
+			if status == testCase.expectedStatus {
+				count = 1
+			}
+
+			expectedCounts = append(expectedCounts, &ReconciliationStatusCount{Status: status, Count: count})
+		}
+
+		if !reflect.DeepEqual(expectedCounts, report.Assignments[0].Counts) {
+			test.Errorf("Case %d: Unexpected counts. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(expectedCounts), util.MustToJSONIndent(report.Assignments[0].Counts))
+			continue
+		}
+
+		if testCase.expectedConflicts != report.HasConflicts() {
+			test.Errorf("Case %d: Unexpected conflicts. Expected: %v, Actual: %v.", i, testCase.expectedConflicts, report.HasConflicts())
+			continue
+		}
+
+		html, err := report.ToHTML()
+		if err != nil {
+			test.Errorf("Case %d: Failed to generate HTML: '%v'.", i, err)
+			continue
+		}
+
+		// Only students that are not in sync are listed.
+		listed := strings.Contains(html, "<td class='text'>course-student@test.edulinq.org</td>")
+		if listed != (testCase.expectedStatus != scoring.ReconcileStatusInSync) {
+			test.Errorf("Case %d: Student is not correctly listed in the HTML (listed: %v): '%s'.", i, listed, html)
+			continue
+		}
+	}
+}
+
+// Assignments without an LMS ID are not included.
+func TestCourseReconciliationReportNoLMSID(test *testing.T) {
+	report, err := GetCourseReconciliationReport(db.MustGetTestCourse())
+	if err != nil {
+		test.Fatalf("Failed to get reconciliation report: '%v'.", err)
+	}
+
+	if len(report.Assignments) != 0 {
+		test.Fatalf("Unexpected assignments: '%s'.", util.MustToJSONIndent(report.Assignments))
+	}
+}
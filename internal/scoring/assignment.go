@@ -31,22 +31,37 @@ func FullAssignmentScoringAndUpload(assignment *model.Assignment, dryRun bool) (
 		return nil, fmt.Errorf("Could not fetch LMS grades: '%w'.", err)
 	}
 
+	scoringInfos, err := computeScoringInfos(assignment, users, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadedScores, err := computeFinalScores(assignment, users, scoringInfos, lmsScores, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to apply late policy: '%w'.", err)
+	}
+
+	return uploadedScores, nil
+}
+
+// Get the scores (with team scores and the late policy applied) for all the students in an assignment.
+func computeScoringInfos(assignment *model.Assignment, users map[string]*model.CourseUser, dryRun bool) (map[string]*model.ScoringInfo, error) {
 	scoringInfos, err := db.GetExistingScoringInfos(assignment, model.CourseRoleStudent)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get scoring information: '%w'.", err)
 	}
 
-	err = ApplyLatePolicy(assignment, users, scoringInfos, dryRun)
+	err = propagateTeamScores(assignment, users, scoringInfos)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to apply late policy: '%w'.", err)
+		return nil, fmt.Errorf("Failed to propagate team scores: '%w'.", err)
 	}
 
-	uploadedScores, err := computeFinalScores(assignment, users, scoringInfos, lmsScores, dryRun)
+	err = ApplyLatePolicy(assignment, users, scoringInfos, dryRun)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to apply late policy: '%w'.", err)
 	}
 
-	return uploadedScores, nil
+	return scoringInfos, nil
 }
 
 func computeFinalScores(
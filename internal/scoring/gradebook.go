@@ -0,0 +1,76 @@
+package scoring
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/gradebook"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Compute the final scores (including the late policy) of every student on every assignment in a course.
+// Nothing is uploaded, so this works for courses without an LMS
+// (as long as their late policies do not need one, e.g., late days).
+func ComputeCourseGradebook(course *model.Course) (*gradebook.Gradebook, error) {
+	users, err := db.GetCourseUsers(course)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch autograder users: '%w'.", err)
+	}
+
+	book := &gradebook.Gradebook{
+		Assignments: make([]*gradebook.Assignment, 0),
+		Users:       make([]*gradebook.User, 0, len(users)),
+	}
+
+	bookUsers := make(map[string]*gradebook.User, len(users))
+	for email, user := range users {
+		if user.Role != model.CourseRoleStudent {
+			continue
+		}
+
+		bookUser := &gradebook.User{
+			Email:  email,
+			Name:   user.GetName(false),
+			LMSID:  user.GetLMSID(),
+			Scores: make(map[string]*gradebook.Score),
+		}
+
+		book.Users = append(book.Users, bookUser)
+		bookUsers[email] = bookUser
+	}
+
+	slices.SortFunc(book.Users, func(a *gradebook.User, b *gradebook.User) int {
+		return strings.Compare(a.Email, b.Email)
+	})
+
+	for _, assignment := range course.GetSortedAssignments() {
+		scoringInfos, err := computeScoringInfos(assignment, users, true)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to score assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+
+		book.Assignments = append(book.Assignments, &gradebook.Assignment{
+			ID:        assignment.GetID(),
+			Name:      assignment.GetName(),
+			LMSID:     assignment.GetLMSID(),
+			MaxPoints: assignment.MaxPoints,
+		})
+
+		for email, scoringInfo := range scoringInfos {
+			bookUser := bookUsers[email]
+			if (bookUser == nil) || scoringInfo.Reject {
+				continue
+			}
+
+			bookUser.Scores[assignment.GetID()] = &gradebook.Score{
+				Score:          scoringInfo.Score,
+				SubmissionTime: scoringInfo.SubmissionTime,
+				NumDaysLate:    scoringInfo.NumDaysLate,
+			}
+		}
+	}
+
+	return book, nil
+}
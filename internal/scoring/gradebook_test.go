@@ -0,0 +1,48 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func TestComputeCourseGradebook(test *testing.T) {
+	testCases := []struct {
+		removeLMS bool
+	}{
+		{false},
+		{true},
+	}
+
+	expected := "email,name,lms-id,hw0\n" +
+		"course-student@test.edulinq.org,course-student,lms-course-student@test.edulinq.org,2\n"
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		course := db.MustGetTestCourse()
+		if testCase.removeLMS {
+			course.LMS = nil
+		}
+
+		book, err := ComputeCourseGradebook(course)
+		if err != nil {
+			test.Errorf("Case %d: Failed to compute gradebook: '%v'.", i, err)
+			continue
+		}
+
+		actual, err := book.ToCSV(model.GRADEBOOK_FORMAT_GENERIC)
+		if err != nil {
+			test.Errorf("Case %d: Failed to write gradebook: '%v'.", i, err)
+			continue
+		}
+
+		if expected != actual {
+			test.Errorf("Case %d: Unexpected gradebook. Expected: \n'%s', \nActual: \n'%s'.", i, expected, actual)
+			continue
+		}
+	}
+
+	db.ResetForTesting()
+}
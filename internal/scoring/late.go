@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/lms"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/log"
@@ -47,7 +48,7 @@ func ApplyLatePolicy(
 		return nil
 	}
 
-	lmsAssignment, err := lms.FetchAssignment(assignment.GetCourse(), assignment.GetLMSID())
+	lmsAssignment, err := getAssignmentDueInfo(assignment)
 	if err != nil {
 		return err
 	}
@@ -56,7 +57,13 @@ func ApplyLatePolicy(
 		return fmt.Errorf("Assignment does not have a due date.")
 	}
 
-	applyBaselinePolicy(assignment, policy, users, scores, *lmsAssignment.DueDate)
+	// Users with an extension (or whose team has one) have their own due date.
+	extensions, err := db.GetUserExtensions(assignment)
+	if err != nil {
+		return fmt.Errorf("Failed to get extensions: '%w'.", err)
+	}
+
+	applyBaselinePolicy(assignment, policy, users, scores, *lmsAssignment.DueDate, extensions)
 
 	// Baseline policy is complete.
 	if policy.Type == model.BaselinePolicy {
@@ -86,10 +93,49 @@ func ApplyLatePolicy(
 	return fmt.Errorf("Unknown late policy type: '%s'.", policy.Type)
 }
 
+// Get the due date and max points for an assignment.
+// These come from the LMS when the assignment is linked to one,
+// and fall back to the assignment's own config (e.g., for courses without an LMS).
+func getAssignmentDueInfo(assignment *model.Assignment) (*lmstypes.Assignment, error) {
+	var lmsAssignment *lmstypes.Assignment = nil
+
+	if (assignment.GetCourse().GetLMSAdapter() != nil) && (assignment.GetLMSID() != "") {
+		var err error
+		lmsAssignment, err = lms.FetchAssignment(assignment.GetCourse(), assignment.GetLMSID())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if lmsAssignment == nil {
+		lmsAssignment = &lmstypes.Assignment{}
+	}
+
+	if lmsAssignment.DueDate == nil {
+		lmsAssignment.DueDate = assignment.DueDate
+	}
+
+	if util.IsZero(lmsAssignment.MaxPoints) {
+		lmsAssignment.MaxPoints = assignment.MaxPoints
+	}
+
+	return lmsAssignment, nil
+}
+
 // Apply a common policy.
-func applyBaselinePolicy(assignment *model.Assignment, policy model.LateGradingPolicy, users map[string]*model.CourseUser, scores map[string]*model.ScoringInfo, dueDate timestamp.Timestamp) {
+// Extensions (keyed by email) replace the due date for their user.
+func applyBaselinePolicy(
+	assignment *model.Assignment, policy model.LateGradingPolicy,
+	users map[string]*model.CourseUser, scores map[string]*model.ScoringInfo,
+	dueDate timestamp.Timestamp, extensions map[string]*model.Extension) {
 	for email, score := range scores {
-		score.NumDaysLate = computeLateDays(dueDate, score.SubmissionTime)
+		userDueDate := dueDate
+		extension := extensions[email]
+		if (extension != nil) && (extension.DueDate != nil) {
+			userDueDate = *extension.DueDate
+		}
+
+		score.NumDaysLate = computeLateDays(userDueDate, score.SubmissionTime)
 
 		_, ok := users[email]
 		if !ok {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -63,3 +64,39 @@ func TestComputeLateDays(test *testing.T) {
 		}
 	}
 }
+
+// Users with an extension have their late days computed from their own due date.
+func TestApplyBaselinePolicyExtension(test *testing.T) {
+	var dayMSecs int64 = 24 * 60 * 60 * 1000
+
+	dueDate := timestamp.Timestamp(0)
+	extensionDueDate := timestamp.Timestamp(2 * dayMSecs)
+	submissionTime := timestamp.Timestamp((3 * dayMSecs) - 1)
+
+	users := map[string]*model.CourseUser{
+		"with@test.edulinq.org":    &model.CourseUser{Email: "with@test.edulinq.org"},
+		"without@test.edulinq.org": &model.CourseUser{Email: "without@test.edulinq.org"},
+	}
+
+	scores := map[string]*model.ScoringInfo{
+		"with@test.edulinq.org":    &model.ScoringInfo{SubmissionTime: submissionTime},
+		"without@test.edulinq.org": &model.ScoringInfo{SubmissionTime: submissionTime},
+	}
+
+	extensions := map[string]*model.Extension{
+		"with@test.edulinq.org": &model.Extension{DueDate: &extensionDueDate},
+	}
+
+	applyBaselinePolicy(nil, model.LateGradingPolicy{}, users, scores, dueDate, extensions)
+
+	expected := map[string]int{
+		"with@test.edulinq.org":    1,
+		"without@test.edulinq.org": 3,
+	}
+
+	for email, expectedDays := range expected {
+		if scores[email].NumDaysLate != expectedDays {
+			test.Errorf("Bad late days for '%s'. Expected: %d, Actual: %d.", email, expectedDays, scores[email].NumDaysLate)
+		}
+	}
+}
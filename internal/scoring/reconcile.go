@@ -0,0 +1,196 @@
+package scoring
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// How a student's score in the autograder compares to their score in the LMS.
+type ReconcileStatus string
+
+const (
+	// The LMS score matches the autograder score.
+	ReconcileStatusInSync ReconcileStatus = "in-sync"
+	// The autograder score has not been uploaded (or has changed since it was uploaded).
+	// The next upload will update the LMS score.
+	ReconcileStatusNeedsUpload ReconcileStatus = "needs-upload"
+	// The LMS score was changed by someone other than the autograder.
+	// The next upload will overwrite the LMS score (unless it is locked).
+	ReconcileStatusLMSEdited ReconcileStatus = "lms-edited"
+	// The LMS score is locked, the autograder will not upload a score.
+	ReconcileStatusLocked ReconcileStatus = "locked"
+	// The student does not have an LMS ID, the autograder cannot upload a score.
+	ReconcileStatusMissingLMSID ReconcileStatus = "missing-lms-id"
+	// The student does not have a (valid) submission, the autograder will not upload a score.
+	ReconcileStatusMissingSubmission ReconcileStatus = "missing-submission"
+)
+
+var ReconcileStatuses []ReconcileStatus = []ReconcileStatus{
+	ReconcileStatusInSync,
+	ReconcileStatusNeedsUpload,
+	ReconcileStatusLMSEdited,
+	ReconcileStatusLocked,
+	ReconcileStatusMissingLMSID,
+	ReconcileStatusMissingSubmission,
+}
+
+type ReconcileResult struct {
+	Email  string          `json:"email"`
+	LMSID  string          `json:"lms-id"`
+	Status ReconcileStatus `json:"status"`
+
+	// The score computed by the autograder (nil if there is no submission).
+	AutograderScore *float64 `json:"autograder-score"`
+	// The score in the LMS (nil if the LMS does not have a score).
+	LMSScore *float64 `json:"lms-score"`
+	// The score the autograder last uploaded (nil if the autograder has not uploaded a score).
+	UploadedScore *float64 `json:"uploaded-score"`
+}
+
+// Compare the scores that would be uploaded for an assignment against the scores currently in the LMS.
+// Nothing is uploaded.
+// Returns one result for each student (sorted by email).
+func ReconcileAssignmentScores(assignment *model.Assignment) ([]*ReconcileResult, error) {
+	if assignment.GetCourse().GetLMSAdapter() == nil {
+		return nil, fmt.Errorf("Assignment's course has no LMS info associated with it.")
+	}
+
+	if assignment.GetLMSID() == "" {
+		return nil, fmt.Errorf("Assignment has no LMS ID.")
+	}
+
+	users, err := db.GetCourseUsers(assignment.GetCourse())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch autograder users: '%w'.", err)
+	}
+
+	lmsScores, err := lms.FetchAssignmentScores(assignment.GetCourse(), assignment.GetLMSID())
+	if err != nil {
+		return nil, fmt.Errorf("Could not fetch LMS grades: '%w'.", err)
+	}
+
+	scoringInfos, err := computeScoringInfos(assignment, users, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return reconcileScores(assignment, users, scoringInfos, lmsScores)
+}
+
+// Reconcile the scores for all assignments that have an LMS ID.
+// Returns: {assignmentID: results, ...}.
+func ReconcileCourseScores(course *model.Course) (map[string][]*ReconcileResult, error) {
+	results := make(map[string][]*ReconcileResult)
+	for _, assignment := range course.GetSortedAssignments() {
+		if assignment.GetLMSID() == "" {
+			log.Warn("Assignment has no LMS id, skipping reconciliation.", course, assignment)
+			continue
+		}
+
+		assignmentResults, err := ReconcileAssignmentScores(assignment)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to reconcile assignment '%s' for course '%s': '%w'.", assignment.GetID(), course.GetID(), err)
+		}
+
+		results[assignment.GetID()] = assignmentResults
+	}
+
+	return results, nil
+}
+
+func reconcileScores(
+	assignment *model.Assignment,
+	users map[string]*model.CourseUser, scoringInfos map[string]*model.ScoringInfo,
+	lmsScores []*lmstypes.SubmissionScore,
+) ([]*ReconcileResult, error) {
+	locks, existingComments, err := parseComments(lmsScores)
+	if err != nil {
+		return nil, err
+	}
+
+	lmsScoresByID := make(map[string]*lmstypes.SubmissionScore, len(lmsScores))
+	for _, lmsScore := range lmsScores {
+		lmsScoresByID[lmsScore.UserID] = lmsScore
+	}
+
+	results := make([]*ReconcileResult, 0)
+	for email, user := range users {
+		if user.Role != model.CourseRoleStudent {
+			continue
+		}
+
+		result := &ReconcileResult{
+			Email: email,
+			LMSID: user.GetLMSID(),
+		}
+
+		results = append(results, result)
+
+		scoringInfo := scoringInfos[email]
+		if (scoringInfo != nil) && !scoringInfo.Reject {
+			result.AutograderScore = &scoringInfo.Score
+		}
+
+		if result.LMSID == "" {
+			result.Status = ReconcileStatusMissingLMSID
+			continue
+		}
+
+		lmsScore := lmsScoresByID[result.LMSID]
+		if lmsScore != nil {
+			result.LMSScore = &lmsScore.Score
+		}
+
+		existingComment := existingComments[result.LMSID]
+		if existingComment != nil {
+			result.UploadedScore = &existingComment.Score
+		}
+
+		result.Status = getReconcileStatus(result, locks[result.LMSID])
+	}
+
+	slices.SortFunc(results, func(a *ReconcileResult, b *ReconcileResult) int {
+		return strings.Compare(a.Email, b.Email)
+	})
+
+	return results, nil
+}
+
+// Get the status of a student that has an LMS ID.
+// The LMS does not always distinguish between a missing score and a zero,
+// so without a score uploaded by the autograder, only non-zero LMS scores are considered edits.
+func getReconcileStatus(result *ReconcileResult, locked bool) ReconcileStatus {
+	if locked {
+		return ReconcileStatusLocked
+	}
+
+	if result.AutograderScore == nil {
+		return ReconcileStatusMissingSubmission
+	}
+
+	if result.LMSScore == nil {
+		return ReconcileStatusNeedsUpload
+	}
+
+	if result.UploadedScore != nil {
+		if !util.IsClose(*result.LMSScore, *result.UploadedScore) {
+			return ReconcileStatusLMSEdited
+		}
+	} else if !util.IsZero(*result.LMSScore) && !util.IsClose(*result.LMSScore, *result.AutograderScore) {
+		return ReconcileStatusLMSEdited
+	}
+
+	if util.IsClose(*result.LMSScore, *result.AutograderScore) {
+		return ReconcileStatusInSync
+	}
+
+	return ReconcileStatusNeedsUpload
+}
@@ -0,0 +1,165 @@
+package scoring
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestReconcileScores(test *testing.T) {
+	assignment := db.MustGetTestAssignment()
+
+	users := make(map[string]*model.CourseUser)
+	scoringInfos := make(map[string]*model.ScoringInfo)
+	lmsScores := make([]*lmstypes.SubmissionScore, 0)
+
+	// Nil values indicate a missing score.
+	testCases := []struct {
+		name            string
+		hasLMSID        bool
+		autograderScore *float64
+		lmsScore        *float64
+		uploadedScore   *float64
+		locked          bool
+		expected        ReconcileStatus
+	}{
+		{"in-sync", true, floatPointer(1), floatPointer(1), floatPointer(1), false, ReconcileStatusInSync},
+		{"in-sync-no-comment", true, floatPointer(1), floatPointer(1), nil, false, ReconcileStatusInSync},
+		{"new", true, floatPointer(1), nil, nil, false, ReconcileStatusNeedsUpload},
+		{"new-zero", true, floatPointer(1), floatPointer(0), nil, false, ReconcileStatusNeedsUpload},
+		{"changed", true, floatPointer(2), floatPointer(1), floatPointer(1), false, ReconcileStatusNeedsUpload},
+		{"edited", true, floatPointer(1), floatPointer(3), floatPointer(1), false, ReconcileStatusLMSEdited},
+		{"edited-to-match", true, floatPointer(3), floatPointer(3), floatPointer(1), false, ReconcileStatusLMSEdited},
+		{"edited-no-comment", true, floatPointer(1), floatPointer(3), nil, false, ReconcileStatusLMSEdited},
+		{"locked", true, floatPointer(1), floatPointer(3), floatPointer(1), true, ReconcileStatusLocked},
+		{"no-lms-id", false, floatPointer(1), nil, nil, false, ReconcileStatusMissingLMSID},
+		{"no-submission", true, nil, floatPointer(3), nil, false, ReconcileStatusMissingSubmission},
+	}
+
+	expected := make([]*ReconcileResult, 0, len(testCases))
+
+	for _, testCase := range testCases {
+		email := testCase.name + "@test.edulinq.org"
+		lmsID := ""
+
+		user := &model.CourseUser{Email: email, Role: model.CourseRoleStudent}
+		if testCase.hasLMSID {
+			lmsID = "lms-" + email
+			user.LMSID = &lmsID
+		}
+
+		users[email] = user
+
+		if testCase.autograderScore != nil {
+			scoringInfos[email] = &model.ScoringInfo{Score: *testCase.autograderScore}
+		}
+
+		lmsScore := &lmstypes.SubmissionScore{UserID: lmsID}
+		if testCase.lmsScore != nil {
+			lmsScore.Score = *testCase.lmsScore
+		}
+
+		if testCase.uploadedScore != nil {
+			lmsScore.Comments = append(lmsScore.Comments, &lmstypes.SubmissionComment{
+				Text: util.MustToJSON(model.ScoringInfo{
+					Score:                   *testCase.uploadedScore,
+					Lock:                    testCase.locked,
+					AutograderStructVersion: model.SCORING_INFO_STRUCT_VERSION,
+				}),
+			})
+		}
+
+		if testCase.hasLMSID && ((testCase.lmsScore != nil) || (testCase.uploadedScore != nil)) {
+			lmsScores = append(lmsScores, lmsScore)
+		}
+
+		expected = append(expected, &ReconcileResult{
+			Email:           email,
+			LMSID:           lmsID,
+			Status:          testCase.expected,
+			AutograderScore: testCase.autograderScore,
+			LMSScore:        testCase.lmsScore,
+			UploadedScore:   testCase.uploadedScore,
+		})
+	}
+
+	// Non-students are not included.
+	users["grader@test.edulinq.org"] = &model.CourseUser{Email: "grader@test.edulinq.org", Role: model.CourseRoleGrader}
+
+	actual, err := reconcileScores(assignment, users, scoringInfos, lmsScores)
+	if err != nil {
+		test.Fatalf("Failed to reconcile scores: '%v'.", err)
+	}
+
+	if len(expected) != len(actual) {
+		test.Fatalf("Unexpected number of results. Expected: %d, Actual: %d.", len(expected), len(actual))
+	}
+
+	expectedByEmail := make(map[string]*ReconcileResult, len(expected))
+	for _, result := range expected {
+		expectedByEmail[result.Email] = result
+	}
+
+	for i, result := range actual {
+		if (i > 0) && (actual[i-1].Email > result.Email) {
+			test.Errorf("Results are not sorted by email.")
+		}
+
+		if !reflect.DeepEqual(expectedByEmail[result.Email], result) {
+			test.Errorf("Unexpected result for '%s'. Expected: '%s', Actual: '%s'.",
+				result.Email, util.MustToJSONIndent(expectedByEmail[result.Email]), util.MustToJSONIndent(result))
+		}
+	}
+}
+
+func TestReconcileCourseScores(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer lmstest.ClearAssignmentScores()
+
+	course := db.MustGetTestCourse()
+	course.Assignments["hw0"].LMSID = "001"
+
+	lmstest.SetAssignmentScores("001", []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID: "lms-course-student@test.edulinq.org",
+			Score:  1,
+			Comments: []*lmstypes.SubmissionComment{
+				&lmstypes.SubmissionComment{
+					Text: util.MustToJSON(model.ScoringInfo{Score: 2, AutograderStructVersion: model.SCORING_INFO_STRUCT_VERSION}),
+				},
+			},
+		},
+	})
+
+	expected := map[string][]*ReconcileResult{
+		"hw0": []*ReconcileResult{
+			&ReconcileResult{
+				Email:           "course-student@test.edulinq.org",
+				LMSID:           "lms-course-student@test.edulinq.org",
+				Status:          ReconcileStatusLMSEdited,
+				AutograderScore: floatPointer(2),
+				LMSScore:        floatPointer(1),
+				UploadedScore:   floatPointer(2),
+			},
+		},
+	}
+
+	actual, err := ReconcileCourseScores(course)
+	if err != nil {
+		test.Fatalf("Failed to reconcile course scores: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		test.Fatalf("Unexpected results. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+}
+
+func floatPointer(value float64) *float64 {
+	return &value
+}
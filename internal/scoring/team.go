@@ -0,0 +1,52 @@
+package scoring
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Give team members without a score of their own (e.g., they joined the team after its last submission)
+// the most recent score of their team.
+// Members already have their own copy of every submission the team made while they were on it.
+func propagateTeamScores(assignment *model.Assignment, users map[string]*model.CourseUser, scoringInfos map[string]*model.ScoringInfo) error {
+	if assignment.Teams == nil {
+		return nil
+	}
+
+	teams, err := db.GetTeams(assignment)
+	if err != nil {
+		return fmt.Errorf("Failed to get teams: '%w'.", err)
+	}
+
+	for _, team := range teams {
+		var latest *model.ScoringInfo
+		for _, member := range team.Members {
+			scoringInfo := scoringInfos[member]
+			if (scoringInfo != nil) && ((latest == nil) || (scoringInfo.SubmissionTime > latest.SubmissionTime)) {
+				latest = scoringInfo
+			}
+		}
+
+		if latest == nil {
+			continue
+		}
+
+		for _, member := range team.Members {
+			user := users[member]
+			if (user == nil) || (user.Role != model.CourseRoleStudent) {
+				continue
+			}
+
+			if scoringInfos[member] != nil {
+				continue
+			}
+
+			scoringInfo := *latest
+			scoringInfos[member] = &scoringInfo
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestPropagateTeamScores(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+
+	// All the members must be students for the config team to be used,
+	// but the passed in users may have a different role.
+	db.MustSetTestStudents(assignment.GetCourse(), "course-other@test.edulinq.org", "course-grader@test.edulinq.org", "course-admin@test.edulinq.org")
+
+	assignment.Teams = &model.TeamInfo{
+		Teams: []*model.Team{
+			&model.Team{ID: "team-a", Members: []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org", "course-grader@test.edulinq.org", "course-admin@test.edulinq.org"}},
+		},
+	}
+
+	users := map[string]*model.CourseUser{
+		"course-student@test.edulinq.org": &model.CourseUser{Email: "course-student@test.edulinq.org", Role: model.CourseRoleStudent},
+		"course-other@test.edulinq.org":   &model.CourseUser{Email: "course-other@test.edulinq.org", Role: model.CourseRoleStudent},
+		"course-grader@test.edulinq.org":  &model.CourseUser{Email: "course-grader@test.edulinq.org", Role: model.CourseRoleStudent},
+		"course-admin@test.edulinq.org":   &model.CourseUser{Email: "course-admin@test.edulinq.org", Role: model.CourseRoleGrader},
+	}
+
+	scoringInfos := map[string]*model.ScoringInfo{
+		"course-student@test.edulinq.org": &model.ScoringInfo{ID: "old", SubmissionTime: timestamp.FromMSecs(100), Score: 1},
+		"course-other@test.edulinq.org":   &model.ScoringInfo{ID: "new", SubmissionTime: timestamp.FromMSecs(200), Score: 2},
+	}
+
+	err := propagateTeamScores(assignment, users, scoringInfos)
+	if err != nil {
+		test.Fatalf("Failed to propagate team scores: '%v'.", err)
+	}
+
+	// Members with their own scores keep them.
+	if scoringInfos["course-student@test.edulinq.org"].ID != "old" {
+		test.Fatalf("Member score was replaced.")
+	}
+
+	// Members without a score get the team's most recent score.
+	if (scoringInfos["course-grader@test.edulinq.org"] == nil) || (scoringInfos["course-grader@test.edulinq.org"].ID != "new") {
+		test.Fatalf("Member did not get the team's most recent score: '%+v'.", scoringInfos["course-grader@test.edulinq.org"])
+	}
+
+	// Only students get scores.
+	if scoringInfos["course-admin@test.edulinq.org"] != nil {
+		test.Fatalf("Staff member got a team score.")
+	}
+}
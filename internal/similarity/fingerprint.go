@@ -0,0 +1,82 @@
+package similarity
+
+// Fingerprint token streams using winnowing.
+// See: Schleimer, Wilkerson, and Aiken. "Winnowing: Local Algorithms for Document Fingerprinting." SIGMOD 2003.
+// Every k-gram (run of k consecutive tokens) is hashed,
+// and the smallest hash in every window of w consecutive hashes is selected as a fingerprint.
+// This guarantees that any match of at least (k + w - 1) tokens is found,
+// and that no match shorter than k tokens is found.
+
+import (
+	"hash/fnv"
+)
+
+// A set of fingerprints (hashes) and the file each fingerprint was first found in: {hash: relpath}.
+type Fingerprints map[uint64]string
+
+func hashKGram(tokens []string) uint64 {
+	hasher := fnv.New64a()
+
+	for _, token := range tokens {
+		hasher.Write([]byte(token))
+		hasher.Write([]byte{0})
+	}
+
+	return hasher.Sum64()
+}
+
+// Get the fingerprints of a token stream.
+// Token streams shorter than a single k-gram have no fingerprints.
+func Winnow(tokens []string, kGramSize int, windowSize int) []uint64 {
+	kGramSize = max(1, kGramSize)
+	windowSize = max(1, windowSize)
+
+	if len(tokens) < kGramSize {
+		return []uint64{}
+	}
+
+	hashes := make([]uint64, 0, len(tokens)-kGramSize+1)
+	for i := 0; (i + kGramSize) <= len(tokens); i++ {
+		hashes = append(hashes, hashKGram(tokens[i:(i+kGramSize)]))
+	}
+
+	// A single (short) window.
+	windowSize = min(windowSize, len(hashes))
+
+	fingerprints := make([]uint64, 0)
+	lastSelected := -1
+
+	for start := 0; (start + windowSize) <= len(hashes); start++ {
+		// Select the rightmost minimal hash in the window.
+		selected := start
+		for i := start + 1; i < (start + windowSize); i++ {
+			if hashes[i] <= hashes[selected] {
+				selected = i
+			}
+		}
+
+		if selected != lastSelected {
+			fingerprints = append(fingerprints, hashes[selected])
+			lastSelected = selected
+		}
+	}
+
+	return fingerprints
+}
+
+// Add the fingerprints of a file.
+func (this Fingerprints) AddFile(relpath string, text string, kGramSize int, windowSize int) {
+	for _, fingerprint := range Winnow(Tokenize(relpath, text), kGramSize, windowSize) {
+		_, exists := this[fingerprint]
+		if !exists {
+			this[fingerprint] = relpath
+		}
+	}
+}
+
+// Remove all the fingerprints that also appear in other.
+func (this Fingerprints) Remove(other Fingerprints) {
+	for fingerprint := range other {
+		delete(this, fingerprint)
+	}
+}
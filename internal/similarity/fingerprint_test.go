@@ -0,0 +1,100 @@
+package similarity
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func makeTokens(prefix string, count int) []string {
+	tokens := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		tokens = append(tokens, fmt.Sprintf("%s%d", prefix, i))
+	}
+
+	return tokens
+}
+
+func TestWinnowBase(test *testing.T) {
+	tokens := makeTokens("a", 50)
+
+	fingerprints := Winnow(tokens, 5, 4)
+	if len(fingerprints) == 0 {
+		test.Fatalf("Did not get any fingerprints.")
+	}
+
+	// Every window must have a fingerprint, so there must be at least one fingerprint per window (without overlap).
+	numHashes := len(tokens) - 5 + 1
+	if len(fingerprints) < (numHashes / 4) {
+		test.Fatalf("Too few fingerprints. Expected at least %d, found %d.", numHashes/4, len(fingerprints))
+	}
+
+	if len(fingerprints) > numHashes {
+		test.Fatalf("Too many fingerprints. Expected at most %d, found %d.", numHashes, len(fingerprints))
+	}
+
+	// Fingerprinting is deterministic.
+	if !slices.Equal(fingerprints, Winnow(tokens, 5, 4)) {
+		test.Fatalf("Fingerprints are not deterministic.")
+	}
+}
+
+func TestWinnowShort(test *testing.T) {
+	testCases := []struct {
+		numTokens int
+		expected  int
+	}{
+		{0, 0},
+		{4, 0},
+		// A single k-gram.
+		{5, 1},
+		// Fewer k-grams than the window size still gets a fingerprint.
+		{6, 1},
+	}
+
+	for i, testCase := range testCases {
+		fingerprints := Winnow(makeTokens("a", testCase.numTokens), 5, 4)
+		if testCase.expected != len(fingerprints) {
+			test.Errorf("Case %d: Unexpected number of fingerprints. Expected: %d, Actual: %d.", i, testCase.expected, len(fingerprints))
+		}
+	}
+}
+
+// Any match of at least (k + w - 1) tokens must share a fingerprint.
+func TestWinnowGuarantee(test *testing.T) {
+	kGramSize := 5
+	windowSize := 4
+
+	shared := makeTokens("shared", kGramSize+windowSize-1)
+
+	tokens1 := append(append(makeTokens("a", 20), shared...), makeTokens("b", 20)...)
+	tokens2 := append(append(makeTokens("c", 7), shared...), makeTokens("d", 13)...)
+
+	fingerprints1 := make(Fingerprints)
+	for _, fingerprint := range Winnow(tokens1, kGramSize, windowSize) {
+		fingerprints1[fingerprint] = "1"
+	}
+
+	found := false
+	for _, fingerprint := range Winnow(tokens2, kGramSize, windowSize) {
+		_, ok := fingerprints1[fingerprint]
+		if ok {
+			found = true
+		}
+	}
+
+	if !found {
+		test.Fatalf("Did not find a shared fingerprint.")
+	}
+}
+
+func TestFingerprintsRemove(test *testing.T) {
+	fingerprints := Fingerprints{1: "a", 2: "a", 3: "b"}
+	fingerprints.Remove(Fingerprints{2: "c", 4: "c"})
+
+	expected := Fingerprints{1: "a", 3: "b"}
+	if !reflect.DeepEqual(expected, fingerprints) {
+		test.Fatalf("Unexpected fingerprints. Expected: '%v', Actual: '%v'.", expected, fingerprints)
+	}
+}
@@ -0,0 +1,106 @@
+package similarity
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+func (this *SimilarityReport) ToHTML() (string, error) {
+	title := fmt.Sprintf("Similarity Report for %s", this.AssignmentName)
+	templateHTML := fmt.Sprintf(outterShell, title, style, reportTemplate)
+
+	tmpl, err := template.New("similarity-report").Parse(templateHTML)
+	if err != nil {
+		return "", fmt.Errorf("Could not parse similarity report template: '%w'.", err)
+	}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, this)
+	if err != nil {
+		return "", fmt.Errorf("Failed to execute similarity report template: '%w'.", err)
+	}
+
+	return builder.String(), nil
+}
+
+// Replacements: [title, head, body]
+var outterShell string = `
+    <html>
+        <head>
+            <meta charset="utf-8"/>
+            <meta name="viewport" content="width=device-width, initial-scale=1.0">
+
+            <title>%s</title>
+
+            %s
+        </head>
+        <body>
+            %s
+        </body>
+    </html>
+`
+
+var reportTemplate string = `
+    <div class='autograder autograder-similarity-report'>
+        <div class='ag-header'>
+            <h2>Assignment: {{ .AssignmentName }}</h2>
+            <p>Number of Submissions: {{ .NumberOfSubmissions }}</p>
+            <p>Starter Code Fingerprints: {{ .StarterFingerprints }}</p>
+            <p>Generated: {{ .GenerationTime.UnsafePrettyString }}</p>
+        </div>
+        <div class='ag-body'>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Score</th>
+                        <th>User 1</th>
+                        <th>Score 1</th>
+                        <th>Files 1</th>
+                        <th>User 2</th>
+                        <th>Score 2</th>
+                        <th>Files 2</th>
+                        <th>Shared Fingerprints</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{ range .Pairs }}
+                        <tr>
+                            <td class='numeric'>{{ .ScoreString }}</td>
+                            <td class='text' title='{{ .SubmissionID1 }}'>{{ .User1 }}</td>
+                            <td class='numeric'>{{ .Score1String }}</td>
+                            <td class='text'>{{ range $i, $file := .Files1 }}{{ if $i }}, {{ end }}{{ $file }}{{ end }}</td>
+                            <td class='text' title='{{ .SubmissionID2 }}'>{{ .User2 }}</td>
+                            <td class='numeric'>{{ .Score2String }}</td>
+                            <td class='text'>{{ range $i, $file := .Files2 }}{{ if $i }}, {{ end }}{{ $file }}{{ end }}</td>
+                            <td class='numeric'>{{ .SharedFingerprints }}</td>
+                        </tr>
+                    {{ end }}
+                </tbody>
+            </table>
+        </div>
+    </div>
+`
+
+var style string = `
+    <style>
+        .autograder-similarity-report table th,
+        .autograder-similarity-report table .text {
+            text-align: left;
+        }
+
+        .autograder-similarity-report table .numeric {
+            text-align: right;
+        }
+
+        .autograder-similarity-report table th,
+        .autograder-similarity-report table td {
+            padding: 5px;
+            padding-right: 10px;
+        }
+
+        .autograder-similarity-report table td.text {
+            padding-right: 15px;
+        }
+    </style>
+`
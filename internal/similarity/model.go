@@ -0,0 +1,98 @@
+package similarity
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+const (
+	DEFAULT_KGRAM_SIZE  = 12
+	DEFAULT_WINDOW_SIZE = 8
+)
+
+type SimilarityOptions struct {
+	// Only report pairs with at least this score.
+	MinScore float64 `json:"min-score" help:"Only report pairs of submissions with at least this score (0.0 - 1.0)." default:"0.0"`
+
+	// Do not remove starter code (the assignment's static files) before comparing submissions.
+	IncludeStarterCode bool `json:"include-starter-code" help:"Do not ignore code that is also in the assignment's static files (starter code)." default:"false"`
+
+	// The number of tokens in each k-gram (the minimum length of a match).
+	// Defaults to DEFAULT_KGRAM_SIZE.
+	KGramSize int `json:"kgram-size" help:"The minimum number of tokens in a match (defaults to 12)." default:"0"`
+
+	// The winnowing window size (any match of at least (KGramSize + WindowSize - 1) tokens is guaranteed to be found).
+	// Defaults to DEFAULT_WINDOW_SIZE.
+	WindowSize int `json:"window-size" help:"The winnowing window size, larger windows produce fewer fingerprints (defaults to 8)." default:"0"`
+}
+
+// The similarity between two submissions.
+type SimilarityPair struct {
+	User1         string `json:"user-1"`
+	SubmissionID1 string `json:"submission-id-1"`
+	User2         string `json:"user-2"`
+	SubmissionID2 string `json:"submission-id-2"`
+
+	// The number of fingerprints that are in both submissions.
+	SharedFingerprints int `json:"shared-fingerprints"`
+
+	// The fraction of the smaller submission that is also in the other submission.
+	Score float64 `json:"score"`
+	// The fraction of each submission that is also in the other submission.
+	Score1 float64 `json:"score-1"`
+	Score2 float64 `json:"score-2"`
+
+	// The files (from each submission) that contain shared code.
+	Files1 []string `json:"files-1"`
+	Files2 []string `json:"files-2"`
+}
+
+type SimilarityReport struct {
+	CourseID       string            `json:"course-id"`
+	AssignmentID   string            `json:"assignment-id"`
+	AssignmentName string            `json:"assignment-name"`
+	Options        SimilarityOptions `json:"options"`
+
+	GenerationTime timestamp.Timestamp `json:"generation-time"`
+
+	// The number of submissions that were compared (each user's most recent submission).
+	NumberOfSubmissions int `json:"number-of-submissions"`
+	// The number of fingerprints in the starter code (these are ignored in every submission).
+	StarterFingerprints int `json:"starter-fingerprints"`
+
+	// Sorted by score (highest first).
+	Pairs []*SimilarityPair `json:"pairs"`
+}
+
+func (this *SimilarityOptions) validate() error {
+	if this.KGramSize == 0 {
+		this.KGramSize = DEFAULT_KGRAM_SIZE
+	}
+
+	if this.WindowSize == 0 {
+		this.WindowSize = DEFAULT_WINDOW_SIZE
+	}
+
+	if this.KGramSize < 0 {
+		return fmt.Errorf("K-gram size must be positive, found %d.", this.KGramSize)
+	}
+
+	if this.WindowSize < 0 {
+		return fmt.Errorf("Window size must be positive, found %d.", this.WindowSize)
+	}
+
+	return nil
+}
+
+func (this *SimilarityPair) ScoreString() string {
+	return fmt.Sprintf("%0.2f", this.Score)
+}
+
+func (this *SimilarityPair) Score1String() string {
+	return fmt.Sprintf("%0.2f", this.Score1)
+}
+
+func (this *SimilarityPair) Score2String() string {
+	return fmt.Sprintf("%0.2f", this.Score2)
+}
@@ -0,0 +1,255 @@
+package similarity
+
+// Find similar submissions for an assignment (e.g., to detect plagiarism).
+// Each user's most recent submission is fingerprinted (see Winnow()),
+// and every pair of submissions is compared based on how many fingerprints they share.
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type submissionFingerprints struct {
+	User         string
+	SubmissionID string
+	Fingerprints Fingerprints
+}
+
+func GetSimilarityReport(assignment *model.Assignment, options SimilarityOptions) (*SimilarityReport, error) {
+	err := options.validate()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid similarity options: '%w'.", err)
+	}
+
+	results, err := db.GetRecentSubmissionContents(assignment, model.CourseRoleUnknown)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get recent submissions: '%w'.", err)
+	}
+
+	starterFingerprints := make(Fingerprints)
+	if !options.IncludeStarterCode {
+		starterFingerprints, err = getStarterFingerprints(assignment, options)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fingerprint starter code: '%w'.", err)
+		}
+	}
+
+	users := make([]string, 0, len(results))
+	for user, result := range results {
+		if (result == nil) || (result.Info == nil) {
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	slices.Sort(users)
+
+	submissions := make([]*submissionFingerprints, 0, len(users))
+	for _, user := range users {
+		result := results[user]
+
+		fingerprints, err := getSubmissionFingerprints(result.InputFilesGZip, options)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fingerprint submission '%s': '%w'.", result.Info.ID, err)
+		}
+
+		fingerprints.Remove(starterFingerprints)
+
+		submissions = append(submissions, &submissionFingerprints{
+			User:         user,
+			SubmissionID: result.Info.ID,
+			Fingerprints: fingerprints,
+		})
+	}
+
+	pairs := make([]*SimilarityPair, 0)
+	for i := 0; i < len(submissions); i++ {
+		for j := i + 1; j < len(submissions); j++ {
+			pair := compare(submissions[i], submissions[j])
+			if (pair == nil) || (pair.Score < options.MinScore) {
+				continue
+			}
+
+			pairs = append(pairs, pair)
+		}
+	}
+
+	slices.SortStableFunc(pairs, func(a *SimilarityPair, b *SimilarityPair) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+
+			return 1
+		}
+
+		value := strings.Compare(a.User1, b.User1)
+		if value != 0 {
+			return value
+		}
+
+		return strings.Compare(a.User2, b.User2)
+	})
+
+	report := &SimilarityReport{
+		CourseID:            assignment.GetCourse().GetID(),
+		AssignmentID:        assignment.GetID(),
+		AssignmentName:      assignment.GetName(),
+		Options:             options,
+		GenerationTime:      timestamp.Now(),
+		NumberOfSubmissions: len(submissions),
+		StarterFingerprints: len(starterFingerprints),
+		Pairs:               pairs,
+	}
+
+	return report, nil
+}
+
+// Compare two submissions.
+// Returns nil if the submissions do not share any fingerprints.
+func compare(submission1 *submissionFingerprints, submission2 *submissionFingerprints) *SimilarityPair {
+	if (len(submission1.Fingerprints) == 0) || (len(submission2.Fingerprints) == 0) {
+		return nil
+	}
+
+	shared := 0
+	files1 := make(map[string]bool)
+	files2 := make(map[string]bool)
+
+	for fingerprint, file1 := range submission1.Fingerprints {
+		file2, ok := submission2.Fingerprints[fingerprint]
+		if !ok {
+			continue
+		}
+
+		shared++
+		files1[file1] = true
+		files2[file2] = true
+	}
+
+	if shared == 0 {
+		return nil
+	}
+
+	score1 := float64(shared) / float64(len(submission1.Fingerprints))
+	score2 := float64(shared) / float64(len(submission2.Fingerprints))
+
+	return &SimilarityPair{
+		User1:              submission1.User,
+		SubmissionID1:      submission1.SubmissionID,
+		User2:              submission2.User,
+		SubmissionID2:      submission2.SubmissionID,
+		SharedFingerprints: shared,
+		Score:              max(score1, score2),
+		Score1:             score1,
+		Score2:             score2,
+		Files1:             sortedKeys(files1),
+		Files2:             sortedKeys(files2),
+	}
+}
+
+// Fingerprint a submission's files (in the same format as GradingResult.InputFilesGZip).
+// Binary files are skipped.
+func getSubmissionFingerprints(filesGZip map[string][]byte, options SimilarityOptions) (Fingerprints, error) {
+	fingerprints := make(Fingerprints)
+
+	relpaths := make([]string, 0, len(filesGZip))
+	for relpath := range filesGZip {
+		relpaths = append(relpaths, relpath)
+	}
+
+	// Sort so that shared fingerprints are consistently attributed to the same file.
+	slices.Sort(relpaths)
+
+	for _, relpath := range relpaths {
+		data, err := util.GzipBytesToBytes(filesGZip[relpath])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decompress file '%s': '%w'.", relpath, err)
+		}
+
+		if isBinary(data) {
+			continue
+		}
+
+		fingerprints.AddFile(relpath, string(data), options.KGramSize, options.WindowSize)
+	}
+
+	return fingerprints, nil
+}
+
+// Fingerprint the assignment's static files.
+func getStarterFingerprints(assignment *model.Assignment, options SimilarityOptions) (Fingerprints, error) {
+	fingerprints := make(Fingerprints)
+
+	imageInfo := assignment.GetImageInfo()
+	if (imageInfo == nil) || (len(imageInfo.StaticFiles) == 0) {
+		return fingerprints, nil
+	}
+
+	tempDir, err := util.MkDirTemp("similarity-starter-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to make temp dir: '%w'.", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	err = common.CopyFileSpecs(imageInfo.BaseDirFunc(), tempDir, tempDir, imageInfo.StaticFiles, false, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to copy static files: '%w'.", err)
+	}
+
+	paths, err := util.FindFiles("", tempDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find static files: '%w'.", err)
+	}
+
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read static file '%s': '%w'.", path, err)
+		}
+
+		if isBinary(data) {
+			continue
+		}
+
+		relpath, err := filepath.Rel(tempDir, path)
+		if err != nil {
+			relpath = filepath.Base(path)
+		}
+
+		fingerprints.AddFile(relpath, string(data), options.KGramSize, options.WindowSize)
+	}
+
+	log.Trace("Fingerprinted starter code.", assignment, log.NewAttr("num-fingerprints", len(fingerprints)))
+
+	return fingerprints, nil
+}
+
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+func sortedKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,264 @@
+package similarity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const testCode = `
+function add() {
+    local a=$1
+    local b=$2
+    echo $((a + b))
+}
+
+function sum_all() {
+    local total=0
+    for value in "$@" ; do
+        total=$(add "${total}" "${value}")
+    done
+    echo "${total}"
+}
+
+function main() {
+    if [[ $# -eq 0 ]] ; then
+        echo "USAGE: $0 <value> ..."
+        exit 1
+    fi
+
+    sum_all "$@"
+}
+`
+
+// The same code as testCode, but with renamed variables and different comments.
+const testCodeRenamed = `
+# Add two numbers.
+function plus() {
+    local first=$1
+    local second=$2
+    echo $((first + second))
+}
+
+# Add up all the arguments.
+function total_of() {
+    local result=0
+    for item in "$@" ; do
+        result=$(plus "${result}" "${item}")
+    done
+    echo "${result}"
+}
+
+function run() {
+    if [[ $# -eq 0 ]] ; then
+        echo "Usage: $0 <number> ..."
+        exit 2
+    fi
+
+    total_of "$@"
+}
+`
+
+const testCodeDifferent = `
+function multiply() {
+    local x=$1
+    local y=$2
+    echo $((x * y))
+}
+
+while read -r line ; do
+    case "${line}" in
+        quit)
+            break
+            ;;
+        *)
+            multiply ${line}
+            ;;
+    esac
+done
+`
+
+func TestGetSimilarityReportBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	saveTestSubmission(test, assignment, "course-other@test.edulinq.org", testCode)
+	saveTestSubmission(test, assignment, "course-student@test.edulinq.org", testCodeRenamed)
+	saveTestSubmission(test, assignment, "course-grader@test.edulinq.org", testCodeDifferent)
+
+	report, err := GetSimilarityReport(assignment, SimilarityOptions{})
+	if err != nil {
+		test.Fatalf("Failed to get similarity report: '%v'.", err)
+	}
+
+	if report.NumberOfSubmissions != 3 {
+		test.Fatalf("Unexpected number of submissions. Expected: 3, Actual: %d.", report.NumberOfSubmissions)
+	}
+
+	if report.Options.KGramSize != DEFAULT_KGRAM_SIZE {
+		test.Fatalf("Default k-gram size not set. Expected: %d, Actual: %d.", DEFAULT_KGRAM_SIZE, report.Options.KGramSize)
+	}
+
+	if report.StarterFingerprints == 0 {
+		test.Fatalf("Starter code was not fingerprinted.")
+	}
+
+	if len(report.Pairs) == 0 {
+		test.Fatalf("Did not find any similar pairs.")
+	}
+
+	pair := report.Pairs[0]
+	if (pair.User1 != "course-other@test.edulinq.org") || (pair.User2 != "course-student@test.edulinq.org") {
+		test.Fatalf("Unexpected most similar pair. Expected: ('course-other@test.edulinq.org', 'course-student@test.edulinq.org'), Actual: ('%s', '%s').", pair.User1, pair.User2)
+	}
+
+	if pair.Score != 1.0 {
+		test.Fatalf("Renamed copy did not get a perfect score. Actual: %f.", pair.Score)
+	}
+
+	if (len(pair.Files1) != 1) || (pair.Files1[0] != "assignment.sh") {
+		test.Fatalf("Unexpected files. Expected: '[assignment.sh]', Actual: '%v'.", pair.Files1)
+	}
+
+	for _, other := range report.Pairs[1:] {
+		if other.Score >= 0.5 {
+			test.Fatalf("Different code has a high score: '%s'.", util.MustToJSONIndent(other))
+		}
+	}
+
+	// Filter out the low scoring pairs.
+	report, err = GetSimilarityReport(assignment, SimilarityOptions{MinScore: 0.5})
+	if err != nil {
+		test.Fatalf("Failed to get filtered similarity report: '%v'.", err)
+	}
+
+	if len(report.Pairs) != 1 {
+		test.Fatalf("Unexpected number of filtered pairs. Expected: 1, Actual: %d.", len(report.Pairs))
+	}
+
+	html, err := report.ToHTML()
+	if err != nil {
+		test.Fatalf("Failed to get HTML: '%v'.", err)
+	}
+
+	if !strings.Contains(html, "course-student@test.edulinq.org") {
+		test.Fatalf("HTML does not contain the similar user.")
+	}
+}
+
+func TestGetSimilarityReportStarterCode(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	starterPath := filepath.Join(assignment.GetSourceDir(), "grader.sh")
+	starterCode, err := util.ReadFile(starterPath)
+	if err != nil {
+		test.Fatalf("Failed to read starter code: '%v'.", err)
+	}
+
+	// Both users submit only the starter code.
+	saveTestSubmission(test, assignment, "course-other@test.edulinq.org", starterCode)
+	saveTestSubmission(test, assignment, "course-student@test.edulinq.org", starterCode)
+
+	report, err := GetSimilarityReport(assignment, SimilarityOptions{})
+	if err != nil {
+		test.Fatalf("Failed to get similarity report: '%v'.", err)
+	}
+
+	if len(report.Pairs) != 0 {
+		test.Fatalf("Starter code was not ignored: '%s'.", util.MustToJSONIndent(report.Pairs))
+	}
+
+	report, err = GetSimilarityReport(assignment, SimilarityOptions{IncludeStarterCode: true})
+	if err != nil {
+		test.Fatalf("Failed to get similarity report (including starter code): '%v'.", err)
+	}
+
+	if report.StarterFingerprints != 0 {
+		test.Fatalf("Starter code was fingerprinted when it should be included.")
+	}
+
+	if (len(report.Pairs) != 1) || (report.Pairs[0].Score != 1.0) {
+		test.Fatalf("Starter code was not matched: '%s'.", util.MustToJSONIndent(report.Pairs))
+	}
+}
+
+func TestGetSimilarityReportBadOptions(test *testing.T) {
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	_, err := GetSimilarityReport(assignment, SimilarityOptions{KGramSize: -1})
+	if err == nil {
+		test.Fatalf("Did not get an error on a bad k-gram size.")
+	}
+}
+
+func saveTestSubmission(test *testing.T, assignment *model.Assignment, email string, code string) {
+	tempDir := util.MustMkDirTemp("test-similarity-")
+	defer os.RemoveAll(tempDir)
+
+	inputDir := filepath.Join(tempDir, common.GRADING_INPUT_DIRNAME)
+	outputDir := filepath.Join(tempDir, common.GRADING_OUTPUT_DIRNAME)
+
+	err := util.MkDir(inputDir)
+	if err != nil {
+		test.Fatalf("Failed to make input dir: '%v'.", err)
+	}
+
+	err = util.MkDir(outputDir)
+	if err != nil {
+		test.Fatalf("Failed to make output dir: '%v'.", err)
+	}
+
+	err = util.WriteFile(code, filepath.Join(inputDir, "assignment.sh"))
+	if err != nil {
+		test.Fatalf("Failed to write submission: '%v'.", err)
+	}
+
+	err = util.WriteFile("{}", filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME))
+	if err != nil {
+		test.Fatalf("Failed to write output: '%v'.", err)
+	}
+
+	inputGZip, err := util.GzipDirectoryToBytes(inputDir)
+	if err != nil {
+		test.Fatalf("Failed to gzip submission: '%v'.", err)
+	}
+
+	outputGZip, err := util.GzipDirectoryToBytes(outputDir)
+	if err != nil {
+		test.Fatalf("Failed to gzip output: '%v'.", err)
+	}
+
+	shortID, err := db.GetNextSubmissionID(assignment, email)
+	if err != nil {
+		test.Fatalf("Failed to get submission ID: '%v'.", err)
+	}
+
+	result := &model.GradingResult{
+		Info: &model.GradingInfo{
+			ID:           common.CreateFullSubmissionID(assignment.GetCourse().GetID(), assignment.GetID(), email, shortID),
+			ShortID:      shortID,
+			CourseID:     assignment.GetCourse().GetID(),
+			AssignmentID: assignment.GetID(),
+			User:         email,
+			Questions:    []*model.GradedQuestion{},
+		},
+		InputFilesGZip:  inputGZip,
+		OutputFilesGZip: outputGZip,
+	}
+
+	err = db.SaveSubmission(assignment, result)
+	if err != nil {
+		test.Fatalf("Failed to save submission: '%v'.", err)
+	}
+}
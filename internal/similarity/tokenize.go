@@ -0,0 +1,280 @@
+package similarity
+
+// Turn source files into normalized token streams.
+// Normalization removes the things that are easy to change without changing the code
+// (whitespace, comments, identifier names, and literal values),
+// so that renaming variables or rewording comments does not hide copied code.
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Normalized tokens for things whose exact value is ignored.
+const (
+	TOKEN_IDENTIFIER = "<id>"
+	TOKEN_NUMBER     = "<num>"
+	TOKEN_STRING     = "<str>"
+)
+
+// How to tokenize a family of languages.
+type language struct {
+	Name string
+
+	LineComments  []string
+	BlockComments [][2]string
+	// Characters that start (and end) a string literal.
+	StringDelimiters string
+	// Python-style triple-quoted strings.
+	TripleQuotes bool
+	// Strings can span multiple lines (triple-quoted and backtick strings always can).
+	MultilineStrings bool
+	// Line comments only start at the beginning of a word (e.g., "$#" in shell is not a comment).
+	CommentsAtWordStart bool
+
+	// Keywords are kept as-is (all other words are normalized to TOKEN_IDENTIFIER).
+	Keywords map[string]bool
+}
+
+var languageC = &language{
+	Name:             "c",
+	LineComments:     []string{"//"},
+	BlockComments:    [][2]string{{"/*", "*/"}},
+	StringDelimiters: "\"'`",
+	Keywords: makeKeywords(
+		// C/C++
+		"auto", "break", "case", "char", "const", "continue", "default", "do", "double", "else", "enum", "extern",
+		"float", "for", "goto", "if", "inline", "int", "long", "register", "return", "short", "signed", "sizeof",
+		"static", "struct", "switch", "typedef", "union", "unsigned", "void", "volatile", "while",
+		"bool", "catch", "class", "delete", "false", "friend", "namespace", "new", "nullptr", "operator", "private",
+		"protected", "public", "template", "this", "throw", "true", "try", "typename", "using", "virtual",
+		// Java/C#/JS/Go
+		"abstract", "boolean", "byte", "extends", "final", "finally", "implements", "import", "instanceof",
+		"interface", "native", "null", "package", "super", "synchronized", "throws", "transient",
+		"function", "let", "var", "of", "in", "typeof", "undefined", "async", "await", "yield",
+		"chan", "defer", "func", "go", "map", "range", "select", "type", "nil",
+	),
+}
+
+var languagePython = &language{
+	Name:             "python",
+	LineComments:     []string{"#"},
+	StringDelimiters: "\"'",
+	TripleQuotes:     true,
+	Keywords: makeKeywords(
+		"False", "None", "True", "and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del",
+		"elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda",
+		"nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield", "self",
+	),
+}
+
+var languageShell = &language{
+	Name:                "shell",
+	LineComments:        []string{"#"},
+	StringDelimiters:    "\"'",
+	MultilineStrings:    true,
+	CommentsAtWordStart: true,
+	Keywords: makeKeywords(
+		"if", "then", "else", "elif", "fi", "case", "esac", "for", "select", "while", "until", "do", "done", "in",
+		"function", "time", "local", "return", "export", "readonly", "declare", "echo", "exit",
+	),
+}
+
+// Used for files that do not have a known language.
+var languageText = &language{
+	Name:     "text",
+	Keywords: map[string]bool{},
+}
+
+var extensionLanguages = map[string]*language{
+	".c":     languageC,
+	".h":     languageC,
+	".cc":    languageC,
+	".cpp":   languageC,
+	".cxx":   languageC,
+	".hpp":   languageC,
+	".cs":    languageC,
+	".go":    languageC,
+	".java":  languageC,
+	".js":    languageC,
+	".jsx":   languageC,
+	".kt":    languageC,
+	".rs":    languageC,
+	".scala": languageC,
+	".swift": languageC,
+	".ts":    languageC,
+	".tsx":   languageC,
+	".py":    languagePython,
+	".bash":  languageShell,
+	".sh":    languageShell,
+}
+
+func makeKeywords(keywords ...string) map[string]bool {
+	result := make(map[string]bool, len(keywords))
+	for _, keyword := range keywords {
+		result[keyword] = true
+	}
+
+	return result
+}
+
+func getLanguage(path string) *language {
+	language, ok := extensionLanguages[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return languageText
+	}
+
+	return language
+}
+
+// Get the normalized tokens for a file.
+// The language is chosen based on the file's extension.
+func Tokenize(path string, text string) []string {
+	return getLanguage(path).tokenize(text)
+}
+
+func (this *language) tokenize(text string) []string {
+	tokens := make([]string, 0)
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		char := runes[i]
+
+		if unicode.IsSpace(char) {
+			i++
+			continue
+		}
+
+		// Comments.
+		end, ok := this.matchComment(runes, i)
+		if ok {
+			i = end
+			continue
+		}
+
+		// Strings.
+		if strings.ContainsRune(this.StringDelimiters, char) {
+			i = this.skipString(runes, i)
+			tokens = append(tokens, TOKEN_STRING)
+			continue
+		}
+
+		// Numbers.
+		if unicode.IsDigit(char) {
+			for (i < len(runes)) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || (runes[i] == '.') || (runes[i] == '_')) {
+				i++
+			}
+
+			tokens = append(tokens, TOKEN_NUMBER)
+			continue
+		}
+
+		// Words.
+		if isWordRune(char) {
+			start := i
+			for (i < len(runes)) && (isWordRune(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+
+			word := string(runes[start:i])
+			if this.Keywords[word] {
+				tokens = append(tokens, word)
+			} else {
+				tokens = append(tokens, TOKEN_IDENTIFIER)
+			}
+
+			continue
+		}
+
+		// Everything else (operators and punctuation) is a single character token.
+		tokens = append(tokens, string(char))
+		i++
+	}
+
+	return tokens
+}
+
+func isWordRune(char rune) bool {
+	return unicode.IsLetter(char) || (char == '_') || (char == '$')
+}
+
+// If a comment starts at this position, return the position right after the comment.
+func (this *language) matchComment(runes []rune, start int) (int, bool) {
+	for _, prefix := range this.LineComments {
+		if !hasPrefixAt(runes, start, prefix) {
+			continue
+		}
+
+		if this.CommentsAtWordStart && (start > 0) && !unicode.IsSpace(runes[start-1]) {
+			continue
+		}
+
+		i := start
+		for (i < len(runes)) && (runes[i] != '\n') {
+			i++
+		}
+
+		return i, true
+	}
+
+	for _, delimiters := range this.BlockComments {
+		if !hasPrefixAt(runes, start, delimiters[0]) {
+			continue
+		}
+
+		for i := start + len([]rune(delimiters[0])); i < len(runes); i++ {
+			if hasPrefixAt(runes, i, delimiters[1]) {
+				return i + len([]rune(delimiters[1])), true
+			}
+		}
+
+		// Unterminated comment.
+		return len(runes), true
+	}
+
+	return start, false
+}
+
+// Return the position right after the string that starts at this position.
+func (this *language) skipString(runes []rune, start int) int {
+	delimiter := string(runes[start])
+	if this.TripleQuotes && hasPrefixAt(runes, start, strings.Repeat(delimiter, 3)) {
+		delimiter = strings.Repeat(delimiter, 3)
+	}
+
+	multiline := this.MultilineStrings || (len(delimiter) == 3) || (delimiter == "`")
+
+	for i := start + len([]rune(delimiter)); i < len(runes); i++ {
+		if runes[i] == '\\' {
+			i++
+			continue
+		}
+
+		if (runes[i] == '\n') && !multiline {
+			return i
+		}
+
+		if hasPrefixAt(runes, i, delimiter) {
+			return i + len([]rune(delimiter))
+		}
+	}
+
+	// Unterminated string.
+	return len(runes)
+}
+
+func hasPrefixAt(runes []rune, start int, prefix string) bool {
+	prefixRunes := []rune(prefix)
+	if (start + len(prefixRunes)) > len(runes) {
+		return false
+	}
+
+	for i, char := range prefixRunes {
+		if runes[start+i] != char {
+			return false
+		}
+	}
+
+	return true
+}
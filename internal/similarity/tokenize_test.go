@@ -0,0 +1,81 @@
+package similarity
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeBase(test *testing.T) {
+	testCases := []struct {
+		path     string
+		text     string
+		expected []string
+	}{
+		{
+			"a.py",
+			"def add(a, b):  # Add two numbers.\n    return a + b\n",
+			[]string{"def", "<id>", "(", "<id>", ",", "<id>", ")", ":", "return", "<id>", "+", "<id>"},
+		},
+		{
+			"a.py",
+			"x = '''multi\nline # not a comment''' + \"a\" + 1.5e3",
+			[]string{"<id>", "=", "<str>", "+", "<str>", "+", "<num>"},
+		},
+		{
+			"a.java",
+			"/* Block\n comment. */ int x = 10; // Line comment.\nString s = \"a // b\";",
+			[]string{"int", "<id>", "=", "<num>", ";", "<id>", "<id>", "=", "<str>", ";"},
+		},
+		{
+			"a.sh",
+			"echo $# # Comment.\nlocal x=\"a\nb\"",
+			[]string{"echo", "<id>", "#", "local", "<id>", "=", "<str>"},
+		},
+		{
+			// Unknown languages do not have comments or strings.
+			"a.txt",
+			"# a 'b' 1",
+			[]string{"#", "<id>", "'", "<id>", "'", "<num>"},
+		},
+		{
+			"a.c",
+			"char *s = \"unterminated\nint x;",
+			[]string{"char", "*", "<id>", "=", "<str>", "int", "<id>", ";"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		actual := Tokenize(testCase.path, testCase.text)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected tokens. Expected: '%s', Actual: '%s'.",
+				i, strings.Join(testCase.expected, " "), strings.Join(actual, " "))
+		}
+	}
+}
+
+// Renaming identifiers and changing comments/whitespace should not change the tokens.
+func TestTokenizeNormalization(test *testing.T) {
+	original := `
+def fib(n):
+    # Compute the nth Fibonacci number.
+    if n < 2:
+        return n
+
+    return fib(n - 1) + fib(n - 2)
+`
+
+	disguised := `
+def fibonacci(value):
+    # A different comment.
+    if value < 2: return value
+    return fibonacci(value - 1) + fibonacci(value - 2)  # Recurse.
+`
+
+	expected := Tokenize("original.py", original)
+	actual := Tokenize("disguised.py", disguised)
+
+	if !reflect.DeepEqual(expected, actual) {
+		test.Fatalf("Tokens do not match. Expected: '%s', Actual: '%s'.", strings.Join(expected, " "), strings.Join(actual, " "))
+	}
+}
@@ -12,8 +12,9 @@ import (
 type CourseMetricType string
 
 const (
-	CourseMetricTypeUnknown     CourseMetricType = ""
-	CourseMetricTypeGradingTime                  = "grading-time"
+	CourseMetricTypeUnknown          CourseMetricType = ""
+	CourseMetricTypeGradingTime                       = "grading-time"
+	CourseMetricTypeGradingQueueWait                  = "grading-queue-wait"
 )
 
 type CourseMetric struct {
@@ -39,13 +40,15 @@ type CourseMetricQuery struct {
 }
 
 var courseMetricTypeToString = map[CourseMetricType]string{
-	CourseMetricTypeUnknown:     string(CourseMetricTypeUnknown),
-	CourseMetricTypeGradingTime: string(CourseMetricTypeGradingTime),
+	CourseMetricTypeUnknown:          string(CourseMetricTypeUnknown),
+	CourseMetricTypeGradingTime:      string(CourseMetricTypeGradingTime),
+	CourseMetricTypeGradingQueueWait: string(CourseMetricTypeGradingQueueWait),
 }
 
 var stringToCourseMetricType = map[string]CourseMetricType{
-	string(CourseMetricTypeUnknown):     CourseMetricTypeUnknown,
-	string(CourseMetricTypeGradingTime): CourseMetricTypeGradingTime,
+	string(CourseMetricTypeUnknown):          CourseMetricTypeUnknown,
+	string(CourseMetricTypeGradingTime):      CourseMetricTypeGradingTime,
+	string(CourseMetricTypeGradingQueueWait): CourseMetricTypeGradingQueueWait,
 }
 
 func (this CourseMetricType) MarshalJSON() ([]byte, error) {
@@ -92,10 +95,21 @@ func (this CourseMetricQuery) Match(record *CourseMetric) bool {
 // Store a grading time metric without blocking (unless this is running in test mode, then it will block).
 // Course ID is required, and all provided IDs should already be validated.
 func AsyncStoreCourseGradingTime(startTime timestamp.Timestamp, endTime timestamp.Timestamp, courseID string, assignmentID string, userEmail string) {
+	asyncStoreCourseDuration(CourseMetricTypeGradingTime, startTime, endTime, courseID, assignmentID, userEmail)
+}
+
+// Store the time a grader waited for a free grading slot without blocking (unless this is running in test mode, then it will block).
+// Course ID is required, and all provided IDs should already be validated.
+func AsyncStoreCourseGradingQueueWait(startTime timestamp.Timestamp, endTime timestamp.Timestamp, courseID string, assignmentID string, userEmail string) {
+	asyncStoreCourseDuration(CourseMetricTypeGradingQueueWait, startTime, endTime, courseID, assignmentID, userEmail)
+}
+
+func asyncStoreCourseDuration(metricType CourseMetricType, startTime timestamp.Timestamp, endTime timestamp.Timestamp, courseID string, assignmentID string, userEmail string) {
 	storeFunc := func() {
-		err := storeCourseGradingTime(startTime, endTime, courseID, assignmentID, userEmail)
+		err := storeCourseDuration(metricType, startTime, endTime, courseID, assignmentID, userEmail)
 		if err != nil {
-			log.Error("Failed to log course grading time.", err, log.NewCourseAttr(courseID), log.NewAssignmentAttr(assignmentID), log.NewUserAttr(userEmail))
+			log.Error("Failed to log course duration.", err, log.NewAttr("type", metricType),
+				log.NewCourseAttr(courseID), log.NewAssignmentAttr(assignmentID), log.NewUserAttr(userEmail))
 		}
 	}
 
@@ -106,7 +120,7 @@ func AsyncStoreCourseGradingTime(startTime timestamp.Timestamp, endTime timestam
 	}
 }
 
-func storeCourseGradingTime(startTime timestamp.Timestamp, endTime timestamp.Timestamp, courseID string, assignmentID string, userEmail string) error {
+func storeCourseDuration(metricType CourseMetricType, startTime timestamp.Timestamp, endTime timestamp.Timestamp, courseID string, assignmentID string, userEmail string) error {
 	if courseID == "" {
 		return fmt.Errorf("Cannot log course statistic without course ID.")
 	}
@@ -115,7 +129,7 @@ func storeCourseGradingTime(startTime timestamp.Timestamp, endTime timestamp.Tim
 		BaseMetric: BaseMetric{
 			Timestamp: startTime,
 		},
-		Type:         CourseMetricTypeGradingTime,
+		Type:         metricType,
 		CourseID:     courseID,
 		AssignmentID: assignmentID,
 		UserEmail:    userEmail,
@@ -23,4 +23,8 @@ type SystemMetrics struct {
 	MemPercent       float64 `json:"mem-percent"`
 	NetBytesSent     uint64  `json:"net-bytes-sent"`
 	NetBytesReceived uint64  `json:"net-bytes-received"`
+
+	// The number of graders waiting for a free grading slot, and the number currently running.
+	GradingQueueDepth int `json:"grading-queue-depth"`
+	GradingRunning    int `json:"grading-running"`
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
@@ -24,6 +25,9 @@ var (
 	getStatsLock      sync.Mutex
 	lastBytesSent     uint64 = 0
 	lastBytesReceived uint64 = 0
+
+	gradingQueueDepth atomic.Int64
+	gradingRunning    atomic.Int64
 )
 
 func collectSystemStats(systemIntervalMS int) {
@@ -147,11 +151,20 @@ func getSystemMetrics(intervalMS int) (*SystemMetrics, error) {
 		BaseMetric: BaseMetric{
 			Timestamp: timestamp.Now(),
 		},
-		CPUPercent:       util.RoundWithPrecision(cpuMetrics[0], 2),
-		MemPercent:       util.RoundWithPrecision(memMetrics.UsedPercent, 2),
-		NetBytesSent:     bytesSentDelta,
-		NetBytesReceived: bytesReceivedDelta,
+		CPUPercent:        util.RoundWithPrecision(cpuMetrics[0], 2),
+		MemPercent:        util.RoundWithPrecision(memMetrics.UsedPercent, 2),
+		NetBytesSent:      bytesSentDelta,
+		NetBytesReceived:  bytesReceivedDelta,
+		GradingQueueDepth: int(gradingQueueDepth.Load()),
+		GradingRunning:    int(gradingRunning.Load()),
 	}
 
 	return &results, nil
 }
+
+// Set the current state of the grading scheduler.
+// These values will be included in any system metrics collected after this call.
+func SetGradingQueueState(depth int, running int) {
+	gradingQueueDepth.Store(int64(depth))
+	gradingRunning.Store(int64(running))
+}
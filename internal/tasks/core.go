@@ -119,6 +119,8 @@ func runTask(task *model.FullScheduledTask) {
 		err = RunCourseBackupTask(task)
 	case model.TaskTypeCourseEmailLogs:
 		err = RunCourseEmailLogsTask(task)
+	case model.TaskTypeCourseLMSReconcile:
+		err = RunCourseLMSReconcileTask(task)
 	case model.TaskTypeCourseReport:
 		err = RunCourseReportTask(task)
 	case model.TaskTypeCourseScoringUpload:
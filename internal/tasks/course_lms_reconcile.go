@@ -0,0 +1,59 @@
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/report"
+)
+
+func RunCourseLMSReconcileTask(task *model.FullScheduledTask) error {
+	course, err := db.GetCourse(task.CourseID)
+	if err != nil {
+		return fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
+	}
+
+	if course == nil {
+		return fmt.Errorf("Unable to find course '%s'.", task.CourseID)
+	}
+
+	to, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "to", []string{})
+	if err != nil {
+		return fmt.Errorf("Unable to get recipients: '%w'.", err)
+	}
+
+	sendEmpty := (task.Options["send-empty"] == true)
+
+	report, err := report.GetCourseReconciliationReport(course)
+	if err != nil {
+		return fmt.Errorf("Failed to get reconciliation report for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	if !report.HasConflicts() && !sendEmpty {
+		log.Debug("No LMS conflicts found, skipping reconciliation report.", course)
+		return nil
+	}
+
+	html, err := report.ToHTML()
+	if err != nil {
+		return fmt.Errorf("Failed to generate HTML for reconciliation report for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	subject := fmt.Sprintf("Autograder LMS Reconciliation Report for %s", course.GetName())
+
+	to, err = db.ResolveCourseUsers(course, to)
+	if err != nil {
+		return fmt.Errorf("Failed to resolve users for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	err = email.Send(to, subject, html, true)
+	if err != nil {
+		return fmt.Errorf("Failed to send reconciliation report for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	log.Debug("Reconciliation report completed successfully.", course, log.NewAttr("to", to))
+	return nil
+}
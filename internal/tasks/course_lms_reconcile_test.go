@@ -0,0 +1,72 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func TestRunCourseLMSReconcileTaskBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer lmstest.ClearAssignmentScores()
+	defer email.ClearTestMessages()
+
+	// Give the assignment an LMS ID.
+	course := db.MustGetTestCourse()
+	course.Assignments["hw0"].LMSID = "001"
+	db.MustSaveCourse(course)
+
+	testCases := []struct {
+		lmsScore      float64
+		sendEmpty     bool
+		expectedEmail bool
+	}{
+		// In sync.
+		{2, false, false},
+		{2, true, true},
+
+		// Edited in the LMS.
+		{1, false, true},
+		{1, true, true},
+	}
+
+	for i, testCase := range testCases {
+		email.ClearTestMessages()
+
+		lmstest.SetAssignmentScores("001", []*lmstypes.SubmissionScore{
+			&lmstypes.SubmissionScore{
+				UserID: "lms-course-student@test.edulinq.org",
+				Score:  testCase.lmsScore,
+			},
+		})
+
+		task := &model.FullScheduledTask{
+			UserTaskInfo: model.UserTaskInfo{
+				Options: map[string]any{
+					"to":         []string{"course-admin@test.edulinq.org"},
+					"send-empty": testCase.sendEmpty,
+				},
+			},
+			SystemTaskInfo: model.SystemTaskInfo{
+				CourseID: db.TEST_COURSE_ID,
+			},
+		}
+
+		err := RunCourseLMSReconcileTask(task)
+		if err != nil {
+			test.Errorf("Case %d: Got an unexpected error running task: '%v'.", i, err)
+			continue
+		}
+
+		sentEmail := (len(email.GetTestMessages()) > 0)
+		if testCase.expectedEmail != sentEmail {
+			test.Errorf("Case %d: Unexpected email. Expected: %v, Actual: %v.", i, testCase.expectedEmail, sentEmail)
+			continue
+		}
+	}
+}
@@ -3,6 +3,7 @@ package util
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
@@ -11,6 +12,32 @@ func RemoveDirent(path string) error {
 	return os.RemoveAll(path)
 }
 
+// Get the total size (in bytes) of all the files in a dirent.
+// Links are not followed.
+func GetDirentSize(path string) (int64, error) {
+	var size int64 = 0
+
+	err := filepath.WalkDir(path, func(path string, dirent fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !dirent.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := dirent.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+		return nil
+	})
+
+	return size, err
+}
+
 // Copy a file or directory into dest.
 // If source is a file, then dest can be a file or dir.
 // If source is a dir, then see CopyDir() for semantics.
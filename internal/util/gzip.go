@@ -35,17 +35,27 @@ func GzipFileToBytes(path string) ([]byte, error) {
 }
 
 func GzipBytesToFile(data []byte, path string) error {
+	clearData, err := GzipBytesToBytes(data)
+	if err != nil {
+		return fmt.Errorf("Failed to decompress data to go in '%s': '%w'.", path, err)
+	}
+
+	return WriteBinaryFile(clearData, path)
+}
+
+// Decompress gzipped bytes (e.g., a single file from GzipDirectoryToBytes()).
+func GzipBytesToBytes(data []byte) ([]byte, error) {
 	reader, err := gzip.NewReader(bytes.NewBuffer(bytes.Clone(data)))
 	if err != nil {
-		return fmt.Errorf("Failed to create gzip read for data to go in '%s': '%w'.", path, err)
+		return nil, fmt.Errorf("Failed to create gzip reader: '%w'.", err)
 	}
 
 	clearData, err := io.ReadAll(reader)
 	if err != nil {
-		return fmt.Errorf("Failed to read gzip contents to go in '%s': '%w'.", path, err)
+		return nil, fmt.Errorf("Failed to read gzip contents: '%w'.", err)
 	}
 
-	return WriteBinaryFile(clearData, path)
+	return clearData, nil
 }
 
 // Gzip each file in a direcotry to bytes and return the output as a map: {<relpath>: bytes, ...}.
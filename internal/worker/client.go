@@ -0,0 +1,118 @@
+package worker
+
+// The client side of the remote grading worker protocol (see internal/api/workers).
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/api/workers"
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type client struct {
+	serverURL   string
+	userContext core.APIRequestUserContext
+}
+
+func newClient(serverURL string, email string, pass string) *client {
+	return &client{
+		serverURL: strings.TrimSuffix(serverURL, "/"),
+		userContext: core.APIRequestUserContext{
+			UserEmail: email,
+			UserPass:  util.Sha256HexFromString(pass),
+		},
+	}
+}
+
+func (this *client) register(name string) (*workers.RegisterResponse, error) {
+	request := workers.RegisterRequest{
+		APIRequestUserContext: this.userContext,
+		Name:                  name,
+	}
+
+	var response workers.RegisterResponse
+	err := this.send(`workers/register`, request, &response, false)
+
+	return &response, err
+}
+
+func (this *client) heartbeat(workerID string) (bool, error) {
+	request := workers.HeartbeatRequest{
+		APIRequestUserContext: this.userContext,
+		WorkerID:              core.NonEmptyString(workerID),
+	}
+
+	var response workers.HeartbeatResponse
+	err := this.send(`workers/heartbeat`, request, &response, false)
+
+	return response.Known, err
+}
+
+func (this *client) claim(workerID string, knownImages []string) (*workers.ClaimResponse, error) {
+	request := workers.ClaimRequest{
+		APIRequestUserContext: this.userContext,
+		WorkerID:              core.NonEmptyString(workerID),
+		KnownImages:           knownImages,
+	}
+
+	var response workers.ClaimResponse
+	err := this.send(`workers/claim`, request, &response, false)
+
+	return &response, err
+}
+
+func (this *client) complete(workerID string, result *model.RemoteGradingResult) (bool, error) {
+	request := workers.CompleteRequest{
+		APIRequestUserContext: this.userContext,
+		WorkerID:              core.NonEmptyString(workerID),
+		Result:                result,
+	}
+
+	// Results can be large, so send them as a multipart form (which has a larger size limit).
+	var response workers.CompleteResponse
+	err := this.send(`workers/complete`, request, &response, true)
+
+	return response.Accepted, err
+}
+
+func (this *client) send(endpoint string, request any, response any, multipart bool) error {
+	url := this.serverURL + core.MakeFullAPIPath(endpoint)
+
+	form := map[string]string{
+		core.API_REQUEST_CONTENT_KEY: util.MustToJSON(request),
+	}
+
+	var responseText string
+	var err error
+
+	if multipart {
+		responseText, err = common.PostFiles(url, form, nil, false)
+	} else {
+		responseText, err = common.PostNoCheck(url, form)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to send request to '%s': '%w'.", endpoint, err)
+	}
+
+	var apiResponse core.APIResponse
+	err = util.JSONFromString(responseText, &apiResponse)
+	if err != nil {
+		return fmt.Errorf("Failed to parse response from '%s': '%w'.", endpoint, err)
+	}
+
+	if !apiResponse.Success {
+		return fmt.Errorf("Request to '%s' was not successful (locator: '%s'): '%s'.", endpoint, apiResponse.Locator, apiResponse.Message)
+	}
+
+	err = util.JSONFromString(util.MustToJSON(apiResponse.Content), response)
+	if err != nil {
+		return fmt.Errorf("Failed to parse response content from '%s': '%w'.", endpoint, err)
+	}
+
+	return nil
+}
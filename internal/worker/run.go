@@ -0,0 +1,126 @@
+package worker
+
+// Run remote grading tasks on this machine using Docker.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var (
+	imageLock sync.Mutex
+	// The images that this worker has built: {image name: image hash}.
+	builtImages map[string]string = make(map[string]string)
+)
+
+// Get the hashes of all the images that this worker has built.
+func GetKnownImages() []string {
+	imageLock.Lock()
+	defer imageLock.Unlock()
+
+	hashes := make([]string, 0, len(builtImages))
+	for _, hash := range builtImages {
+		hashes = append(hashes, hash)
+	}
+
+	return hashes
+}
+
+// Run a remote grading task.
+// Failures to run the grader are reported in the result's error (and not returned) so that the server can give the task to another worker.
+func RunTask(ctx context.Context, task *model.RemoteGradingTask, leaveTempDir bool) *model.RemoteGradingResult {
+	result := &model.RemoteGradingResult{
+		TaskID: task.ID,
+	}
+
+	err := runTask(ctx, task, leaveTempDir, result)
+	if err != nil {
+		log.Warn("Failed to run remote grading task.", err, task)
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func runTask(ctx context.Context, task *model.RemoteGradingTask, leaveTempDir bool, result *model.RemoteGradingResult) error {
+	err := task.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = ensureImage(task)
+	if err != nil {
+		return err
+	}
+
+	tempDir, inputDir, outputDir, _, err := common.PrepTempGradingDir("worker")
+	if err != nil {
+		return err
+	}
+
+	if !leaveTempDir {
+		defer os.RemoveAll(tempDir)
+	} else {
+		log.Debug("Leaving behind temp grading dir.", task, log.NewAttr("path", tempDir))
+	}
+
+	err = util.GzipBytesToDirectory(inputDir, task.InputFilesGZip)
+	if err != nil {
+		return fmt.Errorf("Failed to write submission files: '%w'.", err)
+	}
+
+	stdout, stderr, exitStatus, timeout, canceled, err := docker.RunContainer(ctx, task, task.ImageName, inputDir, outputDir, task.FullSubmissionID, task.MaxRuntimeSecs, &task.ResourceLimits)
+
+	result.Stdout = stdout
+	result.Stderr = stderr
+	result.ExitStatus = exitStatus
+	result.Timeout = timeout
+
+	if err != nil {
+		return err
+	}
+
+	if canceled {
+		return fmt.Errorf("Grading was canceled on the worker.")
+	}
+
+	result.OutputFilesGZip, err = util.GzipDirectoryToBytes(outputDir)
+	if err != nil {
+		return fmt.Errorf("Failed to copy grading output '%s': '%w'.", outputDir, err)
+	}
+
+	return nil
+}
+
+// Make sure that the image for a task has been built (with the correct build context).
+func ensureImage(task *model.RemoteGradingTask) error {
+	imageLock.Lock()
+	defer imageLock.Unlock()
+
+	if builtImages[task.ImageName] == task.ImageHash {
+		return nil
+	}
+
+	if task.ImageContextGZip == nil {
+		return fmt.Errorf("Worker does not have image '%s' (hash: '%s') and no build context was provided.", task.ImageName, task.ImageHash)
+	}
+
+	log.Info("Building grading image.", task, log.NewAttr("image-hash", task.ImageHash))
+
+	err := docker.BuildImageFromContext(task, task.ImageName, task.ImageContextGZip)
+	if err != nil {
+		return fmt.Errorf("Failed to build image '%s': '%w'.", task.ImageName, err)
+	}
+
+	builtImages[task.ImageName] = task.ImageHash
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package worker
+
+// A remote grading worker.
+// A worker registers with an autograder server, then repeatedly claims grading tasks, runs them, and sends back the results.
+// Heartbeats are sent in the background so the server knows that the worker is still alive.
+// If the server ever forgets about the worker (e.g., the server restarted), then the worker will register again.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// How long to wait before trying again after failing to talk to the server.
+const RETRY_WAIT_SECS = 5
+
+// The number of times to try and send a result before giving up (the server will eventually reassign the task).
+const COMPLETE_ATTEMPTS = 3
+
+type Options struct {
+	ServerURL    string
+	Email        string
+	Pass         string
+	Name         string
+	Concurrency  int
+	LeaveTempDir bool
+}
+
+type worker struct {
+	client  *client
+	options Options
+
+	lock          sync.Mutex
+	id            string
+	heartbeatSecs int
+}
+
+func GetDefaultOptions() Options {
+	name := config.WORKER_NAME.Get()
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+
+	return Options{
+		ServerURL:    config.WORKER_SERVER.Get(),
+		Email:        config.WORKER_EMAIL.Get(),
+		Pass:         config.WORKER_PASS.Get(),
+		Name:         name,
+		Concurrency:  config.WORKER_CONCURRENCY.Get(),
+		LeaveTempDir: config.KEEP_BUILD_DIRS.Get(),
+	}
+}
+
+// Run a worker until the context is canceled.
+// An error is only returned if the worker could not initially register with the server.
+func Run(ctx context.Context, options Options) error {
+	if options.ServerURL == "" {
+		return fmt.Errorf("No server URL provided for the remote grading worker.")
+	}
+
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+
+	instance := &worker{
+		client:  newClient(options.ServerURL, options.Email, options.Pass),
+		options: options,
+	}
+
+	err := instance.register("")
+	if err != nil {
+		return err
+	}
+
+	var waitGroup sync.WaitGroup
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+		instance.sendHeartbeats(ctx)
+	}()
+
+	for i := 0; i < options.Concurrency; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			instance.runTasks(ctx)
+		}()
+	}
+
+	waitGroup.Wait()
+
+	log.Info("Remote grading worker stopped.", log.NewAttr("worker-name", options.Name))
+
+	return nil
+}
+
+// Register with the server.
+// If an old ID is provided, then only register if that ID is still in use
+// (so that multiple goroutines noticing an unknown ID only cause a single registration).
+func (this *worker) register(oldID string) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if (oldID != "") && (oldID != this.id) {
+		return nil
+	}
+
+	response, err := this.client.register(this.options.Name)
+	if err != nil {
+		return fmt.Errorf("Failed to register remote grading worker: '%w'.", err)
+	}
+
+	this.id = response.WorkerID
+	this.heartbeatSecs = response.HeartbeatSecs
+
+	log.Info("Remote grading worker registered.", log.NewAttr("worker-id", this.id), log.NewAttr("worker-name", this.options.Name))
+
+	return nil
+}
+
+func (this *worker) getID() (string, int) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.id, this.heartbeatSecs
+}
+
+func (this *worker) sendHeartbeats(ctx context.Context) {
+	for {
+		id, heartbeatSecs := this.getID()
+
+		if !sleep(ctx, heartbeatSecs) {
+			return
+		}
+
+		known, err := this.client.heartbeat(id)
+		if err != nil {
+			log.Warn("Failed to send heartbeat.", err, log.NewAttr("worker-id", id))
+			continue
+		}
+
+		if !known {
+			this.registerOrWait(ctx, id)
+		}
+	}
+}
+
+func (this *worker) runTasks(ctx context.Context) {
+	for ctx.Err() == nil {
+		id, _ := this.getID()
+
+		response, err := this.client.claim(id, GetKnownImages())
+		if err != nil {
+			log.Warn("Failed to claim grading task.", err, log.NewAttr("worker-id", id))
+			sleep(ctx, RETRY_WAIT_SECS)
+			continue
+		}
+
+		if !response.Known {
+			this.registerOrWait(ctx, id)
+			continue
+		}
+
+		if !response.FoundTask || (response.Task == nil) {
+			continue
+		}
+
+		log.Debug("Running grading task.", response.Task, log.NewAttr("worker-id", id))
+
+		result := RunTask(ctx, response.Task, this.options.LeaveTempDir)
+		this.sendResult(id, response.Task, result)
+	}
+}
+
+func (this *worker) sendResult(id string, task *model.RemoteGradingTask, result *model.RemoteGradingResult) {
+	for i := 0; i < COMPLETE_ATTEMPTS; i++ {
+		accepted, err := this.client.complete(id, result)
+		if err == nil {
+			if !accepted {
+				log.Warn("Server did not accept grading task result.", task, log.NewAttr("worker-id", id))
+			}
+
+			return
+		}
+
+		log.Warn("Failed to send grading task result.", err, task, log.NewAttr("worker-id", id), log.NewAttr("attempt", i+1))
+
+		// Do not use the worker's context, the result should still be sent when the worker is stopping.
+		if i < (COMPLETE_ATTEMPTS - 1) {
+			time.Sleep(time.Duration(RETRY_WAIT_SECS) * time.Second)
+		}
+	}
+}
+
+func (this *worker) registerOrWait(ctx context.Context, oldID string) {
+	err := this.register(oldID)
+	if err != nil {
+		log.Warn("Failed to register again.", err)
+		sleep(ctx, RETRY_WAIT_SECS)
+	}
+}
+
+// Returns false if the context was canceled.
+func sleep(ctx context.Context, secs int) bool {
+	timer := time.NewTimer(time.Duration(secs) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}